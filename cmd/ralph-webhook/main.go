@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/alecthomas/kong"
 	"github.com/zon/ralph/internal/argo"
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/webhook"
 	"github.com/zon/ralph/internal/webhookconfig"
 )
 
 type CLI struct {
-	Serve ServeCmd `cmd:"" default:"withargs" help:"Start the webhook server"`
-	Set   SetCmd   `cmd:"" help:"Set webhook configuration"`
+	Serve  ServeCmd  `cmd:"" default:"withargs" help:"Start the webhook server"`
+	Set    SetCmd    `cmd:"" help:"Set webhook configuration"`
+	Rotate RotateCmd `cmd:"" help:"Rotate webhook credentials"`
 }
 
 type ServeCmd struct {
@@ -30,12 +37,34 @@ func (c *ServeCmd) Run() error {
 		return err
 	}
 
-	s := webhook.NewServer(cfg, out, argo.NewClient())
+	// gh reads its token from the environment, so a token from the secrets file must be exported
+	if cfg.Secrets.GithubToken != "" {
+		if err := os.Setenv("GH_TOKEN", cfg.Secrets.GithubToken); err != nil {
+			return err
+		}
+	}
+
+	s := webhook.NewServer(cfg, out, argo.NewClient(), github.NewGH(out))
 	out.Infof("starting ralph-webhook service on port %d", cfg.App.Port)
 	return s.Run()
 }
 
+// loadRalphConfig loads .ralph/config.yaml so commands resolve their Kubernetes context like ralph
+// does. ralph-webhook is often run outside a checkout, where a missing .ralph directory just leaves
+// the flags and the current kubectl context to decide.
+func loadRalphConfig() (*config.RalphConfig, error) {
+	ralphConfig, err := config.LoadConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return ralphConfig, err
+}
+
 func main() {
+	// Commands stop their kubectl calls on SIGINT/SIGTERM instead of waiting out the timeout
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	cli := &CLI{}
 	ctx := kong.Parse(cli,
 		kong.Name("ralph-webhook"),
@@ -44,9 +73,10 @@ func main() {
 		kong.ConfigureHelp(kong.HelpOptions{
 			Compact: true,
 		}),
+		kong.BindTo(runCtx, (*context.Context)(nil)),
 	)
 	if err := ctx.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "error: %v\n", output.RedactError(err))
 		os.Exit(1)
 	}
 }
@@ -2,13 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/k8s"
 )
 
 func captureWebhookHelpOutput(args []string) string {
@@ -55,6 +58,9 @@ func TestWebhookCommandsParsed(t *testing.T) {
 	}{
 		{name: "serve", args: []string{"serve"}},
 		{name: "set config", args: []string{"set", "config"}},
+		{name: "rotate secret", args: []string{"rotate", "secret", "--repo", "acme/api"}},
+		{name: "set config for an environment", args: []string{"set", "config", "--env", "staging"}},
+		{name: "rotate secret for an environment", args: []string{"rotate", "secret", "--env", "prod"}},
 	}
 
 	for _, tt := range tests {
@@ -71,3 +77,59 @@ func TestWebhookCommandsParsed(t *testing.T) {
 		})
 	}
 }
+
+func TestRotateSecretCmdHelpText(t *testing.T) {
+	output := captureWebhookHelpOutput([]string{"rotate", "secret", "--help"})
+	assert.Contains(t, output, "Rotate webhook secrets")
+	assert.Contains(t, output, "--repo")
+}
+
+func TestWebhookCommandsResolveContext(t *testing.T) {
+	tests := []struct {
+		name            string
+		configContext   string
+		noRalphDir      bool
+		flagContext     string
+		expectedContext string
+	}{
+		{name: "workflow.context used when no flag is given", configContext: "config-context", expectedContext: "config-context"},
+		{name: "flag context overrides workflow.context", configContext: "config-context", flagContext: "flag-context", expectedContext: "flag-context"},
+		{name: "current context used when config sets none", expectedContext: "kubectl-context"},
+		{name: "current context used outside a checkout", noRalphDir: true, expectedContext: "kubectl-context"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+			if !tt.noRalphDir {
+				ralphDir := filepath.Join(dir, ".ralph")
+				require.NoError(t, os.MkdirAll(ralphDir, 0755))
+				if tt.configContext != "" {
+					content := "workflow:\n  context: " + tt.configContext + "\n"
+					require.NoError(t, os.WriteFile(filepath.Join(ralphDir, "config.yaml"), []byte(content), 0644))
+				}
+			}
+
+			ralphConfig, err := loadRalphConfig()
+			require.NoError(t, err)
+
+			k8sClient := &k8s.MockClient{
+				GetCurrentContextFunc: func(context.Context) (k8s.Context, error) {
+					return k8s.Context{Name: "kubectl-context", Namespace: "kubectl-namespace"}, nil
+				},
+			}
+
+			setCtx, err := (&setconfigCtxClient{ctx: context.Background(), k8sClient: k8sClient, ralphConfig: ralphConfig}).Resolve(tt.flagContext, "ralph-webhook")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContext, setCtx.Name, "set config")
+			assert.Equal(t, "ralph-webhook", setCtx.Namespace, "set config")
+
+			rotateCtx, err := (&rotateCtxClient{ctx: context.Background(), k8sClient: k8sClient, ralphConfig: ralphConfig}).Resolve(tt.flagContext, "ralph-webhook")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContext, rotateCtx.Name, "rotate secret")
+			assert.Equal(t, "ralph-webhook", rotateCtx.Namespace, "rotate secret")
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/github"
+	"github.com/zon/ralph/internal/k8s"
+	webhookrotatesecret "github.com/zon/ralph/internal/orchestration/webhookrotatesecret"
+	"github.com/zon/ralph/internal/output"
+	"github.com/zon/ralph/internal/webhookconfig"
+)
+
+type RotateCmd struct {
+	Secret RotateSecretCmd `cmd:"" help:"Rotate webhook secrets"`
+}
+
+type RotateSecretCmd struct {
+	Context   string `help:"Kubernetes context to use (defaults to workflow.context in .ralph/config.yaml, then the current context)"`
+	Namespace string `help:"Kubernetes namespace to use" default:"ralph-webhook"`
+	Repo      string `help:"Repository (owner/name) to rotate; defaults to every repository" placeholder:"OWNER/NAME"`
+	Env       string `help:"Register the rotated webhooks with this deployment from the environments in the webhook config instead of the default hostname" placeholder:"NAME"`
+}
+
+func (c *RotateSecretCmd) Run(ctx context.Context) error {
+	out := output.NewClient(os.Stdout, os.Stderr, false)
+
+	ralphConfig, err := loadRalphConfig()
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ghClient := github.NewGH(out)
+
+	cmd := &webhookrotatesecret.RotateSecretCmd{
+		Ctx:     &rotateCtxClient{ctx: ctx, k8sClient: k8sClient, ralphConfig: ralphConfig},
+		Config:  &rotateConfigClient{ctx: ctx, k8sClient: k8sClient},
+		Secrets: &rotateSecretsClient{ctx: ctx, k8sClient: k8sClient, out: out},
+		GitHub:  &rotateGitHubClient{ctx: ctx, ghClient: ghClient, out: out},
+	}
+
+	return cmd.Run(webhookrotatesecret.Flags{
+		Context:   c.Context,
+		Namespace: c.Namespace,
+		Repo:      c.Repo,
+		Env:       c.Env,
+	})
+}
+
+type rotateCtxClient struct {
+	ctx         context.Context
+	k8sClient   k8s.Client
+	ralphConfig *config.RalphConfig
+}
+
+func (c *rotateCtxClient) Resolve(flagContext, flagNamespace string) (webhookrotatesecret.K8sContext, error) {
+	k8sCtx, err := k8s.ResolveContext(c.ctx, c.k8sClient, c.ralphConfig, nil, flagContext, flagNamespace)
+	if err != nil {
+		return webhookrotatesecret.K8sContext{}, err
+	}
+	return webhookrotatesecret.K8sContext{Name: k8sCtx.Name, Namespace: k8sCtx.Namespace}, nil
+}
+
+type rotateConfigClient struct {
+	ctx       context.Context
+	k8sClient k8s.Client
+}
+
+func (c *rotateConfigClient) WebhookURL(k8sCtx webhookrotatesecret.K8sContext, env string) (string, error) {
+	return webhookconfig.IngressWebhookURLFromK8s(c.ctx, c.k8sClient, k8sCtx.Namespace, k8sCtx.Name, env)
+}
+
+type rotateSecretsClient struct {
+	ctx       context.Context
+	k8sClient k8s.Client
+	out       *output.Client
+}
+
+func (c *rotateSecretsClient) Read(k8sCtx webhookrotatesecret.K8sContext) (webhookrotatesecret.WebhookSecrets, error) {
+	secrets, err := webhookconfig.ReadWebhookSecretsFromK8s(c.ctx, c.k8sClient, k8sCtx.Namespace, k8sCtx.Name)
+	if err != nil {
+		return webhookrotatesecret.WebhookSecrets{}, err
+	}
+	return *secrets, nil
+}
+
+// Rotate returns the updated secret, with its tokens untouched, and the rotated repo entries.
+func (c *rotateSecretsClient) Rotate(secrets webhookrotatesecret.WebhookSecrets, repo string) (webhookrotatesecret.WebhookSecrets, webhookrotatesecret.WebhookSecrets, error) {
+	updated := secrets
+	updated.Repos = append([]webhookconfig.RepoSecret(nil), secrets.Repos...)
+	rotated, err := webhookconfig.RotateWebhookSecrets(&updated, repo, webhookconfig.GenerateWebhookSecret)
+	if err != nil {
+		return webhookrotatesecret.WebhookSecrets{}, webhookrotatesecret.WebhookSecrets{}, err
+	}
+	return updated, webhookrotatesecret.WebhookSecrets{Repos: rotated}, nil
+}
+
+func (c *rotateSecretsClient) Write(k8sCtx webhookrotatesecret.K8sContext, secrets webhookrotatesecret.WebhookSecrets) error {
+	return webhookconfig.WriteWebhookSecretsAndLog(c.ctx, c.k8sClient, k8sCtx.Name, k8sCtx.Namespace, &secrets, c.out)
+}
+
+type rotateGitHubClient struct {
+	ctx      context.Context
+	ghClient github.GHClient
+	out      *output.Client
+}
+
+func (c *rotateGitHubClient) RegisterWebhooks(webhookURL string, secrets webhookrotatesecret.WebhookSecrets) {
+	webhookconfig.RegisterAllGitHubWebhooks(c.ctx, c.ghClient, c.out, webhookURL, secrets.Repos)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	webhookrotatesecret "github.com/zon/ralph/internal/orchestration/webhookrotatesecret"
+	"github.com/zon/ralph/internal/output"
+	"github.com/zon/ralph/internal/webhookconfig"
+)
+
+func TestRotateSecretsKeepTokens(t *testing.T) {
+	stored := "repos:\n  - owner: acme\n    name: api\n    webhookSecret: current\ntriggerToken: trigger\ngithubToken: gh-token\n"
+	var written webhookconfig.Secrets
+	client := &rotateSecretsClient{
+		ctx:       context.Background(),
+		k8sClient: secretsK8sClient(stored, &written),
+		out:       output.NewClient(io.Discard, io.Discard, false),
+	}
+	k8sCtx := webhookrotatesecret.K8sContext{Name: "ctx", Namespace: "ralph-webhook"}
+
+	current, err := client.Read(k8sCtx)
+	require.NoError(t, err)
+	updated, rotated, err := client.Rotate(current, "")
+	require.NoError(t, err)
+	require.NoError(t, client.Write(k8sCtx, updated))
+
+	assert.Equal(t, "trigger", written.TriggerToken)
+	assert.Equal(t, "gh-token", written.GithubToken)
+	require.Len(t, written.Repos, 1)
+	assert.Equal(t, "current", written.Repos[0].PreviousSecret)
+	assert.Equal(t, rotated.Repos[0].WebhookSecret, written.Repos[0].WebhookSecret)
+	assert.Equal(t, "current", current.Repos[0].WebhookSecret, "the secret read is left untouched")
+}
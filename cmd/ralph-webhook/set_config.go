@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"os"
+	"strings"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/k8s"
 	webhooksetconfig "github.com/zon/ralph/internal/orchestration/webhooksetconfig"
@@ -16,43 +18,79 @@ type SetCmd struct {
 }
 
 type SetConfigCmd struct {
-	Context   string `help:"Kubernetes context to use (defaults to current context)"`
-	Namespace string `help:"Kubernetes namespace to use" default:"ralph-webhook"`
-	Config    string `name:"partial-config" help:"Path to a partial AppConfig YAML file to use as a starting point" type:"path" optional:""`
+	Context         string   `help:"Kubernetes context to use (defaults to workflow.context in .ralph/config.yaml, then the current context)"`
+	Namespace       string   `help:"Kubernetes namespace to use" default:"ralph-webhook"`
+	Config          string   `name:"partial-config" help:"Path to a partial AppConfig YAML file to use as a starting point" type:"path" optional:""`
+	Remove          []string `name:"remove" help:"Remove a repository (owner/name) from the config, deleting its GitHub webhook and webhook secret. Repeatable" placeholder:"OWNER/NAME"`
+	DryRun          bool     `name:"dry-run" help:"Print a diff of the config changes against the existing configmap without writing anything"`
+	CreateNamespace bool     `name:"create-namespace" help:"Create the namespace when it does not exist instead of asking"`
+	Env             string   `name:"env" help:"Register webhooks with this deployment from the environments in the webhook config instead of the default hostname" placeholder:"NAME"`
+	Output          string   `name:"output" help:"Also write the generated secrets YAML to this file, readable only by you, for backup in a password manager" type:"path" placeholder:"PATH"`
 }
 
-func (c *SetConfigCmd) Run() error {
-	ctx := context.Background()
+func (c *SetConfigCmd) Run(ctx context.Context) error {
 	out := output.NewClient(os.Stdout, os.Stderr, false)
 
-	k8sClient := k8s.NewClient()
+	ralphConfig, err := loadRalphConfig()
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
 	ghClient := github.NewGH(out)
 
 	cmd := &webhooksetconfig.SetConfigCmd{
-		Ctx:     &setconfigCtxClient{ctx: ctx, k8sClient: k8sClient},
-		Config:  &setconfigCfgClient{ctx: ctx, k8sClient: k8sClient, ghClient: ghClient, out: out},
-		Secrets: &setconfigSecretsClient{ctx: ctx, k8sClient: k8sClient, out: out},
-		GitHub:  &setconfigGitHubClient{ctx: ctx, ghClient: ghClient, out: out},
+		Ctx:        &setconfigCtxClient{ctx: ctx, k8sClient: k8sClient, ralphConfig: ralphConfig},
+		Namespaces: &setconfigNamespaceClient{ctx: ctx, k8sClient: k8sClient, out: out, opts: k8s.NamespaceOptions{Create: c.CreateNamespace, Confirm: out.TerminalConfirm()}},
+		Config:     &setconfigCfgClient{ctx: ctx, k8sClient: k8sClient, ghClient: ghClient, out: out},
+		Secrets:    &setconfigSecretsClient{ctx: ctx, k8sClient: k8sClient, out: out},
+		GitHub:     &setconfigGitHubClient{ctx: ctx, ghClient: ghClient, out: out},
 	}
 
 	return cmd.Run(webhooksetconfig.Flags{
 		Context:    c.Context,
 		Namespace:  c.Namespace,
 		ConfigPath: c.Config,
+		Remove:     c.Remove,
+		DryRun:     c.DryRun,
+		Env:        c.Env,
+		Output:     c.Output,
 	})
 }
 
 type setconfigCtxClient struct {
-	ctx       context.Context
-	k8sClient k8s.Client
+	ctx         context.Context
+	k8sClient   k8s.Client
+	ralphConfig *config.RalphConfig
 }
 
 func (c *setconfigCtxClient) Resolve(flagContext, flagNamespace string) (webhooksetconfig.K8sContext, error) {
-	kubeCtx, err := webhookconfig.GetKubeContext(c.ctx, c.k8sClient, flagContext)
+	k8sCtx, err := k8s.ResolveContext(c.ctx, c.k8sClient, c.ralphConfig, nil, flagContext, flagNamespace)
 	if err != nil {
 		return webhooksetconfig.K8sContext{}, err
 	}
-	return webhooksetconfig.K8sContext{Name: kubeCtx, Namespace: flagNamespace}, nil
+	return webhooksetconfig.K8sContext{Name: k8sCtx.Name, Namespace: k8sCtx.Namespace}, nil
+}
+
+type setconfigNamespaceClient struct {
+	ctx       context.Context
+	k8sClient k8s.Client
+	out       *output.Client
+	opts      k8s.NamespaceOptions
+}
+
+func (c *setconfigNamespaceClient) Ensure(k8sCtx webhooksetconfig.K8sContext) error {
+	created, err := k8s.EnsureNamespace(c.ctx, c.k8sClient, k8sCtx.Namespace, k8sCtx.Name, c.opts)
+	if err != nil {
+		return err
+	}
+	if created {
+		c.out.Successf("Namespace '%s' created", k8sCtx.Namespace)
+	}
+	return nil
 }
 
 type setconfigCfgClient struct {
@@ -62,8 +100,8 @@ type setconfigCfgClient struct {
 	out       *output.Client
 }
 
-func (c *setconfigCfgClient) Build(k8sCtx webhooksetconfig.K8sContext, configPath string) webhookconfig.AppConfig {
-	return webhookconfig.BuildWebhookAppConfigFromK8s(c.ctx, k8sCtx.Namespace, k8sCtx.Name, configPath, c.k8sClient, c.ghClient, c.out)
+func (c *setconfigCfgClient) Build(k8sCtx webhooksetconfig.K8sContext, configPath string, remove []string) (webhookconfig.AppConfig, error) {
+	return webhookconfig.BuildWebhookAppConfigFromK8s(c.ctx, k8sCtx.Namespace, k8sCtx.Name, configPath, remove, c.k8sClient, c.ghClient, c.out)
 }
 
 func (c *setconfigCfgClient) Write(k8sCtx webhooksetconfig.K8sContext, cfg webhookconfig.AppConfig) error {
@@ -78,23 +116,55 @@ func (c *setconfigCfgClient) Read(k8sCtx webhooksetconfig.K8sContext) (webhookco
 	return *cfg, nil
 }
 
+func (c *setconfigCfgClient) WebhookURL(cfg webhookconfig.AppConfig, env string) (string, error) {
+	return webhookconfig.IngressWebhookURL(&cfg, env)
+}
+
+func (c *setconfigCfgClient) PrintDiff(k8sCtx webhooksetconfig.K8sContext, configPath string, remove []string) error {
+	diff, err := webhookconfig.DiffWebhookAppConfigFromK8s(c.ctx, k8sCtx.Namespace, k8sCtx.Name, configPath, remove, c.k8sClient, c.ghClient, c.out)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		c.out.Infof("No changes to configmap '%s'", webhookconfig.WebhookConfigMapName)
+		return nil
+	}
+	c.out.Info(strings.TrimSuffix(diff, "\n"))
+	return nil
+}
+
 type setconfigSecretsClient struct {
 	ctx       context.Context
 	k8sClient k8s.Client
 	out       *output.Client
 }
 
-func (c *setconfigSecretsClient) Generate(cfg webhookconfig.AppConfig) (webhooksetconfig.WebhookSecrets, error) {
-	secrets, err := webhookconfig.BuildWebhookSecrets(&cfg, webhookconfig.GenerateWebhookSecret)
+func (c *setconfigSecretsClient) Read(k8sCtx webhooksetconfig.K8sContext) (webhooksetconfig.WebhookSecrets, error) {
+	secrets, err := webhookconfig.ReadExistingWebhookSecretsFromK8s(c.ctx, c.k8sClient, k8sCtx.Namespace, k8sCtx.Name)
 	if err != nil {
 		return webhooksetconfig.WebhookSecrets{}, err
 	}
-	return webhooksetconfig.WebhookSecrets{Repos: secrets.Repos}, nil
+	return *secrets, nil
+}
+
+func (c *setconfigSecretsClient) Generate(cfg webhookconfig.AppConfig, existing webhooksetconfig.WebhookSecrets) (webhooksetconfig.WebhookSecrets, error) {
+	secrets, err := webhookconfig.BuildWebhookSecrets(&cfg, &existing, webhookconfig.GenerateWebhookSecret)
+	if err != nil {
+		return webhooksetconfig.WebhookSecrets{}, err
+	}
+	return *secrets, nil
 }
 
 func (c *setconfigSecretsClient) Write(k8sCtx webhooksetconfig.K8sContext, secrets webhooksetconfig.WebhookSecrets) error {
-	s := &webhookconfig.Secrets{Repos: secrets.Repos}
-	return webhookconfig.WriteWebhookSecretsAndLog(c.ctx, c.k8sClient, k8sCtx.Name, k8sCtx.Namespace, s, c.out)
+	return webhookconfig.WriteWebhookSecretsAndLog(c.ctx, c.k8sClient, k8sCtx.Name, k8sCtx.Namespace, &secrets, c.out)
+}
+
+func (c *setconfigSecretsClient) Save(path string, secrets webhooksetconfig.WebhookSecrets) error {
+	if err := webhookconfig.WriteSecretsFile(path, &secrets); err != nil {
+		return err
+	}
+	c.out.Warnf("Webhook secrets written to %s; it holds every webhook secret in plain text, so move it to a password manager and delete it", path)
+	return nil
 }
 
 type setconfigGitHubClient struct {
@@ -103,6 +173,10 @@ type setconfigGitHubClient struct {
 	out      *output.Client
 }
 
-func (c *setconfigGitHubClient) RegisterWebhooks(secrets webhooksetconfig.WebhookSecrets) {
-	webhookconfig.RegisterAllGitHubWebhooks(c.ctx, c.ghClient, c.out, secrets.Repos)
+func (c *setconfigGitHubClient) RegisterWebhooks(webhookURL string, secrets webhooksetconfig.WebhookSecrets) {
+	webhookconfig.RegisterAllGitHubWebhooks(c.ctx, c.ghClient, c.out, webhookURL, secrets.Repos)
+}
+
+func (c *setconfigGitHubClient) UnregisterWebhooks(webhookURL string, fullNames []string) {
+	webhookconfig.UnregisterGitHubWebhooks(c.ctx, c.ghClient, c.out, webhookURL, fullNames)
 }
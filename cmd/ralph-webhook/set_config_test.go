@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/k8s"
+	webhooksetconfig "github.com/zon/ralph/internal/orchestration/webhooksetconfig"
+	"github.com/zon/ralph/internal/output"
+	"github.com/zon/ralph/internal/webhookconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// secretsK8sClient serves stored as the webhook secret and records what is written back.
+func secretsK8sClient(stored string, written *webhookconfig.Secrets) *k8s.MockClient {
+	return &k8s.MockClient{
+		SecretExistsFunc: func(context.Context, string, string, string) (bool, error) { return true, nil },
+		GetSecretDataFunc: func(context.Context, string, string, string) (string, error) {
+			return stored, nil
+		},
+		CreateOrUpdateSecretFunc: func(_ context.Context, _, _, _ string, data map[string]string) error {
+			return yaml.Unmarshal([]byte(data["secrets.yaml"]), written)
+		},
+	}
+}
+
+func TestSetConfigSecretsKeepExisting(t *testing.T) {
+	stored := "repos:\n  - owner: acme\n    name: api\n    webhookSecret: current\n    previousSecret: old\ntriggerToken: trigger\ngithubToken: gh-token\n"
+	var written webhookconfig.Secrets
+	client := &setconfigSecretsClient{
+		ctx:       context.Background(),
+		k8sClient: secretsK8sClient(stored, &written),
+		out:       output.NewClient(io.Discard, io.Discard, false),
+	}
+	k8sCtx := webhooksetconfig.K8sContext{Name: "ctx", Namespace: "ralph-webhook"}
+
+	existing, err := client.Read(k8sCtx)
+	require.NoError(t, err)
+	cfg := webhookconfig.AppConfig{Repos: []webhookconfig.RepoConfig{{Owner: "acme", Name: "api"}, {Owner: "acme", Name: "web"}}}
+	secrets, err := client.Generate(cfg, existing)
+	require.NoError(t, err)
+	require.NoError(t, client.Write(k8sCtx, secrets))
+
+	assert.Equal(t, "trigger", written.TriggerToken)
+	assert.Equal(t, "gh-token", written.GithubToken)
+	require.Len(t, written.Repos, 2)
+	assert.Equal(t, webhookconfig.RepoSecret{Owner: "acme", Name: "api", WebhookSecret: "current", PreviousSecret: "old"}, written.Repos[0])
+	assert.NotEmpty(t, written.Repos[1].WebhookSecret)
+}
@@ -16,6 +16,7 @@ var Date = "unknown"
 var cleanupManager = cleanup.NewManager(output.NewClient(os.Stdout, os.Stderr, false))
 
 func main() {
+	cleanupManager.SetHandlerTimeout(cleanup.HandlerTimeoutFromEnv())
 	cleanupManager.SetupSignalHandlers()
 
 	defer cleanupManager.Cleanup()
@@ -23,6 +24,7 @@ func main() {
 	c := &cmd.Cmd{}
 	c.SetVersion(version.Version(), Date)
 	c.SetCleanupRegistrar(cleanupManager.RegisterCleanup)
+	c.SetContext(cleanupManager.Context())
 
 	ctx := kong.Parse(c,
 		kong.Name("ralph"),
@@ -34,5 +36,5 @@ func main() {
 	)
 
 	err := ctx.Run()
-	ctx.FatalIfErrorf(err)
+	ctx.FatalIfErrorf(output.RedactError(err))
 }
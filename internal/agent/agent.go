@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/opencode"
+)
+
+// OpenCodeName selects the OpenCode backend in the agent field of .ralph/config.yaml; it is the default.
+const OpenCodeName = config.AgentOpenCode
+
+// Agent runs a coding agent on a prompt in the current repository.
+type Agent interface {
+	// Run sends prompt to the agent and returns the last lines of the output it printed
+	Run(ctx context.Context, prompt string) (string, error)
+}
+
+// Options pick the model an Agent runs with and where its live output goes.
+type Options struct {
+	Model   string
+	Variant string
+	Stdout  io.Writer // Receives the agent's output as it runs (default: os.Stdout)
+	Stderr  io.Writer // Receives the agent's errors as it runs (default: os.Stderr)
+}
+
+// Backend creates the Agents of one agent program. The prompt-building code takes a Backend
+// so the agent behind the run loop can be swapped, and faked with a MockBackend in tests.
+type Backend interface {
+	Agent(opts Options) Agent
+}
+
+// ForName returns the Backend selected by the agent field of .ralph/config.yaml; an empty name selects
+// OpenCode. Names outside config.Agents fail with the same error LoadConfig reports.
+func ForName(name string) (Backend, error) {
+	if err := config.ValidateAgent(name); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "", OpenCodeName:
+		return NewOpenCode(opencode.New()), nil
+	default:
+		return nil, fmt.Errorf("agent %q has no backend", name)
+	}
+}
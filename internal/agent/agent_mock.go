@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// Call is one prompt run by an Agent from MockBackend.
+type Call struct {
+	Options Options
+	Prompt  string
+}
+
+// MockBackend records every prompt its Agents are asked to run. RunFunc, when set, supplies
+// the result of each call; otherwise calls succeed with no output.
+type MockBackend struct {
+	RunFunc func(call Call) (string, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ Backend = (*MockBackend)(nil)
+
+func (m *MockBackend) Agent(opts Options) Agent {
+	return &mockAgent{backend: m, opts: opts}
+}
+
+// Calls returns the prompts run so far, in order.
+func (m *MockBackend) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Prompts returns the text of the prompts run so far, in order.
+func (m *MockBackend) Prompts() []string {
+	calls := m.Calls()
+	prompts := make([]string, len(calls))
+	for i, call := range calls {
+		prompts[i] = call.Prompt
+	}
+	return prompts
+}
+
+type mockAgent struct {
+	backend *MockBackend
+	opts    Options
+}
+
+func (a *mockAgent) Run(_ context.Context, prompt string) (string, error) {
+	call := Call{Options: a.opts, Prompt: prompt}
+	a.backend.mu.Lock()
+	a.backend.calls = append(a.backend.calls, call)
+	a.backend.mu.Unlock()
+	if a.backend.RunFunc != nil {
+		return a.backend.RunFunc(call)
+	}
+	return "", nil
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zon/ralph/internal/opencode"
+)
+
+// tailLines is how much of a run's output is kept for its result and for the error of a failed run
+const tailLines = 10
+
+// OpenCode is the Backend that runs prompts with the opencode CLI.
+type OpenCode struct {
+	oc opencode.OCClient
+}
+
+var _ Backend = (*OpenCode)(nil)
+
+func NewOpenCode(oc opencode.OCClient) *OpenCode {
+	return &OpenCode{oc: oc}
+}
+
+func (o *OpenCode) Agent(opts Options) Agent {
+	return &openCodeAgent{oc: o.oc, opts: opts}
+}
+
+// Stats reports the token usage and cost opencode has recorded.
+func (o *OpenCode) Stats() (opencode.Stats, error) {
+	return o.oc.GetStats()
+}
+
+type openCodeAgent struct {
+	oc   opencode.OCClient
+	opts Options
+}
+
+func (a *openCodeAgent) Run(ctx context.Context, prompt string) (string, error) {
+	ring := &ringWriter{n: tailLines}
+	stdout := io.MultiWriter(writerOr(a.opts.Stdout, os.Stdout), ring)
+	stderr := io.MultiWriter(writerOr(a.opts.Stderr, os.Stderr), ring)
+	if err := a.oc.RunCommand(ctx, a.opts.Model, a.opts.Variant, prompt, stdout, stderr); err != nil {
+		return ring.Tail(), fmt.Errorf("opencode execution failed: %w\n\nLast %d lines of output:\n%s", err, tailLines, ring.Tail())
+	}
+	return ring.Tail(), nil
+}
+
+func writerOr(w, fallback io.Writer) io.Writer {
+	if w == nil {
+		return fallback
+	}
+	return w
+}
+
+// ringWriter keeps the last n lines written to it, so a long agent run holds a bounded amount
+// of output in memory.
+type ringWriter struct {
+	n     int
+	lines []string
+	buf   string
+}
+
+func (r *ringWriter) Write(p []byte) (int, error) {
+	s := r.buf + string(p)
+	parts := strings.Split(s, "\n")
+	r.buf = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		r.lines = append(r.lines, line)
+		if len(r.lines) > r.n {
+			r.lines = r.lines[1:]
+		}
+	}
+	return len(p), nil
+}
+
+func (r *ringWriter) Tail() string {
+	lines := r.lines
+	if r.buf != "" {
+		lines = append(lines, r.buf)
+	}
+	return strings.Join(lines, "\n")
+}
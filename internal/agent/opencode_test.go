@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/opencode"
+)
+
+func TestOpenCodeRun(t *testing.T) {
+	var gotModel, gotVariant, gotPrompt string
+	oc := &opencode.MockOC{
+		RunCommandFunc: func(_ context.Context, model, variant, prompt string, stdout, stderr io.Writer) error {
+			gotModel, gotVariant, gotPrompt = model, variant, prompt
+			fmt.Fprint(stdout, "working\n")
+			fmt.Fprint(stderr, "warning\n")
+			return nil
+		},
+	}
+	var stdout, stderr bytes.Buffer
+
+	out, err := NewOpenCode(oc).Agent(Options{Model: "m", Variant: "v", Stdout: &stdout, Stderr: &stderr}).Run(context.Background(), "do it")
+	require.NoError(t, err)
+
+	assert.Equal(t, "m", gotModel)
+	assert.Equal(t, "v", gotVariant)
+	assert.Equal(t, "do it", gotPrompt)
+	assert.Equal(t, "working\nwarning", out)
+	assert.Equal(t, "working\n", stdout.String())
+	assert.Equal(t, "warning\n", stderr.String())
+}
+
+func TestOpenCodeRunReturnsOutputTail(t *testing.T) {
+	oc := &opencode.MockOC{
+		RunCommandFunc: func(_ context.Context, _, _, _ string, stdout, _ io.Writer) error {
+			for i := 1; i <= 12; i++ {
+				fmt.Fprintf(stdout, "line %d\n", i)
+			}
+			return nil
+		},
+	}
+
+	out, err := NewOpenCode(oc).Agent(Options{Stdout: io.Discard, Stderr: io.Discard}).Run(context.Background(), "prompt")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "line 3\n"))
+	assert.True(t, strings.HasSuffix(out, "line 12"))
+}
+
+func TestOpenCodeRunErrorKeepsOutputTail(t *testing.T) {
+	errFailed := errors.New("exit status 1")
+	oc := &opencode.MockOC{
+		RunCommandFunc: func(_ context.Context, _, _, _ string, stdout, _ io.Writer) error {
+			for i := 1; i <= 12; i++ {
+				fmt.Fprintf(stdout, "line %d\n", i)
+			}
+			return errFailed
+		},
+	}
+
+	_, err := NewOpenCode(oc).Agent(Options{Stdout: io.Discard, Stderr: io.Discard}).Run(context.Background(), "prompt")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFailed)
+	assert.Contains(t, err.Error(), "opencode execution failed")
+	assert.Contains(t, err.Error(), "line 3\n")
+	assert.True(t, strings.HasSuffix(err.Error(), "line 12"))
+	assert.NotContains(t, err.Error(), "line 2\n")
+}
+
+func TestRingWriterTail(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		buf      string
+		expected string
+	}{
+		{
+			name:     "empty",
+			lines:    []string{},
+			buf:      "",
+			expected: "",
+		},
+		{
+			name:     "fewer than n lines",
+			lines:    []string{"line1", "line2", "line3"},
+			buf:      "",
+			expected: "line1\nline2\nline3",
+		},
+		{
+			name:     "with partial line",
+			lines:    []string{"line1", "line2"},
+			buf:      "line3",
+			expected: "line1\nline2\nline3",
+		},
+		{
+			name:     "exactly n lines",
+			lines:    []string{"line1", "line2", "line3"},
+			buf:      "",
+			expected: "line1\nline2\nline3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := &ringWriter{n: 10, lines: tt.lines, buf: tt.buf}
+			result := cw.Tail()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestForName(t *testing.T) {
+	tests := []struct {
+		name    string
+		agent   string
+		wantErr string
+	}{
+		{name: "default", agent: ""},
+		{name: "opencode", agent: OpenCodeName},
+		{name: "unknown", agent: "aider", wantErr: `unknown agent "aider"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, err := ForName(tt.agent)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, &OpenCode{}, backend)
+		})
+	}
+}
@@ -4,17 +4,15 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
-
-	"github.com/zon/ralph/internal/opencode"
 )
 
 //go:embed pr-summary-instructions.md
@@ -63,6 +61,8 @@ type DevelopPromptData struct {
 	ProjectFilePath     string
 	Services            []config.Service
 	Instructions        string
+	Size                string // Size hint of the selected requirement (small, medium, large)
+	AcceptanceNotes     string // Notes recorded on the selected requirement by earlier iterations
 }
 
 type PickPromptData struct {
@@ -70,6 +70,13 @@ type PickPromptData struct {
 	CommitLog      string
 	ProjectContent string
 	PickedReqPath  string
+	Blocked        []BlockedRequirement
+}
+
+// BlockedRequirement names a failing requirement and the dependencies that are not yet passing
+type BlockedRequirement struct {
+	Slug      string
+	BlockedBy []string
 }
 
 type PRSummaryPromptData struct {
@@ -162,6 +169,8 @@ func BuildDevelopPrompt(data DevelopPromptData) (string, error) {
 		SelectedRequirement string
 		ProjectFilePath     string
 		Services            []config.Service
+		Size                string
+		AcceptanceNotes     string
 	}{
 		Notes:               data.Notes,
 		CommitLog:           data.CommitLog,
@@ -169,6 +178,8 @@ func BuildDevelopPrompt(data DevelopPromptData) (string, error) {
 		SelectedRequirement: data.SelectedRequirement,
 		ProjectFilePath:     data.ProjectFilePath,
 		Services:            data.Services,
+		Size:                data.Size,
+		AcceptanceNotes:     strings.TrimRight(data.AcceptanceNotes, "\n"),
 	}
 
 	return executeTemplate(data.Instructions, tmplData)
@@ -180,11 +191,13 @@ func BuildPickPrompt(data PickPromptData) (string, error) {
 		CommitLog      string
 		ProjectContent string
 		PickedReqPath  string
+		Blocked        []BlockedRequirement
 	}{
 		Notes:          data.Notes,
 		CommitLog:      data.CommitLog,
 		ProjectContent: strings.TrimRight(data.ProjectContent, "\n"),
 		PickedReqPath:  data.PickedReqPath,
+		Blocked:        data.Blocked,
 	}
 
 	return executeTemplate(config.DefaultPickInstructions(), tmplData)
@@ -321,22 +334,26 @@ func resolveVariant(ctx *execcontext.Context) string {
 	return ralphConfig.Variant
 }
 
-func RunAgent(ctx *execcontext.Context, oc opencode.OCClient, prompt string) error {
-	if ctx.IsVerbose() {
-		ctx.Output().Debug(prompt)
-	}
-
-	model := resolveModel(ctx)
-
-	return oc.RunAgent(ctx.GoContext(), model, resolveVariant(ctx), prompt)
+func RunAgent(ctx *execcontext.Context, backend agent.Backend, prompt string) error {
+	return RunAgentWithModel(ctx, backend, prompt, resolveModel(ctx))
 }
 
-func RunAgentWithModel(ctx *execcontext.Context, oc opencode.OCClient, prompt string, model string) error {
+func RunAgentWithModel(ctx *execcontext.Context, backend agent.Backend, prompt string, model string) error {
 	if ctx.IsVerbose() {
 		ctx.Output().Debug(prompt)
 	}
 
-	return oc.RunAgent(ctx.GoContext(), model, resolveVariant(ctx), prompt)
+	opts := agent.Options{Model: model, Variant: resolveVariant(ctx)}
+	if ctx.ShouldStream() {
+		stdout, stderr := ctx.Output().LineWriters()
+		defer stdout.Flush()
+		defer stderr.Flush()
+		opts.Stdout = stdout
+		opts.Stderr = stderr
+	}
+
+	_, err := backend.Agent(opts).Run(ctx.GoContext(), prompt)
+	return err
 }
 
 // createTempFile creates a temp file under the repo's tmp/ directory so that
@@ -349,15 +366,15 @@ func createTempFile(name string) (*os.File, error) {
 	return os.Create(path)
 }
 
-func runOpenCodeAndReadResult(ctx *execcontext.Context, oc opencode.OCClient, model, prompt, outputFile string) (string, error) {
-	var stdoutWriter, stderrWriter io.Writer
+func runAgentAndReadResult(ctx *execcontext.Context, backend agent.Backend, model, prompt, outputFile string) (string, error) {
+	opts := agent.Options{Model: model, Variant: resolveVariant(ctx)}
 	if ctx.IsVerbose() {
-		stdoutWriter = os.Stdout
-		stderrWriter = os.Stderr
+		opts.Stdout = os.Stdout
+		opts.Stderr = os.Stderr
 	}
 
-	if err := oc.RunCommand(ctx.GoContext(), model, resolveVariant(ctx), prompt, stdoutWriter, stderrWriter); err != nil {
-		return "", fmt.Errorf("opencode execution failed: %w", err)
+	if _, err := backend.Agent(opts).Run(ctx.GoContext(), prompt); err != nil {
+		return "", err
 	}
 
 	summaryBytes, err := os.ReadFile(outputFile)
@@ -373,7 +390,7 @@ func runOpenCodeAndReadResult(ctx *execcontext.Context, oc opencode.OCClient, mo
 	return summary, nil
 }
 
-func GeneratePRSummary(ctx *execcontext.Context, oc opencode.OCClient, projectDesc, projectStatus, baseBranch, commitLog string) (summary string, err error) {
+func GeneratePRSummary(ctx *execcontext.Context, backend agent.Backend, projectDesc, projectStatus, baseBranch, commitLog string) (summary string, err error) {
 	f, err := createTempFile("pr-summary.md")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temporary PR summary file: %w", err)
@@ -392,7 +409,7 @@ func GeneratePRSummary(ctx *execcontext.Context, oc opencode.OCClient, projectDe
 	}
 
 	model := resolveModel(ctx)
-	summary, err = runOpenCodeAndReadResult(ctx, oc, model, prPrompt, tmpFile)
+	summary, err = runAgentAndReadResult(ctx, backend, model, prPrompt, tmpFile)
 	if err != nil {
 		return "", err
 	}
@@ -400,7 +417,7 @@ func GeneratePRSummary(ctx *execcontext.Context, oc opencode.OCClient, projectDe
 	return summary, nil
 }
 
-func GenerateChangelog(ctx *execcontext.Context, oc opencode.OCClient) (err error) {
+func GenerateChangelog(ctx *execcontext.Context, backend agent.Backend) (err error) {
 	f, err := createTempFile("changelog.md")
 	if err != nil {
 		return fmt.Errorf("failed to create temporary changelog file: %w", err)
@@ -419,7 +436,7 @@ func GenerateChangelog(ctx *execcontext.Context, oc opencode.OCClient) (err erro
 	}
 
 	model := resolveModel(ctx)
-	_, err = runOpenCodeAndReadResult(ctx, oc, model, changelogPrompt, tmpFile)
+	_, err = runAgentAndReadResult(ctx, backend, model, changelogPrompt, tmpFile)
 	if err != nil {
 		return err
 	}
@@ -431,7 +448,7 @@ func GenerateChangelog(ctx *execcontext.Context, oc opencode.OCClient) (err erro
 	return nil
 }
 
-func GenerateReviewPRBody(ctx *execcontext.Context, oc opencode.OCClient, projectName, projectDesc string, requirementSummaries []string) (summary string, err error) {
+func GenerateReviewPRBody(ctx *execcontext.Context, backend agent.Backend, projectName, projectDesc string, requirementSummaries []string) (summary string, err error) {
 	f, err := createTempFile("review-pr-body.md")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temporary review PR body file: %w", err)
@@ -450,7 +467,7 @@ func GenerateReviewPRBody(ctx *execcontext.Context, oc opencode.OCClient, projec
 	}
 
 	model := resolveModel(ctx)
-	summary, err = runOpenCodeAndReadResult(ctx, oc, model, reviewPrompt, tmpFile)
+	summary, err = runAgentAndReadResult(ctx, backend, model, reviewPrompt, tmpFile)
 	if err != nil {
 		return "", err
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/agent"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/output"
@@ -85,7 +86,7 @@ func TestGenerateChangelog(t *testing.T) {
 
 			ctx := &execcontext.Context{}
 			mockOC := tt.setupMock(t)
-			err := GenerateChangelog(ctx, mockOC)
+			err := GenerateChangelog(ctx, agent.NewOpenCode(mockOC))
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -118,7 +119,7 @@ func TestGenerateChangelog(t *testing.T) {
 			},
 		}
 
-		err := GenerateChangelog(ctx, mockOC)
+		err := GenerateChangelog(ctx, agent.NewOpenCode(mockOC))
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "Write a concise changelog entry")
 	})
@@ -223,21 +224,14 @@ func TestRunAgent(t *testing.T) {
 		ctx.SetModel("gpt-4")
 		ctx.SetVariant("custom-variant")
 
-		var capturedModel, capturedVariant, capturedPrompt string
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				capturedModel = model
-				capturedVariant = variant
-				capturedPrompt = prompt
-				return nil
-			},
-		}
-
-		err := RunAgent(ctx, mockOC, "test prompt")
+		backend := &agent.MockBackend{}
+		err := RunAgent(ctx, backend, "test prompt")
 		require.NoError(t, err)
-		assert.Equal(t, "gpt-4", capturedModel)
-		assert.Equal(t, "custom-variant", capturedVariant)
-		assert.Equal(t, "test prompt", capturedPrompt)
+		require.Len(t, backend.Calls(), 1)
+		call := backend.Calls()[0]
+		assert.Equal(t, "gpt-4", call.Options.Model)
+		assert.Equal(t, "custom-variant", call.Options.Variant)
+		assert.Equal(t, "test prompt", call.Prompt)
 	})
 
 	t.Run("returns underlying error unchanged", func(t *testing.T) {
@@ -245,13 +239,11 @@ func TestRunAgent(t *testing.T) {
 		ctx.SetModel("gpt-4")
 
 		expectedErr := errors.New("agent execution failed")
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return expectedErr
-			},
+		backend := &agent.MockBackend{
+			RunFunc: func(agent.Call) (string, error) { return "", expectedErr },
 		}
 
-		err := RunAgent(ctx, mockOC, "test prompt")
+		err := RunAgent(ctx, backend, "test prompt")
 		assert.Equal(t, expectedErr, err, "error should be returned unchanged, not wrapped")
 	})
 
@@ -261,13 +253,7 @@ func TestRunAgent(t *testing.T) {
 		ctx.SetVerbose(true)
 		ctx.SetOutput(output.NewClient(&buf, &buf, true))
 
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return nil
-			},
-		}
-
-		err := RunAgent(ctx, mockOC, "verbose prompt")
+		err := RunAgent(ctx, &agent.MockBackend{}, "verbose prompt")
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "verbose prompt")
 	})
@@ -278,15 +264,41 @@ func TestRunAgent(t *testing.T) {
 		ctx.SetVerbose(false)
 		ctx.SetOutput(output.NewClient(&buf, &buf, false))
 
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return nil
+		err := RunAgent(ctx, &agent.MockBackend{}, "quiet prompt")
+		require.NoError(t, err)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("streams agent output line by line", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		ctx := &execcontext.Context{}
+		ctx.SetModel("gpt-4")
+		ctx.SetStream(true)
+		ctx.SetOutput(output.NewClient(&out, &errOut, false))
+
+		backend := &agent.MockBackend{
+			RunFunc: func(call agent.Call) (string, error) {
+				fmt.Fprint(call.Options.Stdout, "reading files\nedit")
+				assert.Equal(t, "reading files\n", out.String(), "complete lines are relayed as they are written")
+				fmt.Fprint(call.Options.Stdout, "ing main.go\n")
+				assert.Equal(t, "reading files\nediting main.go\n", out.String())
+				fmt.Fprint(call.Options.Stderr, "no trailing newline")
+				return "", nil
 			},
 		}
+		require.NoError(t, RunAgent(ctx, backend, "test prompt"))
+		assert.Equal(t, "no trailing newline\n", errOut.String(), "a partial line is flushed when the agent exits")
+	})
 
-		err := RunAgent(ctx, mockOC, "quiet prompt")
-		require.NoError(t, err)
-		assert.Empty(t, buf.String())
+	t.Run("leaves agent output on the process streams without streaming", func(t *testing.T) {
+		ctx := &execcontext.Context{}
+		ctx.SetModel("gpt-4")
+
+		backend := &agent.MockBackend{}
+		require.NoError(t, RunAgent(ctx, backend, "test prompt"))
+		require.Len(t, backend.Calls(), 1)
+		assert.Nil(t, backend.Calls()[0].Options.Stdout)
+		assert.Nil(t, backend.Calls()[0].Options.Stderr)
 	})
 }
 
@@ -296,32 +308,23 @@ func TestRunAgentWithModel(t *testing.T) {
 		ctx.SetModel("default-model")
 		ctx.SetVariant("my-variant")
 
-		var capturedModel, capturedVariant string
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				capturedModel = model
-				capturedVariant = variant
-				return nil
-			},
-		}
-
-		err := RunAgentWithModel(ctx, mockOC, "test prompt", "explicit-model")
+		backend := &agent.MockBackend{}
+		err := RunAgentWithModel(ctx, backend, "test prompt", "explicit-model")
 		require.NoError(t, err)
-		assert.Equal(t, "explicit-model", capturedModel, "model should be passed verbatim, not resolved")
-		assert.Equal(t, "my-variant", capturedVariant, "variant should still be resolved from context")
+		require.Len(t, backend.Calls(), 1)
+		assert.Equal(t, "explicit-model", backend.Calls()[0].Options.Model, "model should be passed verbatim, not resolved")
+		assert.Equal(t, "my-variant", backend.Calls()[0].Options.Variant, "variant should still be resolved from context")
 	})
 
 	t.Run("returns underlying error unchanged", func(t *testing.T) {
 		ctx := &execcontext.Context{}
 
 		expectedErr := errors.New("agent execution failed")
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return expectedErr
-			},
+		backend := &agent.MockBackend{
+			RunFunc: func(agent.Call) (string, error) { return "", expectedErr },
 		}
 
-		err := RunAgentWithModel(ctx, mockOC, "test prompt", "some-model")
+		err := RunAgentWithModel(ctx, backend, "test prompt", "some-model")
 		assert.Equal(t, expectedErr, err, "error should be returned unchanged, not wrapped")
 	})
 
@@ -331,13 +334,7 @@ func TestRunAgentWithModel(t *testing.T) {
 		ctx.SetVerbose(true)
 		ctx.SetOutput(output.NewClient(&buf, &buf, true))
 
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return nil
-			},
-		}
-
-		err := RunAgentWithModel(ctx, mockOC, "verbose prompt", "some-model")
+		err := RunAgentWithModel(ctx, &agent.MockBackend{}, "verbose prompt", "some-model")
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "verbose prompt")
 	})
@@ -348,19 +345,13 @@ func TestRunAgentWithModel(t *testing.T) {
 		ctx.SetVerbose(false)
 		ctx.SetOutput(output.NewClient(&buf, &buf, false))
 
-		mockOC := &opencode.MockOC{
-			RunAgentFunc: func(_ context.Context, model, variant, prompt string) error {
-				return nil
-			},
-		}
-
-		err := RunAgentWithModel(ctx, mockOC, "quiet prompt", "some-model")
+		err := RunAgentWithModel(ctx, &agent.MockBackend{}, "quiet prompt", "some-model")
 		require.NoError(t, err)
 		assert.Empty(t, buf.String())
 	})
 }
 
-func TestRunOpenCodeAndReadResult(t *testing.T) {
+func TestRunAgentAndReadResult(t *testing.T) {
 	originalErr := errors.New("command failed")
 
 	tests := []struct {
@@ -427,7 +418,7 @@ func TestRunOpenCodeAndReadResult(t *testing.T) {
 			outputFile := filepath.Join(t.TempDir(), "output.md")
 			mockOC := tt.setupMock(t, outputFile)
 
-			result, err := runOpenCodeAndReadResult(tt.ctx, mockOC, "some-model", "some prompt", outputFile)
+			result, err := runAgentAndReadResult(tt.ctx, agent.NewOpenCode(mockOC), "some-model", "some prompt", outputFile)
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -443,7 +434,7 @@ func TestRunOpenCodeAndReadResult(t *testing.T) {
 	}
 }
 
-func TestRunOpenCodeAndReadResultVerboseWiring(t *testing.T) {
+func TestRunAgentAndReadResultVerboseWiring(t *testing.T) {
 	tests := []struct {
 		name    string
 		verbose bool
@@ -467,24 +458,23 @@ func TestRunOpenCodeAndReadResultVerboseWiring(t *testing.T) {
 			ctx.SetVerbose(tt.verbose)
 			outputFile := filepath.Join(t.TempDir(), "output.md")
 
-			var capturedStdout, capturedStderr io.Writer
-			mockOC := &opencode.MockOC{
-				RunCommandFunc: func(_ context.Context, model, variant, prompt string, stdoutWriter, stderrWriter io.Writer) error {
-					capturedStdout = stdoutWriter
-					capturedStderr = stderrWriter
-					return os.WriteFile(outputFile, []byte("content"), 0644)
+			backend := &agent.MockBackend{
+				RunFunc: func(agent.Call) (string, error) {
+					return "", os.WriteFile(outputFile, []byte("content"), 0644)
 				},
 			}
 
-			_, err := runOpenCodeAndReadResult(ctx, mockOC, "model", "prompt", outputFile)
+			_, err := runAgentAndReadResult(ctx, backend, "model", "prompt", outputFile)
 			require.NoError(t, err)
+			require.Len(t, backend.Calls(), 1)
 
+			opts := backend.Calls()[0].Options
 			if tt.wantNil {
-				assert.Nil(t, capturedStdout)
-				assert.Nil(t, capturedStderr)
+				assert.Nil(t, opts.Stdout)
+				assert.Nil(t, opts.Stderr)
 			} else {
-				assert.Equal(t, os.Stdout, capturedStdout)
-				assert.Equal(t, os.Stderr, capturedStderr)
+				assert.Equal(t, os.Stdout, opts.Stdout)
+				assert.Equal(t, os.Stderr, opts.Stderr)
 			}
 		})
 	}
@@ -619,7 +609,7 @@ func TestGeneratePRSummary(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := &execcontext.Context{}
 			mockOC := tt.setupMock(t)
-			result, err := GeneratePRSummary(ctx, mockOC, tt.projectDesc, tt.projectStatus, tt.baseBranch, tt.commitLog)
+			result, err := GeneratePRSummary(ctx, agent.NewOpenCode(mockOC), tt.projectDesc, tt.projectStatus, tt.baseBranch, tt.commitLog)
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -645,7 +635,7 @@ func TestGeneratePRSummary(t *testing.T) {
 			},
 		}
 		ctx := &execcontext.Context{}
-		_, err := GeneratePRSummary(ctx, mockOC, "My Project", "Beta", "develop", "abc: init\ndef: add\n")
+		_, err := GeneratePRSummary(ctx, agent.NewOpenCode(mockOC), "My Project", "Beta", "develop", "abc: init\ndef: add\n")
 		require.NoError(t, err)
 		assert.Contains(t, capturedPrompt, "My Project")
 		assert.Contains(t, capturedPrompt, "Beta")
@@ -665,7 +655,7 @@ func TestGeneratePRSummary(t *testing.T) {
 				return writeOutputFromPrompt(prompt, "result")
 			},
 		}
-		_, err := GeneratePRSummary(ctx, mockOC, "Test", "Active", "main", "abc\n")
+		_, err := GeneratePRSummary(ctx, agent.NewOpenCode(mockOC), "Test", "Active", "main", "abc\n")
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "Project:")
 		assert.Contains(t, buf.String(), "Test")
@@ -682,7 +672,7 @@ func TestGeneratePRSummary(t *testing.T) {
 				return writeOutputFromPrompt(prompt, "result")
 			},
 		}
-		_, err := GeneratePRSummary(ctx, mockOC, "Test", "Active", "main", "abc\n")
+		_, err := GeneratePRSummary(ctx, agent.NewOpenCode(mockOC), "Test", "Active", "main", "abc\n")
 		require.NoError(t, err)
 		assert.Empty(t, buf.String())
 	})
@@ -740,7 +730,7 @@ func TestGenerateReviewPRBody(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := &execcontext.Context{}
 			mockOC := tt.setupMock(t)
-			result, err := GenerateReviewPRBody(ctx, mockOC, tt.projectName, tt.projectDesc, tt.requirements)
+			result, err := GenerateReviewPRBody(ctx, agent.NewOpenCode(mockOC), tt.projectName, tt.projectDesc, tt.requirements)
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -766,7 +756,7 @@ func TestGenerateReviewPRBody(t *testing.T) {
 			},
 		}
 		ctx := &execcontext.Context{}
-		_, err := GenerateReviewPRBody(ctx, mockOC, "MyProject", "Auth review", []string{"- **security**: JWT", "- **style**: naming"})
+		_, err := GenerateReviewPRBody(ctx, agent.NewOpenCode(mockOC), "MyProject", "Auth review", []string{"- **security**: JWT", "- **style**: naming"})
 		require.NoError(t, err)
 		assert.Contains(t, capturedPrompt, "MyProject")
 		assert.Contains(t, capturedPrompt, "Auth review")
@@ -785,7 +775,7 @@ func TestGenerateReviewPRBody(t *testing.T) {
 				return writeOutputFromPrompt(prompt, "result")
 			},
 		}
-		_, err := GenerateReviewPRBody(ctx, mockOC, "P", "D", []string{"req1"})
+		_, err := GenerateReviewPRBody(ctx, agent.NewOpenCode(mockOC), "P", "D", []string{"req1"})
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "Review Name:")
 		assert.Contains(t, buf.String(), "P")
@@ -802,7 +792,7 @@ func TestGenerateReviewPRBody(t *testing.T) {
 				return writeOutputFromPrompt(prompt, "result")
 			},
 		}
-		_, err := GenerateReviewPRBody(ctx, mockOC, "P", "D", []string{"req1"})
+		_, err := GenerateReviewPRBody(ctx, agent.NewOpenCode(mockOC), "P", "D", []string{"req1"})
 		require.NoError(t, err)
 		assert.Empty(t, buf.String())
 	})
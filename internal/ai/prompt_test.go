@@ -212,6 +212,50 @@ func TestBuildDevelopPrompt(t *testing.T) {
 			},
 			check: func(t *testing.T, prompt string) {
 				assert.NotContains(t, prompt, "**System Notes:**")
+				assert.NotContains(t, prompt, "**Size:**")
+				assert.NotContains(t, prompt, "**Acceptance Notes**")
+			},
+		},
+		{
+			name: "with acceptance notes",
+			data: DevelopPromptData{
+				ProjectContent:      "slug: test",
+				SelectedRequirement: "slug: x\ndescription: X",
+				ProjectFilePath:     "/path",
+				Instructions:        config.DefaultDevelopmentInstructions(),
+				AcceptanceNotes:     "verified via integration test TestX\n",
+			},
+			check: func(t *testing.T, prompt string) {
+				assert.Contains(t, prompt, "**Acceptance Notes**")
+				assert.Contains(t, prompt, "verified via integration test TestX")
+			},
+		},
+		{
+			name: "large requirement",
+			data: DevelopPromptData{
+				ProjectContent:      "slug: test",
+				SelectedRequirement: "slug: x\ndescription: X\nsize: large",
+				ProjectFilePath:     "/path",
+				Instructions:        config.DefaultDevelopmentInstructions(),
+				Size:                "large",
+			},
+			check: func(t *testing.T, prompt string) {
+				assert.Contains(t, prompt, "**Size:** large")
+				assert.Contains(t, prompt, "Split it into small, independently testable steps")
+			},
+		},
+		{
+			name: "medium requirement",
+			data: DevelopPromptData{
+				ProjectContent:      "slug: test",
+				SelectedRequirement: "slug: x\ndescription: X\nsize: medium",
+				ProjectFilePath:     "/path",
+				Instructions:        config.DefaultDevelopmentInstructions(),
+				Size:                "medium",
+			},
+			check: func(t *testing.T, prompt string) {
+				assert.Contains(t, prompt, "**Size:** medium")
+				assert.NotContains(t, prompt, "Split it into small, independently testable steps")
 			},
 		},
 	}
@@ -295,6 +339,23 @@ func TestBuildPickPrompt(t *testing.T) {
 			},
 			check: func(t *testing.T, prompt string) {
 				assert.NotContains(t, prompt, "**System Notes:**")
+				assert.NotContains(t, prompt, "**Blocked Requirements:**")
+			},
+		},
+		{
+			name: "with blocked requirements",
+			data: PickPromptData{
+				ProjectContent: "slug: test",
+				PickedReqPath:  "/path",
+				Blocked: []BlockedRequirement{
+					{Slug: "api", BlockedBy: []string{"schema"}},
+					{Slug: "ui", BlockedBy: []string{"api", "auth"}},
+				},
+			},
+			check: func(t *testing.T, prompt string) {
+				assert.Contains(t, prompt, "**Blocked Requirements:**")
+				assert.Contains(t, prompt, "- `api` is blocked by `schema` (not yet passing)")
+				assert.Contains(t, prompt, "- `ui` is blocked by `api`, `auth` (not yet passing)")
 			},
 		},
 	}
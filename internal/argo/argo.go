@@ -2,10 +2,15 @@ package argo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/zon/ralph/internal/execrun"
 )
 
 type K8sContext struct {
@@ -18,14 +23,93 @@ type Client interface {
 	StopWorkflow(ctx K8sContext, workflowName string) error
 	FollowLogs(ctx K8sContext, workflowName string) error
 	SubmitYAML(ctx context.Context, workflowYAML string, kubeCtx K8sContext) (string, error)
+	LintYAML(ctx context.Context, workflowYAML string) error
+}
+
+// ErrArgoNotInstalled is returned when the argo CLI is not installed on the PATH.
+var ErrArgoNotInstalled = errors.New("argo CLI not found")
+
+// SubmitError is returned when argo submit fails. Error reports the line of argo's output that
+// explains the failure; Output keeps everything argo printed for verbose logging.
+type SubmitError struct {
+	Reason string
+	Output string
+	Err    error
+}
+
+func (e *SubmitError) Error() string {
+	return "failed to submit workflow: " + e.Reason
+}
+
+func (e *SubmitError) Unwrap() error {
+	return e.Err
 }
 
-type client struct{}
+// submitFailures explain the argo submit failures that are common enough to recognise. The
+// first whose pattern appears in a line of the output wins.
+var submitFailures = []struct {
+	pattern string
+	hint    string
+}{
+	{"denied the request", "rejected by an admission webhook"},
+	{"exceeded quota", "the namespace resource quota is used up"},
+	{`no matches for kind "Workflow"`, "the Argo Workflows CRDs are not installed in the cluster"},
+	{"the server could not find the requested resource", "the Argo Workflows CRDs are not installed in the cluster"},
+	{"cannot create resource", "the current kubectl user may not create workflows in this namespace"},
+	{"namespaces \"", "the namespace does not exist"},
+	{"unable to connect to the server", "the cluster is unreachable"},
+	{"connection refused", "the cluster is unreachable"},
+}
+
+// submitFailureReason picks the most relevant line from the output of a failed argo submit.
+// A recognised failure is prefixed with a hint; otherwise the last error line, or the last
+// line, is used as is.
+func submitFailureReason(output string, err error) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = argoLogMessage(strings.TrimSpace(line)); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for _, failure := range submitFailures {
+		for _, line := range lines {
+			if strings.Contains(strings.ToLower(line), strings.ToLower(failure.pattern)) {
+				return failure.hint + ": " + line
+			}
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(lines[i]), "error") {
+			return lines[i]
+		}
+	}
+	if len(lines) > 0 {
+		return lines[len(lines)-1]
+	}
+	return err.Error()
+}
+
+// argoLogMessage returns the msg field of a line argo logged as time="..." level=... msg="...",
+// and any other line unchanged.
+func argoLogMessage(line string) string {
+	_, msg, ok := strings.Cut(line, ` msg="`)
+	if !ok || !strings.HasPrefix(line, "time=") {
+		return line
+	}
+	msg = strings.TrimSuffix(msg, `"`)
+	return strings.ReplaceAll(msg, `\"`, `"`)
+}
+
+// client streams list, stop and logs output to the terminal directly, and runs the commands
+// whose output it parses through runner.
+type client struct {
+	runner execrun.Runner
+}
 
 var _ Client = (*client)(nil)
 
 func NewClient() Client {
-	return &client{}
+	return &client{runner: execrun.Exec{}}
 }
 
 func (c *client) ListWorkflows(ctx K8sContext) error {
@@ -78,8 +162,8 @@ func (c *client) FollowLogs(ctx K8sContext, workflowName string) error {
 }
 
 func (c *client) SubmitYAML(ctx context.Context, workflowYAML string, kubeCtx K8sContext) (string, error) {
-	if _, err := exec.LookPath("argo"); err != nil {
-		return "", fmt.Errorf("argo CLI not found - please install Argo CLI to use remote execution: https://github.com/argoproj/argo-workflows/releases")
+	if err := c.runner.LookPath("argo"); err != nil {
+		return "", fmt.Errorf("%w - please install Argo CLI to use remote execution: https://github.com/argoproj/argo-workflows/releases", ErrArgoNotInstalled)
 	}
 
 	args := []string{"submit", "-", "-n", kubeCtx.Namespace}
@@ -87,17 +171,15 @@ func (c *client) SubmitYAML(ctx context.Context, workflowYAML string, kubeCtx K8
 		args = append(args, "--context", kubeCtx.Name)
 	}
 
-	cmd := exec.CommandContext(ctx, "argo", args...)
-	cmd.Stdin = strings.NewReader(workflowYAML)
-
-	output, err := cmd.CombinedOutput()
+	stdout, stderr, err := c.runner.RunInput(ctx, workflowYAML, "argo", args...)
+	output := stdout + stderr
 	if err != nil {
-		return "", fmt.Errorf("failed to submit workflow: %w\nOutput: %s", err, string(output))
+		return "", &SubmitError{Reason: submitFailureReason(output, err), Output: strings.TrimSpace(output), Err: err}
 	}
 
-	workflowName := extractWorkflowName(string(output))
+	workflowName := extractWorkflowName(output)
 	if workflowName == "" {
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		lines := strings.Split(strings.TrimSpace(output), "\n")
 		if len(lines) > 0 {
 			workflowName = strings.TrimSpace(lines[0])
 		}
@@ -105,6 +187,91 @@ func (c *client) SubmitYAML(ctx context.Context, workflowYAML string, kubeCtx K8
 	return workflowName, nil
 }
 
+// submitBackoff is the wait before the second submit attempt; it doubles after each further failure
+var submitBackoff = 2 * time.Second
+
+// transientSubmitFailures appear in argo submit output when the API server turned the request
+// away before creating anything: the connection or TLS handshake failed, or the server answered
+// 503 or 429. Timeouts and dropped connections are left out because the workflow may have been
+// created anyway, and a retry would submit it twice.
+var transientSubmitFailures = []string{
+	"service unavailable",
+	"the server is currently unable to handle the request",
+	"too many requests",
+	"tls handshake timeout",
+	"connection refused",
+}
+
+// IsTransientSubmitError reports whether err is an argo submit failure worth retrying. Admission
+// webhook denials and other validation errors are never transient, even when the webhook's
+// message mentions one of the transient failures.
+func IsTransientSubmitError(err error) bool {
+	var submitErr *SubmitError
+	if !errors.As(err, &submitErr) {
+		return false
+	}
+	output := strings.ToLower(submitErr.Output)
+	if strings.Contains(output, "denied the request") {
+		return false
+	}
+	for _, failure := range transientSubmitFailures {
+		if strings.Contains(output, failure) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitWithRetry submits workflowYAML, trying up to attempts times with a doubling backoff while
+// the submission fails with a transient error. Any other error is returned at once.
+func SubmitWithRetry(ctx context.Context, client Client, workflowYAML string, kubeCtx K8sContext, attempts int) (string, error) {
+	wait := submitBackoff
+	for attempt := 1; ; attempt++ {
+		name, err := client.SubmitYAML(ctx, workflowYAML, kubeCtx)
+		if err == nil || !IsTransientSubmitError(err) {
+			return name, err
+		}
+		if attempt >= attempts {
+			if attempt > 1 {
+				return "", fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return "", err
+		}
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// LintYAML checks workflowYAML with `argo lint --offline`, which validates it against the
+// Argo schema without contacting a cluster. It returns ErrArgoNotInstalled when argo is not installed.
+func (c *client) LintYAML(ctx context.Context, workflowYAML string) error {
+	if err := c.runner.LookPath("argo"); err != nil {
+		return ErrArgoNotInstalled
+	}
+
+	// argo lint takes file paths, so the workflow is written to a temporary file
+	dir, err := os.MkdirTemp("", "ralph-lint-")
+	if err != nil {
+		return fmt.Errorf("failed to create lint directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "workflow.yaml")
+	if err := os.WriteFile(path, []byte(workflowYAML), 0644); err != nil {
+		return fmt.Errorf("failed to write workflow for lint: %w", err)
+	}
+
+	stdout, stderr, err := c.runner.Run(ctx, "argo", "lint", "--offline", path)
+	if err != nil {
+		return fmt.Errorf("argo lint failed: %w\nOutput: %s", err, strings.TrimSpace(stdout+stderr))
+	}
+	return nil
+}
+
 func extractWorkflowName(output string) string {
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
@@ -1,9 +1,16 @@
 package argo
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/execrun"
 )
 
 func TestNewClient(t *testing.T) {
@@ -54,3 +61,322 @@ func TestExtractWorkflowName(t *testing.T) {
 		})
 	}
 }
+
+// fakeArgo puts an argo script that runs body on an otherwise empty PATH, so body
+// may only use shell builtins. $dir in body refers to the script's directory.
+func fakeArgo(t *testing.T, body string) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\ndir=" + dir + "\n" + body + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "argo"), []byte(script), 0755))
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestLintYAML(t *testing.T) {
+	t.Run("runs argo lint offline on the workflow", func(t *testing.T) {
+		dir := fakeArgo(t, `echo "$@" > "$dir/args"; while IFS= read -r line; do echo "$line"; done < "$3" > "$dir/workflow"`)
+
+		err := NewClient().LintYAML(context.Background(), "kind: Workflow\n")
+		require.NoError(t, err)
+
+		args, err := os.ReadFile(filepath.Join(dir, "args"))
+		require.NoError(t, err)
+		assert.Contains(t, string(args), "lint --offline")
+		workflow, err := os.ReadFile(filepath.Join(dir, "workflow"))
+		require.NoError(t, err)
+		assert.Equal(t, "kind: Workflow\n", string(workflow))
+	})
+
+	t.Run("returns lint output on failure", func(t *testing.T) {
+		fakeArgo(t, `echo "spec.templates: required"; exit 1`)
+
+		err := NewClient().LintYAML(context.Background(), "kind: Workflow\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.templates: required")
+	})
+
+	t.Run("returns ErrArgoNotInstalled without argo", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+
+		err := NewClient().LintYAML(context.Background(), "kind: Workflow\n")
+		assert.ErrorIs(t, err, ErrArgoNotInstalled)
+	})
+}
+
+func TestSubmitYAML_ArgoNotFound(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := NewClient().SubmitYAML(context.Background(), "kind: Workflow\n", K8sContext{Namespace: "default"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrArgoNotInstalled)
+	assert.Contains(t, err.Error(), "please install Argo CLI")
+}
+
+func TestSubmitYAML_Arguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		kubeCtx  K8sContext
+		wantArgs []string
+	}{
+		{
+			name:     "namespace only",
+			kubeCtx:  K8sContext{Namespace: "argo"},
+			wantArgs: []string{"submit", "-", "-n", "argo"},
+		},
+		{
+			name:     "namespace and context",
+			kubeCtx:  K8sContext{Name: "prod", Namespace: "team-ns"},
+			wantArgs: []string{"submit", "-", "-n", "team-ns", "--context", "prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &execrun.MockRunner{
+				RunFunc: func(call execrun.Call) (string, string, error) {
+					return "Name: ralph-abc123\nStatus: Pending\n", "", nil
+				},
+			}
+			c := &client{runner: runner}
+
+			name, err := c.SubmitYAML(context.Background(), "kind: Workflow\n", tt.kubeCtx)
+			require.NoError(t, err)
+			assert.Equal(t, "ralph-abc123", name)
+
+			calls := runner.Calls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, "argo", calls[0].Name)
+			assert.Equal(t, tt.wantArgs, calls[0].Args)
+			assert.Equal(t, "kind: Workflow\n", calls[0].Input)
+		})
+	}
+}
+
+func TestSubmitYAML_FailureIncludesOutput(t *testing.T) {
+	runner := &execrun.MockRunner{
+		RunFunc: func(call execrun.Call) (string, string, error) {
+			return "", "namespace not found", errors.New("exit status 1")
+		},
+	}
+	c := &client{runner: runner}
+
+	_, err := c.SubmitYAML(context.Background(), "kind: Workflow\n", K8sContext{Namespace: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace not found")
+}
+
+func TestSubmitYAML_FailureIsSubmitError(t *testing.T) {
+	errExit := errors.New("exit status 1")
+	output := "time=\"2026-01-02T03:04:05Z\" level=info msg=\"connecting\"\n" +
+		"time=\"2026-01-02T03:04:06Z\" level=fatal msg=\"Failed to submit workflow: rpc error: code = Unknown desc = workflows.argoproj.io \\\"ralph-\\\" is forbidden: exceeded quota: compute, requested: pods=1, used: pods=10, limited: pods=10\"\n"
+	runner := &execrun.MockRunner{
+		RunFunc: func(call execrun.Call) (string, string, error) {
+			return "", output, errExit
+		},
+	}
+	c := &client{runner: runner}
+
+	_, err := c.SubmitYAML(context.Background(), "kind: Workflow\n", K8sContext{Namespace: "argo"})
+	var submitErr *SubmitError
+	require.ErrorAs(t, err, &submitErr)
+	assert.ErrorIs(t, err, errExit)
+	assert.Equal(t, strings.TrimSpace(output), submitErr.Output)
+	assert.Equal(t, `failed to submit workflow: the namespace resource quota is used up: Failed to submit workflow: rpc error: code = Unknown desc = workflows.argoproj.io "ralph-" is forbidden: exceeded quota: compute, requested: pods=1, used: pods=10, limited: pods=10`, err.Error())
+	assert.NotContains(t, err.Error(), "connecting")
+}
+
+func TestSubmitFailureReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name: "admission webhook denial",
+			output: "Warning: the workflow uses a deprecated field\n" +
+				`time="2026-01-02T03:04:05Z" level=fatal msg="Failed to submit workflow: admission webhook \"validate.kyverno.svc-fail\" denied the request: image registry is not allowed"`,
+			want: `rejected by an admission webhook: Failed to submit workflow: admission webhook "validate.kyverno.svc-fail" denied the request: image registry is not allowed`,
+		},
+		{
+			name:   "quota exceeded",
+			output: `Error from server (Forbidden): workflows.argoproj.io "ralph-abc" is forbidden: exceeded quota: argo-quota, requested: count/workflows.argoproj.io=1`,
+			want:   `the namespace resource quota is used up: Error from server (Forbidden): workflows.argoproj.io "ralph-abc" is forbidden: exceeded quota: argo-quota, requested: count/workflows.argoproj.io=1`,
+		},
+		{
+			name:   "CRD missing",
+			output: `time="2026-01-02T03:04:05Z" level=fatal msg="Failed to submit workflow: the server could not find the requested resource (post workflows.argoproj.io)"`,
+			want:   "the Argo Workflows CRDs are not installed in the cluster: Failed to submit workflow: the server could not find the requested resource (post workflows.argoproj.io)",
+		},
+		{
+			name:   "RBAC forbidden",
+			output: `Error from server (Forbidden): workflows.argoproj.io is forbidden: User "dev" cannot create resource "workflows" in API group "argoproj.io" in the namespace "argo"`,
+			want:   `the current kubectl user may not create workflows in this namespace: Error from server (Forbidden): workflows.argoproj.io is forbidden: User "dev" cannot create resource "workflows" in API group "argoproj.io" in the namespace "argo"`,
+		},
+		{
+			name:   "namespace missing",
+			output: `time="2026-01-02T03:04:05Z" level=fatal msg="Failed to submit workflow: namespaces \"missing\" not found"`,
+			want:   `the namespace does not exist: Failed to submit workflow: namespaces "missing" not found`,
+		},
+		{
+			name:   "cluster unreachable",
+			output: "E0102 03:04:05.000000 memcache.go:265] couldn't get current server API group list\nUnable to connect to the server: dial tcp 10.0.0.1:6443: i/o timeout",
+			want:   "the cluster is unreachable: Unable to connect to the server: dial tcp 10.0.0.1:6443: i/o timeout",
+		},
+		{
+			name:   "unrecognised error line",
+			output: "Submitting workflow\nError: something went wrong\nsee the docs",
+			want:   "Error: something went wrong",
+		},
+		{
+			name:   "no error line",
+			output: "\nfirst\nlast\n",
+			want:   "last",
+		},
+		{
+			name: "no output",
+			want: "exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, submitFailureReason(tt.output, errors.New("exit status 1")))
+		})
+	}
+}
+
+func TestSubmitYAML_MissingArgoRunsNothing(t *testing.T) {
+	runner := &execrun.MockRunner{Missing: []string{"argo"}}
+	c := &client{runner: runner}
+
+	_, err := c.SubmitYAML(context.Background(), "kind: Workflow\n", K8sContext{Namespace: "argo"})
+	assert.ErrorIs(t, err, ErrArgoNotInstalled)
+	assert.Empty(t, runner.Calls())
+}
+
+func noSubmitBackoff(t *testing.T) {
+	t.Helper()
+	backoff := submitBackoff
+	submitBackoff = 0
+	t.Cleanup(func() { submitBackoff = backoff })
+}
+
+func TestSubmitWithRetry(t *testing.T) {
+	const unavailable = `Error from server (ServiceUnavailable): the server is currently unable to handle the request (post workflows.argoproj.io)`
+	const refused = `The connection to the server 10.0.0.1:6443 was refused - did you specify the right host or port?: dial tcp 10.0.0.1:6443: connect: connection refused`
+	const webhookTimeout = `Error from server (InternalError): Internal error occurred: failed calling webhook "validate.kyverno.svc": context deadline exceeded`
+	const denied = `Error from server: admission webhook "validate.kyverno.svc" denied the request: image registry is not allowed`
+
+	tests := []struct {
+		name      string
+		failures  []string
+		attempts  int
+		wantName  string
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "succeeds after two transient failures",
+			failures:  []string{unavailable, refused},
+			attempts:  3,
+			wantName:  "ralph-abc12",
+			wantCalls: 3,
+		},
+		{
+			name:      "validation error is not retried",
+			failures:  []string{denied},
+			attempts:  3,
+			wantCalls: 1,
+			wantErr:   "rejected by an admission webhook",
+		},
+		{
+			name:      "timeout is not retried since the workflow may exist",
+			failures:  []string{webhookTimeout},
+			attempts:  3,
+			wantCalls: 1,
+			wantErr:   "context deadline exceeded",
+		},
+		{
+			name:      "gives up after the configured attempts",
+			failures:  []string{unavailable, unavailable, unavailable},
+			attempts:  2,
+			wantCalls: 2,
+			wantErr:   "(after 2 attempts)",
+		},
+		{
+			name:      "single attempt never retries",
+			failures:  []string{unavailable},
+			attempts:  1,
+			wantCalls: 1,
+			wantErr:   "the server is currently unable to handle the request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noSubmitBackoff(t)
+			calls := 0
+			runner := &execrun.MockRunner{
+				RunFunc: func(call execrun.Call) (string, string, error) {
+					calls++
+					if calls <= len(tt.failures) {
+						return "", tt.failures[calls-1], errors.New("exit status 1")
+					}
+					return "Name:                ralph-abc12\nNamespace:           argo\n", "", nil
+				},
+			}
+
+			name, err := SubmitWithRetry(context.Background(), &client{runner: runner}, "kind: Workflow\n", K8sContext{Namespace: "argo"}, tt.attempts)
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				var submitErr *SubmitError
+				assert.ErrorAs(t, err, &submitErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestSubmitWithRetry_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &MockClient{
+		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx K8sContext) (string, error) {
+			return "", &SubmitError{Reason: "connection refused", Output: "dial tcp: connection refused", Err: errors.New("exit status 1")}
+		},
+	}
+
+	_, err := SubmitWithRetry(ctx, client, "kind: Workflow\n", K8sContext{Namespace: "argo"}, 3)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestIsTransientSubmitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "service unavailable", err: &SubmitError{Output: "Error from server (ServiceUnavailable): service unavailable"}, want: true},
+		{name: "rate limited", err: &SubmitError{Output: "Error from server (TooManyRequests): Too Many Requests"}, want: true},
+		{name: "tls handshake timeout", err: &SubmitError{Output: "Unable to connect to the server: net/http: TLS handshake timeout"}, want: true},
+		{name: "connection refused", err: &SubmitError{Output: "dial tcp 10.0.0.1:6443: connect: connection refused"}, want: true},
+		{name: "etcd timeout", err: &SubmitError{Output: "rpc error: etcdserver: request timed out"}},
+		{name: "connection reset", err: &SubmitError{Output: "read tcp 10.0.0.2:51234->10.0.0.1:6443: read: connection reset by peer"}},
+		{name: "i/o timeout", err: &SubmitError{Output: "dial tcp 10.0.0.1:6443: i/o timeout"}},
+		{name: "webhook call timeout", err: &SubmitError{Output: `Internal error occurred: failed calling webhook "x": context deadline exceeded`}},
+		{name: "webhook denial mentioning a timeout", err: &SubmitError{Output: `admission webhook "x" denied the request: context deadline exceeded`}},
+		{name: "quota exceeded", err: &SubmitError{Output: "is forbidden: exceeded quota: argo-quota"}},
+		{name: "not a submit error", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransientSubmitError(tt.err))
+		})
+	}
+}
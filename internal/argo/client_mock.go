@@ -7,11 +7,13 @@ type MockClient struct {
 	StopWorkflowFunc  func(ctx K8sContext, workflowName string) error
 	FollowLogsFunc    func(ctx K8sContext, workflowName string) error
 	SubmitYAMLFunc    func(ctx context.Context, workflowYAML string, kubeCtx K8sContext) (string, error)
+	LintYAMLFunc      func(ctx context.Context, workflowYAML string) error
 
 	ListWorkflowsCalled bool
 	StopWorkflowCalled  bool
 	FollowLogsCalled    bool
 	SubmitYAMLCalled    bool
+	LintYAMLCalled      bool
 }
 
 func (m *MockClient) ListWorkflows(ctx K8sContext) error {
@@ -46,4 +48,12 @@ func (m *MockClient) SubmitYAML(ctx context.Context, workflowYAML string, kubeCt
 	return "", nil
 }
 
+func (m *MockClient) LintYAML(ctx context.Context, workflowYAML string) error {
+	m.LintYAMLCalled = true
+	if m.LintYAMLFunc != nil {
+		return m.LintYAMLFunc(ctx, workflowYAML)
+	}
+	return nil
+}
+
 var _ Client = (*MockClient)(nil)
@@ -1,43 +1,179 @@
 package cleanup
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/zon/ralph/internal/output"
 )
 
+// DefaultHandlerTimeout is how long Cleanup waits for a single handler before moving on
+const DefaultHandlerTimeout = 30 * time.Second
+
+// DefaultForceExitWindow is how soon a repeated signal must follow the previous one to skip cleanup
+const DefaultForceExitWindow = 3 * time.Second
+
+// HandlerTimeoutEnv overrides the per-handler cleanup timeout (e.g. "10s"; "0" waits forever)
+const HandlerTimeoutEnv = "RALPH_CLEANUP_TIMEOUT"
+
+type handler struct {
+	name string
+	fn   func()
+}
+
 type Manager struct {
-	mu        sync.Mutex
-	cleanupFn []func()
-	exitFn    func(int)
-	out       *output.Client
+	mu              sync.Mutex
+	once            *sync.Once
+	handlers        []handler
+	timeout         time.Duration
+	forceExitWindow time.Duration
+	exitFn          func(int)
+	out             *output.Client
+	ctx             context.Context
+	cancel          context.CancelFunc
 }
 
 func NewManager(out *output.Client) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
-		cleanupFn: make([]func(), 0),
-		exitFn:    os.Exit,
-		out:       out,
+		ctx:             ctx,
+		cancel:          cancel,
+		once:            &sync.Once{},
+		handlers:        make([]handler, 0),
+		timeout:         DefaultHandlerTimeout,
+		forceExitWindow: DefaultForceExitWindow,
+		exitFn:          os.Exit,
+		out:             out,
+	}
+}
+
+// HandlerTimeoutFromEnv returns the timeout set by RALPH_CLEANUP_TIMEOUT, or DefaultHandlerTimeout
+// when it is unset or invalid
+func HandlerTimeoutFromEnv() time.Duration {
+	val := os.Getenv(HandlerTimeoutEnv)
+	if val == "" {
+		return DefaultHandlerTimeout
+	}
+	timeout, err := time.ParseDuration(val)
+	if err != nil || timeout < 0 {
+		return DefaultHandlerTimeout
 	}
+	return timeout
 }
 
+// SetHandlerTimeout sets how long Cleanup waits for each handler; zero disables the timeout
+func (m *Manager) SetHandlerTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeout = timeout
+}
+
+// SetForceExitWindow sets how soon a second signal must arrive to exit without waiting for cleanup
+func (m *Manager) SetForceExitWindow(window time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forceExitWindow = window
+}
+
+// RegisterCleanup registers an unnamed cleanup function
 func (m *Manager) RegisterCleanup(fn func()) {
+	m.RegisterNamedCleanup("", fn)
+}
+
+// RegisterNamedCleanup registers a cleanup function with a description shown by List
+func (m *Manager) RegisterNamedCleanup(name string, fn func()) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.cleanupFn = append(m.cleanupFn, fn)
+	if name == "" {
+		name = fmt.Sprintf("cleanup #%d", len(m.handlers)+1)
+	}
+	m.handlers = append(m.handlers, handler{name: name, fn: fn})
 }
 
-func (m *Manager) Cleanup() {
+// List returns the descriptions of registered handlers in the order Cleanup runs them
+func (m *Manager) List() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return m.listLocked()
+}
+
+func (m *Manager) listLocked() []string {
+	names := make([]string, 0, len(m.handlers))
+	for i := len(m.handlers) - 1; i >= 0; i-- {
+		names = append(names, m.handlers[i].name)
+	}
+	return names
+}
+
+// Cleanup runs the registered handlers in reverse registration order. Each handler runs at most
+// once: concurrent or repeated calls wait for the run in progress and then return.
+func (m *Manager) Cleanup() {
+	m.mu.Lock()
+	once := m.once
+	m.mu.Unlock()
+
+	once.Do(m.runHandlers)
+}
+
+// runHandlers takes the registered handlers under the lock and runs them without it, so a
+// handler may register cleanups or call List without deadlocking.
+func (m *Manager) runHandlers() {
+	m.mu.Lock()
+	handlers := m.handlers
+	names := m.listLocked()
+	timeout := m.timeout
+	m.handlers = nil
+	m.mu.Unlock()
+	// Handlers registered after this run belong to the next one
+	defer func() {
+		m.mu.Lock()
+		m.once = &sync.Once{}
+		m.mu.Unlock()
+	}()
 
-	for i := len(m.cleanupFn) - 1; i >= 0; i-- {
-		m.cleanupFn[i]()
+	if m.out != nil && len(handlers) > 0 {
+		m.out.Debugf("Running %d cleanup handlers: %s", len(handlers), strings.Join(names, ", "))
+	}
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if m.out != nil {
+			m.out.Debugf("Running cleanup: %s", handlers[i].name)
+		}
+		m.run(handlers[i], timeout)
 	}
-	m.cleanupFn = nil
+}
+
+// run calls a handler and gives up waiting on it once the timeout elapses
+func (m *Manager) run(h handler, timeout time.Duration) {
+	if timeout <= 0 {
+		h.fn()
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		if m.out != nil {
+			m.out.Warnf("Cleanup %s did not finish within %s, skipping", h.name, timeout)
+		}
+	}
+}
+
+// Context returns a context that is cancelled when the first signal arrives, before cleanup runs,
+// so work started under it (such as git processes) is interrupted.
+func (m *Manager) Context() context.Context {
+	return m.ctx
 }
 
 func (m *Manager) SetupSignalHandlers() {
@@ -47,10 +183,38 @@ func (m *Manager) SetupSignalHandlers() {
 	go m.handleSignal(sigChan)
 }
 
+// handleSignal runs cleanup on the first signal and exits once it finishes. A second signal
+// arriving within the force exit window of the previous one exits immediately instead.
 func (m *Manager) handleSignal(sigChan <-chan os.Signal) {
 	sig := <-sigChan
+	m.cancel()
 	m.out.Infof("Received signal: %v", sig)
 	m.out.Info("Cleaning up...")
-	m.Cleanup()
-	m.exitFn(0)
+
+	m.mu.Lock()
+	window := m.forceExitWindow
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Cleanup()
+	}()
+
+	last := time.Now()
+	for {
+		select {
+		case <-done:
+			m.exitFn(0)
+			return
+		case sig := <-sigChan:
+			if time.Since(last) <= window {
+				m.out.Warnf("Received signal: %v again, exiting without waiting for cleanup", sig)
+				m.exitFn(1)
+				return
+			}
+			last = time.Now()
+			m.out.Infof("Cleanup in progress, send %v again within %s to exit immediately", sig, window)
+		}
+	}
 }
@@ -1,6 +1,7 @@
 package cleanup
 
 import (
+	"bytes"
 	"os"
 	"os/signal"
 	"sync"
@@ -127,3 +128,221 @@ func TestCleanupIsCalledOnce(t *testing.T) {
 
 	assert.Equal(t, 1, callCount)
 }
+
+func TestSignalCancelsContext(t *testing.T) {
+	m := NewManager(output.NewClient(os.Stdout, os.Stderr, false))
+	m.exitFn = func(code int) {}
+
+	assert.NoError(t, m.Context().Err())
+
+	sigChan := make(chan os.Signal, 1)
+	go m.handleSignal(sigChan)
+	sigChan <- syscall.SIGTERM
+
+	select {
+	case <-m.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled by the signal")
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(m *Manager)
+		want     []string
+	}{
+		{
+			name:     "empty manager",
+			register: func(m *Manager) {},
+			want:     []string{},
+		},
+		{
+			name: "named handlers in execution order",
+			register: func(m *Manager) {
+				m.RegisterNamedCleanup("stop services", func() {})
+				m.RegisterNamedCleanup("remove worktree", func() {})
+				m.RegisterNamedCleanup("close log", func() {})
+			},
+			want: []string{"close log", "remove worktree", "stop services"},
+		},
+		{
+			name: "unnamed handlers get a default description",
+			register: func(m *Manager) {
+				m.RegisterCleanup(func() {})
+				m.RegisterNamedCleanup("stop services", func() {})
+			},
+			want: []string{"stop services", "cleanup #1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(nil)
+			tt.register(m)
+			assert.Equal(t, tt.want, m.List())
+		})
+	}
+}
+
+func TestListMatchesExecutionOrder(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewManager(output.NewClient(&buf, &buf, true))
+
+	var ran []string
+	for _, name := range []string{"first", "second", "third"} {
+		m.RegisterNamedCleanup(name, func() { ran = append(ran, name) })
+	}
+
+	listed := m.List()
+	m.Cleanup()
+
+	assert.Equal(t, listed, ran)
+	assert.Contains(t, buf.String(), "Running 3 cleanup handlers: third, second, first")
+	assert.Empty(t, m.List(), "handlers should be cleared after cleanup")
+}
+
+func TestCleanupHandlerTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewManager(output.NewClient(&buf, &buf, false))
+	m.SetHandlerTimeout(50 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var ran []string
+	m.RegisterNamedCleanup("after", func() { ran = append(ran, "after") })
+	m.RegisterNamedCleanup("blocking", func() { <-release })
+	m.RegisterNamedCleanup("before", func() { ran = append(ran, "before") })
+
+	start := time.Now()
+	m.Cleanup()
+
+	assert.Less(t, time.Since(start), time.Second, "cleanup should not wait on a blocked handler")
+	assert.Equal(t, []string{"before", "after"}, ran, "handlers after the blocked one should still run")
+	assert.Contains(t, buf.String(), "Cleanup blocking did not finish within 50ms, skipping")
+}
+
+func TestHandlerTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		want time.Duration
+	}{
+		{name: "unset", val: "", want: DefaultHandlerTimeout},
+		{name: "duration", val: "5s", want: 5 * time.Second},
+		{name: "disabled", val: "0", want: 0},
+		{name: "invalid", val: "soon", want: DefaultHandlerTimeout},
+		{name: "negative", val: "-1s", want: DefaultHandlerTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(HandlerTimeoutEnv, tt.val)
+			assert.Equal(t, tt.want, HandlerTimeoutFromEnv())
+		})
+	}
+}
+
+func TestCleanupConcurrentCallsRunHandlersOnce(t *testing.T) {
+	m := NewManager(nil)
+
+	var mu sync.Mutex
+	count := 0
+	m.RegisterNamedCleanup("count", func() {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Cleanup()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, count, "handlers should run exactly once")
+}
+
+func TestHandleSignalForceExit(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   time.Duration
+		wantCode int
+	}{
+		{name: "second signal within window exits immediately", window: time.Minute, wantCode: 1},
+		{name: "second signal after window waits for cleanup", window: 0, wantCode: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			m := NewManager(output.NewClient(&buf, &buf, false))
+			m.SetForceExitWindow(tt.window)
+
+			exitCodes := make(chan int, 1)
+			m.exitFn = func(code int) { exitCodes <- code }
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			m.RegisterNamedCleanup("blocking", func() {
+				close(started)
+				<-release
+			})
+
+			sigChan := make(chan os.Signal, 1)
+			handled := make(chan struct{})
+			go func() {
+				defer close(handled)
+				m.handleSignal(sigChan)
+			}()
+
+			sigChan <- os.Interrupt
+			<-started
+			sigChan <- os.Interrupt
+
+			if tt.wantCode == 1 {
+				assert.Equal(t, 1, <-exitCodes)
+				<-handled
+				close(release)
+				return
+			}
+
+			assert.Eventually(t, func() bool { return len(sigChan) == 0 }, time.Second, 10*time.Millisecond)
+			close(release)
+			assert.Equal(t, 0, <-exitCodes)
+			<-handled
+		})
+	}
+}
+
+func TestCleanupHandlerCanUseManager(t *testing.T) {
+	m := NewManager(nil)
+
+	var listed []string
+	ran := false
+	m.RegisterNamedCleanup("outer", func() {
+		listed = m.List()
+		m.RegisterNamedCleanup("late", func() { ran = true })
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Cleanup()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a handler using the manager deadlocked cleanup")
+	}
+
+	assert.Empty(t, listed, "running handlers are no longer registered")
+	assert.False(t, ran, "a handler registered during cleanup waits for the next run")
+	m.Cleanup()
+	assert.True(t, ran)
+}
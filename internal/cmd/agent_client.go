@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/ai"
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
@@ -15,12 +16,21 @@ import (
 )
 
 type AgentClient struct {
-	ctx *context.Context
-	oc  opencode.OCClient
+	ctx     *context.Context
+	backend agent.Backend
 }
 
-func NewAgentClient(ctx *context.Context, oc opencode.OCClient) *AgentClient {
-	return &AgentClient{ctx: ctx, oc: oc}
+func NewAgentClient(ctx *context.Context, backend agent.Backend) *AgentClient {
+	return &AgentClient{ctx: ctx, backend: backend}
+}
+
+// newAgentBackend returns the agent backend named by the agent field of .ralph/config.yaml.
+func newAgentBackend() (agent.Backend, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return agent.ForName(cfg.Agent)
 }
 
 func (a *AgentClient) RunPicker(proj *project.Project) (string, error) {
@@ -42,7 +52,7 @@ func (a *AgentClient) RunPicker(proj *project.Project) (string, error) {
 		PickedReqPath: pickedReqPath,
 	}
 
-	return project.PickRequirement(a.ctx, a.oc, setup)
+	return project.PickRequirement(a.ctx, a.backend, setup)
 }
 
 func (a *AgentClient) RunDeveloper(proj *project.Project, req string) error {
@@ -62,7 +72,17 @@ func (a *AgentClient) RunDeveloper(proj *project.Project, req string) error {
 		Config:    cfg,
 	}
 
-	return project.DevelopRequirement(a.ctx, a.oc, setup, req)
+	return project.DevelopRequirement(a.ctx, a.backend, setup, req)
+}
+
+// AddNote records a system note for the next picker and developer prompts.
+func (a *AgentClient) AddNote(note string) {
+	a.ctx.AddNote(note)
+}
+
+// ClearNotes drops the system notes once the prompts they were meant for have run.
+func (a *AgentClient) ClearNotes() {
+	a.ctx.ClearNotes()
 }
 
 func (a *AgentClient) IsFatal(err error) bool {
@@ -70,7 +90,7 @@ func (a *AgentClient) IsFatal(err error) bool {
 }
 
 func (a *AgentClient) GenerateChangelog(proj *project.Project) error {
-	return ai.GenerateChangelog(a.ctx, a.oc)
+	return ai.GenerateChangelog(a.ctx, a.backend)
 }
 
 func (a *AgentClient) FixServiceStartup(cfg *config.RalphConfig, err error) error {
@@ -80,7 +100,7 @@ func (a *AgentClient) FixServiceStartup(cfg *config.RalphConfig, err error) erro
 		if buildErr != nil {
 			return buildErr
 		}
-		return ai.RunAgent(a.ctx, a.oc, fixPrompt)
+		return ai.RunAgent(a.ctx, a.backend, fixPrompt)
 	}
 	return nil
 }
@@ -97,7 +117,7 @@ func (a *AgentClient) WriteOrchestration(input *project.InputFile) error {
 		a.ctx.Output().Debug(prompt)
 	}
 
-	return ai.RunAgent(a.ctx, a.oc, prompt)
+	return ai.RunAgent(a.ctx, a.backend, prompt)
 }
 
 func (a *AgentClient) WriteProject(input *project.InputFile) (*project.Project, error) {
@@ -122,7 +142,7 @@ func (a *AgentClient) WriteProject(input *project.InputFile) (*project.Project,
 		a.ctx.Output().Debug(prompt)
 	}
 
-	if err := ai.RunAgent(a.ctx, a.oc, prompt); err != nil {
+	if err := ai.RunAgent(a.ctx, a.backend, prompt); err != nil {
 		return nil, err
 	}
 
@@ -146,8 +166,7 @@ func findNewestProject() (*project.Project, error) {
 		if e.IsDir() {
 			continue
 		}
-		ext := filepath.Ext(e.Name())
-		if ext != ".yaml" && ext != ".yml" {
+		if !project.IsProjectFile(e.Name()) {
 			continue
 		}
 		info, err := e.Info()
@@ -174,7 +193,11 @@ func findNewestProject() (*project.Project, error) {
 }
 
 func (a *AgentClient) PrintStats() {
-	stats, err := a.oc.GetStats()
+	oc, ok := a.backend.(*agent.OpenCode)
+	if !ok {
+		return
+	}
+	stats, err := oc.Stats()
 	if err != nil {
 		return
 	}
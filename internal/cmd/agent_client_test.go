@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/agent"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/opencode"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
@@ -23,7 +25,7 @@ import (
 
 func TestAgentClientIsFatal(t *testing.T) {
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, &opencode.MockOC{})
+	client := NewAgentClient(ctx, &agent.MockBackend{})
 
 	t.Run("returns false for nil error", func(t *testing.T) {
 		assert.False(t, client.IsFatal(nil))
@@ -89,7 +91,7 @@ requirements:
 	ctx.SetProjectFile("test-project.yaml")
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			// Simulate mock agent writing picked-requirement.yaml for pick prompts
 			if strings.Contains(strings.ToLower(prompt), "picked-requirement") {
 				pickedReqPath := filepath.Join(filepath.Dir(ctx.ProjectFile()), "picked-requirement.yaml")
@@ -117,7 +119,7 @@ requirements:
 			return nil
 		},
 	}
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	proj := &project.Project{Slug: "test-project"}
 	req, err := client.RunPicker(proj)
@@ -128,9 +130,62 @@ requirements:
 	require.NoError(t, err)
 }
 
+func TestAgentClientRunsBuiltPromptsOnBackend(t *testing.T) {
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+
+	testutil.InitGitRepo(t, workDir)
+	testutil.MakeInitialCommit(t, workDir)
+	testutil.CreateRalphConfig(t, workDir)
+
+	projectYAML := `slug: test-project
+title: Test project
+requirements:
+  - slug: req-1
+    description: Test requirement
+    items:
+      - Item 1
+    passing: false
+`
+	require.NoError(t, os.WriteFile("test-project.yaml", []byte(projectYAML), 0644))
+
+	ctx := execcontext.NewContext()
+	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+	ctx.SetProjectFile("test-project.yaml")
+	ctx.SetModel("fake-model")
+
+	pickedReqPath := filepath.Join(workDir, "picked-requirement.yaml")
+	picked := "slug: req-1\ndescription: Test requirement\n"
+	backend := &agent.MockBackend{
+		RunFunc: func(call agent.Call) (string, error) {
+			if strings.Contains(call.Prompt, pickedReqPath) {
+				return "", os.WriteFile(pickedReqPath, []byte(picked), 0644)
+			}
+			return "", nil
+		},
+	}
+	client := NewAgentClient(ctx, backend)
+
+	proj, err := project.LoadProject(filepath.Join(workDir, "test-project.yaml"))
+	require.NoError(t, err)
+	req, err := client.RunPicker(proj)
+	require.NoError(t, err)
+	require.Equal(t, picked, req)
+	require.NoError(t, client.RunDeveloper(proj, req))
+
+	calls := backend.Calls()
+	require.Len(t, calls, 2)
+	assert.Contains(t, calls[0].Prompt, pickedReqPath, "picker should be asked to write the picked requirement")
+	assert.Contains(t, calls[0].Prompt, "Test requirement", "picker prompt should include the project")
+	assert.Contains(t, calls[1].Prompt, picked, "developer prompt should include the picked requirement")
+	for _, call := range calls {
+		assert.Equal(t, "fake-model", call.Options.Model)
+	}
+}
+
 func TestAgentClientImplementsInterface(t *testing.T) {
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, &opencode.MockOC{})
+	client := NewAgentClient(ctx, &agent.MockBackend{})
 	require.NotNil(t, client)
 	var _ orchestrationRun.AIClient = client
 }
@@ -138,7 +193,7 @@ func TestAgentClientImplementsInterface(t *testing.T) {
 func TestAgentClientPrintStatsDoesNotPanicOnError(t *testing.T) {
 	ctx := execcontext.NewContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
-	client := NewAgentClient(ctx, &opencode.MockOC{})
+	client := NewAgentClient(ctx, &agent.MockBackend{})
 	require.NotPanics(t, func() { client.PrintStats() })
 }
 
@@ -159,7 +214,7 @@ requirements:
 `
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			assert.Contains(t, prompt, "orchestration file")
 			assert.Contains(t, prompt, "orchestration.md")
 			assert.Contains(t, prompt, "ralph-write-project")
@@ -168,7 +223,7 @@ requirements:
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
@@ -194,7 +249,7 @@ requirements:
 `
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			assert.Contains(t, prompt, "specification file")
 			assert.Contains(t, prompt, "spec.md")
 			assert.Contains(t, prompt, "orchestration.md")
@@ -204,7 +259,7 @@ requirements:
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForSpecInput("specs/features/test/spec.md")
 	proj, err := client.WriteProject(input)
@@ -218,12 +273,12 @@ func TestAgentClientWriteProjectAgentFailureReturnsError(t *testing.T) {
 	expectedErr := errors.New("agent failed")
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return expectedErr
 		},
 	}
 
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
 	require.Error(t, err)
@@ -237,13 +292,13 @@ func TestAgentClientWriteProjectNoProjectFileCreatedReturnsError(t *testing.T) {
 	require.NoError(t, os.MkdirAll("projects", 0755))
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return nil
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
@@ -280,13 +335,13 @@ requirements:
 	require.NoError(t, os.WriteFile("projects/old.yaml", []byte(oldYAML), 0644))
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return os.WriteFile("projects/new.yaml", []byte(newYAML), 0644)
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
@@ -302,13 +357,13 @@ func TestAgentClientWriteProjectInvalidYamlReturnsError(t *testing.T) {
 	require.NoError(t, os.MkdirAll("projects", 0755))
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return os.WriteFile("projects/invalid.yaml", []byte("invalid: yaml: ["), 0644)
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
@@ -339,12 +394,12 @@ requirements:
 `
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return os.WriteFile("projects/generated.yaml", []byte(projectYAML), 0644)
 		},
 	}
 
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	_, err := client.WriteProject(input)
 	require.NoError(t, err)
@@ -354,14 +409,14 @@ requirements:
 func TestAgentClientWriteOrchestrationWithSpecInput(t *testing.T) {
 	var promptUsed string
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			promptUsed = prompt
 			return nil
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForSpecInput("specs/features/test/spec.md")
 	err := client.WriteOrchestration(input)
@@ -373,13 +428,13 @@ func TestAgentClientWriteOrchestrationWithSpecInput(t *testing.T) {
 
 func TestAgentClientWriteOrchestrationFailureReturnsError(t *testing.T) {
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return errors.New("agent failed")
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForSpecInput("specs/features/test/spec.md")
 	err := client.WriteOrchestration(input)
@@ -391,13 +446,13 @@ func TestAgentClientWriteProjectNoProjectsDirReturnsError(t *testing.T) {
 	t.Chdir(dir)
 
 	mockOC := &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			return nil
 		},
 	}
 
 	ctx := execcontext.NewContext()
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 
 	input := project.ForOrchestrationInput("specs/features/test/orchestration.md")
 	proj, err := client.WriteProject(input)
@@ -416,7 +471,7 @@ func TestAgentClientPrintStatsUsesStoredOCClient(t *testing.T) {
 	}
 	ctx := execcontext.NewContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
-	client := NewAgentClient(ctx, mockOC)
+	client := NewAgentClient(ctx, agent.NewOpenCode(mockOC))
 	client.PrintStats()
 	assert.True(t, called, "PrintStats should call GetStats on the stored OCClient")
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
+	"github.com/zon/ralph/internal/git"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
 	"github.com/zon/ralph/internal/project"
 )
@@ -15,8 +16,29 @@ func NewLocalRunnerClient(ctx *execcontext.Context) *LocalRunnerClient {
 	return &LocalRunnerClient{ctx: ctx}
 }
 
+// RunLocal holds the .ralph project lock for the whole run, so a second local run in the
+// same repository stops with project.ErrRunInProgress instead of writing the project file too.
 func (c *LocalRunnerClient) RunLocal(input *project.InputFile, cfg *config.RalphConfig, baseBranch string) error {
-	runner := NewLocalRunner(c.ctx, baseBranch)
+	if cfg.ConfigDir != "" {
+		lock, err := project.AcquireLock(cfg.ConfigDir)
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
+		if err := git.ExcludeLocally("**/.ralph/" + project.LockFileName); err != nil {
+			c.ctx.Output().Debugf("Could not exclude the project lock file from git: %v", err)
+		}
+	}
+
+	runner, err := NewLocalRunner(c.ctx, baseBranch, cfg)
+	if err != nil {
+		return err
+	}
+	runner.SetSummaryPath(c.ctx.SummaryPath())
+	runner.SetEventsPath(c.ctx.EventsPath())
+	if c.ctx.ShouldStream() {
+		runner.SetHeaders(c.ctx.Output())
+	}
 	return runner.RunLocal(input, cfg)
 }
 
@@ -1,5 +1,11 @@
 package cmd
 
+import (
+	"context"
+
+	"github.com/zon/ralph/internal/output"
+)
+
 // Cmd defines the command-line arguments and execution context
 type Cmd struct {
 	// Subcommands
@@ -12,6 +18,11 @@ type Cmd struct {
 	List           ListCmd           `cmd:"" help:"List Argo workflows"`
 	Stop           StopCmd           `cmd:"" help:"Stop an Argo workflow"`
 	Pass           PassCmd           `cmd:"" help:"Mark a project requirement as passing or failing"`
+	Requirements   RequirementsCmd   `cmd:"" help:"List requirements or set their status"`
+	Completion     CompletionCmd     `cmd:"" help:"Print a shell completion script (bash, zsh, fish)"`
+	Version        VersionCmd        `cmd:"" help:"Show the ralph version and the container image workflows use"`
+
+	Color string `help:"When to color output: auto (terminals only, unless NO_COLOR is set), always, or never" enum:"auto,always,never" default:"auto"`
 
 	version          string       `kong:"-"`
 	date             string       `kong:"-"`
@@ -27,12 +38,26 @@ type WorkflowGroup struct {
 	Token   WorkflowTokenCmd   `cmd:"" help:"Generate a GitHub App installation token and configure git HTTPS authentication"`
 }
 
+// AfterApply applies the global flags once they are parsed (implements kong's AfterApply hook)
+func (c *Cmd) AfterApply() error {
+	output.SetColorMode(output.ColorMode(c.Color))
+	return nil
+}
+
 // SetVersion sets the version information
 func (c *Cmd) SetVersion(version, date string) {
 	c.version = version
 	c.date = date
 	c.Run.version = version
 	c.Run.date = date
+	c.Version.version = version
+	c.Version.date = date
+}
+
+// SetContext sets the context that execution contexts, the git commands they run, and
+// kubectl calls are cancelled with
+func (c *Cmd) SetContext(ctx context.Context) {
+	runContext = ctx
 }
 
 // SetCleanupRegistrar sets the cleanup registrar function
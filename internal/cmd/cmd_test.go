@@ -6,11 +6,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/output"
 )
 
 func TestLocalFlagValidation(t *testing.T) {
@@ -575,3 +577,40 @@ func TestBaseFlagParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestColorFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantColor bool
+		wantErr   bool
+	}{
+		{name: "always colors a buffer", args: []string{"--color", "always", "test.yaml"}, wantColor: true},
+		{name: "never disables color", args: []string{"--color", "never", "test.yaml"}, wantColor: false},
+		{name: "default is auto", args: []string{"test.yaml"}, wantColor: false},
+		{name: "unknown mode is rejected", args: []string{"--color", "sometimes", "test.yaml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { output.SetColorMode(output.ColorAuto) })
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd,
+				kong.Name("ralph"),
+				kong.Exit(func(int) {}),
+			)
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			output.NewClient(&out, &out, false).Warn("careful")
+			assert.Equal(t, tt.wantColor, strings.Contains(out.String(), "\x1b["))
+		})
+	}
+}
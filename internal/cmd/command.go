@@ -85,7 +85,7 @@ func (c *commandWorkflowClient) Submit(command []string) (string, error) {
 
 	workflowName, err := wf.Submit(c.ctx.GoContext(), c.argoClient)
 	if err != nil {
-		return "", err
+		return "", submitError(c.ctx, err)
 	}
 
 	c.ctx.Output().Successf("Workflow submitted: %s", workflowName)
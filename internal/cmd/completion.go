@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// CompletionCmd prints a shell completion script for the requested shell
+type CompletionCmd struct {
+	Shell string `arg:"" enum:"bash,zsh,fish" help:"Shell to generate completions for (bash, zsh, fish)"`
+}
+
+// Run writes the completion script to stdout (implements kong.Run interface)
+func (c *CompletionCmd) Run(kctx *kong.Context) error {
+	return writeCompletion(kctx.Stdout, c.Shell, kctx.Model.Node)
+}
+
+// completionNode is a flattened view of a command node used to render completion scripts
+type completionNode struct {
+	path     string
+	children []*kong.Node
+	flags    []string
+}
+
+func writeCompletion(w io.Writer, shell string, root *kong.Node) error {
+	nodes := collectCompletionNodes(root, root.Name)
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w, root.Name, nodes)
+	case "zsh":
+		return writeZshCompletion(w, root.Name, nodes)
+	case "fish":
+		return writeFishCompletion(w, root.Name, nodes)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+func collectCompletionNodes(node *kong.Node, path string) []completionNode {
+	current := completionNode{path: path, flags: []string{"--help"}}
+	for _, flag := range node.Flags {
+		if flag.Hidden || flag.Name == "help" {
+			continue
+		}
+		current.flags = append(current.flags, "--"+flag.Name)
+		if flag.Short != 0 {
+			current.flags = append(current.flags, "-"+string(flag.Short))
+		}
+	}
+
+	var children []completionNode
+	for _, child := range node.Children {
+		if child.Type != kong.CommandNode || child.Hidden {
+			continue
+		}
+		current.children = append(current.children, child)
+		children = append(children, collectCompletionNodes(child, path+" "+child.Name)...)
+	}
+
+	return append([]completionNode{current}, children...)
+}
+
+func childNames(node completionNode) []string {
+	names := make([]string, 0, len(node.children))
+	for _, child := range node.children {
+		names = append(names, child.Name)
+	}
+	return names
+}
+
+func commandPaths(nodes []completionNode) []string {
+	paths := make([]string, 0, len(nodes))
+	for _, node := range nodes[1:] {
+		paths = append(paths, fmt.Sprintf("%q", node.path))
+	}
+	return paths
+}
+
+func writeBashCompletion(w io.Writer, name string, nodes []completionNode) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", name)
+	fmt.Fprintf(&b, "_%s() {\n", name)
+	b.WriteString("    local cur cmdpath word\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(&b, "    cmdpath=%q\n", name)
+	b.WriteString("    for word in \"${COMP_WORDS[@]:1:COMP_CWORD-1}\"; do\n")
+	b.WriteString("        case \"$cmdpath $word\" in\n")
+	fmt.Fprintf(&b, "            %s) cmdpath=\"$cmdpath $word\" ;;\n", strings.Join(commandPaths(nodes), "|"))
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		words := append(childNames(node), node.flags...)
+		fmt.Fprintf(&b, "        %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", node.path, strings.Join(words, " "))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F _%s %s\n", name, name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer, name string, nodes []completionNode) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", name)
+	fmt.Fprintf(&b, "_%s() {\n", name)
+	b.WriteString("    local cmdpath word\n")
+	fmt.Fprintf(&b, "    cmdpath=%q\n", name)
+	b.WriteString("    for word in \"${words[@]:1:CURRENT-2}\"; do\n")
+	b.WriteString("        case \"$cmdpath $word\" in\n")
+	fmt.Fprintf(&b, "            %s) cmdpath=\"$cmdpath $word\" ;;\n", strings.Join(commandPaths(nodes), "|"))
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		words := append(childNames(node), node.flags...)
+		fmt.Fprintf(&b, "        %q) compadd -- %s ;;\n", node.path, strings.Join(words, " "))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "compdef _%s %s\n", name, name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishCompletion(w io.Writer, name string, nodes []completionNode) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", name)
+	fmt.Fprintf(&b, "function __%s_path\n", name)
+	fmt.Fprintf(&b, "    set -l cmdpath %s\n", name)
+	b.WriteString("    for word in (commandline -opc)[2..-1]\n")
+	b.WriteString("        switch \"$cmdpath $word\"\n")
+	fmt.Fprintf(&b, "            case %s\n", strings.Join(commandPaths(nodes), " "))
+	b.WriteString("                set cmdpath \"$cmdpath $word\"\n")
+	b.WriteString("        end\n")
+	b.WriteString("    end\n")
+	b.WriteString("    echo $cmdpath\n")
+	b.WriteString("end\n")
+	fmt.Fprintf(&b, "complete -c %s -f\n", name)
+	for _, node := range nodes {
+		condition := fmt.Sprintf("test (__%s_path) = %q", name, node.path)
+		for _, child := range node.children {
+			fmt.Fprintf(&b, "complete -c %s -n '%s' -a %s -d %s\n", name, condition, child.Name, fishQuote(child.Help))
+		}
+		for _, flag := range node.flags {
+			if strings.HasPrefix(flag, "--") {
+				fmt.Fprintf(&b, "complete -c %s -n '%s' -l %s\n", name, condition, strings.TrimPrefix(flag, "--"))
+			} else {
+				fmt.Fprintf(&b, "complete -c %s -n '%s' -s %s\n", name, condition, strings.TrimPrefix(flag, "-"))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
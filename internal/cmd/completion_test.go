@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCmd(t *testing.T) {
+	tests := []struct {
+		name  string
+		shell string
+	}{
+		{name: "bash", shell: "bash"},
+		{name: "zsh", shell: "zsh"},
+		{name: "fish", shell: "fish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd,
+				kong.Name("ralph"),
+				kong.Exit(func(int) {}),
+				kong.Writers(&stdout, &stdout),
+			)
+			require.NoError(t, err)
+
+			kctx, err := parser.Parse([]string{"completion", tt.shell})
+			require.NoError(t, err)
+			require.NoError(t, kctx.Run())
+
+			script := stdout.String()
+			require.NotEmpty(t, script)
+			for _, subcommand := range []string{"run", "merge", "workflow", "validate", "completion", "comment", "token"} {
+				assert.Contains(t, script, subcommand)
+			}
+			assert.Contains(t, script, "extra-iterations")
+		})
+	}
+}
+
+func TestCompletionCmdRejectsUnknownShell(t *testing.T) {
+	cmd := &Cmd{}
+	parser, err := kong.New(cmd,
+		kong.Name("ralph"),
+		kong.Exit(func(int) {}),
+	)
+	require.NoError(t, err)
+
+	_, err = parser.Parse([]string{"completion", "powershell"})
+	require.Error(t, err)
+}
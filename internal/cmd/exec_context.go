@@ -1,9 +1,21 @@
 package cmd
 
 import (
-	"github.com/zon/ralph/internal/context"
+	"context"
+
+	execcontext "github.com/zon/ralph/internal/context"
+	"github.com/zon/ralph/internal/git"
 )
 
-func createExecutionContext() *context.Context {
-	return context.NewContextFromEnv()
+// runContext is the standard context every execution context, and each command's kubectl
+// calls, start from; main replaces it with one that is cancelled on SIGINT/SIGTERM
+var runContext = context.Background()
+
+func createExecutionContext() *execcontext.Context {
+	ctx := execcontext.NewContextFromEnv().WithGoContext(runContext)
+	if ctx.Actor() == "" {
+		ctx.SetActor(execcontext.LocalActor())
+	}
+	git.SetContext(ctx.GoContext())
+	return ctx
 }
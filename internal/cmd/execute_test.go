@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/project"
@@ -31,7 +33,7 @@ requirements:
 
 func mockAgentOC(ctx context.Context, projectFile string, fail bool) *opencode.MockOC {
 	return &opencode.MockOC{
-		RunAgentFunc: func(_ context.Context, _, _, prompt string) error {
+		RunCommandFunc: func(_ context.Context, _, _, prompt string, _, _ io.Writer) error {
 			if fail {
 				return fmt.Errorf("opencode execution failed: mock AI failure\n\nline 9 output\nline 10 output\nline 11 output\nline 12 output")
 			}
@@ -69,7 +71,7 @@ func TestExecute_ValidProject(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.NoError(t, err)
 }
@@ -83,7 +85,7 @@ func TestExecute_BlockedMDExists(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "blocked")
@@ -99,7 +101,7 @@ func TestExecute_BlockedMDContents(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), blockedContent)
@@ -113,7 +115,7 @@ func TestExecute_NoBlockedMD(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.NoError(t, err)
 }
@@ -127,7 +129,7 @@ func TestExecute_NormalizeTrailingNewlines(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 	require.NoError(t, err)
 
 	content, err := os.ReadFile("test-project.yaml")
@@ -154,7 +156,7 @@ func TestExecute_StagesFileWithChanges(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 	require.NoError(t, err)
 
 	// Normalization should have changed and staged the file.
@@ -176,7 +178,7 @@ func TestExecute_DoesNotStageFileWithoutChanges(t *testing.T) {
 
 	ctx := testutil.NewContext(testutil.WithProjectFile("test-project.yaml"))
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 	require.NoError(t, err)
 
 	cmd := exec.Command("git", "diff", "--staged", "--name-only")
@@ -203,7 +205,7 @@ func TestExecute_StartsServices(t *testing.T) {
 		testutil.WithNoServices(false),
 	)
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", false)
-	err = project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err = project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.NoError(t, err)
 }
@@ -218,7 +220,7 @@ func TestExecute_WritesBlockedOnAgentFailure(t *testing.T) {
 	require.NoError(t, os.WriteFile("test-project.yaml", []byte(projectYAML), 0644))
 
 	mockOC := mockAgentOC(context.Background(), "test-project.yaml", true)
-	err := project.ExecuteDevelopmentIteration(ctx, mockOC, nil)
+	err := project.ExecuteDevelopmentIteration(ctx, agent.NewOpenCode(mockOC), nil)
 
 	require.Error(t, err)
 
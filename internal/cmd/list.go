@@ -15,14 +15,17 @@ type ListCmd struct {
 }
 
 func (l *ListCmd) Run() error {
-	ctx := context.Background()
+	ctx := runContext
 
 	ralphConfig, err := config.LoadConfig()
 	if err != nil {
 		return err
 	}
 
-	k8sClient := k8s.NewClient()
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
 	cmd := newOrchestrationArgoCmd(ctx, k8sClient, ralphConfig)
 	return cmd.List(orchestrationArgo.ListFlags{
 		Context:   l.Context,
@@ -37,7 +40,7 @@ type argoContextClient struct {
 }
 
 func (a *argoContextClient) Resolve(flagContext, flagNamespace string) (orchestrationArgo.K8sContext, error) {
-	k8sCtx, err := resolveKubeContext(a.ctx, a.k8sClient, a.ralphConfig, nil, flagContext, flagNamespace)
+	k8sCtx, err := k8s.ResolveContext(a.ctx, a.k8sClient, a.ralphConfig, nil, flagContext, flagNamespace)
 	if err != nil {
 		return orchestrationArgo.K8sContext{}, err
 	}
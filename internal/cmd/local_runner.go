@@ -1,24 +1,33 @@
 package cmd
 
 import (
+	"github.com/zon/ralph/internal/agent"
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/notify"
-	"github.com/zon/ralph/internal/opencode"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
 	"github.com/zon/ralph/internal/project"
 	"github.com/zon/ralph/internal/services"
 )
 
-func NewLocalRunner(ctx *context.Context, baseBranch string) *orchestrationRun.Runner {
+// NewLocalRunner wires a Runner for a local run. cfg is the loaded .ralph/config.yaml; the
+// clients it builds read their settings from it instead of loading the file again.
+func NewLocalRunner(ctx *context.Context, baseBranch string, cfg *config.RalphConfig) (*orchestrationRun.Runner, error) {
+	backend, err := agent.ForName(cfg.Agent)
+	if err != nil {
+		return nil, err
+	}
+	git.SetRemoteTimeout(cfg)
+	project.SetBackup(cfg)
 	return orchestrationRun.NewRunner(
-		&project.Client{},
-		NewAgentClient(ctx, opencode.New()),
-		git.NewClient(ctx),
-		github.NewClient(ctx, baseBranch, github.NewGH(ctx.Output()), opencode.New()),
-		services.NewClient(ctx.Output()),
+		&project.Client{Out: ctx.Output()},
+		NewAgentClient(ctx, backend),
+		git.NewClient(ctx).WithBaseBranch(baseBranch).WithConfig(cfg),
+		github.NewClient(ctx, baseBranch, github.NewGH(ctx.Output()), backend, cfg),
+		services.NewClient(ctx.Output()).WithNotes(ctx.AddNote).WithContext(ctx.GoContext()),
 		notify.NewClient(ctx),
 		&SystemEnvClient{},
-	)
+	), nil
 }
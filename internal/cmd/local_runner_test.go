@@ -6,18 +6,21 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 )
 
 func TestNewLocalRunnerIsNotNil(t *testing.T) {
 	ctx := context.NewContext()
-	runner := NewLocalRunner(ctx, "main")
+	runner, err := NewLocalRunner(ctx, "main", &config.RalphConfig{})
+	require.NoError(t, err)
 	require.NotNil(t, runner)
 }
 
 func TestNewLocalRunner_WiresSystemEnvClient(t *testing.T) {
 	ctx := context.NewContext()
-	runner := NewLocalRunner(ctx, "main")
+	runner, err := NewLocalRunner(ctx, "main", &config.RalphConfig{})
+	require.NoError(t, err)
 	_, ok := runner.Env().(*SystemEnvClient)
 	require.True(t, ok, "expected runner.env to be *SystemEnvClient")
 }
@@ -25,6 +28,14 @@ func TestNewLocalRunner_WiresSystemEnvClient(t *testing.T) {
 func TestNewLocalRunner_EnvNotInWorkflowByDefault(t *testing.T) {
 	os.Unsetenv("RALPH_WORKFLOW_EXECUTION")
 	ctx := context.NewContext()
-	runner := NewLocalRunner(ctx, "main")
+	runner, err := NewLocalRunner(ctx, "main", &config.RalphConfig{})
+	require.NoError(t, err)
 	require.False(t, runner.Env().InWorkflow())
 }
+
+func TestNewLocalRunner_RejectsUnknownAgent(t *testing.T) {
+	ctx := context.NewContext()
+	_, err := NewLocalRunner(ctx, "main", &config.RalphConfig{Agent: "aider"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown agent "aider"`)
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/orchestration/pass"
 	"github.com/zon/ralph/internal/output"
+	"github.com/zon/ralph/internal/project"
 )
 
 type PassCmd struct {
@@ -16,6 +19,9 @@ type PassCmd struct {
 func (c *PassCmd) Run() error {
 	ctx := createExecutionContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+	if err := configureBackup(); err != nil {
+		return err
+	}
 
 	p := &pass.PassCmd{
 		ProjectFile: c.ProjectFile,
@@ -38,3 +44,18 @@ func (c *PassCmd) Run() error {
 	ctx.Output().Successf("Requirement '%s' is %s", c.Slug, status)
 	return nil
 }
+
+// configureBackup applies the project backup settings from .ralph/config.yaml. Outside a ralph
+// project there is no config to read, so backups stay off; any other load error is returned.
+func configureBackup() error {
+	ralphConfig, err := config.LoadConfig()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			project.SetBackup(nil)
+			return nil
+		}
+		return err
+	}
+	project.SetBackup(ralphConfig)
+	return nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -108,3 +109,20 @@ func TestPassCmd_ConfirmationMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureBackup(t *testing.T) {
+	t.Cleanup(func() { project.SetBackup(nil) })
+
+	t.Run("outside a ralph repository", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		assert.NoError(t, configureBackup())
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph", "config.yaml"), []byte("backup: [\n"), 0644))
+		t.Chdir(dir)
+		assert.Error(t, configureBackup())
+	})
+}
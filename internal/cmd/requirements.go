@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/zon/ralph/internal/orchestration/pass"
+	"github.com/zon/ralph/internal/output"
+	"github.com/zon/ralph/internal/project"
+)
+
+// RequirementsCmd groups subcommands that inspect and update requirement status
+type RequirementsCmd struct {
+	Set  RequirementsSetCmd  `cmd:"" help:"Set a requirement's status to passing or failing"`
+	List RequirementsListCmd `cmd:"" help:"List requirements and their status"`
+}
+
+type RequirementsSetCmd struct {
+	ProjectFile string `arg:"" help:"Path to project YAML file"`
+	Slug        string `arg:"" help:"Requirement slug"`
+	Status      string `arg:"" enum:"passing,failing" help:"New status (passing, failing)"`
+}
+
+func (c *RequirementsSetCmd) Run() error {
+	ctx := createExecutionContext()
+	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+	if err := configureBackup(); err != nil {
+		return err
+	}
+
+	p := &pass.PassCmd{
+		ProjectFile: c.ProjectFile,
+		Slug:        c.Slug,
+		False:       c.Status == "failing",
+	}
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+
+	ctx.Output().Successf("Requirement '%s' is %s", c.Slug, c.Status)
+	return nil
+}
+
+type RequirementsListCmd struct {
+	ProjectFile string `arg:"" help:"Path to project YAML file"`
+}
+
+func (c *RequirementsListCmd) Run() error {
+	ctx := createExecutionContext()
+	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+
+	proj, err := project.LoadProject(c.ProjectFile)
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, req := range proj.Requirements {
+		width = max(width, len(req.Slug))
+	}
+	for _, req := range proj.Requirements {
+		status := "failing"
+		if req.Passing {
+			status = "passing"
+		}
+		ctx.Output().Infof("%-*s  %s", width, req.Slug, status)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/project"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	old := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	fnErr := fn()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out), fnErr
+}
+
+func TestRequirementsSetCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		searchSlug string
+		initial    bool
+		status     string
+		wantStatus bool
+		wantErr    bool
+	}{
+		{name: "set passing", searchSlug: "my-req", initial: false, status: "passing", wantStatus: true},
+		{name: "set failing", searchSlug: "my-req", initial: true, status: "failing", wantStatus: false},
+		{name: "unknown requirement", searchSlug: "unknown-slug", initial: false, status: "passing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := project.FileWithRequirement(t, "my-req", tt.initial)
+
+			cmd := &RequirementsSetCmd{ProjectFile: path, Slug: tt.searchSlug, Status: tt.status}
+			out, err := captureStdout(t, cmd.Run)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, out)
+				assert.Equal(t, tt.initial, project.RequirementStatus(t, path, "my-req"), "project should be unchanged")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, project.RequirementStatus(t, path, tt.searchSlug))
+			assert.Contains(t, out, "Requirement 'my-req' is "+tt.status)
+		})
+	}
+}
+
+func TestRequirementsListCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	content := `slug: test-project
+requirements:
+  - slug: setup
+    items:
+      - Done
+    passing: true
+  - slug: api-endpoints
+    items:
+      - Todo
+    passing: false
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	cmd := &RequirementsListCmd{ProjectFile: path}
+	out, err := captureStdout(t, cmd.Run)
+	require.NoError(t, err)
+	assert.Equal(t, "setup          passing\napi-endpoints  failing\n", out)
+}
+
+func TestRequirementsListCmd_FileNotFound(t *testing.T) {
+	cmd := &RequirementsListCmd{ProjectFile: project.NonExistentFile(t)}
+	_, err := captureStdout(t, cmd.Run)
+	assert.Error(t, err)
+}
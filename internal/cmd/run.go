@@ -1,30 +1,47 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/output"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
+	"github.com/zon/ralph/internal/workflow"
 )
 
 // RunCmd is the default command for executing ralph
 type RunCmd struct {
-	WorkingDir       string `help:"Working directory to run ralph in" type:"path" short:"C"`
-	InputFile        string `arg:"" optional:"" help:"Path to input file (project YAML, orchestration.md, or spec.md)"`
-	ExtraIterations  int    `help:"Extra iterations beyond requirement count (default: 20% of requirements)" name:"extra-iterations"`
-	NoNotify         bool   `help:"Disable desktop notifications" default:"false"`
-	NoServices       bool   `help:"Skip service startup" default:"false"`
-	Verbose          bool   `help:"Enable verbose logging" default:"false"`
-	Local            bool   `help:"Run on this machine instead of in Argo Workflows" default:"false"`
-	Follow           bool   `help:"Follow workflow logs after submission (only applicable without --local)" short:"f" default:"false"`
-	Debug            string `help:"Checkout the given ralph repo branch in the workflow container and invoke ralph via 'go run' instead of the built binary" name:"debug" optional:""`
-	Base             string `help:"Override the base branch for PR creation (default: detects from current branch)" name:"base" optional:"" short:"B"`
-	Model            string `help:"Override the AI model from config" name:"model" optional:""`
-	Variant          string `help:"Override the model variant from config" name:"variant" optional:""`
-	Context          string `help:"Kubernetes context to use" name:"context" optional:""`
-	ShowVersion      bool   `help:"Show version information" short:"v" name:"version"`
+	WorkingDir       string   `help:"Working directory to run ralph in" type:"path" short:"C"`
+	InputFile        string   `arg:"" optional:"" help:"Path to input file (project YAML, orchestration.md, or spec.md)"`
+	ExtraIterations  int      `help:"Extra iterations beyond requirement count (default: 20% of requirements)" name:"extra-iterations"`
+	NoNotify         bool     `help:"Disable desktop notifications" default:"false"`
+	NoServices       bool     `help:"Skip service startup" default:"false"`
+	Verbose          bool     `help:"Enable verbose logging" default:"false"`
+	Local            bool     `help:"Run on this machine instead of in Argo Workflows" default:"false"`
+	Follow           bool     `help:"Follow workflow logs after submission (only applicable without --local)" short:"f" default:"false"`
+	Debug            string   `help:"Checkout the given ralph repo branch in the workflow container and invoke ralph via 'go run' instead of the built binary" name:"debug" optional:""`
+	Base             string   `help:"Override the base branch for PR creation (default: detects from current branch)" name:"base" optional:"" short:"B"`
+	Model            string   `help:"Override the AI model from config" name:"model" optional:""`
+	Variant          string   `help:"Override the model variant from config" name:"variant" optional:""`
+	Context          string   `help:"Kubernetes context to use" name:"context" optional:""`
+	Namespace        string   `help:"Kubernetes namespace to submit the workflow to, overriding workflow.namespace from .ralph/config.yaml (only applicable without --local)" name:"namespace" short:"n" optional:""`
+	Image            string   `help:"Container image for the workflow as repository:tag or repository@sha256:<digest>, overriding workflow.image from .ralph/config.yaml (only applicable without --local)" name:"image" optional:""`
+	SummaryJSON      string   `help:"Write a JSON summary of the run to this path (only applicable with --local)" name:"summary-json" type:"path" optional:""`
+	Events           string   `help:"Append newline-delimited JSON events to this path as the run progresses (only applicable with --local)" name:"events" type:"path" optional:""`
+	AllowIncomplete  bool     `help:"Exit zero instead of non-zero when the iteration limit is reached with requirements still failing (only applicable with --local)" name:"allow-incomplete" default:"false"`
+	ForcePush        bool     `help:"Push iteration commits with --force-with-lease instead of pulling first, for a branch whose history was rewritten (only applicable with --local)" name:"force-push" default:"false"`
+	AllowBasePush    bool     `help:"Allow pushing iteration commits while the base branch is checked out (only applicable with --local)" name:"allow-base-push" default:"false"`
+	DryRun           bool     `help:"Print the generated workflow and lint it with 'argo lint' when argo is installed, instead of submitting it (only applicable without --local)" name:"dry-run" default:"false"`
+	Plan             bool     `help:"Print the branch, base branch and first requirement the run would work on, without running the agent or changing git state" name:"plan" default:"false"`
+	Param            []string `help:"Custom workflow parameter as key=value, exposed to the container as RALPH_PARAM_<KEY> (repeatable, only applicable without --local)" name:"param" sep:"none" placeholder:"KEY=VALUE"`
+	NoPush           bool     `help:"Commit each iteration on the local branch without pushing it or creating a pull request (only applicable with --local)" name:"no-push" default:"false"`
+	Offline          bool     `help:"Work only on local state: skip fetching and the remote sync and branch checks, and commit without pushing or creating a pull request (only applicable with --local)" name:"offline" default:"false"`
+	SquashBeforePR   bool     `help:"Squash the project branch into a single commit against the base branch before creating the pull request (only applicable with --local)" name:"squash-before-pr" default:"false"`
+	Isolated         bool     `help:"Run in a temporary clone of the repository and push the branch from there, leaving the working copy untouched (only applicable with --local)" name:"isolated" default:"false"`
+	Stream           bool     `help:"Relay the agent's output line by line as it is written, under a header for each iteration (only applicable with --local)" name:"stream" default:"false"`
+	PRIfComplete     bool     `help:"When every requirement already passes, open the project's pull request instead of stopping with nothing to do (only applicable with --local)" name:"pr-if-complete" default:"false"`
+	KeepFailed       bool     `help:"Keep the pods of failed workflows, and the workflows themselves, for post-mortem debugging (only applicable without --local)" name:"keep-failed" default:"false"`
+	ShowVersion      bool     `help:"Show version information" short:"v" name:"version"`
 
 	version          string       `kong:"-"`
 	date             string       `kong:"-"`
@@ -37,7 +54,18 @@ func (r *RunCmd) Run() error {
 		return err
 	}
 
+	params, err := workflow.ParseParams(r.Param)
+	if err != nil {
+		return err
+	}
+	if r.Image != "" {
+		if _, err := workflow.ParseImageRef(r.Image); err != nil {
+			return err
+		}
+	}
+
 	ctx := r.newExecutionContext()
+	ctx.SetParams(params)
 
 	flags := orchestrationRun.RunFlags{
 		WorkingDir:      r.WorkingDir,
@@ -49,9 +77,26 @@ func (r *RunCmd) Run() error {
 		Base:            r.Base,
 		Model:           r.Model,
 		Context:         r.Context,
+		Namespace:       r.Namespace,
+		Image:           r.Image,
+		SummaryJSON:     r.SummaryJSON,
+		Events:          r.Events,
+		AllowIncomplete: r.AllowIncomplete,
+		ForcePush:       r.ForcePush,
+		AllowBasePush:   r.AllowBasePush,
+		Params:          params,
+		DryRun:          r.DryRun,
+		Plan:            r.Plan,
+		KeepFailed:      r.KeepFailed,
+		NoPush:          r.NoPush,
+		Offline:         r.Offline,
+		SquashBeforePR:  r.SquashBeforePR,
+		Stream:          r.Stream,
+		Isolated:        r.Isolated,
+		PRIfComplete:    r.PRIfComplete,
 	}
 
-	cmd := newOrchestrationRunCmd(ctx)
+	cmd := newOrchestrationRunCmd(ctx, r.cleanupRegistrar)
 	return cmd.Run(flags)
 }
 
@@ -69,6 +114,17 @@ func (r *RunCmd) newExecutionContext() *execcontext.Context {
 	ctx.SetModel(r.Model)
 	ctx.SetVariant(r.Variant)
 	ctx.SetKubeContext(r.Context)
+	ctx.SetKubeNamespace(r.Namespace)
+	ctx.SetImage(r.Image)
+	ctx.SetSummaryPath(r.SummaryJSON)
+	ctx.SetEventsPath(r.Events)
+	ctx.SetForcePush(r.ForcePush)
+	ctx.SetAllowBasePush(r.AllowBasePush)
+	ctx.SetKeepFailed(r.KeepFailed)
+	ctx.SetNoPush(r.NoPush || r.Offline)
+	ctx.SetOffline(r.Offline)
+	ctx.SetSquashBeforePR(r.SquashBeforePR)
+	ctx.SetStream(r.Stream)
 	return ctx
 }
 
@@ -76,10 +132,5 @@ func (r *RunCmd) handleVersionFlag() error {
 	if !r.ShowVersion {
 		return nil
 	}
-	if r.date != "unknown" {
-		fmt.Printf("ralph version %s (%s)\n", r.version, r.date)
-	} else {
-		fmt.Printf("ralph version %s\n", r.version)
-	}
-	return nil
+	return printVersion(os.Stdout, r.version, r.date)
 }
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
@@ -9,13 +12,94 @@ import (
 	"github.com/zon/ralph/internal/workspace"
 )
 
-func newOrchestrationRunCmd(ctx *execcontext.Context) *orchestrationRun.RunCmd {
+func newOrchestrationRunCmd(ctx *execcontext.Context, cleanupRegistrar func(func())) *orchestrationRun.RunCmd {
 	return orchestrationRun.NewRunCmd(
-		&workspace.Client{},
+		&workspace.Client{Out: ctx.Output(), CleanupRegistrar: cleanupRegistrar},
 		&project.Client{},
 		git.NewClient(ctx),
-		&config.Client{},
+		&runConfigLoader{},
 		NewLocalRunnerClient(ctx),
 		NewRemoteRunnerClient(ctx),
+		&planPrinter{ctx: ctx},
 	)
 }
+
+// runConfigLoader loads .ralph/config.yaml for a run and applies its gitTimeout to every remote
+// git call that follows, local or remote, and its backup settings to every project file rewrite.
+type runConfigLoader struct {
+	config.Client
+}
+
+func (l *runConfigLoader) Load() (*config.RalphConfig, error) {
+	cfg, err := l.Client.Load()
+	if err != nil {
+		return nil, err
+	}
+	git.SetRemoteTimeout(cfg)
+	project.SetBackup(cfg)
+	return cfg, nil
+}
+
+type planPrinter struct {
+	ctx *execcontext.Context
+}
+
+func (p *planPrinter) PrintPlan(plan orchestrationRun.Plan) {
+	p.ctx.Output().Info(formatPlan(plan))
+}
+
+// formatPlan renders plan as the lines printed by --plan.
+func formatPlan(p orchestrationRun.Plan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for %s\n", p.Project)
+	if p.InputPath != "" {
+		fmt.Fprintf(&b, "  Input:            %s\n", p.InputPath)
+	}
+	if p.Local {
+		b.WriteString("  Mode:             local\n")
+	} else {
+		b.WriteString("  Mode:             Argo workflow\n")
+	}
+	fmt.Fprintf(&b, "  Branch:           %s (from %s)\n", p.Branch, p.CurrentBranch)
+	fmt.Fprintf(&b, "  Base branch:      %s\n", p.BaseBranch)
+	if p.Generated {
+		b.WriteString("  Requirements:     generated from the input before the first iteration\n")
+	} else {
+		fmt.Fprintf(&b, "  Requirements:     %d failing of %d\n", p.Failing, p.Requirements)
+		if p.Next != "" {
+			fmt.Fprintf(&b, "  Next requirement: %s\n", p.Next)
+		} else {
+			b.WriteString("  Next requirement: none, all requirements pass\n")
+		}
+		fmt.Fprintf(&b, "  Iteration limit:  %d\n", p.IterationLimit)
+	}
+	fmt.Fprintf(&b, "  Commits:          one per iteration on %s, %s", p.Branch, planDelivery(p))
+	return b.String()
+}
+
+// planDelivery describes what happens to the iteration commits once the run ends.
+func planDelivery(p orchestrationRun.Plan) string {
+	switch {
+	case p.Offline:
+		return "kept local without fetching, pushing or a pull request"
+	case p.NoPush:
+		return "kept local without pushing or a pull request"
+	case p.SquashBeforePR:
+		return fmt.Sprintf("squashed into one commit, then a pull request into %s", p.BaseBranch)
+	default:
+		return fmt.Sprintf("then a pull request into %s", p.BaseBranch)
+	}
+}
+
+func (p *planPrinter) PrintNothingToDo(proj *project.Project, local bool) {
+	p.ctx.Output().Info(nothingToDo(proj, local))
+}
+
+// nothingToDo is printed instead of running an already complete project.
+func nothingToDo(proj *project.Project, local bool) string {
+	msg := fmt.Sprintf("Nothing to do: all %d requirements of %s already pass.", len(proj.Requirements), proj.Slug)
+	if local {
+		msg += " Run with --pr-if-complete to open its pull request anyway."
+	}
+	return msg
+}
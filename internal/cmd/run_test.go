@@ -4,10 +4,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
+	"github.com/zon/ralph/internal/project"
 )
 
 // Tests for the Kong RunCmd struct are in internal/orchestration/run/cmd_test.go
@@ -28,6 +33,74 @@ func TestRunCmdFlagExtraIterations(t *testing.T) {
 	assert.Contains(t, string(out), "--extra-iterations")
 }
 
+func TestFormatPlan(t *testing.T) {
+	plan := orchestrationRun.Plan{
+		Project:        "my-project",
+		InputPath:      "projects/my-project.yaml",
+		Local:          true,
+		Branch:         "my-project",
+		CurrentBranch:  "main",
+		BaseBranch:     "main",
+		Requirements:   3,
+		Failing:        2,
+		Next:           "schema",
+		IterationLimit: 4,
+	}
+	assert.Equal(t, "Plan for my-project\n"+
+		"  Input:            projects/my-project.yaml\n"+
+		"  Mode:             local\n"+
+		"  Branch:           my-project (from main)\n"+
+		"  Base branch:      main\n"+
+		"  Requirements:     2 failing of 3\n"+
+		"  Next requirement: schema\n"+
+		"  Iteration limit:  4\n"+
+		"  Commits:          one per iteration on my-project, then a pull request into main", formatPlan(plan))
+
+	plan.Generated = true
+	plan.Local = false
+	got := formatPlan(plan)
+	assert.Contains(t, got, "  Mode:             Argo workflow\n")
+	assert.Contains(t, got, "  Requirements:     generated from the input before the first iteration\n")
+	assert.NotContains(t, got, "Next requirement")
+}
+
+func TestFormatPlanCommits(t *testing.T) {
+	tests := []struct {
+		name string
+		plan orchestrationRun.Plan
+		want string
+	}{
+		{
+			name: "no push",
+			plan: orchestrationRun.Plan{Branch: "my-project", BaseBranch: "main", NoPush: true},
+			want: "  Commits:          one per iteration on my-project, kept local without pushing or a pull request",
+		},
+		{
+			name: "offline",
+			plan: orchestrationRun.Plan{Branch: "my-project", BaseBranch: "main", NoPush: true, Offline: true},
+			want: "  Commits:          one per iteration on my-project, kept local without fetching, pushing or a pull request",
+		},
+		{
+			name: "squash before pr",
+			plan: orchestrationRun.Plan{Branch: "my-project", BaseBranch: "main", SquashBeforePR: true},
+			want: "  Commits:          one per iteration on my-project, squashed into one commit, then a pull request into main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatPlan(tt.plan)
+			assert.True(t, strings.HasSuffix(got, "\n"+tt.want), got)
+		})
+	}
+}
+
+func TestNothingToDo(t *testing.T) {
+	proj := project.WithAllPassing()
+	assert.Equal(t, "Nothing to do: all 1 requirements of test-project already pass. Run with --pr-if-complete to open its pull request anyway.", nothingToDo(proj, true))
+	assert.Equal(t, "Nothing to do: all 1 requirements of test-project already pass.", nothingToDo(proj, false))
+}
+
 // findRepoRoot walks up from the working directory to find go.mod
 func findRepoRoot(t *testing.T) string {
 	t.Helper()
@@ -45,3 +118,174 @@ func findRepoRoot(t *testing.T) string {
 		dir = parent
 	}
 }
+
+func TestRunCmdFlagAllowIncomplete(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "default", args: []string{"run", "project.yaml"}, want: false},
+		{name: "enabled", args: []string{"run", "project.yaml", "--allow-incomplete"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmd.Run.AllowIncomplete)
+		})
+	}
+}
+
+func TestRunCmdFlagForcePush(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "default", args: []string{"run", "project.yaml", "--local"}, want: false},
+		{name: "set", args: []string{"run", "project.yaml", "--local", "--force-push"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmd.Run.ForcePush)
+			assert.Equal(t, tt.want, cmd.Run.newExecutionContext().IsForcePush())
+		})
+	}
+}
+
+func TestRunCmdFlagContextAndNamespace(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantContext   string
+		wantNamespace string
+	}{
+		{name: "default", args: []string{"run", "project.yaml"}},
+		{name: "long flags", args: []string{"run", "project.yaml", "--context", "prod", "--namespace", "ralph"}, wantContext: "prod", wantNamespace: "ralph"},
+		{name: "short namespace", args: []string{"run", "project.yaml", "-n", "ralph"}, wantNamespace: "ralph"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			ctx := cmd.Run.newExecutionContext()
+			assert.Equal(t, tt.wantContext, ctx.KubeContext())
+			assert.Equal(t, tt.wantNamespace, ctx.KubeNamespace())
+		})
+	}
+}
+
+func TestRunCmdFlagImage(t *testing.T) {
+	cmd := &Cmd{}
+	parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+	require.NoError(t, err)
+
+	_, err = parser.Parse([]string{"run", "project.yaml", "--image", "ghcr.io/me/ralph:pr-42"})
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/me/ralph:pr-42", cmd.Run.newExecutionContext().Image())
+}
+
+func TestRunCmdInvalidImageFailsBeforeRunning(t *testing.T) {
+	r := &RunCmd{Image: "ghcr.io/me/ralph"}
+	err := r.Run()
+	assert.ErrorContains(t, err, `invalid --image "ghcr.io/me/ralph"`)
+}
+
+func TestRunCmdFlagOffline(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "default", args: []string{"run", "project.yaml", "--local"}},
+		{name: "set", args: []string{"run", "project.yaml", "--local", "--offline"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			ctx := cmd.Run.newExecutionContext()
+			assert.Equal(t, tt.want, ctx.IsOffline())
+			assert.Equal(t, tt.want, ctx.IsNoPush(), "--offline implies --no-push")
+		})
+	}
+}
+
+func TestRunCmdFlagStream(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "default", args: []string{"run", "project.yaml", "--local"}},
+		{name: "set", args: []string{"run", "project.yaml", "--local", "--stream"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmd.Run.newExecutionContext().ShouldStream())
+		})
+	}
+}
+
+func TestRunCmdFlagPRIfComplete(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "default", args: []string{"run", "project.yaml", "--local"}},
+		{name: "set", args: []string{"run", "project.yaml", "--local", "--pr-if-complete"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Cmd{}
+			parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmd.Run.PRIfComplete)
+		})
+	}
+}
+
+func TestRunCmdFlagParam(t *testing.T) {
+	cmd := &Cmd{}
+	parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+	require.NoError(t, err)
+
+	_, err = parser.Parse([]string{"run", "project.yaml", "--param", "env=staging", "--param", "query=a=b,c"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env=staging", "query=a=b,c"}, cmd.Run.Param)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/github"
@@ -14,13 +18,15 @@ import (
 )
 
 type SetConfigCmd struct {
-	GithubKey string `help:"Path to GitHub App private key (.pem file)" name:"github-key" optional:""`
-	Context   string `help:"Kubernetes context to use" name:"context" optional:""`
-	Namespace string `help:"Kubernetes namespace to use" short:"n" optional:""`
+	GithubKey       string `help:"Path to GitHub App private key (.pem file), or - to read the key from stdin. Without it, the PEM contents of the RALPH_GITHUB_KEY environment variable are used when set" name:"github-key" optional:""`
+	Context         string `help:"Kubernetes context to use" name:"context" optional:""`
+	Namespace       string `help:"Kubernetes namespace to use" short:"n" optional:""`
+	DryRun          bool   `name:"dry-run" help:"Print the secrets that would be created or updated, with their key names but not their values, without writing anything"`
+	CreateNamespace bool   `name:"create-namespace" help:"Create the namespace when it does not exist instead of asking"`
 }
 
 func (c *SetConfigCmd) Run() error {
-	ctx := context.Background()
+	ctx := runContext
 
 	out := output.NewClient(os.Stdout, os.Stderr, false)
 	out.Info("Configuring credentials for Ralph remote execution...")
@@ -30,18 +36,23 @@ func (c *SetConfigCmd) Run() error {
 		return err
 	}
 
-	k8sClient := k8s.NewClient()
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
 
 	cmd := &setconfig.SetConfigCmd{
-		Ctx:      &setconfigContextClient{ctx: ctx, k8sClient: k8sClient, ralphConfig: ralphConfig},
-		GitHub:   &setconfigGitHubClient{ctx: ctx, k8sClient: k8sClient, out: out},
-		OpenCode: &setconfigOpenCodeClient{ctx: ctx, k8sClient: k8sClient, out: out},
+		Ctx:        &setconfigContextClient{ctx: ctx, k8sClient: k8sClient, ralphConfig: ralphConfig},
+		Namespaces: &setconfigNamespaceClient{ctx: ctx, k8sClient: k8sClient, out: out, opts: k8s.NamespaceOptions{Create: c.CreateNamespace, Confirm: out.TerminalConfirm()}},
+		GitHub:     &setconfigGitHubClient{ctx: ctx, k8sClient: k8sClient, out: out, stdin: os.Stdin, envKey: os.Getenv(githubKeyEnvVar)},
+		OpenCode:   &setconfigOpenCodeClient{ctx: ctx, k8sClient: k8sClient, out: out},
 	}
 
 	return cmd.Run(setconfig.Flags{
 		Context:   c.Context,
 		Namespace: c.Namespace,
-		GithubKey: c.GithubKey,
+		GithubKey: githubKeyPath(c.GithubKey, os.Getenv(githubKeyEnvVar)),
+		DryRun:    c.DryRun,
 	})
 }
 
@@ -52,17 +63,56 @@ type setconfigContextClient struct {
 }
 
 func (a *setconfigContextClient) Resolve(flagContext, flagNamespace string) (setconfig.K8sContext, error) {
-	k8sCtx, err := resolveKubeContext(a.ctx, a.k8sClient, a.ralphConfig, nil, flagContext, flagNamespace)
+	k8sCtx, err := k8s.ResolveContext(a.ctx, a.k8sClient, a.ralphConfig, nil, flagContext, flagNamespace)
 	if err != nil {
 		return setconfig.K8sContext{}, err
 	}
 	return setconfig.K8sContext{Name: k8sCtx.Name, Namespace: k8sCtx.Namespace}, nil
 }
 
+type setconfigNamespaceClient struct {
+	ctx       context.Context
+	k8sClient k8s.Client
+	out       *output.Client
+	opts      k8s.NamespaceOptions
+}
+
+func (c *setconfigNamespaceClient) Ensure(k8sCtx setconfig.K8sContext) error {
+	created, err := k8s.EnsureNamespace(c.ctx, c.k8sClient, k8sCtx.Namespace, k8sCtx.Name, c.opts)
+	if err != nil {
+		return err
+	}
+	if created {
+		c.out.Successf("Namespace '%s' created", k8sCtx.Namespace)
+	}
+	return nil
+}
+
+// githubKeyStdin is the --github-key value that reads the private key from standard input
+const githubKeyStdin = "-"
+
+// githubKeyEnvVar holds the PEM contents of the private key when --github-key is not given
+const githubKeyEnvVar = "RALPH_GITHUB_KEY"
+
+// githubKeyFromEnv is the key path passed on when the private key comes from githubKeyEnvVar
+const githubKeyFromEnv = "$" + githubKeyEnvVar
+
+// githubKeyPath returns the --github-key value, or githubKeyFromEnv when only the environment
+// variable supplies the key.
+func githubKeyPath(flag, envKey string) string {
+	if flag == "" && strings.TrimSpace(envKey) != "" {
+		return githubKeyFromEnv
+	}
+	return flag
+}
+
 type setconfigGitHubClient struct {
 	ctx       context.Context
 	k8sClient k8s.Client
 	out       *output.Client
+	stdin     io.Reader
+	stdinKey  []byte // Key read from stdin, kept so validating and configuring read it once
+	envKey    string // PEM contents of githubKeyEnvVar
 }
 
 func (c *setconfigGitHubClient) SecretExists(k8sCtx setconfig.K8sContext) (bool, error) {
@@ -70,8 +120,12 @@ func (c *setconfigGitHubClient) SecretExists(k8sCtx setconfig.K8sContext) (bool,
 }
 
 func (c *setconfigGitHubClient) Validate(keyPath string) error {
+	privateKeyBytes, err := c.readKey(keyPath)
+	if err != nil {
+		return err
+	}
 	c.out.Info("Validating credentials...")
-	if err := github.ValidateAppCredentials(c.ctx, keyPath, config.DefaultAppID); err != nil {
+	if err := github.ValidateAppKey(c.ctx, privateKeyBytes, config.DefaultAppID); err != nil {
 		return err
 	}
 	c.out.Success("Credentials validated successfully")
@@ -79,18 +133,13 @@ func (c *setconfigGitHubClient) Validate(keyPath string) error {
 }
 
 func (c *setconfigGitHubClient) Configure(k8sCtx setconfig.K8sContext, keyPath string) error {
-	privateKeyBytes, err := github.ReadGitHubAppCredentials(keyPath)
+	secretData, err := c.secretData(keyPath)
 	if err != nil {
 		return err
 	}
 
 	c.out.Infof("Creating/updating Kubernetes secret '%s'...", k8s.GitHubSecretName)
 
-	secretData := map[string]string{
-		"app-id":      config.DefaultAppID,
-		"private-key": string(privateKeyBytes),
-	}
-
 	if err := c.k8sClient.CreateOrUpdateSecret(c.ctx, k8s.GitHubSecretName, k8sCtx.Namespace, k8sCtx.Name, secretData); err != nil {
 		return fmt.Errorf("failed to create/update secret: %w", err)
 	}
@@ -100,6 +149,47 @@ func (c *setconfigGitHubClient) Configure(k8sCtx setconfig.K8sContext, keyPath s
 	return nil
 }
 
+func (c *setconfigGitHubClient) PrintPlan(k8sCtx setconfig.K8sContext, keyPath string) error {
+	secretData, err := c.secretData(keyPath)
+	if err != nil {
+		return err
+	}
+	return printSecretPlan(c.ctx, c.k8sClient, c.out, k8s.GitHubSecretName, k8sCtx, secretData)
+}
+
+func (c *setconfigGitHubClient) secretData(keyPath string) (map[string]string, error) {
+	privateKeyBytes, err := c.readKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"app-id":      config.DefaultAppID,
+		"private-key": string(privateKeyBytes),
+	}, nil
+}
+
+// readKey reads the private key from keyPath, from stdin when keyPath is githubKeyStdin, or
+// from the environment when keyPath is githubKeyFromEnv.
+func (c *setconfigGitHubClient) readKey(keyPath string) ([]byte, error) {
+	if keyPath == githubKeyFromEnv {
+		return []byte(c.envKey), nil
+	}
+	if keyPath != githubKeyStdin {
+		return github.ReadGitHubAppCredentials(keyPath)
+	}
+	if c.stdinKey == nil {
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key from stdin: %w", err)
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			return nil, fmt.Errorf("private key on stdin is empty")
+		}
+		c.stdinKey = data
+	}
+	return c.stdinKey, nil
+}
+
 type setconfigOpenCodeClient struct {
 	ctx       context.Context
 	k8sClient k8s.Client
@@ -107,9 +197,35 @@ type setconfigOpenCodeClient struct {
 }
 
 func (c *setconfigOpenCodeClient) Configure(k8sCtx setconfig.K8sContext) error {
+	secretData, err := c.secretData()
+	if err != nil {
+		return err
+	}
+
+	c.out.Infof("Creating/updating Kubernetes secret '%s'...", k8s.OpenCodeSecretName)
+
+	if err := c.k8sClient.CreateOrUpdateSecret(c.ctx, k8s.OpenCodeSecretName, k8sCtx.Namespace, k8sCtx.Name, secretData); err != nil {
+		return fmt.Errorf("failed to create/update secret: %w", err)
+	}
+
+	c.out.Successf("Secret '%s' created/updated successfully", k8s.OpenCodeSecretName)
+	c.out.Infof("Configuration complete! The secret '%s' is ready for use in namespace '%s'.", k8s.OpenCodeSecretName, k8sCtx.Namespace)
+	return nil
+}
+
+func (c *setconfigOpenCodeClient) PrintPlan(k8sCtx setconfig.K8sContext) error {
+	secretData, err := c.secretData()
+	if err != nil {
+		return err
+	}
+	return printSecretPlan(c.ctx, c.k8sClient, c.out, k8s.OpenCodeSecretName, k8sCtx, secretData)
+}
+
+// secretData reads and checks the local auth.json, warning when its providers sign in with OAuth.
+func (c *setconfigOpenCodeClient) secretData() (map[string]string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
 	authFilePath := homeDir + "/.local/share/opencode/auth.json"
@@ -117,22 +233,51 @@ func (c *setconfigOpenCodeClient) Configure(k8sCtx setconfig.K8sContext) error {
 
 	authFileContent, err := workspace.ReadOpenCodeCredentials(authFilePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	c.out.Success("OpenCode credentials read successfully")
 
-	c.out.Infof("Creating/updating Kubernetes secret '%s'...", k8s.OpenCodeSecretName)
+	oauthProviders, err := workspace.OpenCodeOAuthProviders(authFileContent)
+	if err != nil {
+		return nil, err
+	}
+	if len(oauthProviders) == 0 {
+		c.out.Info("OpenCode uses API key auth; provisioning the keys directly")
+	} else {
+		c.warnOAuth(oauthProviders)
+	}
 
-	secretData := map[string]string{
+	return map[string]string{
 		"auth.json": string(authFileContent),
+	}, nil
+}
+
+// warnOAuth explains that OAuth sessions copied into the secret go stale: OpenCode rotates the
+// refresh token whenever it refreshes, so this machine and the workflows invalidate each other.
+func (c *setconfigOpenCodeClient) warnOAuth(providers []string) {
+	c.out.Warnf("OpenCode signs in to %s with OAuth. OpenCode refreshes OAuth tokens as it runs, so the copy in the secret stops working once this machine or a workflow refreshes it.", strings.Join(providers, ", "))
+	c.out.Warn("Run `opencode auth login` with an API key for these providers, then rerun set-config, to give workflows credentials that do not expire.")
+}
+
+// printSecretPlan reports whether the named secret would be created or updated and which keys it
+// would hold. Values are never printed.
+func printSecretPlan(ctx context.Context, k8sClient k8s.Client, out *output.Client, name string, k8sCtx setconfig.K8sContext, data map[string]string) error {
+	exists, err := k8sClient.SecretExists(ctx, name, k8sCtx.Namespace, k8sCtx.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check for secret '%s': %w", name, err)
+	}
+	action := "create"
+	if exists {
+		action = "update"
 	}
 
-	if err := c.k8sClient.CreateOrUpdateSecret(c.ctx, k8s.OpenCodeSecretName, k8sCtx.Namespace, k8sCtx.Name, secretData); err != nil {
-		return fmt.Errorf("failed to create/update secret: %w", err)
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	c.out.Successf("Secret '%s' created/updated successfully", k8s.OpenCodeSecretName)
-	c.out.Infof("Configuration complete! The secret '%s' is ready for use in namespace '%s'.", k8s.OpenCodeSecretName, k8sCtx.Namespace)
+	out.Infof("Dry run: would %s secret '%s' in namespace '%s' (context '%s') with keys: %s", action, name, k8sCtx.Namespace, k8sCtx.Name, strings.Join(keys, ", "))
 	return nil
 }
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/k8s"
+	"github.com/zon/ralph/internal/orchestration/setconfig"
+	"github.com/zon/ralph/internal/output"
+)
+
+func writeOpenCodeAuth(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".local", "share", "opencode")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(content), 0600))
+}
+
+func TestSetconfigOpenCodeClientConfigure(t *testing.T) {
+	tests := []struct {
+		name      string
+		auth      string
+		wantOAuth bool
+	}{
+		{
+			name: "api key auth is provisioned directly",
+			auth: `{"deepseek":{"type":"api","key":"sk-test"}}`,
+		},
+		{
+			name:      "oauth auth warns that the session goes stale",
+			auth:      `{"anthropic":{"type":"oauth","refresh":"r","access":"a","expires":1}}`,
+			wantOAuth: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeOpenCodeAuth(t, tt.auth)
+			var secret map[string]string
+			k8sClient := &k8s.MockClient{
+				CreateOrUpdateSecretFunc: func(_ context.Context, name, _, _ string, data map[string]string) error {
+					assert.Equal(t, k8s.OpenCodeSecretName, name)
+					secret = data
+					return nil
+				},
+			}
+			var buf bytes.Buffer
+			client := &setconfigOpenCodeClient{ctx: context.Background(), k8sClient: k8sClient, out: output.NewClient(&buf, &buf, false)}
+
+			require.NoError(t, client.Configure(setconfig.K8sContext{Name: "test", Namespace: "argo"}))
+
+			assert.Equal(t, map[string]string{"auth.json": tt.auth}, secret)
+			if tt.wantOAuth {
+				assert.Contains(t, buf.String(), "anthropic with OAuth")
+				assert.NotContains(t, buf.String(), "API key auth")
+			} else {
+				assert.Contains(t, buf.String(), "API key auth")
+				assert.NotContains(t, buf.String(), "OAuth")
+			}
+		})
+	}
+}
+
+func TestSetconfigOpenCodeClientConfigureRejectsMalformedAuth(t *testing.T) {
+	writeOpenCodeAuth(t, `{"deepseek":`)
+	created := false
+	k8sClient := &k8s.MockClient{
+		CreateOrUpdateSecretFunc: func(context.Context, string, string, string, map[string]string) error {
+			created = true
+			return nil
+		},
+	}
+	client := &setconfigOpenCodeClient{ctx: context.Background(), k8sClient: k8sClient, out: output.NewClient(&bytes.Buffer{}, &bytes.Buffer{}, false)}
+
+	err := client.Configure(setconfig.K8sContext{Name: "test", Namespace: "argo"})
+	require.Error(t, err)
+	assert.False(t, created, "a malformed auth.json must not become a secret")
+}
+
+func TestSetconfigPrintPlanWritesNoSecrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		exists     bool
+		wantAction string
+	}{
+		{name: "new secrets would be created", wantAction: "would create"},
+		{name: "existing secrets would be updated", exists: true, wantAction: "would update"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeOpenCodeAuth(t, `{"deepseek":{"type":"api","key":"sk-test"}}`)
+			keyPath := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, os.WriteFile(keyPath, []byte("pem-secret"), 0600))
+
+			created := false
+			k8sClient := &k8s.MockClient{
+				CreateOrUpdateSecretFunc: func(context.Context, string, string, string, map[string]string) error {
+					created = true
+					return nil
+				},
+				SecretExistsFunc: func(context.Context, string, string, string) (bool, error) {
+					return tt.exists, nil
+				},
+			}
+			var buf bytes.Buffer
+			out := output.NewClient(&buf, &buf, false)
+			k8sCtx := setconfig.K8sContext{Name: "test", Namespace: "argo"}
+
+			gh := &setconfigGitHubClient{ctx: context.Background(), k8sClient: k8sClient, out: out}
+			require.NoError(t, gh.PrintPlan(k8sCtx, keyPath))
+			oc := &setconfigOpenCodeClient{ctx: context.Background(), k8sClient: k8sClient, out: out}
+			require.NoError(t, oc.PrintPlan(k8sCtx))
+
+			assert.False(t, created, "a dry run must not write secrets")
+			assert.Contains(t, buf.String(), tt.wantAction+" secret '"+k8s.GitHubSecretName+"' in namespace 'argo' (context 'test') with keys: app-id, private-key")
+			assert.Contains(t, buf.String(), tt.wantAction+" secret '"+k8s.OpenCodeSecretName+"' in namespace 'argo' (context 'test') with keys: auth.json")
+			assert.NotContains(t, buf.String(), "pem-secret")
+			assert.NotContains(t, buf.String(), "sk-test")
+		})
+	}
+}
+
+func TestSetconfigGitHubClientConfigureReadsKeyFromStdin(t *testing.T) {
+	var secret map[string]string
+	k8sClient := &k8s.MockClient{
+		CreateOrUpdateSecretFunc: func(_ context.Context, name, _, _ string, data map[string]string) error {
+			assert.Equal(t, k8s.GitHubSecretName, name)
+			secret = data
+			return nil
+		},
+	}
+	client := &setconfigGitHubClient{
+		ctx:       context.Background(),
+		k8sClient: k8sClient,
+		out:       output.NewClient(&bytes.Buffer{}, &bytes.Buffer{}, false),
+		stdin:     strings.NewReader("pem-from-stdin\n"),
+	}
+	k8sCtx := setconfig.K8sContext{Name: "test", Namespace: "argo"}
+
+	require.NoError(t, client.PrintPlan(k8sCtx, githubKeyStdin))
+	require.NoError(t, client.Configure(k8sCtx, githubKeyStdin))
+
+	assert.Equal(t, "pem-from-stdin\n", secret["private-key"], "stdin is read once and reused")
+}
+
+func TestSetconfigGitHubClientRejectsEmptyStdinKey(t *testing.T) {
+	client := &setconfigGitHubClient{
+		ctx:       context.Background(),
+		k8sClient: &k8s.MockClient{},
+		out:       output.NewClient(&bytes.Buffer{}, &bytes.Buffer{}, false),
+		stdin:     strings.NewReader("\n"),
+	}
+
+	err := client.Configure(setconfig.K8sContext{Name: "test", Namespace: "argo"}, githubKeyStdin)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "private key on stdin is empty")
+}
+
+func TestGithubKeyPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   string
+		envKey string
+		want   string
+	}{
+		{name: "env supplies the key contents", envKey: "pem-from-env", want: githubKeyFromEnv},
+		{name: "flag overrides env", flag: "/keys/app.pem", envKey: "pem-from-env", want: "/keys/app.pem"},
+		{name: "stdin flag overrides env", flag: githubKeyStdin, envKey: "pem-from-env", want: githubKeyStdin},
+		{name: "blank env is ignored", envKey: "\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, githubKeyPath(tt.flag, tt.envKey))
+		})
+	}
+}
+
+func TestSetConfigCmdGithubKeyFlagIgnoresEnv(t *testing.T) {
+	t.Setenv("RALPH_GITHUB_KEY", "pem-from-env")
+	cmd := &Cmd{}
+	parser, err := kong.New(cmd, kong.Name("ralph"), kong.Exit(func(int) {}))
+	require.NoError(t, err)
+
+	_, err = parser.Parse([]string{"set", "config"})
+	require.NoError(t, err)
+
+	assert.Empty(t, cmd.Set.Config.GithubKey, "the env var holds the key contents, not a path")
+}
+
+func TestSetconfigGitHubClientConfigureReadsKeyFromEnv(t *testing.T) {
+	var secret map[string]string
+	k8sClient := &k8s.MockClient{
+		CreateOrUpdateSecretFunc: func(_ context.Context, name, _, _ string, data map[string]string) error {
+			assert.Equal(t, k8s.GitHubSecretName, name)
+			secret = data
+			return nil
+		},
+	}
+	client := &setconfigGitHubClient{
+		ctx:       context.Background(),
+		k8sClient: k8sClient,
+		out:       output.NewClient(&bytes.Buffer{}, &bytes.Buffer{}, false),
+		envKey:    "pem-from-env\n",
+	}
+
+	require.NoError(t, client.Configure(setconfig.K8sContext{Name: "test", Namespace: "argo"}, githubKeyPath("", client.envKey)))
+
+	assert.Equal(t, "pem-from-env\n", secret["private-key"])
+}
+
+func TestSetconfigNamespaceClientEnsure(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    k8s.NamespaceOptions
+		wantErr error
+	}{
+		{name: "create-namespace creates it", opts: k8s.NamespaceOptions{Create: true}},
+		{name: "accepted prompt creates it", opts: k8s.NamespaceOptions{Confirm: func(string) (bool, error) { return true, nil }}},
+		{name: "declined prompt fails", opts: k8s.NamespaceOptions{Confirm: func(string) (bool, error) { return false, nil }}, wantErr: k8s.ErrNamespaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := ""
+			k8sClient := &k8s.MockClient{
+				NamespaceExistsFunc: func(context.Context, string, string) (bool, error) { return false, nil },
+				CreateNamespaceFunc: func(_ context.Context, namespace, _ string) error {
+					created = namespace
+					return nil
+				},
+			}
+			var buf bytes.Buffer
+			client := &setconfigNamespaceClient{ctx: context.Background(), k8sClient: k8sClient, out: output.NewClient(&buf, &buf, false), opts: tt.opts}
+
+			err := client.Ensure(setconfig.K8sContext{Name: "test", Namespace: "argo"})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, created)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "argo", created)
+			assert.Contains(t, buf.String(), "Namespace 'argo' created")
+		})
+	}
+}
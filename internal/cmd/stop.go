@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"context"
-
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/k8s"
 	orchestrationArgo "github.com/zon/ralph/internal/orchestration/argo"
@@ -15,14 +13,17 @@ type StopCmd struct {
 }
 
 func (s *StopCmd) Run() error {
-	ctx := context.Background()
+	ctx := runContext
 
 	ralphConfig, err := config.LoadConfig()
 	if err != nil {
 		return err
 	}
 
-	k8sClient := k8s.NewClient()
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
 	cmd := newOrchestrationArgoCmd(ctx, k8sClient, ralphConfig)
 	return cmd.Stop(orchestrationArgo.StopFlags{
 		Context:      s.Context,
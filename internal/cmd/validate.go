@@ -4,7 +4,6 @@ import (
 	"os"
 
 	"github.com/zon/ralph/internal/output"
-	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/orchestration/validate"
 )
 
@@ -15,7 +14,14 @@ type ValidateCmd struct {
 func (v *ValidateCmd) Run() error {
 	ctx := createExecutionContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
-	validator := validate.New(ctx, opencode.New())
+	if err := configureBackup(); err != nil {
+		return err
+	}
+	backend, err := newAgentBackend()
+	if err != nil {
+		return err
+	}
+	validator := validate.New(ctx, backend)
 	proj, err := validator.Validate(v.ProjectFile)
 	if err != nil {
 		return err
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/workflow"
+)
+
+// VersionCmd prints the CLI version together with the container image workflows would run
+type VersionCmd struct {
+	version string `kong:"-"`
+	date    string `kong:"-"`
+}
+
+// Run executes the version command (implements kong.Run interface)
+func (v *VersionCmd) Run() error {
+	return printVersion(os.Stdout, v.version, v.date)
+}
+
+// printVersion writes the CLI version, the default container image version and the image
+// workflows use once .ralph/config.yaml is applied. Outside a ralph repository the default
+// image is reported. A config that exists but cannot be loaded is reported and returned, since
+// the workflow image is then unknown.
+func printVersion(w io.Writer, version, date string) error {
+	cfg, err := config.LoadConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		writeVersion(w, version, date, nil, nil)
+		return nil
+	}
+	if err != nil {
+		writeVersion(w, version, date, nil, err)
+		return err
+	}
+	writeVersion(w, version, date, &cfg.Workflow, nil)
+	return nil
+}
+
+func writeVersion(w io.Writer, version, date string, workflowConfig *config.WorkflowConfig, configErr error) {
+	if date != "unknown" && date != "" {
+		fmt.Fprintf(w, "ralph version %s (%s)\n", version, date)
+	} else {
+		fmt.Fprintf(w, "ralph version %s\n", version)
+	}
+	fmt.Fprintf(w, "default container version %s\n", workflow.DefaultContainerVersion())
+	if configErr != nil {
+		fmt.Fprintf(w, "workflow image unknown (invalid .ralph/config.yaml: %v)\n", configErr)
+		return
+	}
+	if workflowConfig == nil {
+		fmt.Fprintf(w, "workflow image %s (default, no .ralph/config.yaml found)\n", workflow.ResolvedImage(config.WorkflowConfig{}))
+		return
+	}
+	fmt.Fprintf(w, "workflow image %s\n", workflow.ResolvedImage(*workflowConfig))
+}
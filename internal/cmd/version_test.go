@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/workflow"
+)
+
+func TestWriteVersion(t *testing.T) {
+	defaultVersion := workflow.DefaultContainerVersion()
+
+	tests := []struct {
+		name   string
+		date   string
+		config *config.WorkflowConfig
+		err    error
+		want   string
+	}{
+		{
+			name:   "configured image",
+			date:   "2026-03-04",
+			config: &config.WorkflowConfig{Image: config.ImageConfig{Repository: "registry.example.com/ralph", Tag: "1.8.0"}},
+			want: "ralph version 1.9.0 (2026-03-04)\n" +
+				"default container version " + defaultVersion + "\n" +
+				"workflow image registry.example.com/ralph:1.8.0\n",
+		},
+		{
+			name: "unknown date keeps the one-line version",
+			date: "unknown",
+			want: "ralph version 1.9.0\n" +
+				"default container version " + defaultVersion + "\n" +
+				"workflow image ghcr.io/zon/ralph:" + defaultVersion + " (default, no .ralph/config.yaml found)\n",
+		},
+		{
+			name: "invalid config",
+			date: "unknown",
+			err:  errors.New("field maxIteration not found"),
+			want: "ralph version 1.9.0\n" +
+				"default container version " + defaultVersion + "\n" +
+				"workflow image unknown (invalid .ralph/config.yaml: field maxIteration not found)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeVersion(&out, "1.9.0", tt.date, tt.config, tt.err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestPrintVersion(t *testing.T) {
+	t.Run("outside a ralph repository", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		var out bytes.Buffer
+		require.NoError(t, printVersion(&out, "1.9.0", "unknown"))
+		assert.Contains(t, out.String(), "no .ralph/config.yaml found")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph", "config.yaml"), []byte("maxIterations: [\n"), 0644))
+		t.Chdir(dir)
+
+		var out bytes.Buffer
+		err := printVersion(&out, "1.9.0", "unknown")
+		require.Error(t, err)
+		assert.Contains(t, out.String(), "workflow image unknown (invalid .ralph/config.yaml: ")
+	})
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -22,7 +23,57 @@ type workflowClientAdapter struct {
 }
 
 func (a *workflowClientAdapter) Submit(input *project.InputFile, cloneBranch string, debug string, baseBranch string) (string, error) {
-	projectBranch := git.SanitizeBranchName(input.Slug())
+	wf, err := a.generate(input, cloneBranch, debug, baseBranch)
+	if err != nil {
+		return "", err
+	}
+	name, err := wf.Submit(a.ctx.GoContext(), a.argoClient)
+	if err != nil {
+		return "", submitError(a.ctx, err)
+	}
+	return name, nil
+}
+
+// submitError prints everything argo printed for a failed submit when verbose logging is on,
+// and otherwise points at --verbose, since the error itself only carries the relevant line.
+func submitError(ctx *context.Context, err error) error {
+	var submitErr *argo.SubmitError
+	if !errors.As(err, &submitErr) || submitErr.Output == "" {
+		return err
+	}
+	if ctx.IsVerbose() {
+		ctx.Output().Debugf("argo submit output:\n%s", submitErr.Output)
+		return err
+	}
+	return fmt.Errorf("%w (run with --verbose for the full argo output)", err)
+}
+
+// DryRun prints the workflow that Submit would submit and lints it with argo when available.
+func (a *workflowClientAdapter) DryRun(input *project.InputFile, cloneBranch string, debug string, baseBranch string) error {
+	wf, err := a.generate(input, cloneBranch, debug, baseBranch)
+	if err != nil {
+		return err
+	}
+	workflowYAML, err := wf.Render()
+	if err != nil {
+		return err
+	}
+	a.ctx.Output().Info(workflowYAML)
+
+	err = a.argoClient.LintYAML(a.ctx.GoContext(), workflowYAML)
+	if errors.Is(err, argo.ErrArgoNotInstalled) {
+		a.ctx.Output().Warn("argo CLI not found, skipping workflow lint")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	a.ctx.Output().Success("Workflow passed argo lint")
+	return nil
+}
+
+func (a *workflowClientAdapter) generate(input *project.InputFile, cloneBranch string, debug string, baseBranch string) (*workflow.Workflow, error) {
+	projectBranch := input.BranchName()
 
 	var repoURL string
 	owner, name := a.ctx.RepoOwnerAndName()
@@ -31,7 +82,7 @@ func (a *workflowClientAdapter) Submit(input *project.InputFile, cloneBranch str
 	} else {
 		repo, err := githubpkg.GetRepo(a.ctx.GoContext())
 		if err != nil {
-			return "", fmt.Errorf("failed to get repository: %w", err)
+			return nil, fmt.Errorf("failed to get repository: %w", err)
 		}
 		repoURL = repo.CloneURL()
 	}
@@ -42,11 +93,11 @@ func (a *workflowClientAdapter) Submit(input *project.InputFile, cloneBranch str
 		if owner == "" {
 			repoRoot, err := git.FindRepoRoot()
 			if err != nil {
-				return "", fmt.Errorf("failed to get repository root: %w", err)
+				return nil, fmt.Errorf("failed to get repository root: %w", err)
 			}
 			relProjectPath, err = filepath.Rel(repoRoot, relProjectPath)
 			if err != nil {
-				return "", fmt.Errorf("failed to calculate relative project path: %w", err)
+				return nil, fmt.Errorf("failed to calculate relative project path: %w", err)
 			}
 		}
 	}
@@ -56,11 +107,11 @@ func (a *workflowClientAdapter) Submit(input *project.InputFile, cloneBranch str
 	}
 	wf, err := workflow.GenerateWorkflow(a.ctx, input.Slug(), cloneBranch, projectBranch, baseBranch, a.ctx.IsVerbose(), repoURL, relProjectPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	a.namespace = wf.Namespace
 	a.kubeContext = wf.KubeContext
-	return wf.Submit(a.ctx.GoContext(), a.argoClient)
+	return wf, nil
 }
 
 func (a *workflowClientAdapter) FollowLogs(workflowName string) error {
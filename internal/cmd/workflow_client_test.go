@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zon/ralph/internal/argo"
+	execcontext "github.com/zon/ralph/internal/context"
+	"github.com/zon/ralph/internal/output"
+)
+
+func TestSubmitError(t *testing.T) {
+	submitErr := &argo.SubmitError{
+		Reason: "the namespace does not exist: namespaces \"missing\" not found",
+		Output: "W0102 noise\nnamespaces \"missing\" not found",
+		Err:    errors.New("exit status 1"),
+	}
+
+	t.Run("points at --verbose", func(t *testing.T) {
+		var out bytes.Buffer
+		ctx := execcontext.NewContext()
+		ctx.SetOutput(output.NewClient(&out, &out, false))
+
+		err := submitError(ctx, submitErr)
+		assert.ErrorIs(t, err, submitErr)
+		assert.Equal(t, "failed to submit workflow: the namespace does not exist: namespaces \"missing\" not found (run with --verbose for the full argo output)", err.Error())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("prints the full output when verbose", func(t *testing.T) {
+		var out bytes.Buffer
+		ctx := execcontext.NewContext()
+		ctx.SetVerbose(true)
+		ctx.SetOutput(output.NewClient(&out, &out, true))
+
+		err := submitError(ctx, submitErr)
+		assert.Equal(t, submitErr, err)
+		assert.Contains(t, out.String(), "argo submit output:\nW0102 noise\nnamespaces \"missing\" not found")
+	})
+
+	t.Run("leaves other errors alone", func(t *testing.T) {
+		ctx := execcontext.NewContext()
+		errOther := errors.New("failed to marshal workflow to YAML")
+
+		assert.Equal(t, errOther, submitError(ctx, errOther))
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
-	"github.com/zon/ralph/internal/opencode"
 	orchestrationComment "github.com/zon/ralph/internal/orchestration/comment"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/services"
@@ -57,6 +56,7 @@ func (w *WorkflowCommentCmd) Run() error {
 		RepoName:         w.RepoName,
 		NoServices:       w.NoServices,
 		InstructionsFile: w.InstructionsFile,
+		Assignee:         ctx.WebhookLogin(),
 	}
 	return cmd.Run(flags)
 }
@@ -68,7 +68,7 @@ func newOrchestrationWorkflowCommentCmd(ctx *execcontext.Context) *orchestration
 		&workflowCommentAIClient{ctx: ctx},
 		&workflowCommentServicesClient{ctx: ctx},
 		&workflowCommentGitClient{},
-		&workflowCommentGitHubClient{},
+		&workflowCommentGitHubClient{ctx: ctx},
 	)
 }
 
@@ -105,11 +105,19 @@ func (c *workflowCommentAIClient) RenderCommentPrompt(ctx orchestrationComment.C
 }
 
 func (c *workflowCommentAIClient) RunAgent(prompt string) error {
-	return ai.RunAgent(c.ctx, opencode.New(), prompt)
+	backend, err := newAgentBackend()
+	if err != nil {
+		return err
+	}
+	return ai.RunAgent(c.ctx, backend, prompt)
 }
 
 func (c *workflowCommentAIClient) GenerateChangelog() error {
-	return ai.GenerateChangelog(c.ctx, opencode.New())
+	backend, err := newAgentBackend()
+	if err != nil {
+		return err
+	}
+	return ai.GenerateChangelog(c.ctx, backend)
 }
 
 func (c *workflowCommentAIClient) GenerateCommentReply(ctx orchestrationComment.CommentContext, pushed bool) (string, error) {
@@ -166,7 +174,9 @@ func (c *workflowCommentGitClient) CommitAndPushFromReport() error {
 // workflowCommentGitHubClient implements orchestration/comment.GitHubClient
 // ---------------------------------------------------------------------------
 
-type workflowCommentGitHubClient struct{}
+type workflowCommentGitHubClient struct {
+	ctx *execcontext.Context
+}
 
 func (c *workflowCommentGitHubClient) PostComment(prNumber int, body string) error {
 	cmd := exec.Command("gh", "pr", "comment", fmt.Sprintf("%d", prNumber), "--body", body)
@@ -176,3 +186,10 @@ func (c *workflowCommentGitHubClient) PostComment(prNumber int, body string) err
 	}
 	return nil
 }
+
+func (c *workflowCommentGitHubClient) AssignPR(prNumber int, login string) {
+	cmd := exec.Command("gh", "pr", "edit", fmt.Sprintf("%d", prNumber), "--add-assignee", login)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		c.ctx.Output().Warnf("Could not assign %s to PR #%d: %v (output: %s)", login, prNumber, err, strings.TrimSpace(string(output)))
+	}
+}
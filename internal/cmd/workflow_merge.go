@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/github"
@@ -62,6 +63,9 @@ func newOrchestrationWorkflowMergeCmd(ctx *execcontext.Context) *orchestrationMe
 type workflowMergeGitClient struct{}
 
 func (c *workflowMergeGitClient) CommitAndPush(message string) error {
+	if cfg, err := config.LoadConfig(); err == nil {
+		message = git.WithCoAuthorTrailer(message, cfg.CoAuthor)
+	}
 	return git.CommitChanges(true, "", "", message)
 }
 
@@ -11,7 +11,6 @@ import (
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/github"
-	"github.com/zon/ralph/internal/opencode"
 	wksp "github.com/zon/ralph/internal/orchestration/workspace"
 	orchestrationWorkflow "github.com/zon/ralph/internal/orchestration/workflowrun"
 	"github.com/zon/ralph/internal/project"
@@ -102,8 +101,25 @@ func (c *workspaceGitClient) Clone(branch string) error {
 	if cloneBranch == "" {
 		cloneBranch = os.Getenv("GIT_BRANCH")
 	}
+	cloneDir := os.Getenv("RALPH_CLONE_DIR")
+	if cloneDir == "" {
+		cloneDir = workspace.DefaultWorkDir
+	}
 	cloneURL := github.CloneURL(owner, repo)
-	return workspace.PrepareWorkspace(c.ctx.Output(), cloneURL, cloneBranch, workspace.DefaultWorkDir)
+	if err := workspace.PrepareWorkspace(c.ctx.Output(), cloneURL, cloneBranch, cloneDir); err != nil {
+		return err
+	}
+	if os.Getenv("RALPH_LFS") == "true" {
+		c.ctx.Output().Info("Pulling Git LFS content...")
+		if err := git.PullLFS(); err != nil {
+			return err
+		}
+	}
+	if os.Getenv("RALPH_SUBMODULES") == "true" {
+		c.ctx.Output().Info("Initializing submodules...")
+		return git.UpdateSubmodules()
+	}
+	return nil
 }
 
 func (c *workspaceGitClient) RemoteBranchExists(branch string) (bool, error) {
@@ -154,7 +170,11 @@ func (a *aiAdapter) ResolveMergeConflicts(baseBranch, projectBranch string) erro
 	if err != nil {
 		return err
 	}
-	return ai.RunAgent(a.ctx, opencode.New(), prompt)
+	backend, err := newAgentBackend()
+	if err != nil {
+		return err
+	}
+	return ai.RunAgent(a.ctx, backend, prompt)
 }
 
 // ---------------------------------------------------------------------------
@@ -167,7 +187,10 @@ type runnerAdapter struct {
 }
 
 func (a *runnerAdapter) RunLocal(proj *project.Project, cfg *config.RalphConfig) error {
-	runner := NewLocalRunner(a.ctx, a.baseBranch)
+	runner, err := NewLocalRunner(a.ctx, a.baseBranch, cfg)
+	if err != nil {
+		return err
+	}
 	return runner.RunLocal(project.ForProjectInput(proj), cfg)
 }
 
@@ -191,6 +214,22 @@ func (a *configOptionalAdapter) LoadOptional() (*config.RalphConfig, error) {
 
 type projectLoadAdapter struct{}
 
+// Locate keeps path when the file there works on branch. A workflow started from a pull request
+// only has a path guessed from the branch name, so otherwise the projects directory is searched.
+func (a *projectLoadAdapter) Locate(path, branch string) (string, error) {
+	if derived, err := project.DeriveBranchName(path); err == nil && derived == branch {
+		return path, nil
+	}
+	found, err := project.FindProjectFile(".", branch)
+	if err != nil {
+		if _, statErr := os.Stat(path); statErr == nil {
+			return path, nil
+		}
+		return "", err
+	}
+	return found, nil
+}
+
 func (a *projectLoadAdapter) Load(path string) (*project.Project, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
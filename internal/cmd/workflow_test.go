@@ -121,6 +121,10 @@ type mockWorProjectClient struct {
 	loadFn func(path string) (*project.Project, error)
 }
 
+func (m *mockWorProjectClient) Locate(path, branch string) (string, error) {
+	return path, nil
+}
+
 func (m *mockWorProjectClient) Load(path string) (*project.Project, error) {
 	if m.loadFn != nil {
 		return m.loadFn(path)
@@ -20,7 +20,7 @@ func TestClientLoad(t *testing.T) {
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
 	// Write config file with known, distinctive content
-	configContent := `maxIterations: 5
+	configContent := `extraIterations: 5
 defaultBranch: develop
 services:
   - name: test-service
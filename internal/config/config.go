@@ -1,10 +1,17 @@
 package config
 
 import (
+	"bytes"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
 
 	"gopkg.in/yaml.v3"
 )
@@ -33,6 +40,12 @@ type Before struct {
 	Optional bool     `yaml:"optional,omitempty"`
 }
 
+// Hook is a command ralph runs from the repository root at a fixed point of a run
+type Hook struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+}
+
 // Service represents a service to be started/stopped
 type Service struct {
 	Name    string   `yaml:"name"`
@@ -58,33 +71,103 @@ type AppInfo struct {
 type ImageConfig struct {
 	Repository string `yaml:"repository,omitempty"`
 	Tag        string `yaml:"tag,omitempty"`
+	Digest     string `yaml:"digest,omitempty"` // Pins the image by content (sha256:...); takes precedence over Tag
 }
 
+// imageDigestPattern matches an OCI sha256 content digest
+var imageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
 // ConfigMapMount represents a ConfigMap to mount with destination info
 type ConfigMapMount struct {
-	Name     string `yaml:"name"`               // Name of the ConfigMap
-	DestFile string `yaml:"destFile,omitempty"` // Destination file path (if mounting a single file)
-	DestDir  string `yaml:"destDir,omitempty"`  // Destination directory (if mounting entire ConfigMap)
-	Link     bool   `yaml:"link,omitempty"`     // Whether to create a symlink in workspace (default: false)
+	Name     string      `yaml:"name"`               // Name of the ConfigMap
+	DestFile string      `yaml:"destFile,omitempty"` // Destination file path (if mounting a single file)
+	DestDir  string      `yaml:"destDir,omitempty"`  // Destination directory (if mounting entire ConfigMap)
+	Link     bool        `yaml:"link,omitempty"`     // Whether to create a symlink in workspace (default: false)
+	Items    []MountItem `yaml:"items,omitempty"`    // Keys to project into the destination directory (if mounting several keys)
 }
 
 // SecretMount represents a Secret to mount with destination info
 type SecretMount struct {
-	Name     string `yaml:"name"`               // Name of the Secret
-	DestFile string `yaml:"destFile,omitempty"` // Destination file path (if mounting a single file)
-	DestDir  string `yaml:"destDir,omitempty"`  // Destination directory (if mounting entire Secret)
-	Link     bool   `yaml:"link,omitempty"`     // Whether to create a symlink in workspace (default: false)
+	Name     string      `yaml:"name"`               // Name of the Secret
+	DestFile string      `yaml:"destFile,omitempty"` // Destination file path (if mounting a single file)
+	DestDir  string      `yaml:"destDir,omitempty"`  // Destination directory (if mounting entire Secret)
+	Link     bool        `yaml:"link,omitempty"`     // Whether to create a symlink in workspace (default: false)
+	Items    []MountItem `yaml:"items,omitempty"`    // Keys to project into the destination directory (if mounting several keys)
+}
+
+// MountItem maps one key of a ConfigMap or Secret to a path inside its mount directory
+type MountItem struct {
+	Key  string `yaml:"key"`            // Key in the ConfigMap or Secret
+	Path string `yaml:"path,omitempty"` // Relative path inside the mount directory (default: the key)
 }
 
 // WorkflowConfig represents Argo Workflow configuration options
 type WorkflowConfig struct {
-	Image      ImageConfig       `yaml:"image,omitempty"`
-	ConfigMaps []ConfigMapMount  `yaml:"configMaps,omitempty"`
-	Secrets    []SecretMount     `yaml:"secrets,omitempty"`
-	Env        map[string]string `yaml:"env,omitempty"`
-	Context    string            `yaml:"context,omitempty"`
-	Namespace  string            `yaml:"namespace,omitempty"`
-	Labels     map[string]string `yaml:"labels,omitempty"`
+	Image       ImageConfig       `yaml:"image,omitempty"`
+	ConfigMaps  []ConfigMapMount  `yaml:"configMaps,omitempty"`
+	Secrets     []SecretMount     `yaml:"secrets,omitempty"`
+	Env         map[string]string `yaml:"env,omitempty"`
+	Context     string            `yaml:"context,omitempty"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Submodules  bool              `yaml:"submodules,omitempty"`
+	LFS         bool              `yaml:"lfs,omitempty"`
+	ArchiveLogs bool              `yaml:"archiveLogs,omitempty"`
+	WorkDir     string            `yaml:"workDir,omitempty"`
+	CloneDir    string            `yaml:"cloneDir,omitempty"`
+	Entrypoint  []string          `yaml:"entrypoint,omitempty"`
+	Shell       string            `yaml:"shell,omitempty"`
+	EnvFrom     []EnvFromSource   `yaml:"envFrom,omitempty"`
+	EnvRefs     []EnvRef          `yaml:"envRefs,omitempty"`
+	// CloneStrategy controls whether the container fetches the base branch and merges it into the project branch before running
+	CloneStrategy string `yaml:"cloneStrategy,omitempty"`
+	// ImagePullPolicy sets the Kubernetes imagePullPolicy (Always, IfNotPresent or Never) of the workflow containers; Argo's default applies when empty
+	ImagePullPolicy string `yaml:"imagePullPolicy,omitempty"`
+	// SubmitAttempts is how many times argo submit is tried when it fails with a transient API server error (default: 3)
+	SubmitAttempts int `yaml:"submitAttempts,omitempty"`
+}
+
+const (
+	// CloneStrategyBaseThenBranch fetches the base branch and merges it into the project branch (the default)
+	CloneStrategyBaseThenBranch = "base-then-branch"
+	// CloneStrategyBranchOnly works on the project branch as cloned, never fetching the base branch
+	CloneStrategyBranchOnly = "branch-only"
+)
+
+var validCloneStrategies = map[string]bool{
+	CloneStrategyBaseThenBranch: true,
+	CloneStrategyBranchOnly:     true,
+}
+
+// AgentOpenCode selects the OpenCode agent program; it is the default
+const AgentOpenCode = "opencode"
+
+// Agents lists the agent programs the agent field may name
+var Agents = []string{AgentOpenCode}
+
+var validImagePullPolicies = map[string]bool{
+	"Always":       true,
+	"IfNotPresent": true,
+	"Never":        true,
+}
+
+// SyncsBaseBranch reports whether the clone strategy fetches the base branch into the project branch
+func (w *WorkflowConfig) SyncsBaseBranch() bool {
+	return w.CloneStrategy != CloneStrategyBranchOnly
+}
+
+// EnvFromSource imports every key of a Secret or ConfigMap as container environment variables
+type EnvFromSource struct {
+	Secret    string `yaml:"secret,omitempty"`    // Name of the Secret
+	ConfigMap string `yaml:"configMap,omitempty"` // Name of the ConfigMap
+}
+
+// EnvRef sets one container environment variable from a key of a Secret or ConfigMap
+type EnvRef struct {
+	Name      string `yaml:"name"`                // Environment variable name
+	Secret    string `yaml:"secret,omitempty"`    // Name of the Secret holding the value
+	ConfigMap string `yaml:"configMap,omitempty"` // Name of the ConfigMap holding the value
+	Key       string `yaml:"key"`                 // Key within the Secret or ConfigMap
 }
 
 const LoopTypeDomainFunction = "domain-function"
@@ -112,22 +195,140 @@ type ValidateConfig struct {
 	Model string `yaml:"model,omitempty"`
 }
 
+// CommitConfig controls the commits ralph makes for agent work. With Conventional set, commit
+// subjects are prefixed with a conventional-commit type and scope derived from the changed paths.
+type CommitConfig struct {
+	Conventional bool              `yaml:"conventional,omitempty"`
+	DefaultType  string            `yaml:"defaultType,omitempty"` // Type used when any changed file matches no type pattern (default: feat)
+	Types        map[string]string `yaml:"types,omitempty"`       // Path pattern to commit type (default: test files to test, docs to docs)
+	Scopes       map[string]string `yaml:"scopes,omitempty"`      // Path pattern to commit scope
+}
+
+// DefaultBackupKeep is the number of project backups retained when backups are enabled
+const DefaultBackupKeep = 10
+
+// DefaultGitTimeout is the number of seconds a git fetch, pull or push may run before it is aborted
+const DefaultGitTimeout = 120
+
+// DefaultSubmitAttempts is the number of times a workflow submission is tried before a transient failure is returned
+const DefaultSubmitAttempts = 3
+
+// BackupConfig controls the copies ralph keeps of a project file before modifying it
+type BackupConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	Keep    int  `yaml:"keep,omitempty"` // Number of backups retained per project file (default: 10)
+}
+
+// Actions a guardrail takes when an iteration exceeds one of its limits
+const (
+	GuardrailAbort = "abort" // Stash the iteration's changes instead of committing them
+	GuardrailWarn  = "warn"  // Commit the iteration and note the breach
+)
+
+var validGuardrailActions = map[string]bool{
+	GuardrailAbort: true,
+	GuardrailWarn:  true,
+}
+
+// GuardrailConfig limits how much a single iteration may change, to catch a runaway agent
+type GuardrailConfig struct {
+	MaxFiles int    `yaml:"maxFiles,omitempty"` // Files one iteration may change, untracked ones included (0 = unlimited)
+	MaxLines int    `yaml:"maxLines,omitempty"` // Added plus deleted lines one iteration may change (0 = unlimited)
+	Action   string `yaml:"action,omitempty"`   // abort or warn when a limit is exceeded (default: abort)
+}
+
+// Enabled reports whether any guardrail limit is set
+func (g *GuardrailConfig) Enabled() bool {
+	return g.MaxFiles > 0 || g.MaxLines > 0
+}
+
+// Exceeded describes the first limit that files changed files and lines changed lines break, or
+// returns an empty string when both are within the limits.
+func (g *GuardrailConfig) Exceeded(files, lines int) string {
+	if g.MaxFiles > 0 && files > g.MaxFiles {
+		return fmt.Sprintf("changed %d files, more than the guardrail of %d", files, g.MaxFiles)
+	}
+	if g.MaxLines > 0 && lines > g.MaxLines {
+		return fmt.Sprintf("changed %d lines, more than the guardrail of %d", lines, g.MaxLines)
+	}
+	return ""
+}
+
+// PullRequestConfig controls the pull request ralph opens when a run completes
+type PullRequestConfig struct {
+	IncludeProject bool     `yaml:"includeProject,omitempty"` // Append the project file to the description in a collapsible block
+	TitleTemplate  string   `yaml:"titleTemplate,omitempty"`  // Go template for the title (default: the project title, or its slug)
+	Labels         []string `yaml:"labels,omitempty"`         // Labels added to the pull request; each must already exist in the repository
+	Reviewers      []string `yaml:"reviewers,omitempty"`      // GitHub logins asked to review the pull request
+	TeamReviewers  []string `yaml:"teamReviewers,omitempty"`  // Teams asked to review the pull request, as org/team
+}
+
+// PRTitleData holds the fields a pull request title template can use
+type PRTitleData struct {
+	Project      string // Project title, or its slug when the title is empty
+	Slug         string
+	Branch       string
+	PassingCount int
+	FailingCount int
+}
+
+// Title renders the title template with data. An empty template, or one that renders to only
+// whitespace, gives the project name.
+func (p *PullRequestConfig) Title(data PRTitleData) (string, error) {
+	if p.TitleTemplate == "" {
+		return data.Project, nil
+	}
+	tmpl, err := template.New("titleTemplate").Parse(p.TitleTemplate)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	title := strings.Join(strings.Fields(b.String()), " ")
+	if title == "" {
+		return data.Project, nil
+	}
+	return title, nil
+}
+
+// UpstreamConfig names the repository a fork's pull requests are opened against
+type UpstreamConfig struct {
+	Repo string `yaml:"repo,omitempty"` // Upstream repository as owner/name (default: the parent when the repository is a fork)
+	Base string `yaml:"base,omitempty"` // Upstream branch the pull request targets (default: the run's base branch)
+}
+
 // RalphConfig represents the .ralph/config.yaml structure
 type RalphConfig struct {
-	Variant             string         `yaml:"variant,omitempty"`
-	ExtraIterations     *int           `yaml:"extraIterations,omitempty"`
-	DefaultBranch       string         `yaml:"defaultBranch,omitempty"`
-	Model               string         `yaml:"model,omitempty"` // AI model to use for coding and PR summary (default: deepseek/deepseek-chat)
-	Before              []Before       `yaml:"before,omitempty"`
-	Services            []Service      `yaml:"services,omitempty"`
-	Workflow            WorkflowConfig `yaml:"workflow,omitempty"`
-	App                 AppInfo        `yaml:"app,omitempty"`
-	Review              ReviewConfig   `yaml:"review,omitempty"`
-	Validate            ValidateConfig `yaml:"validate,omitempty"`
-	ConfigPath          string         `yaml:"-"` // Path to the loaded config file
-	Instructions        string         `yaml:"-"` // Not persisted in YAML, loaded from .ralph/instructions.md
-	CommentInstructions string         `yaml:"-"` // Not persisted in YAML, loaded from .ralph/comment-instructions.md
-	MergeInstructions   string         `yaml:"-"` // Not persisted in YAML, loaded from .ralph/merge-instructions.md
+	Variant             string            `yaml:"variant,omitempty"`
+	ExtraIterations     *int              `yaml:"extraIterations,omitempty"`
+	RequirementAttempts int               `yaml:"maxAttemptsPerRequirement,omitempty"` // Consecutive iterations on one failing requirement before it is marked stuck (0 = unlimited)
+	DefaultBranch       string            `yaml:"defaultBranch,omitempty"`
+	Model               string            `yaml:"model,omitempty"`      // AI model to use for coding and PR summary (default: deepseek/deepseek-chat)
+	Agent               string            `yaml:"agent,omitempty"`      // Agent program that runs the prompts (default: opencode)
+	CoAuthor            string            `yaml:"coAuthor,omitempty"`   // Name and email added as a Co-authored-by trailer to agent commits (unset = no trailer)
+	GitTimeout          int               `yaml:"gitTimeout,omitempty"` // Seconds a git fetch, pull or push may run before it is aborted (default: 120)
+	Before              []Before          `yaml:"before,omitempty"`
+	PreRun              *Hook             `yaml:"preRun,omitempty"`         // Runs once before the first iteration
+	PostRun             *Hook             `yaml:"postRun,omitempty"`        // Runs once after the last iteration, even when the run fails
+	AfterIteration      *Hook             `yaml:"afterIteration,omitempty"` // Runs after each iteration's agent step
+	Services            []Service         `yaml:"services,omitempty"`
+	Workflow            WorkflowConfig    `yaml:"workflow,omitempty"`
+	App                 AppInfo           `yaml:"app,omitempty"`
+	Review              ReviewConfig      `yaml:"review,omitempty"`
+	Validate            ValidateConfig    `yaml:"validate,omitempty"`
+	Backup              BackupConfig      `yaml:"backup,omitempty"`
+	Commit              CommitConfig      `yaml:"commit,omitempty"`
+	Upstream            UpstreamConfig    `yaml:"upstream,omitempty"`
+	Guardrails          GuardrailConfig   `yaml:"guardrails,omitempty"`
+	PullRequest         PullRequestConfig `yaml:"pullRequest,omitempty"`
+	ConfigDir           string            `yaml:"-"` // Path to the .ralph directory the config was loaded from
+	ConfigPath          string            `yaml:"-"` // Path to the loaded config file
+	GlobalConfigPath    string            `yaml:"-"` // Path to the loaded user-global config file
+	Instructions        string            `yaml:"-"` // Not persisted in YAML, loaded from .ralph/instructions.md
+	CommentInstructions string            `yaml:"-"` // Not persisted in YAML, loaded from .ralph/comment-instructions.md
+	MergeInstructions   string            `yaml:"-"` // Not persisted in YAML, loaded from .ralph/merge-instructions.md
 }
 
 func DefaultCommentInstructions() string {
@@ -150,6 +351,117 @@ func DefaultPickInstructions() string {
 	return defaultPickInstructions
 }
 
+// ValidateImageDigest checks that digest is an OCI sha256 content digest
+func ValidateImageDigest(digest string) error {
+	if !imageDigestPattern.MatchString(digest) {
+		return fmt.Errorf("has invalid digest %q; expected sha256: followed by 64 lowercase hex characters", digest)
+	}
+	return nil
+}
+
+// ValidateWorkflowConfig validates the clone strategy, image, submit attempts and the secret and configMap references of the
+// workflow environment, once defaults have been applied
+func ValidateWorkflowConfig(w *WorkflowConfig) error {
+	if w.CloneStrategy != "" && !validCloneStrategies[w.CloneStrategy] {
+		return fmt.Errorf("workflow has invalid cloneStrategy %q; valid strategies are: %s, %s", w.CloneStrategy, CloneStrategyBaseThenBranch, CloneStrategyBranchOnly)
+	}
+
+	if w.Image.Digest != "" {
+		if err := ValidateImageDigest(w.Image.Digest); err != nil {
+			return fmt.Errorf("workflow image %w", err)
+		}
+	}
+
+	if w.ImagePullPolicy != "" && !validImagePullPolicies[w.ImagePullPolicy] {
+		return fmt.Errorf("workflow has invalid imagePullPolicy %q; valid policies are: Always, IfNotPresent, Never", w.ImagePullPolicy)
+	}
+
+	if w.SubmitAttempts < 1 {
+		return fmt.Errorf("workflow has invalid submitAttempts %d; it must be at least 1", w.SubmitAttempts)
+	}
+
+	for i, source := range w.EnvFrom {
+		if (source.Secret == "") == (source.ConfigMap == "") {
+			return fmt.Errorf("envFrom entry %d must set exactly one of secret or configMap", i)
+		}
+	}
+
+	for i, ref := range w.EnvRefs {
+		if ref.Name == "" {
+			return fmt.Errorf("envRefs entry %d must have a name", i)
+		}
+		if (ref.Secret == "") == (ref.ConfigMap == "") {
+			return fmt.Errorf("envRefs entry %q must set exactly one of secret or configMap", ref.Name)
+		}
+		if ref.Key == "" {
+			return fmt.Errorf("envRefs entry %q must have a key", ref.Name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpstreamConfig validates that the upstream repository is written as owner/name
+func ValidateUpstreamConfig(u *UpstreamConfig) error {
+	if u.Repo == "" {
+		if u.Base != "" {
+			return fmt.Errorf("upstream base %q requires upstream repo", u.Base)
+		}
+		return nil
+	}
+	owner, name, ok := strings.Cut(u.Repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("upstream repo %q must be written as owner/name", u.Repo)
+	}
+	return nil
+}
+
+// ValidateGuardrailConfig validates that the guardrail limits are not negative and the action is known
+func ValidateGuardrailConfig(g *GuardrailConfig) error {
+	if g.MaxFiles < 0 {
+		return fmt.Errorf("guardrails have invalid maxFiles %d; it must be 0 (unlimited) or more", g.MaxFiles)
+	}
+	if g.MaxLines < 0 {
+		return fmt.Errorf("guardrails have invalid maxLines %d; it must be 0 (unlimited) or more", g.MaxLines)
+	}
+	if g.Action != "" && !validGuardrailActions[g.Action] {
+		return fmt.Errorf("guardrails have invalid action %q; valid actions are: %s, %s", g.Action, GuardrailAbort, GuardrailWarn)
+	}
+	return nil
+}
+
+// ValidatePullRequestConfig validates that the title template parses and uses only known fields,
+// and that team reviewers are written as org/team
+func ValidatePullRequestConfig(p *PullRequestConfig) error {
+	if _, err := p.Title(PRTitleData{}); err != nil {
+		return fmt.Errorf("pull request titleTemplate %q is invalid: %w", p.TitleTemplate, err)
+	}
+	for _, team := range p.TeamReviewers {
+		slug := strings.TrimPrefix(strings.TrimSpace(team), "@")
+		if slug == "" {
+			continue
+		}
+		org, name, ok := strings.Cut(slug, "/")
+		if !ok || org == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("pull request teamReviewers entry %q must be written as org/team", team)
+		}
+	}
+	return nil
+}
+
+// ValidateAgent validates that agent names a supported agent program; empty selects the default
+func ValidateAgent(agent string) error {
+	if agent == "" {
+		return nil
+	}
+	for _, valid := range Agents {
+		if agent == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown agent %q; valid agents are: %s", agent, strings.Join(Agents, ", "))
+}
+
 // ValidateReviewConfig validates the review configuration
 func ValidateReviewConfig(r *ReviewConfig) error {
 	if len(r.Items) == 0 {
@@ -197,6 +509,15 @@ func applyDefaults(config *RalphConfig) {
 	if config.App.ID == "" {
 		config.App.ID = DefaultAppID
 	}
+	if config.GitTimeout == 0 {
+		config.GitTimeout = DefaultGitTimeout
+	}
+	if config.Workflow.SubmitAttempts == 0 {
+		config.Workflow.SubmitAttempts = DefaultSubmitAttempts
+	}
+	if config.Backup.Enabled && config.Backup.Keep == 0 {
+		config.Backup.Keep = DefaultBackupKeep
+	}
 
 	for i := range config.Services {
 		if config.Services[i].Timeout == 0 {
@@ -205,6 +526,50 @@ func applyDefaults(config *RalphConfig) {
 	}
 }
 
+// AllowUnknownFieldsEnv is the environment variable that disables strict YAML decoding
+const AllowUnknownFieldsEnv = "RALPH_ALLOW_UNKNOWN_FIELDS"
+
+// AllowUnknownFields reports whether unknown YAML keys should be ignored instead of rejected.
+func AllowUnknownFields() bool {
+	return os.Getenv(AllowUnknownFieldsEnv) == "true"
+}
+
+// DecodeYAML decodes data into out, rejecting keys that do not map to a field of out
+// unless AllowUnknownFields is set. An empty document leaves out untouched.
+func DecodeYAML(data []byte, out any) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(!AllowUnknownFields())
+	if err := decoder.Decode(out); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if strings.Contains(err.Error(), "not found in type") {
+			return fmt.Errorf("%w (set %s=true to ignore unknown keys)", err, AllowUnknownFieldsEnv)
+		}
+		return err
+	}
+	return nil
+}
+
+// DecodeJSON decodes a single JSON value from data into out with the same strictness as DecodeYAML:
+// keys that do not map to a field of out are rejected unless AllowUnknownFields is set.
+func DecodeJSON(data []byte, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if !AllowUnknownFields() {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(out); err != nil {
+		if strings.Contains(err.Error(), "unknown field") {
+			return fmt.Errorf("%w (set %s=true to ignore unknown keys)", err, AllowUnknownFieldsEnv)
+		}
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
+
 // FindConfigDir searches upwards from startDir for a .ralph directory
 func FindConfigDir(startDir string) (string, error) {
 	curr := startDir
@@ -222,23 +587,83 @@ func FindConfigDir(startDir string) (string, error) {
 	}
 }
 
+// BackupDir returns the directory project backups are written to.
+func (c *RalphConfig) BackupDir() string {
+	if c.ConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(c.ConfigDir, "backups")
+}
+
+// GlobalConfigPath returns the path of the user-global config file.
+// It honors $XDG_CONFIG_HOME and falls back to ~/.config/ralph/config.yaml.
+func GlobalConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ralph", "config.yaml"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "ralph", "config.yaml"), nil
+}
+
 // loadConfigFromPath reads and parses the config file at the given path.
 // If the file does not exist, it returns an empty config and nil error.
 // If the file exists but cannot be read or parsed, it returns an error.
 func loadConfigFromPath(configPath string) (*RalphConfig, error) {
 	var config RalphConfig
+	found, err := overlayConfigFromPath(&config, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		config.ConfigPath = configPath
+	}
+	return &config, nil
+}
+
+// overlayConfigFromPath decodes the config file at the given path on top of config.
+// Fields present in the file replace those already set; absent fields are left untouched.
+// It reports whether the file existed.
+func overlayConfigFromPath(config *RalphConfig, configPath string) (bool, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Missing config file is allowed (use zero values)
-			return &config, nil
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := DecodeYAML(data, config); err != nil {
+		return false, fmt.Errorf("failed to parse config YAML: %w", err)
+	}
+	return true, nil
+}
+
+// loadLayeredConfig loads the user-global config and overlays the repo-local config on top of it.
+// Repo-local values win; the global config only fills in fields the repo config leaves unset.
+func loadLayeredConfig(globalPath, repoPath string) (*RalphConfig, error) {
+	var config RalphConfig
+
+	if globalPath != "" {
+		found, err := overlayConfigFromPath(&config, globalPath)
+		if err != nil {
+			return nil, fmt.Errorf("global config %s: %w", globalPath, err)
+		}
+		if found {
+			config.GlobalConfigPath = globalPath
 		}
-		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config YAML: %w", err)
+
+	found, err := overlayConfigFromPath(&config, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		config.ConfigPath = repoPath
 	}
-	config.ConfigPath = configPath
+
 	return &config, nil
 }
 
@@ -272,7 +697,8 @@ func loadInstructions(configDir string) (instructions, commentInstructions, merg
 	return
 }
 
-// LoadConfig searches upwards for a .ralph directory and loads config.yaml from it.
+// LoadConfig searches upwards for a .ralph directory and loads config.yaml from it,
+// layered on top of the user-global config returned by GlobalConfigPath.
 func LoadConfig() (*RalphConfig, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -284,11 +710,16 @@ func LoadConfig() (*RalphConfig, error) {
 		return nil, fmt.Errorf("failed to find .ralph directory: %w", err)
 	}
 
-	config, err := loadConfigFromPath(filepath.Join(configDir, "config.yaml"))
+	// A missing home directory only disables the global layer
+	globalPath, _ := GlobalConfigPath()
+
+	config, err := loadLayeredConfig(globalPath, filepath.Join(configDir, "config.yaml"))
 	if err != nil {
 		return nil, err
 	}
 
+	config.ConfigDir = configDir
+
 	instructions, commentInstructions, mergeInstructions := loadInstructions(configDir)
 	config.Instructions = instructions
 	config.CommentInstructions = commentInstructions
@@ -302,5 +733,25 @@ func LoadConfig() (*RalphConfig, error) {
 		}
 	}
 
+	if err := ValidateWorkflowConfig(&config.Workflow); err != nil {
+		return nil, fmt.Errorf("invalid workflow config: %w", err)
+	}
+
+	if err := ValidateAgent(config.Agent); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateUpstreamConfig(&config.Upstream); err != nil {
+		return nil, fmt.Errorf("invalid upstream config: %w", err)
+	}
+
+	if err := ValidateGuardrailConfig(&config.Guardrails); err != nil {
+		return nil, fmt.Errorf("invalid guardrails config: %w", err)
+	}
+
+	if err := ValidatePullRequestConfig(&config.PullRequest); err != nil {
+		return nil, fmt.Errorf("invalid pullRequest config: %w", err)
+	}
+
 	return config, nil
 }
@@ -37,7 +37,7 @@ func TestLoadConfig_FromFile(t *testing.T) {
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
 	// Write config file
-	configContent := `maxIterations: 5
+	configContent := `extraIterations: 5
 defaultBranch: develop
 services:
   - name: test-service
@@ -132,7 +132,7 @@ func TestLoadConfig_WithWorkflowConfig(t *testing.T) {
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 5
+	configContent := `extraIterations: 5
 defaultBranch: main
 workflow:
   image:
@@ -244,7 +244,7 @@ func TestLoadConfig_WithoutWorkflowConfig(t *testing.T) {
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 3
+	configContent := `extraIterations: 3
 defaultBranch: main
  `
 	configPath := filepath.Join(ralphDir, "config.yaml")
@@ -303,6 +303,28 @@ func TestApplyDefaults_AppFields(t *testing.T) {
 	assert.Equal(t, "2966665", config.App.ID)
 }
 
+func TestApplyDefaults_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ralphDir := filepath.Join(tmpDir, ".ralph")
+	require.NoError(t, os.Mkdir(ralphDir, 0755))
+
+	configContent := `backup:
+  enabled: true
+`
+	configPath := filepath.Join(ralphDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	t.Chdir(tmpDir)
+
+	config, err := LoadConfig()
+	require.NoError(t, err, "LoadConfig() unexpected error")
+
+	assert.True(t, config.Backup.Enabled)
+	assert.Equal(t, DefaultBackupKeep, config.Backup.Keep)
+	assert.Equal(t, filepath.Join(config.ConfigDir, "backups"), config.BackupDir())
+}
+
 func TestApplyDefaults_ServiceTimeout(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -329,18 +351,37 @@ func TestApplyDefaults_ServiceTimeout(t *testing.T) {
 	assert.Equal(t, 30, config.Services[1].Timeout)
 }
 
+func TestApplyDefaults_GitTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ralphDir := filepath.Join(tmpDir, ".ralph")
+	require.NoError(t, os.Mkdir(ralphDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(ralphDir, "config.yaml"), []byte("model: test\n"), 0644))
+
+	t.Chdir(tmpDir)
+
+	config, err := LoadConfig()
+	require.NoError(t, err, "LoadConfig() unexpected error")
+
+	assert.Equal(t, DefaultGitTimeout, config.GitTimeout)
+	assert.Equal(t, DefaultSubmitAttempts, config.Workflow.SubmitAttempts)
+}
+
 func TestApplyDefaults_DoesNotOverwriteNonZeroValues(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 5
+	configContent := `extraIterations: 5
 defaultBranch: develop
 model: anthropic/claude-3-sonnet
 app:
   name: my-app
   id: 1234567
+gitTimeout: 300
+workflow:
+  submitAttempts: 5
 services:
   - name: svc1
     command: echo
@@ -358,6 +399,8 @@ services:
 	assert.Equal(t, "anthropic/claude-3-sonnet", config.Model)
 	assert.Equal(t, "my-app", config.App.Name)
 	assert.Equal(t, "1234567", config.App.ID)
+	assert.Equal(t, 300, config.GitTimeout)
+	assert.Equal(t, 5, config.Workflow.SubmitAttempts)
 	assert.Equal(t, 60, config.Services[0].Timeout)
 }
 
@@ -367,7 +410,7 @@ func TestLoadConfig_CommentInstructionsFromFile(t *testing.T) {
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 3
+	configContent := `extraIterations: 3
  `
 	configPath := filepath.Join(ralphDir, "config.yaml")
 	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
@@ -390,7 +433,7 @@ func TestLoadConfig_MergeInstructionsFromFile(t *testing.T) {
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 3
+	configContent := `extraIterations: 3
 `
 	configPath := filepath.Join(ralphDir, "config.yaml")
 	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
@@ -413,7 +456,7 @@ func TestLoadConfig_DefaultInstructionsWhenFilesNotExist(t *testing.T) {
 	ralphDir := filepath.Join(tmpDir, ".ralph")
 	require.NoError(t, os.Mkdir(ralphDir, 0755))
 
-	configContent := `maxIterations: 3
+	configContent := `extraIterations: 3
  `
 	configPath := filepath.Join(ralphDir, "config.yaml")
 	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
@@ -446,6 +489,63 @@ func TestLoadConfig_InvalidYAML(t *testing.T) {
 	require.Error(t, err, "LoadConfig() expected error for invalid YAML")
 }
 
+func TestLoadConfig_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		allowUnknown  bool
+		wantErr       bool
+		errorContains string
+	}{
+		{
+			name:          "typo'd key is rejected",
+			content:       "maxIteration: 5\n",
+			wantErr:       true,
+			errorContains: "field maxIteration not found",
+		},
+		{
+			name:          "typo'd nested key is rejected",
+			content:       "workflow:\n  namespaec: argo\n",
+			wantErr:       true,
+			errorContains: "field namespaec not found",
+		},
+		{
+			name:    "valid config loads",
+			content: "defaultBranch: develop\nworkflow:\n  namespace: argo\n",
+		},
+		{
+			name:         "typo'd key is ignored with opt-out",
+			content:      "maxIteration: 5\ndefaultBranch: develop\n",
+			allowUnknown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			if tt.allowUnknown {
+				t.Setenv(AllowUnknownFieldsEnv, "true")
+			}
+
+			tmpDir := t.TempDir()
+			ralphDir := filepath.Join(tmpDir, ".ralph")
+			require.NoError(t, os.Mkdir(ralphDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(ralphDir, "config.yaml"), []byte(tt.content), 0644))
+			t.Chdir(tmpDir)
+
+			cfg, err := LoadConfig()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.Contains(t, err.Error(), AllowUnknownFieldsEnv)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "develop", cfg.DefaultBranch)
+		})
+	}
+}
+
 func TestFindConfigDir(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -474,6 +574,253 @@ func TestFindConfigDir(t *testing.T) {
 	assert.ErrorIs(t, err, os.ErrNotExist)
 }
 
+func TestValidateWorkflowConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *WorkflowConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid references",
+			config: &WorkflowConfig{
+				EnvFrom: []EnvFromSource{{Secret: "app-env"}, {ConfigMap: "app-settings"}},
+				EnvRefs: []EnvRef{{Name: "API_TOKEN", Secret: "api", Key: "token"}, {Name: "REGION", ConfigMap: "app-settings", Key: "region"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "valid clone strategy",
+			config:  &WorkflowConfig{CloneStrategy: CloneStrategyBranchOnly},
+			wantErr: false,
+		},
+		{
+			name:    "invalid clone strategy",
+			config:  &WorkflowConfig{CloneStrategy: "shallow"},
+			wantErr: true,
+			errMsg:  `workflow has invalid cloneStrategy "shallow"`,
+		},
+		{
+			name:    "valid image digest",
+			config:  &WorkflowConfig{Image: ImageConfig{Repository: "my-registry/ralph", Digest: "sha256:" + strings.Repeat("a1", 32)}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid image digest",
+			config:  &WorkflowConfig{Image: ImageConfig{Digest: "sha256:abc"}},
+			wantErr: true,
+			errMsg:  `workflow image has invalid digest "sha256:abc"`,
+		},
+		{
+			name:    "valid image pull policy",
+			config:  &WorkflowConfig{ImagePullPolicy: "IfNotPresent"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid image pull policy",
+			config:  &WorkflowConfig{ImagePullPolicy: "Sometimes"},
+			wantErr: true,
+			errMsg:  `workflow has invalid imagePullPolicy "Sometimes"`,
+		},
+		{
+			name:    "negative submit attempts",
+			config:  &WorkflowConfig{SubmitAttempts: -1},
+			wantErr: true,
+			errMsg:  "workflow has invalid submitAttempts -1; it must be at least 1",
+		},
+		{
+			name:    "envFrom without source",
+			config:  &WorkflowConfig{EnvFrom: []EnvFromSource{{}}},
+			wantErr: true,
+			errMsg:  "envFrom entry 0 must set exactly one of secret or configMap",
+		},
+		{
+			name:    "envFrom with both sources",
+			config:  &WorkflowConfig{EnvFrom: []EnvFromSource{{Secret: "a", ConfigMap: "b"}}},
+			wantErr: true,
+			errMsg:  "envFrom entry 0 must set exactly one of secret or configMap",
+		},
+		{
+			name:    "envRef without name",
+			config:  &WorkflowConfig{EnvRefs: []EnvRef{{Secret: "api", Key: "token"}}},
+			wantErr: true,
+			errMsg:  "envRefs entry 0 must have a name",
+		},
+		{
+			name:    "envRef without source",
+			config:  &WorkflowConfig{EnvRefs: []EnvRef{{Name: "API_TOKEN", Key: "token"}}},
+			wantErr: true,
+			errMsg:  `envRefs entry "API_TOKEN" must set exactly one of secret or configMap`,
+		},
+		{
+			name:    "envRef without key",
+			config:  &WorkflowConfig{EnvRefs: []EnvRef{{Name: "API_TOKEN", Secret: "api"}}},
+			wantErr: true,
+			errMsg:  `envRefs entry "API_TOKEN" must have a key`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &RalphConfig{Workflow: *tt.config}
+			applyDefaults(cfg)
+			err := ValidateWorkflowConfig(&cfg.Workflow)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("zero submit attempts without defaults", func(t *testing.T) {
+		err := ValidateWorkflowConfig(&WorkflowConfig{})
+		assert.EqualError(t, err, "workflow has invalid submitAttempts 0; it must be at least 1")
+	})
+}
+
+func TestValidateUpstreamConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *UpstreamConfig
+		wantErr string
+	}{
+		{name: "unset", config: &UpstreamConfig{}},
+		{name: "repo and base", config: &UpstreamConfig{Repo: "upstream-org/repo", Base: "develop"}},
+		{name: "repo without owner", config: &UpstreamConfig{Repo: "repo"}, wantErr: `upstream repo "repo" must be written as owner/name`},
+		{name: "repo with extra path", config: &UpstreamConfig{Repo: "org/repo/extra"}, wantErr: `upstream repo "org/repo/extra" must be written as owner/name`},
+		{name: "base without repo", config: &UpstreamConfig{Base: "develop"}, wantErr: `upstream base "develop" requires upstream repo`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpstreamConfig(tt.config)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGuardrailConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *GuardrailConfig
+		wantErr string
+	}{
+		{name: "unset", config: &GuardrailConfig{}},
+		{name: "limits with warn", config: &GuardrailConfig{MaxFiles: 20, MaxLines: 1000, Action: GuardrailWarn}},
+		{name: "negative files", config: &GuardrailConfig{MaxFiles: -1}, wantErr: "guardrails have invalid maxFiles -1"},
+		{name: "negative lines", config: &GuardrailConfig{MaxLines: -5}, wantErr: "guardrails have invalid maxLines -5"},
+		{name: "unknown action", config: &GuardrailConfig{MaxFiles: 20, Action: "stop"}, wantErr: `guardrails have invalid action "stop"; valid actions are: abort, warn`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGuardrailConfig(tt.config)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGuardrailConfigExceeded(t *testing.T) {
+	g := &GuardrailConfig{MaxFiles: 20, MaxLines: 1000}
+	assert.True(t, g.Enabled())
+	assert.Empty(t, g.Exceeded(20, 1000), "the limits themselves are allowed")
+	assert.Equal(t, "changed 21 files, more than the guardrail of 20", g.Exceeded(21, 10))
+	assert.Equal(t, "changed 1001 lines, more than the guardrail of 1000", g.Exceeded(3, 1001))
+
+	unlimited := &GuardrailConfig{}
+	assert.False(t, unlimited.Enabled())
+	assert.Empty(t, unlimited.Exceeded(500, 100000))
+}
+
+func TestPullRequestConfigTitle(t *testing.T) {
+	data := PRTitleData{Project: "Add login", Slug: "add-login", Branch: "add-login", PassingCount: 3, FailingCount: 1}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "unset", template: "", want: "Add login"},
+		{name: "fields", template: "[ralph] {{.Project}} ({{.Branch}}, {{.PassingCount}} passing)", want: "[ralph] Add login (add-login, 3 passing)"},
+		{name: "conditional", template: "{{if .FailingCount}}WIP: {{end}}{{.Slug}}", want: "WIP: add-login"},
+		{name: "collapses whitespace", template: "{{.Project}}\n  {{.Branch}} ", want: "Add login add-login"},
+		{name: "blank falls back", template: "{{if false}}x{{end}}", want: "Add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PullRequestConfig{TitleTemplate: tt.template}
+			title, err := p.Title(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, title)
+		})
+	}
+}
+
+func TestValidatePullRequestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *PullRequestConfig
+		wantErr string
+	}{
+		{name: "unset", config: &PullRequestConfig{}},
+		{name: "known fields", config: &PullRequestConfig{TitleTemplate: "[ralph] {{.Project}}: {{.PassingCount}} passing"}},
+		{name: "parse error", config: &PullRequestConfig{TitleTemplate: "{{.Project"}, wantErr: `pull request titleTemplate "{{.Project" is invalid`},
+		{name: "unknown field", config: &PullRequestConfig{TitleTemplate: "{{.Summary}}"}, wantErr: "can't evaluate field Summary"},
+		{name: "team reviewers", config: &PullRequestConfig{Reviewers: []string{"alice"}, TeamReviewers: []string{"acme/platform", "@acme/security", ""}}},
+		{name: "team without org", config: &PullRequestConfig{TeamReviewers: []string{"platform"}}, wantErr: `pull request teamReviewers entry "platform" must be written as org/team`},
+		{name: "team with extra path", config: &PullRequestConfig{TeamReviewers: []string{"acme/platform/web"}}, wantErr: "must be written as org/team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePullRequestConfig(tt.config)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		agent   string
+		wantErr string
+	}{
+		{name: "unset", agent: ""},
+		{name: "opencode", agent: "opencode"},
+		{name: "unknown", agent: "aider", wantErr: `unknown agent "aider"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgent(tt.agent)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateReviewConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -738,6 +1085,115 @@ model: anthropic/claude-3-sonnet`
 	})
 }
 
+func TestLoadConfig_GlobalConfig(t *testing.T) {
+	writeGlobal := func(t *testing.T, content string) string {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+		globalDir := filepath.Join(configHome, "ralph")
+		require.NoError(t, os.MkdirAll(globalDir, 0755))
+		globalPath := filepath.Join(globalDir, "config.yaml")
+		require.NoError(t, os.WriteFile(globalPath, []byte(content), 0644))
+		return globalPath
+	}
+
+	writeRepo := func(t *testing.T, content string) string {
+		tmpDir := t.TempDir()
+		ralphDir := filepath.Join(tmpDir, ".ralph")
+		require.NoError(t, os.Mkdir(ralphDir, 0755))
+		repoPath := filepath.Join(ralphDir, "config.yaml")
+		if content != "" {
+			require.NoError(t, os.WriteFile(repoPath, []byte(content), 0644))
+		}
+		t.Chdir(tmpDir)
+		return repoPath
+	}
+
+	t.Run("repo values win and global fills gaps", func(t *testing.T) {
+		globalPath := writeGlobal(t, `model: global/model
+workflow:
+  image:
+    repository: ghcr.io/global/ralph
+    tag: v1.0.0
+  context: global-cluster
+  namespace: global-ns
+  env:
+    SHARED: global
+    GLOBAL_ONLY: "1"
+`)
+		repoPath := writeRepo(t, `workflow:
+  namespace: repo-ns
+  image:
+    tag: v2.0.0
+  env:
+    SHARED: repo
+`)
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, repoPath, cfg.ConfigPath)
+		assert.Equal(t, globalPath, cfg.GlobalConfigPath)
+		assert.Equal(t, "global/model", cfg.Model)
+		assert.Equal(t, "global-cluster", cfg.Workflow.Context)
+		assert.Equal(t, "repo-ns", cfg.Workflow.Namespace)
+		assert.Equal(t, "ghcr.io/global/ralph", cfg.Workflow.Image.Repository)
+		assert.Equal(t, "v2.0.0", cfg.Workflow.Image.Tag)
+		assert.Equal(t, map[string]string{"SHARED": "repo", "GLOBAL_ONLY": "1"}, cfg.Workflow.Env)
+	})
+
+	t.Run("global only leaves repo config path empty", func(t *testing.T) {
+		globalPath := writeGlobal(t, `workflow:
+  context: global-cluster
+`)
+		writeRepo(t, "")
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, "", cfg.ConfigPath)
+		assert.Equal(t, globalPath, cfg.GlobalConfigPath)
+		assert.Equal(t, "global-cluster", cfg.Workflow.Context)
+	})
+
+	t.Run("missing global config is ignored", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		writeRepo(t, `defaultBranch: develop
+`)
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, "", cfg.GlobalConfigPath)
+		assert.Equal(t, "develop", cfg.DefaultBranch)
+	})
+
+	t.Run("invalid global config returns error", func(t *testing.T) {
+		writeGlobal(t, `model: [invalid`)
+		writeRepo(t, "")
+
+		_, err := LoadConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "global config")
+	})
+}
+
+func TestGlobalConfigPath(t *testing.T) {
+	t.Run("uses XDG_CONFIG_HOME when set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+		path, err := GlobalConfigPath()
+		require.NoError(t, err)
+		assert.Equal(t, "/tmp/xdg/ralph/config.yaml", path)
+	})
+
+	t.Run("falls back to home directory", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", "/tmp/home")
+		path, err := GlobalConfigPath()
+		require.NoError(t, err)
+		assert.Equal(t, "/tmp/home/.config/ralph/config.yaml", path)
+	})
+}
+
 func TestConfigExtraIterationsOmittedWhenNil(t *testing.T) {
 	cfg := &RalphConfig{}
 	out, err := yaml.Marshal(cfg)
@@ -22,3 +22,9 @@ func WithExtraIterations(n int) *RalphConfig {
 	cfg.ExtraIterations = &v
 	return cfg
 }
+
+func WithRequirementAttempts(n int) *RalphConfig {
+	cfg := Any()
+	cfg.RequirementAttempts = n
+	return cfg
+}
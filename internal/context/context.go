@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/zon/ralph/internal/output"
 )
@@ -21,19 +22,32 @@ type Context struct {
 	workflowExecution bool
 	repoOwner         string
 	repoName          string
-	notes             []string // Runtime notes to pass to the agent
-	instructions      string   // Path to an instructions file that overrides the default instructions
-	instructionsMD    string   // Inline instructions content; overrides .ralph/instructions.md when set
-	branch            string   // Branch override; skips local git GetCurrentBranch + sync check
-	debugBranch       string   // When set, workflows checkout this ralph repo branch and invoke ralph via `go run`
-	baseBranch        string   // Base branch override; overrides baseBranch from .ralph/config.yaml for PR creation
-	botName           string   // Git user name for automated commits
-	botEmail          string   // Git user email for automated commits
-	model             string   // Model override; overrides model from .ralph/config.yaml
-	variant           string   // Variant override; overrides variant from .ralph/config.yaml
-	kubeContext       string   // Kubernetes context override; overrides workflow.context from .ralph/config.yaml
-	filter            string   // Filter string for reviewing specific items
-	command           []string // Command tokens for the command subcommand
+	notes             []string          // Runtime notes to pass to the agent
+	instructions      string            // Path to an instructions file that overrides the default instructions
+	instructionsMD    string            // Inline instructions content; overrides .ralph/instructions.md when set
+	branch            string            // Branch override; skips local git GetCurrentBranch + sync check
+	debugBranch       string            // When set, workflows checkout this ralph repo branch and invoke ralph via `go run`
+	baseBranch        string            // Base branch override; overrides baseBranch from .ralph/config.yaml for PR creation
+	botName           string            // Git user name for automated commits
+	botEmail          string            // Git user email for automated commits
+	model             string            // Model override; overrides model from .ralph/config.yaml
+	variant           string            // Variant override; overrides variant from .ralph/config.yaml
+	kubeContext       string            // Kubernetes context override; overrides workflow.context from .ralph/config.yaml
+	kubeNamespace     string            // Kubernetes namespace override; overrides workflow.namespace from .ralph/config.yaml
+	image             string            // Workflow image reference override; overrides workflow.image from .ralph/config.yaml
+	filter            string            // Filter string for reviewing specific items
+	command           []string          // Command tokens for the command subcommand
+	actor             string            // Who or what triggered the run, e.g. "cli:alice" or "webhook:octocat"
+	summaryPath       string            // Path to write a JSON run summary to after a local run
+	eventsPath        string            // Path to append newline-delimited JSON run events to during a local run
+	forcePush         bool              // Push iteration commits with --force-with-lease instead of pulling first
+	allowBasePush     bool              // Allow pushing iteration commits while the base branch is checked out
+	params            map[string]string // Custom workflow parameters passed through to the container
+	keepFailed        bool              // Keep the pods of failed workflows for post-mortem debugging
+	noPush            bool              // Commit iterations locally without pushing or opening a pull request
+	squashBeforePR    bool              // Squash the project branch into one commit before the pull request is created
+	offline           bool              // Skip every git remote check and fetch, working only on local state
+	stream            bool              // Relay agent output line by line under iteration headers during a local run
 }
 
 // NewContext creates a new Context with a background standard context.
@@ -101,6 +115,11 @@ func (c *Context) AddNote(note string) {
 	c.notes = append(c.notes, note)
 }
 
+// ClearNotes drops the notes gathered so far, once the prompts they were meant for have run.
+func (c *Context) ClearNotes() {
+	c.notes = nil
+}
+
 func (c *Context) HasNotes() bool {
 	return len(c.notes) > 0
 }
@@ -225,6 +244,22 @@ func (c *Context) KubeContext() string {
 	return c.kubeContext
 }
 
+func (c *Context) SetKubeNamespace(namespace string) {
+	c.kubeNamespace = namespace
+}
+
+func (c *Context) KubeNamespace() string {
+	return c.kubeNamespace
+}
+
+func (c *Context) SetImage(image string) {
+	c.image = image
+}
+
+func (c *Context) Image() string {
+	return c.image
+}
+
 func (c *Context) SetFilter(filter string) {
 	c.filter = filter
 }
@@ -241,6 +276,120 @@ func (c *Context) Command() []string {
 	return c.command
 }
 
+func (c *Context) SetActor(actor string) {
+	c.actor = actor
+}
+
+func (c *Context) Actor() string {
+	return c.actor
+}
+
+// WebhookLogin returns the GitHub login whose webhook event triggered the run, or "" for runs
+// started from the command line or by a trigger request.
+func (c *Context) WebhookLogin() string {
+	login, ok := strings.CutPrefix(c.actor, "webhook:")
+	if !ok {
+		return ""
+	}
+	return login
+}
+
+func (c *Context) SetSummaryPath(summaryPath string) {
+	c.summaryPath = summaryPath
+}
+
+func (c *Context) SummaryPath() string {
+	return c.summaryPath
+}
+
+func (c *Context) SetEventsPath(eventsPath string) {
+	c.eventsPath = eventsPath
+}
+
+func (c *Context) EventsPath() string {
+	return c.eventsPath
+}
+
+func (c *Context) SetForcePush(forcePush bool) {
+	c.forcePush = forcePush
+}
+
+func (c *Context) IsForcePush() bool {
+	return c.forcePush
+}
+
+func (c *Context) SetAllowBasePush(allowBasePush bool) {
+	c.allowBasePush = allowBasePush
+}
+
+func (c *Context) IsAllowBasePush() bool {
+	return c.allowBasePush
+}
+
+func (c *Context) SetParams(params map[string]string) {
+	c.params = params
+}
+
+func (c *Context) Params() map[string]string {
+	return c.params
+}
+
+func (c *Context) SetKeepFailed(keepFailed bool) {
+	c.keepFailed = keepFailed
+}
+
+func (c *Context) IsKeepFailed() bool {
+	return c.keepFailed
+}
+
+func (c *Context) SetNoPush(noPush bool) {
+	c.noPush = noPush
+}
+
+func (c *Context) IsNoPush() bool {
+	return c.noPush
+}
+
+func (c *Context) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+func (c *Context) IsOffline() bool {
+	return c.offline
+}
+
+func (c *Context) SetSquashBeforePR(squash bool) {
+	c.squashBeforePR = squash
+}
+
+func (c *Context) IsSquashBeforePR() bool {
+	return c.squashBeforePR
+}
+
+func (c *Context) SetStream(stream bool) {
+	c.stream = stream
+}
+
+func (c *Context) ShouldStream() bool {
+	return c.stream
+}
+
+// LocalActor returns the actor for a run started from the command line by the current user.
+func LocalActor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return "cli:" + user
+	}
+	return "cli"
+}
+
+// WebhookActor returns the actor for a run triggered by a GitHub webhook event from login.
+func WebhookActor(login string) string {
+	if login == "" {
+		return "webhook"
+	}
+	return "webhook:" + login
+}
+
 func NewContextFromEnv() *Context {
 	ctx := NewContext()
 
@@ -270,6 +419,9 @@ func NewContextFromEnv() *Context {
 	if val := os.Getenv("INSTRUCTIONS_MD"); val != "" {
 		ctx.SetInstructionsMD(val)
 	}
+	if val := os.Getenv("RALPH_ACTOR"); val != "" {
+		ctx.SetActor(val)
+	}
 
 	return ctx
 }
@@ -312,6 +312,7 @@ func TestNewContextFromEnv(t *testing.T) {
 		"RALPH_NO_SERVICES":        "true",
 		"RALPH_DEBUG_BRANCH":       "debug-branch",
 		"INSTRUCTIONS_MD":          "# Test Instructions",
+		"RALPH_ACTOR":              "webhook:octocat",
 	}
 
 	for key, val := range envVars {
@@ -331,6 +332,7 @@ func TestNewContextFromEnv(t *testing.T) {
 	assert.True(t, ctx.NoServices(), "noServices should be true")
 	assert.Equal(t, "debug-branch", ctx.DebugBranch(), "debug branch should match")
 	assert.Equal(t, "# Test Instructions", ctx.InstructionsMD(), "instructionsMD should match")
+	assert.Equal(t, "webhook:octocat", ctx.Actor(), "actor should match")
 }
 
 func TestNewContextFromEnvEmpty(t *testing.T) {
@@ -344,6 +346,7 @@ func TestNewContextFromEnvEmpty(t *testing.T) {
 		"RALPH_NO_SERVICES",
 		"RALPH_DEBUG_BRANCH",
 		"INSTRUCTIONS_MD",
+		"RALPH_ACTOR",
 	}
 
 	for _, key := range envVars {
@@ -363,6 +366,31 @@ func TestNewContextFromEnvEmpty(t *testing.T) {
 	assert.False(t, ctx.NoServices(), "noServices should be false")
 	assert.Empty(t, ctx.DebugBranch(), "debug branch should be empty")
 	assert.Empty(t, ctx.InstructionsMD(), "instructionsMD should be empty")
+	assert.Empty(t, ctx.Actor(), "actor should be empty")
+}
+
+func TestActorHelpers(t *testing.T) {
+	t.Setenv("USER", "alice")
+	assert.Equal(t, "cli:alice", LocalActor())
+	t.Setenv("USER", "")
+	assert.Equal(t, "cli", LocalActor())
+
+	assert.Equal(t, "webhook:octocat", WebhookActor("octocat"))
+	assert.Equal(t, "webhook", WebhookActor(""))
+}
+
+func TestWebhookLogin(t *testing.T) {
+	ctx := NewContext()
+	assert.Empty(t, ctx.WebhookLogin(), "no actor")
+
+	ctx.SetActor(WebhookActor("octocat"))
+	assert.Equal(t, "octocat", ctx.WebhookLogin())
+
+	ctx.SetActor(WebhookActor(""))
+	assert.Empty(t, ctx.WebhookLogin(), "trigger requests have no login")
+
+	ctx.SetActor("cli:alice")
+	assert.Empty(t, ctx.WebhookLogin(), "command line runs have no login")
 }
 
 func TestCommand(t *testing.T) {
@@ -0,0 +1,53 @@
+package execrun
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Runner runs external commands. Packages that shell out take a Runner so their argument
+// construction can be tested with a MockRunner instead of real binaries.
+type Runner interface {
+	// Run runs name with args and returns what it wrote to stdout and stderr
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr string, err error)
+	// RunInput runs name with args, feeding input to its stdin
+	RunInput(ctx context.Context, input, name string, args ...string) (stdout, stderr string, err error)
+	// LookPath reports an error when name cannot be found on the PATH
+	LookPath(name string) error
+}
+
+// Exec is the Runner that starts real processes.
+type Exec struct {
+	// WaitDelay bounds how long a cancelled command may keep its output open (see exec.Cmd.WaitDelay)
+	WaitDelay time.Duration
+}
+
+var _ Runner = Exec{}
+
+func (e Exec) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	return e.run(ctx, nil, name, args)
+}
+
+func (e Exec) RunInput(ctx context.Context, input, name string, args ...string) (string, string, error) {
+	return e.run(ctx, strings.NewReader(input), name, args)
+}
+
+func (e Exec) LookPath(name string) error {
+	_, err := exec.LookPath(name)
+	return err
+}
+
+func (e Exec) run(ctx context.Context, stdin io.Reader, name string, args []string) (string, string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = e.WaitDelay
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,61 @@
+package execrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Call is one command recorded by MockRunner.
+type Call struct {
+	Name  string
+	Args  []string
+	Input string
+}
+
+// MockRunner records every command instead of running it. RunFunc, when set, supplies the
+// result of each call; otherwise calls succeed with no output.
+type MockRunner struct {
+	RunFunc func(call Call) (stdout, stderr string, err error)
+	// Missing lists the commands LookPath reports as not installed
+	Missing []string
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ Runner = (*MockRunner)(nil)
+
+func (m *MockRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	return m.record(Call{Name: name, Args: args})
+}
+
+func (m *MockRunner) RunInput(ctx context.Context, input, name string, args ...string) (string, string, error) {
+	return m.record(Call{Name: name, Args: args, Input: input})
+}
+
+func (m *MockRunner) LookPath(name string) error {
+	for _, missing := range m.Missing {
+		if missing == name {
+			return fmt.Errorf("exec: %q: executable file not found in $PATH", name)
+		}
+	}
+	return nil
+}
+
+// Calls returns the commands run so far, in order.
+func (m *MockRunner) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *MockRunner) record(call Call) (string, string, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	m.mu.Unlock()
+	if m.RunFunc != nil {
+		return m.RunFunc(call)
+	}
+	return "", "", nil
+}
@@ -0,0 +1,54 @@
+package execrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecRun(t *testing.T) {
+	t.Run("separates stdout and stderr", func(t *testing.T) {
+		stdout, stderr, err := Exec{}.Run(context.Background(), "sh", "-c", "echo out; echo err >&2")
+		require.NoError(t, err)
+		assert.Equal(t, "out\n", stdout)
+		assert.Equal(t, "err\n", stderr)
+	})
+
+	t.Run("returns the exit error with output", func(t *testing.T) {
+		_, stderr, err := Exec{}.Run(context.Background(), "sh", "-c", "echo failed >&2; exit 3")
+		var exitErr interface{ ExitCode() int }
+		require.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, 3, exitErr.ExitCode())
+		assert.Equal(t, "failed\n", stderr)
+	})
+
+	t.Run("feeds input to stdin", func(t *testing.T) {
+		stdout, _, err := Exec{}.RunInput(context.Background(), "hello", "cat")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", stdout)
+	})
+}
+
+func TestExecLookPath(t *testing.T) {
+	assert.NoError(t, Exec{}.LookPath("sh"))
+	assert.Error(t, Exec{}.LookPath("ralph-no-such-binary"))
+}
+
+func TestMockRunner(t *testing.T) {
+	m := &MockRunner{
+		RunFunc: func(call Call) (string, string, error) {
+			return "ran " + call.Name, "", nil
+		},
+		Missing: []string{"argo"},
+	}
+
+	stdout, _, err := m.RunInput(context.Background(), "yaml", "argo", "submit", "-")
+	require.NoError(t, err)
+	assert.Equal(t, "ran argo", stdout)
+	assert.Equal(t, []Call{{Name: "argo", Args: []string{"submit", "-"}, Input: "yaml"}}, m.Calls())
+	assert.Error(t, m.LookPath("argo"))
+	assert.NoError(t, m.LookPath("git"))
+}
@@ -1,12 +1,16 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/zon/ralph/internal/context"
 )
 
+// ErrDetachedHead is returned when an operation needs a checked out branch but HEAD is detached.
+var ErrDetachedHead = errors.New("repository is in detached HEAD state")
+
 // GetCurrentBranch returns the name of the current git branch
 // Returns error if in detached HEAD state
 func GetCurrentBranch() (string, error) {
@@ -20,7 +24,7 @@ func GetCurrentBranch() (string, error) {
 	}
 
 	if branch == "HEAD" {
-		return "", fmt.Errorf("repository is in detached HEAD state, please checkout a branch first")
+		return "", fmt.Errorf("%w, please checkout a branch first", ErrDetachedHead)
 	}
 
 	return branch, nil
@@ -43,6 +47,15 @@ func CheckoutOrCreateBranch(name string) error {
 	return nil
 }
 
+// checkoutOrCreateLocalBranch checks out the named branch if it exists locally, otherwise creates
+// it, without consulting the remote.
+func checkoutOrCreateLocalBranch(name string) error {
+	if _, err := runGit("rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+		return checkoutBranch(name)
+	}
+	return CreateBranch(name)
+}
+
 // CheckoutBranch switches to the specified git branch
 func CheckoutBranch(name string) error {
 	_, err := runGit("checkout", name)
@@ -93,13 +106,21 @@ func IsBranchSyncedWithRemote(branch string) error {
 	return nil
 }
 
+// lsRemoteNoMatch is the exit code of `git ls-remote --exit-code` when no ref matches
+const lsRemoteNoMatch = 2
+
 // RemoteBranchExists checks whether a branch exists on the remote.
+// A missing branch is reported as false; failing to reach the remote is an error.
 func RemoteBranchExists(branch string) (bool, error) {
-	_, err := runGit("ls-remote", "--exit-code", "--heads", "origin", branch)
-	if err != nil {
+	_, err := runRemoteGit("ls-remote", "--exit-code", "--heads", "origin", branch)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == lsRemoteNoMatch {
 		return false, nil
 	}
-	return true, nil
+	return false, fmt.Errorf("failed to check remote branch '%s': %w", branch, err)
 }
 
 // remoteBranchExists checks whether a branch exists on the remote.
@@ -167,18 +188,25 @@ func ValidateGitStateAndSwitchBranch(ctx *context.Context, branchName string) er
 }
 
 func validateBranchSync(ctx *context.Context, currentBranch string) error {
-	if !ctx.IsWorkflowExecution() {
+	if ctx.IsWorkflowExecution() {
+		ctx.Output().Debugf("Skipping remote sync check (running in workflow container)")
+	} else if ctx.IsOffline() {
+		ctx.Output().Debugf("Skipping remote sync check (--offline)")
+	} else {
 		ctx.Output().Debugf("Checking branch '%s' is in sync with remote...", currentBranch)
 		if err := IsBranchSyncedWithRemote(currentBranch); err != nil {
 			return err
 		}
-	} else {
-		ctx.Output().Debugf("Skipping remote sync check (running in workflow container)")
 	}
 	return nil
 }
 
 func SwitchToProjectBranch(ctx *context.Context, branchName string) error {
+	if ctx.IsOffline() {
+		ctx.Output().Debugf("Skipping fetch (--offline)")
+		return checkoutOrCreateLocalBranch(branchName)
+	}
+
 	var auth *AuthConfig
 	if ctx.IsWorkflowExecution() {
 		owner, repo := ctx.RepoOwnerAndName()
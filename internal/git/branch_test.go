@@ -1,14 +1,19 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/zon/ralph/internal/context"
+	"github.com/zon/ralph/internal/execrun"
 	"github.com/zon/ralph/internal/output"
 )
 
@@ -104,8 +109,8 @@ func TestGetCurrentBranch_DetachedHead(t *testing.T) {
 	_, err = GetCurrentBranch()
 	require.Error(t, err, "Expected GetCurrentBranch to return error in detached HEAD state")
 
-	expectedMsg := "detached HEAD state"
-	assert.Contains(t, err.Error(), expectedMsg, "Expected error containing '%s', got: %v", expectedMsg, err)
+	assert.ErrorIs(t, err, ErrDetachedHead)
+	assert.Contains(t, err.Error(), "please checkout a branch first")
 }
 
 func TestCheckoutOrCreateBranch_CreateNew(t *testing.T) {
@@ -173,6 +178,47 @@ func TestCheckoutOrCreateBranch_ExistingRemoteBranch(t *testing.T) {
 	assert.Equal(t, branchName, currentBranch)
 }
 
+// exitStatus fakes the exit code of a git process run through a mock runner
+type exitStatus int
+
+func (e exitStatus) Error() string { return "exit status " + strconv.Itoa(int(e)) }
+func (e exitStatus) ExitCode() int { return int(e) }
+
+func TestRemoteBranchExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    bool
+		wantErr string
+	}{
+		{name: "branch on the remote", want: true},
+		{name: "branch missing from the remote", err: exitStatus(2)},
+		{name: "remote unreachable", err: exitStatus(128), wantErr: "failed to check remote branch 'main'"},
+		{name: "runner failure", err: errors.New("git not found"), wantErr: "failed to check remote branch 'main'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockGitRunner(t, func(call execrun.Call) (string, string, error) {
+				if tt.err != nil {
+					return "", "", tt.err
+				}
+				return "abc123\trefs/heads/main\n", "", nil
+			})
+
+			exists, err := RemoteBranchExists("main")
+			assert.Equal(t, []execrun.Call{{Name: "git", Args: []string{"ls-remote", "--exit-code", "--heads", "origin", "main"}}}, m.Calls())
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, exists)
+		})
+	}
+}
+
 func TestIsBranchSyncedWithRemote_Synced(t *testing.T) {
 	workDir, _ := setupBareRemoteRepo(t)
 	t.Chdir(workDir)
@@ -326,3 +372,85 @@ func TestSwitchToProjectBranch_ExitingRemoteBranch(t *testing.T) {
 	require.NoError(t, err, "GetCurrentBranch failed")
 	assert.Equal(t, branchName, currentBranch, "Should be on the remote branch")
 }
+
+// traceGit puts a git on the PATH that logs each command before running the real git, and
+// returns a function reporting the commands logged so far.
+func traceGit(t *testing.T) func() []string {
+	t.Helper()
+	realGit, err := exec.LookPath("git")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	log := filepath.Join(dir, "git.log")
+	script := "#!/bin/sh\necho \"$*\" >> " + log + "\nexec " + realGit + " \"$@\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return func() []string {
+		data, err := os.ReadFile(log)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+func TestValidateGitStateAndSwitchBranch_Offline(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool
+	}{
+		{name: "creates the branch locally"},
+		{name: "checks out an existing local branch", existing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir, _ := setupBareRemoteRepo(t)
+			t.Chdir(workDir)
+			branchName := "offline-branch"
+			if tt.existing {
+				out, err := exec.Command("git", "branch", branchName).CombinedOutput()
+				require.NoError(t, err, "git branch: %s", out)
+			}
+			// An unpushed commit would fail the remote sync check
+			require.NoError(t, os.WriteFile("local.txt", []byte("local\n"), 0644))
+			for _, args := range [][]string{{"add", "."}, {"commit", "-m", "local only"}} {
+				out, err := exec.Command("git", args...).CombinedOutput()
+				require.NoError(t, err, "git %v: %s", args, out)
+			}
+			commands := traceGit(t)
+
+			ctx := context.NewContext()
+			ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+			ctx.SetLocal(true)
+			ctx.SetOffline(true)
+
+			require.NoError(t, ValidateGitStateAndSwitchBranch(ctx, branchName))
+
+			current, err := GetCurrentBranch()
+			require.NoError(t, err)
+			assert.Equal(t, branchName, current)
+			for _, command := range commands() {
+				assert.NotContains(t, command, "fetch", "offline mode must not fetch")
+				assert.NotContains(t, command, "ls-remote", "offline mode must not query the remote")
+				assert.NotContains(t, command, "origin/", "offline mode must not check remote sync")
+			}
+		})
+	}
+}
+
+func TestValidateGitStateAndSwitchBranch_OnlineChecksRemote(t *testing.T) {
+	workDir, _ := setupBareRemoteRepo(t)
+	t.Chdir(workDir)
+	commands := traceGit(t)
+
+	ctx := context.NewContext()
+	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
+	ctx.SetLocal(true)
+
+	require.NoError(t, ValidateGitStateAndSwitchBranch(ctx, "online-branch"))
+
+	joined := strings.Join(commands(), "\n")
+	assert.Contains(t, joined, "fetch origin")
+	assert.Contains(t, joined, "ls-remote")
+}
@@ -4,18 +4,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 )
 
 type Client struct {
-	ctx *context.Context
+	ctx        *context.Context
+	baseBranch string
+	cfg        *config.RalphConfig
 }
 
 func NewClient(ctx *context.Context) *Client {
 	return &Client{ctx: ctx}
 }
 
+// WithBaseBranch makes the client refuse to push iteration commits while base is checked out.
+func (a *Client) WithBaseBranch(base string) *Client {
+	a.baseBranch = base
+	return a
+}
+
+// WithConfig makes the client's commits follow cfg: its co-author trailer and, when enabled,
+// conventional commit prefixes. Without it commits carry only the actor trailer.
+func (a *Client) WithConfig(cfg *config.RalphConfig) *Client {
+	a.cfg = cfg
+	return a
+}
+
 func (a *Client) SwitchToBranch(slug string) error {
 	return ValidateGitStateAndSwitchBranch(a.ctx, slug)
 }
@@ -48,13 +65,30 @@ func (a *Client) ReportExists() bool {
 }
 
 func (a *Client) CommitFromReport(slug string) error {
+	if !a.ctx.IsNoPush() {
+		if err := a.checkPushBranch(); err != nil {
+			return err
+		}
+	}
 	data, err := os.ReadFile("report.md")
 	if err != nil {
 		return fmt.Errorf("failed to read report.md: %w", err)
 	}
-	message := string(data)
+	if err := StageAll(); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	message, err := a.agentMessage(string(data))
+	if err != nil {
+		return err
+	}
 	owner, repo := a.ctx.RepoOwnerAndName()
-	if err := CommitChanges(a.ctx.IsWorkflowExecution(), owner, repo, message); err != nil {
+	commit := CommitChanges
+	if a.ctx.IsNoPush() {
+		commit = commitWithoutPush
+	} else if a.ctx.IsForcePush() {
+		commit = CommitAndForcePush
+	}
+	if err := commit(a.ctx.IsWorkflowExecution(), owner, repo, message); err != nil {
 		return err
 	}
 	if err := os.Remove("report.md"); err != nil {
@@ -63,6 +97,16 @@ func (a *Client) CommitFromReport(slug string) error {
 	return nil
 }
 
+// DiffStat measures the working tree changes without the paths in exclude and the agent's
+// report.md, which holds the commit message rather than a change.
+func (a *Client) DiffStat(exclude ...string) (DiffStat, error) {
+	return WorkingTreeDiffStat(append(exclude, "report.md")...)
+}
+
+func (a *Client) StashChanges(message string, keep ...string) error {
+	return StashChanges(message, keep...)
+}
+
 func (a *Client) CurrentBranch() (string, error) {
 	return GetCurrentBranch()
 }
@@ -71,13 +115,105 @@ func (a *Client) IsBranchSyncedWithRemote(branch string) error {
 	return IsBranchSyncedWithRemote(branch)
 }
 
+func (a *Client) RemoteBranchExists(branch string) (bool, error) {
+	return RemoteBranchExists(branch)
+}
+
 func (a *Client) CommitOrchestrationRemoval(_ string) error {
-	return Commit("chore: remove orchestration doc before PR")
+	return Commit(a.withTrailers("chore: remove orchestration doc before PR"))
 }
 
 func (a *Client) CommitGeneratedArtifacts(slug string) error {
 	if err := StageAll(); err != nil {
 		return err
 	}
-	return Commit(fmt.Sprintf("chore: generate project for %s", slug))
+	return Commit(a.withTrailers(fmt.Sprintf("chore: generate project for %s", slug)))
+}
+
+// SquashForPR replaces the commits on the project branch since it left the base branch with a
+// single commit listing their subjects, then force pushes it with a lease unless pushing is off.
+// It does nothing unless --squash-before-pr is set, and only squashes the branch ralph created for slug.
+func (a *Client) SquashForPR(slug string) error {
+	if !a.ctx.IsSquashBeforePR() {
+		return nil
+	}
+	branch := SanitizeBranchName(slug)
+	current, err := GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if current != branch || branch == a.baseBranch {
+		return fmt.Errorf("--squash-before-pr only squashes the project branch %s, not %s", branch, current)
+	}
+	if a.baseBranch == "" {
+		return fmt.Errorf("--squash-before-pr requires a base branch to squash against")
+	}
+
+	subjects, err := CommitSubjects(a.baseBranch)
+	if err != nil {
+		return err
+	}
+	if len(subjects) < 2 {
+		return nil
+	}
+	message := slug + "\n\n- " + strings.Join(subjects, "\n- ")
+	if err := SquashOnto(a.baseBranch, a.withTrailers(message)); err != nil {
+		return err
+	}
+	if a.ctx.IsNoPush() {
+		return nil
+	}
+
+	var auth *AuthConfig
+	if a.ctx.IsWorkflowExecution() {
+		owner, repo := a.ctx.RepoOwnerAndName()
+		auth = &AuthConfig{Owner: owner, Repo: repo}
+	}
+	_, err = ForcePushWithLease(auth, branch)
+	return err
+}
+
+// checkPushBranch guards against pushing to the base branch unless the override is set.
+func (a *Client) checkPushBranch() error {
+	if a.baseBranch == "" || a.ctx.IsAllowBasePush() {
+		return nil
+	}
+	current, err := GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return CheckPushBranch(current, a.baseBranch)
+}
+
+// withTrailers adds the triggering actor and the configured co-author to an agent commit message.
+func (a *Client) withTrailers(message string) string {
+	message = WithActorTrailer(message, a.ctx.Actor())
+	if a.cfg != nil {
+		message = WithCoAuthorTrailer(message, a.cfg.CoAuthor)
+	}
+	return message
+}
+
+// agentMessage prepares the message for an iteration commit. When conventional commits are
+// enabled the prefix is chosen from the staged files, so the caller stages the commit first.
+func (a *Client) agentMessage(message string) (string, error) {
+	if a.cfg != nil && a.cfg.Commit.Conventional {
+		files, err := StagedFiles()
+		if err != nil {
+			return "", fmt.Errorf("failed to list staged files: %w", err)
+		}
+		message = WithConventionalPrefix(message, ConventionalPrefix(withoutReport(files), a.cfg.Commit))
+	}
+	return a.withTrailers(message), nil
+}
+
+// withoutReport drops the agent's report.md, which holds the commit message rather than a change.
+func withoutReport(files []string) []string {
+	kept := make([]string, 0, len(files))
+	for _, file := range files {
+		if file != "report.md" {
+			kept = append(kept, file)
+		}
+	}
+	return kept
 }
@@ -9,10 +9,16 @@ type MockClient struct {
 	CommitFromReportFunc         func(slug string) error
 	CurrentBranchFunc            func() (string, error)
 	IsBranchSyncedWithRemoteFunc      func(branch string) error
+	RemoteBranchExistsFunc            func(branch string) (bool, error)
 	CommitOrchestrationRemovalFunc         func(slug string) error
 	CommitOrchestrationRemovalCalled       bool
 	CommitGeneratedArtifactsFunc           func(slug string) error
 	CommitGeneratedArtifactsCalled         bool
+	SquashForPRFunc                        func(slug string) error
+	SquashForPRCalled                      bool
+	DiffStatFunc                           func(exclude ...string) (DiffStat, error)
+	StashChangesFunc                       func(message string, keep ...string) error
+	StashedMessages                        []string
 }
 
 func (m *MockClient) SwitchToBranch(slug string) error {
@@ -70,6 +76,13 @@ func (m *MockClient) IsBranchSyncedWithRemote(branch string) error {
 	return nil
 }
 
+func (m *MockClient) RemoteBranchExists(branch string) (bool, error) {
+	if m.RemoteBranchExistsFunc != nil {
+		return m.RemoteBranchExistsFunc(branch)
+	}
+	return true, nil
+}
+
 func (m *MockClient) CommitOrchestrationRemoval(slug string) error {
 	m.CommitOrchestrationRemovalCalled = true
 	if m.CommitOrchestrationRemovalFunc != nil {
@@ -85,3 +98,26 @@ func (m *MockClient) CommitGeneratedArtifacts(slug string) error {
 	}
 	return nil
 }
+
+func (m *MockClient) SquashForPR(slug string) error {
+	m.SquashForPRCalled = true
+	if m.SquashForPRFunc != nil {
+		return m.SquashForPRFunc(slug)
+	}
+	return nil
+}
+
+func (m *MockClient) DiffStat(exclude ...string) (DiffStat, error) {
+	if m.DiffStatFunc != nil {
+		return m.DiffStatFunc(exclude...)
+	}
+	return DiffStat{}, nil
+}
+
+func (m *MockClient) StashChanges(message string, keep ...string) error {
+	m.StashedMessages = append(m.StashedMessages, message)
+	if m.StashChangesFunc != nil {
+		return m.StashChangesFunc(message, keep...)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
@@ -117,6 +118,128 @@ func TestGitClientCommitFromReport(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "report.md should be deleted after commit")
 }
 
+func TestGitClientCommitFromReportBaseBranchGuard(t *testing.T) {
+	tests := []struct {
+		name      string
+		branch    string
+		allowBase bool
+		wantErr   bool
+	}{
+		{name: "refuses on the base branch", branch: "main", wantErr: true},
+		{name: "pushes from a feature branch", branch: "ralph/feature"},
+		{name: "override allows the base branch", branch: "main", allowBase: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			t.Chdir(workDir)
+			testutil.InitGitRepo(t, workDir)
+			testutil.MakeInitialCommit(t, workDir)
+			setupLocalRemote(t, workDir)
+			require.NoError(t, exec.Command("git", "checkout", "-B", tt.branch).Run())
+
+			ctx := context.NewContext()
+			ctx.SetAllowBasePush(tt.allowBase)
+			client := git.NewClient(ctx).WithBaseBranch("main")
+
+			require.NoError(t, os.WriteFile("report.md", []byte("Add feature"), 0644))
+			require.NoError(t, os.WriteFile("newfile.txt", []byte("change"), 0644))
+
+			err := client.CommitFromReport("test-slug")
+			if tt.wantErr {
+				require.ErrorIs(t, err, git.ErrPushToBaseBranch)
+				assert.Contains(t, err.Error(), "--allow-base-push")
+				_, statErr := os.Stat("report.md")
+				assert.NoError(t, statErr, "nothing should be committed")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGitClientCommitFromReportNoPush(t *testing.T) {
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	testutil.InitGitRepo(t, workDir)
+	testutil.MakeInitialCommit(t, workDir)
+	setupLocalRemote(t, workDir)
+
+	ctx := context.NewContext()
+	ctx.SetNoPush(true)
+	client := git.NewClient(ctx).WithBaseBranch("main")
+
+	require.NoError(t, os.WriteFile("report.md", []byte("Add feature"), 0644))
+	require.NoError(t, os.WriteFile("newfile.txt", []byte("change"), 0644))
+	require.NoError(t, client.CommitFromReport("test-slug"), "the base branch guard only applies to pushes")
+
+	out, err := exec.Command("git", "log", "-1", "--format=%s").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "Add feature", strings.TrimSpace(string(out)))
+
+	out, err = exec.Command("git", "rev-list", "--count", "origin/main..HEAD").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "1", strings.TrimSpace(string(out)), "the commit should stay unpushed")
+}
+
+func TestGitClientSquashForPR(t *testing.T) {
+	setup := func(t *testing.T, branch string) string {
+		workDir := t.TempDir()
+		t.Chdir(workDir)
+		testutil.InitGitRepo(t, workDir)
+		testutil.MakeInitialCommit(t, workDir)
+		setupLocalRemote(t, workDir)
+		require.NoError(t, exec.Command("git", "checkout", "-b", branch).Run())
+		for _, name := range []string{"one", "two", "three"} {
+			require.NoError(t, os.WriteFile(name+".txt", []byte(name), 0644))
+			require.NoError(t, exec.Command("git", "add", name+".txt").Run())
+			require.NoError(t, exec.Command("git", "commit", "-m", "Add "+name).Run())
+		}
+		require.NoError(t, exec.Command("git", "push", "--set-upstream", "origin", branch).Run())
+		return workDir
+	}
+	gitOutput := func(t *testing.T, args ...string) string {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	t.Run("collapses the project branch into one commit against the base", func(t *testing.T) {
+		setup(t, "my-feature")
+		ctx := context.NewContext()
+		ctx.SetSquashBeforePR(true)
+		client := git.NewClient(ctx).WithBaseBranch("main")
+
+		require.NoError(t, client.SquashForPR("my-feature"))
+
+		assert.Equal(t, "1", gitOutput(t, "rev-list", "--count", "main..HEAD"))
+		assert.Equal(t, "my-feature\n\n- Add one\n- Add two\n- Add three", gitOutput(t, "log", "-1", "--format=%B"))
+		assert.Equal(t, "one.txt\nthree.txt\ntwo.txt", gitOutput(t, "diff", "--name-only", "main", "HEAD"))
+		assert.Equal(t, gitOutput(t, "rev-parse", "HEAD"), gitOutput(t, "rev-parse", "origin/my-feature"), "the squashed branch should be force pushed")
+	})
+
+	t.Run("refuses a branch ralph did not create", func(t *testing.T) {
+		setup(t, "someone-elses-branch")
+		ctx := context.NewContext()
+		ctx.SetSquashBeforePR(true)
+		client := git.NewClient(ctx).WithBaseBranch("main")
+
+		err := client.SquashForPR("my-feature")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only squashes the project branch my-feature")
+		assert.Equal(t, "3", gitOutput(t, "rev-list", "--count", "main..HEAD"))
+	})
+
+	t.Run("does nothing unless enabled", func(t *testing.T) {
+		setup(t, "my-feature")
+		client := git.NewClient(context.NewContext()).WithBaseBranch("main")
+
+		require.NoError(t, client.SquashForPR("my-feature"))
+		assert.Equal(t, "3", gitOutput(t, "rev-list", "--count", "main..HEAD"))
+	})
+}
+
 func TestGitClientCommitFromReportFailsWhenNoReport(t *testing.T) {
 	workDir := t.TempDir()
 	t.Chdir(workDir)
@@ -152,6 +275,54 @@ func TestGitClientCommitGeneratedArtifacts(t *testing.T) {
 	assert.Equal(t, "chore: generate project for my-feature", strings.TrimSpace(string(out)))
 }
 
+func TestGitClientCommitAddsCoAuthorTrailer(t *testing.T) {
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	testutil.InitGitRepo(t, workDir)
+	testutil.MakeInitialCommit(t, workDir)
+	setupLocalRemote(t, workDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "report.md"), []byte("Add feature\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "feature.txt"), []byte("feature\n"), 0644))
+
+	ctx := context.NewContext()
+	ctx.SetActor("cli:alice")
+	client := git.NewClient(ctx).WithConfig(&config.RalphConfig{CoAuthor: "ralph-bot <ralph-bot@users.noreply.github.com>"})
+	require.NoError(t, client.CommitFromReport("my-feature"))
+
+	cmd := exec.Command("git", "log", "-1", "--format=%B")
+	cmd.Dir = workDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "Add feature\n\nTriggered-by: cli:alice\nCo-authored-by: ralph-bot <ralph-bot@users.noreply.github.com>", strings.TrimSpace(string(out)))
+
+	cmd = exec.Command("git", "log", "-1", "--format=%(trailers:key=Co-authored-by,valueonly)")
+	cmd.Dir = workDir
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "ralph-bot <ralph-bot@users.noreply.github.com>", strings.TrimSpace(string(out)))
+}
+
+func TestGitClientCommitFromReportConventionalPrefix(t *testing.T) {
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	testutil.InitGitRepo(t, workDir)
+	testutil.MakeInitialCommit(t, workDir)
+	setupLocalRemote(t, workDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "report.md"), []byte("Cover parser edge cases\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "parser_test.go"), []byte("package main\n"), 0644))
+
+	client := git.NewClient(context.NewContext()).WithConfig(&config.RalphConfig{Commit: config.CommitConfig{Conventional: true}})
+	require.NoError(t, client.CommitFromReport("my-feature"))
+
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = workDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "test: Cover parser edge cases", strings.TrimSpace(string(out)))
+}
+
 func TestGitClientCommitGeneratedArtifactsNoChanges(t *testing.T) {
 	workDir := t.TempDir()
 	t.Chdir(workDir)
@@ -167,6 +167,46 @@ func CommitAllAndPush(auth *AuthConfig, branchName, commitMsg string) error {
 	return nil
 }
 
+// WithActorTrailer appends a Triggered-by trailer naming the actor to a commit message.
+// The message is returned unchanged when actor is empty.
+func WithActorTrailer(message, actor string) string {
+	if actor == "" {
+		return message
+	}
+	return appendTrailer(message, "Triggered-by: "+actor)
+}
+
+// WithCoAuthorTrailer appends a Co-authored-by trailer, such as
+// "ralph-bot <ralph-bot@users.noreply.github.com>", to a commit message.
+// The message is returned unchanged when coAuthor is empty.
+func WithCoAuthorTrailer(message, coAuthor string) string {
+	if coAuthor == "" {
+		return message
+	}
+	return appendTrailer(message, "Co-authored-by: "+coAuthor)
+}
+
+// appendTrailer adds trailer to the message's trailer block, starting one when the
+// last paragraph is not already made of trailers.
+func appendTrailer(message, trailer string) string {
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	if len(paragraphs) > 1 && isTrailerBlock(paragraphs[len(paragraphs)-1]) {
+		return message + "\n" + trailer + "\n"
+	}
+	return message + "\n\n" + trailer + "\n"
+}
+
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		key, _, ok := strings.Cut(line, ": ")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return false
+		}
+	}
+	return true
+}
+
 func performCommit(message string) error {
 	message = strings.TrimSpace(message)
 	if message == "" {
@@ -184,6 +224,64 @@ func performCommit(message string) error {
 	return nil
 }
 
+// CommitAndForcePush commits all changes and pushes the current branch with --force-with-lease,
+// without pulling first, so a locally rewritten history replaces the remote one.
+func CommitAndForcePush(isWorkflow bool, owner, repo, message string) error {
+	if err := StageAll(); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	if err := performCommit(message); err != nil {
+		return err
+	}
+
+	var auth *AuthConfig
+	if isWorkflow {
+		auth = &AuthConfig{Owner: owner, Repo: repo}
+	}
+	if _, err := ForcePushWithLease(auth, ""); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// commitWithoutPush stages and commits all changes on the current branch, leaving the push to the user.
+func commitWithoutPush(_ bool, _, _, message string) error {
+	if err := StageAll(); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	return performCommit(message)
+}
+
+// CommitSubjects returns the subjects of the commits on the current branch since it left base, oldest first.
+func CommitSubjects(base string) ([]string, error) {
+	out, err := runGit("log", "--reverse", "--format=%s", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits since %s: %w", base, err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// SquashOnto soft-resets the current branch to where it left base and commits the result with
+// message, so the branch carries the same files in a single commit.
+func SquashOnto(base, message string) error {
+	mergeBase, err := runGit("merge-base", base, "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to find where the branch left %s: %w", base, err)
+	}
+	if _, err := runGit("reset", "--soft", strings.TrimSpace(mergeBase)); err != nil {
+		return fmt.Errorf("failed to reset onto %s: %w", base, err)
+	}
+	return performCommit(message)
+}
+
 func CommitChanges(isWorkflow bool, owner, repo, message string) error {
 	if err := StageAll(); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
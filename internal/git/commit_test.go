@@ -290,3 +290,43 @@ func TestCommitChanges_NoStagedChanges(t *testing.T) {
 	require.Error(t, err, "CommitChanges should fail with no staged changes")
 	assert.True(t, errors.Is(err, ErrNoChanges), "Expected ErrNoChanges, got: %v", err)
 }
+
+func TestWithActorTrailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		actor   string
+		want    string
+	}{
+		{name: "no actor", message: "Add feature\n", actor: "", want: "Add feature\n"},
+		{name: "cli actor", message: "Add feature\n\nDetails.\n", actor: "cli:alice", want: "Add feature\n\nDetails.\n\nTriggered-by: cli:alice\n"},
+		{name: "webhook actor without trailing newline", message: "Fix bug", actor: "webhook:octocat", want: "Fix bug\n\nTriggered-by: webhook:octocat\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WithActorTrailer(tt.message, tt.actor))
+		})
+	}
+}
+
+func TestWithCoAuthorTrailer(t *testing.T) {
+	const bot = "ralph-bot <ralph-bot@users.noreply.github.com>"
+	tests := []struct {
+		name     string
+		message  string
+		coAuthor string
+		want     string
+	}{
+		{name: "disabled", message: "Add feature\n", coAuthor: "", want: "Add feature\n"},
+		{name: "subject only", message: "Add feature", coAuthor: bot, want: "Add feature\n\nCo-authored-by: " + bot + "\n"},
+		{name: "body without trailers", message: "Add feature\n\nDetails: see below.\nMore text here.\n", coAuthor: bot, want: "Add feature\n\nDetails: see below.\nMore text here.\n\nCo-authored-by: " + bot + "\n"},
+		{name: "joins existing trailer block", message: "Add feature\n\nTriggered-by: cli:alice\n", coAuthor: bot, want: "Add feature\n\nTriggered-by: cli:alice\nCo-authored-by: " + bot + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WithCoAuthorTrailer(tt.message, tt.coAuthor))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package git
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zon/ralph/internal/config"
+)
+
+// DefaultConventionalType is the commit type used for changes outside every type pattern
+const DefaultConventionalType = "feat"
+
+// defaultConventionalTypes categorizes files when the config sets no type patterns
+var defaultConventionalTypes = map[string]string{
+	"*_test.go": "test",
+	"test/":     "test",
+	"tests/":    "test",
+	"testdata/": "test",
+	"docs/":     "docs",
+	"*.md":      "docs",
+}
+
+var conventionalSubject = regexp.MustCompile(`^[a-z]+(\([^)]*\))?!?: `)
+
+// categorizeFiles groups files by the value of the most specific matching pattern.
+// Files that match no pattern are grouped under "".
+func categorizeFiles(files []string, patterns map[string]string) map[string][]string {
+	ordered := make([]string, 0, len(patterns))
+	for pattern := range patterns {
+		ordered = append(ordered, pattern)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if len(ordered[i]) != len(ordered[j]) {
+			return len(ordered[i]) > len(ordered[j])
+		}
+		return ordered[i] < ordered[j]
+	})
+
+	categories := map[string][]string{}
+	for _, file := range files {
+		category := ""
+		for _, pattern := range ordered {
+			if matchPath(pattern, file) {
+				category = patterns[pattern]
+				break
+			}
+		}
+		categories[category] = append(categories[category], file)
+	}
+	return categories
+}
+
+// matchPath matches a directory prefix ending in "/" anywhere in the path, or a glob
+// against the full path or the base name.
+func matchPath(pattern, file string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(file, pattern) || strings.Contains(file, "/"+pattern)
+	}
+	if ok, _ := path.Match(pattern, file); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(file))
+	return ok
+}
+
+// ConventionalPrefix returns the "type(scope): " prefix for a commit that changes files.
+// Any file outside the type patterns makes the commit the default type, since tests and docs
+// that accompany code do not change what the commit is; otherwise the type covering the most
+// files wins. The scope is set only when every scoped file shares one scope.
+func ConventionalPrefix(files []string, cfg config.CommitConfig) string {
+	if len(files) == 0 {
+		return ""
+	}
+	patterns := cfg.Types
+	if len(patterns) == 0 {
+		patterns = defaultConventionalTypes
+	}
+	commitType := cfg.DefaultType
+	if commitType == "" {
+		commitType = DefaultConventionalType
+	}
+
+	types := categorizeFiles(files, patterns)
+	if _, uncategorized := types[""]; !uncategorized {
+		commitType = dominantCategory(types)
+	}
+
+	scope := ""
+	scopes := categorizeFiles(files, cfg.Scopes)
+	delete(scopes, "")
+	if len(scopes) == 1 {
+		for s := range scopes {
+			scope = s
+		}
+	}
+
+	if scope == "" {
+		return commitType + ": "
+	}
+	return commitType + "(" + scope + "): "
+}
+
+// dominantCategory returns the category with the most files, breaking ties alphabetically.
+func dominantCategory(categories map[string][]string) string {
+	best := ""
+	for category, files := range categories {
+		if best == "" || len(files) > len(categories[best]) || (len(files) == len(categories[best]) && category < best) {
+			best = category
+		}
+	}
+	return best
+}
+
+// WithConventionalPrefix prefixes the message subject, leaving subjects that already
+// carry a conventional-commit type unchanged.
+func WithConventionalPrefix(message, prefix string) string {
+	if prefix == "" || conventionalSubject.MatchString(message) {
+		return message
+	}
+	return prefix + message
+}
+
+// StagedFiles lists the paths staged for the next commit.
+func StagedFiles() ([]string, error) {
+	out, err := runGit("diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
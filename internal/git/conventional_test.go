@@ -0,0 +1,100 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zon/ralph/internal/config"
+)
+
+func TestConventionalPrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		cfg   config.CommitConfig
+		want  string
+	}{
+		{
+			name:  "no files",
+			files: nil,
+			want:  "",
+		},
+		{
+			name:  "test-only change",
+			files: []string{"internal/api/handler_test.go", "internal/api/testdata/fixture.json"},
+			want:  "test: ",
+		},
+		{
+			name:  "docs change",
+			files: []string{"docs/config.md", "README.md"},
+			want:  "docs: ",
+		},
+		{
+			name:  "mixed change is the default type",
+			files: []string{"internal/api/handler.go", "internal/api/handler_test.go", "docs/api.md"},
+			want:  "feat: ",
+		},
+		{
+			name:  "configured default type",
+			files: []string{"internal/api/handler.go"},
+			cfg:   config.CommitConfig{DefaultType: "fix"},
+			want:  "fix: ",
+		},
+		{
+			name:  "dominant category wins without uncategorized files",
+			files: []string{"a_test.go", "b_test.go", "docs/c.md"},
+			want:  "test: ",
+		},
+		{
+			name:  "shared scope",
+			files: []string{"internal/api/handler.go", "internal/api/handler_test.go"},
+			cfg:   config.CommitConfig{Scopes: map[string]string{"internal/api/": "api", "internal/web/": "web"}},
+			want:  "feat(api): ",
+		},
+		{
+			name:  "mixed scopes are omitted",
+			files: []string{"internal/api/handler.go", "internal/web/page.go"},
+			cfg:   config.CommitConfig{Scopes: map[string]string{"internal/api/": "api", "internal/web/": "web"}},
+			want:  "feat: ",
+		},
+		{
+			name:  "configured types replace the defaults",
+			files: []string{"deploy/chart.yaml"},
+			cfg:   config.CommitConfig{Types: map[string]string{"deploy/": "ci"}},
+			want:  "ci: ",
+		},
+		{
+			name:  "most specific pattern wins",
+			files: []string{"docs/examples/example_test.go"},
+			cfg:   config.CommitConfig{Types: map[string]string{"docs/": "docs", "docs/examples/": "test"}},
+			want:  "test: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ConventionalPrefix(tt.files, tt.cfg))
+		})
+	}
+}
+
+func TestWithConventionalPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		prefix  string
+		want    string
+	}{
+		{name: "adds prefix", message: "Add CSV export\n", prefix: "feat: ", want: "feat: Add CSV export\n"},
+		{name: "empty prefix", message: "Add CSV export\n", prefix: "", want: "Add CSV export\n"},
+		{name: "keeps existing type", message: "fix: Handle empty rows\n", prefix: "feat: ", want: "fix: Handle empty rows\n"},
+		{name: "keeps existing scoped type", message: "feat(api)!: Drop v1\n", prefix: "test: ", want: "feat(api)!: Drop v1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WithConventionalPrefix(tt.message, tt.prefix))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DiffStat is the size of the uncommitted changes in the working tree
+type DiffStat struct {
+	Files int // Changed, added and deleted files, untracked ones included
+	Lines int // Added plus deleted lines; binary files count as a file but add no lines
+}
+
+// WorkingTreeDiffStat measures the changes the next commit of the whole working tree would
+// hold: tracked changes against HEAD, staged or not, plus every untracked file that is not ignored.
+// The paths in exclude are left out of the count.
+func WorkingTreeDiffStat(exclude ...string) (DiffStat, error) {
+	var stat DiffStat
+	pathspec := []string{"--", ":/"}
+	for _, path := range exclude {
+		pathspec = append(pathspec, ":(exclude)"+path)
+	}
+	out, err := runGit(append([]string{"diff", "--numstat", "HEAD"}, pathspec...)...)
+	if err != nil {
+		return stat, fmt.Errorf("failed to measure diff: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		stat.Files++
+		// Binary files report "-" for both counts
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		stat.Lines += added + deleted
+	}
+
+	out, err = runGit(append([]string{"ls-files", "--others", "--exclude-standard"}, pathspec...)...)
+	if err != nil {
+		return stat, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, path := range strings.Split(out, "\n") {
+		if path == "" {
+			continue
+		}
+		stat.Files++
+		stat.Lines += countLines(path)
+	}
+	return stat, nil
+}
+
+// countLines returns the number of lines in a text file, or zero for a binary or unreadable one.
+func countLines(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 || len(data) == 0 {
+		return 0
+	}
+	lines := bytes.Count(data, []byte("\n"))
+	if data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// StashChanges moves every uncommitted change, untracked files included, into a stash entry
+// described by message, except for the paths in keep, which are left as they are.
+func StashChanges(message string, keep ...string) error {
+	args := []string{"stash", "push", "--include-untracked", "-m", message, "--", ":/"}
+	for _, path := range keep {
+		args = append(args, ":(exclude)"+path)
+	}
+	if _, err := runGit(args...); err != nil {
+		return fmt.Errorf("failed to stash changes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkingTreeDiffStat(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	t.Chdir(tempDir)
+
+	stat, err := WorkingTreeDiffStat()
+	require.NoError(t, err)
+	assert.Equal(t, DiffStat{}, stat, "a clean tree has no changes")
+
+	// Modify a tracked file, stage a new one and leave an untracked one in a subdirectory
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("rewritten\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "staged.txt"), []byte("a\nb\n"), 0644))
+	require.NoError(t, StageFile("staged.txt"))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sub", "new.txt"), []byte(strings.Repeat("line\n", 5)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sub", "image.bin"), []byte{0, 1, 2}, 0644))
+
+	// Measured from a subdirectory, the whole tree still counts
+	t.Chdir(filepath.Join(tempDir, "sub"))
+	stat, err = WorkingTreeDiffStat()
+	require.NoError(t, err)
+	// README.md swaps one line for another, staged.txt adds 2, new.txt adds 5 and image.bin none
+	assert.Equal(t, DiffStat{Files: 4, Lines: 9}, stat)
+}
+
+func TestWorkingTreeDiffStat_LargeDiff(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	t.Chdir(tempDir)
+
+	for i := 0; i < 30; i++ {
+		content := strings.Repeat(fmt.Sprintf("generated %d\n", i), 100)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("gen%02d.go", i)), []byte(content), 0644))
+	}
+
+	stat, err := WorkingTreeDiffStat()
+	require.NoError(t, err)
+	assert.Equal(t, DiffStat{Files: 30, Lines: 3000}, stat)
+}
+
+func TestWorkingTreeDiffStat_Exclude(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	t.Chdir(tempDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("rewritten\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "report.md"), []byte("feat: change\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "projects"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "projects", "p.yaml"), []byte("slug: p\n"), 0644))
+
+	stat, err := WorkingTreeDiffStat("report.md", filepath.Join(tempDir, "projects", "p.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, DiffStat{Files: 1, Lines: 2}, stat, "only README.md counts")
+}
+
+func TestStashChanges(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	t.Chdir(tempDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("rewritten\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "untracked.txt"), []byte("new\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "project.yaml"), []byte("name: kept\n"), 0644))
+
+	require.NoError(t, StashChanges("ralph: discarded iteration", filepath.Join(tempDir, "project.yaml")))
+
+	_, err := os.Stat(filepath.Join(tempDir, "untracked.txt"))
+	assert.True(t, os.IsNotExist(err), "untracked files are stashed")
+	data, err := os.ReadFile(filepath.Join(tempDir, "project.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: kept\n", string(data), "kept paths are left in place")
+
+	list, err := runGit("stash", "list")
+	require.NoError(t, err)
+	assert.Contains(t, list, "ralph: discarded iteration")
+
+	stat, err := WorkingTreeDiffStat()
+	require.NoError(t, err)
+	assert.Equal(t, DiffStat{Files: 1, Lines: 1}, stat, "only the kept file remains")
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-// DetectModifiedProjectFile finds the first modified or new YAML file in the projects directory.
+// DetectModifiedProjectFile finds the first modified or new YAML or JSON file in the projects directory.
 // Returns the absolute path to the modified project file, or empty string if none found.
 func DetectModifiedProjectFile(projectsDir string) (string, error) {
 	absProjectsDir, err := filepath.Abs(projectsDir)
@@ -28,7 +28,7 @@ func DetectModifiedProjectFile(projectsDir string) (string, error) {
 			continue
 		}
 		name := entry.Name()
-		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".json") {
 			continue
 		}
 		filePath := filepath.Join(absProjectsDir, name)
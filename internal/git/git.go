@@ -1,16 +1,103 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/execrun"
+)
+
+// waitDelay bounds how long a cancelled git command may keep its output open, since
+// helpers git spawns (such as git-remote-https) can outlive the killed git process
+const waitDelay = 5 * time.Second
+
+var (
+	runCtxMu      sync.RWMutex
+	runCtx        = context.Background()
+	remoteTimeout = config.DefaultGitTimeout * time.Second
 )
 
+// runner starts the git processes; SetRunner replaces it
+var runner execrun.Runner = execrun.Exec{WaitDelay: waitDelay}
+
+// SetContext makes later git invocations run under ctx, so cancelling ctx kills any git
+// process still running.
+func SetContext(ctx context.Context) {
+	runCtxMu.Lock()
+	defer runCtxMu.Unlock()
+	runCtx = ctx
+}
+
+func currentContext() context.Context {
+	runCtxMu.RLock()
+	defer runCtxMu.RUnlock()
+	return runCtx
+}
+
+// SetRunner makes later git invocations go through r, so tests can assert the arguments
+// without a real git binary.
+func SetRunner(r execrun.Runner) {
+	runCtxMu.Lock()
+	defer runCtxMu.Unlock()
+	runner = r
+}
+
+func currentRunner() execrun.Runner {
+	runCtxMu.RLock()
+	defer runCtxMu.RUnlock()
+	return runner
+}
+
+// SetRemoteTimeout makes later fetches, pulls and pushes abort after the gitTimeout of cfg, so
+// the config is resolved once instead of on every remote call. A nil cfg, or one without a
+// gitTimeout, restores the default.
+func SetRemoteTimeout(cfg *config.RalphConfig) {
+	seconds := config.DefaultGitTimeout
+	if cfg != nil && cfg.GitTimeout > 0 {
+		seconds = cfg.GitTimeout
+	}
+	runCtxMu.Lock()
+	defer runCtxMu.Unlock()
+	remoteTimeout = time.Duration(seconds) * time.Second
+}
+
+func currentRemoteTimeout() time.Duration {
+	runCtxMu.RLock()
+	defer runCtxMu.RUnlock()
+	return remoteTimeout
+}
+
 func runGit(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	return runGitContext(currentContext(), args...)
+}
+
+// runRemoteGit runs a git command that talks to the remote, aborting it once the configured
+// gitTimeout elapses so an unreachable remote cannot block the run indefinitely.
+func runRemoteGit(args ...string) (string, error) {
+	timeout := currentRemoteTimeout()
+	ctx, cancel := context.WithTimeout(currentContext(), timeout)
+	defer cancel()
+
+	output, err := runGitContext(ctx, args...)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		return output, fmt.Errorf("git %v timed out after %s (raise gitTimeout in .ralph/config.yaml): %w", args, timeout, context.DeadlineExceeded)
+	}
+	return output, err
+}
+
+func runGitContext(ctx context.Context, args ...string) (string, error) {
+	stdout, stderr, err := currentRunner().Run(ctx, "git", args...)
+	output := stdout + stderr
 	if err != nil {
-		return strings.TrimSpace(string(output)), fmt.Errorf("git %v failed: %w (output: %s)", args, err, output)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return strings.TrimSpace(output), fmt.Errorf("git %v interrupted: %w", args, ctxErr)
+		}
+		return strings.TrimSpace(output), fmt.Errorf("git %v failed: %w (output: %s)", args, err, output)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return strings.TrimSpace(output), nil
 }
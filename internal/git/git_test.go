@@ -1,15 +1,22 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/execrun"
 	"github.com/zon/ralph/internal/testutil"
 )
 
@@ -56,6 +63,62 @@ func TestRunGit(t *testing.T) {
 	}
 }
 
+func TestRunGitCancelledContextKillsGit(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "pid")
+	script := "#!/bin/sh\necho $$ > " + pidFile + "\nexec sleep 30\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	SetContext(ctx)
+	t.Cleanup(func() { SetContext(context.Background()) })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GetCurrentBranch()
+		done <- err
+	}()
+
+	var pid int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			return false
+		}
+		pid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("git kept running after the context was cancelled")
+	}
+	assert.Error(t, syscall.Kill(pid, 0), "git process should be terminated")
+}
+
+func TestFetchTimesOut(t *testing.T) {
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "git"), []byte("#!/bin/sh\nexec sleep 30\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	t.Chdir(t.TempDir())
+	SetRemoteTimeout(&config.RalphConfig{GitTimeout: 1})
+	t.Cleanup(func() { SetRemoteTimeout(nil) })
+
+	start := time.Now()
+	err := Fetch(nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timed out after 1s")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
 func TestDetectModifiedProjectFile(t *testing.T) {
 	t.Run("returns empty when no project files exist", func(t *testing.T) {
 		dir := t.TempDir()
@@ -180,3 +243,56 @@ func setupBareRemoteRepo(t *testing.T) (workDir, remoteDir string) {
 
 	return workDir, remoteDir
 }
+
+// mockGitRunner routes git invocations through a MockRunner for the rest of the test.
+func mockGitRunner(t *testing.T, fn func(call execrun.Call) (string, string, error)) *execrun.MockRunner {
+	t.Helper()
+	m := &execrun.MockRunner{RunFunc: fn}
+	SetRunner(m)
+	t.Cleanup(func() { SetRunner(execrun.Exec{WaitDelay: waitDelay}) })
+	return m
+}
+
+func TestRunnerArguments(t *testing.T) {
+	t.Run("checkout passes the branch", func(t *testing.T) {
+		m := mockGitRunner(t, nil)
+
+		require.NoError(t, CheckoutBranch("ralph/my-feature"))
+		assert.Equal(t, []execrun.Call{{Name: "git", Args: []string{"checkout", "ralph/my-feature"}}}, m.Calls())
+	})
+
+	t.Run("current branch is read from trimmed stdout", func(t *testing.T) {
+		mockGitRunner(t, func(call execrun.Call) (string, string, error) {
+			return "main\n", "", nil
+		})
+
+		branch, err := GetCurrentBranch()
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch)
+	})
+
+	t.Run("fetch falls back to a plain fetch of the branch", func(t *testing.T) {
+		m := mockGitRunner(t, func(call execrun.Call) (string, string, error) {
+			if call.Args[len(call.Args)-1] == "main:main" {
+				return "", "refusing to fetch into current branch", errors.New("exit status 128")
+			}
+			return "", "", nil
+		})
+
+		require.NoError(t, FetchBranch("main"))
+		calls := m.Calls()
+		require.Len(t, calls, 2)
+		assert.Equal(t, []string{"fetch", "origin", "main:main"}, calls[0].Args)
+		assert.Equal(t, []string{"fetch", "origin", "main"}, calls[1].Args)
+	})
+
+	t.Run("failure output includes stderr", func(t *testing.T) {
+		mockGitRunner(t, func(call execrun.Call) (string, string, error) {
+			return "", "pathspec 'nope' did not match", errors.New("exit status 1")
+		})
+
+		err := CheckoutBranch("nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pathspec 'nope' did not match")
+	})
+}
@@ -14,6 +14,13 @@ type AuthConfig struct {
 
 var ErrWorkflowPermission = errors.New("push rejected: GitHub App token requires `workflows` permission to push workflow files")
 
+// isNonFastForwardError reports whether a push was rejected because the remote branch has
+// commits the local branch does not.
+func isNonFastForwardError(output string) bool {
+	return strings.Contains(output, "non-fast-forward") ||
+		(strings.Contains(output, "[rejected]") && strings.Contains(output, "fetch first"))
+}
+
 func isWorkflowPermissionError(output string) bool {
 	return strings.Contains(output, "refusing to allow a GitHub App to create or update workflow") ||
 		strings.Contains(output, "without `workflows` permission")
@@ -35,7 +42,7 @@ func Fetch(auth *AuthConfig) error {
 		return fmt.Errorf("failed to configure git auth: %w", err)
 	}
 
-	_, err := runGit("fetch", "origin")
+	_, err := runRemoteGit("fetch", "origin")
 	if err != nil {
 		return fmt.Errorf("failed to fetch from remote: %w", err)
 	}
@@ -57,7 +64,7 @@ func PullRebase(auth *AuthConfig) error {
 		return nil
 	}
 
-	_, err = runGit("pull", "--rebase", "origin", branch)
+	_, err = runRemoteGit("pull", "--rebase", "origin", branch)
 	if err != nil {
 		return fmt.Errorf("failed to pull --rebase: %w", err)
 	}
@@ -73,6 +80,14 @@ func RemoteURL() (string, error) {
 	return remoteURL, nil
 }
 
+// SetRemoteURL points the origin remote of the current repository at url.
+func SetRemoteURL(url string) error {
+	if _, err := runGit("remote", "set-url", "origin", url); err != nil {
+		return fmt.Errorf("failed to set remote URL: %w", err)
+	}
+	return nil
+}
+
 func Push(auth *AuthConfig, branch string) (string, error) {
 	if err := configureAuth(auth); err != nil {
 		return "", fmt.Errorf("failed to configure git auth: %w", err)
@@ -91,7 +106,10 @@ func Push(auth *AuthConfig, branch string) (string, error) {
 		return "", fmt.Errorf("no commits to push on branch '%s'", branchToPush)
 	}
 
-	output, err := runGit("push", "--set-upstream", "origin", branchToPush)
+	output, err := runRemoteGit("push", "--set-upstream", "origin", branchToPush)
+	if err != nil && isNonFastForwardError(output) && retryPushAfterRebase(auth, branchToPush) {
+		err = nil
+	}
 	if err != nil {
 		if isWorkflowPermissionError(output) {
 			return "", fmt.Errorf("%w (output: %s)", ErrWorkflowPermission, output)
@@ -102,6 +120,53 @@ func Push(auth *AuthConfig, branch string) (string, error) {
 	return RemoteURL()
 }
 
+// retryPushAfterRebase rebases the current branch onto the remote and pushes it once more.
+// It only applies when branch is checked out, since the rebase works on the current branch.
+func retryPushAfterRebase(auth *AuthConfig, branch string) bool {
+	current, err := GetCurrentBranch()
+	if err != nil || current != branch {
+		return false
+	}
+	if err := PullRebase(auth); err != nil {
+		_, _ = runGit("rebase", "--abort")
+		return false
+	}
+	_, err = runRemoteGit("push", "--set-upstream", "origin", branch)
+	return err == nil
+}
+
+// forcePushArgs returns the git arguments for a force push that only succeeds while the
+// remote branch still points at the commit last fetched, so commits pushed by others are never overwritten.
+func forcePushArgs(branch string) []string {
+	return []string{"push", "--force-with-lease", "--set-upstream", "origin", branch}
+}
+
+// ForcePushWithLease pushes branch, or the current branch when empty, replacing the remote
+// history when it has been rewritten locally, e.g. after a rebase.
+func ForcePushWithLease(auth *AuthConfig, branch string) (string, error) {
+	if err := configureAuth(auth); err != nil {
+		return "", fmt.Errorf("failed to configure git auth: %w", err)
+	}
+
+	if branch == "" {
+		var err error
+		branch, err = GetCurrentBranch()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current branch: %w", err)
+		}
+	}
+
+	output, err := runRemoteGit(forcePushArgs(branch)...)
+	if err != nil {
+		if isWorkflowPermissionError(output) {
+			return "", fmt.Errorf("%w (output: %s)", ErrWorkflowPermission, output)
+		}
+		return "", fmt.Errorf("failed to force push branch '%s': %w", branch, err)
+	}
+
+	return RemoteURL()
+}
+
 func Clone(url, branch, dir string) error {
 	args := []string{"clone"}
 	if branch != "" {
@@ -116,6 +181,25 @@ func Clone(url, branch, dir string) error {
 	return nil
 }
 
+// UpdateSubmodules initializes and checks out all submodules of the current repository, recursively.
+func UpdateSubmodules() error {
+	if _, err := runGit("submodule", "update", "--init", "--recursive"); err != nil {
+		return fmt.Errorf("failed to update submodules: %w", err)
+	}
+	return nil
+}
+
+// PullLFS installs the Git LFS hooks in the current repository and replaces LFS pointer files with their content.
+func PullLFS() error {
+	if _, err := runGit("lfs", "install", "--local"); err != nil {
+		return fmt.Errorf("failed to install git lfs: %w", err)
+	}
+	if _, err := runGit("lfs", "pull"); err != nil {
+		return fmt.Errorf("failed to pull git lfs content: %w", err)
+	}
+	return nil
+}
+
 func configureAuth(auth *AuthConfig) error {
 	if auth == nil || authConfigurator == nil {
 		return nil
@@ -125,6 +209,18 @@ func configureAuth(auth *AuthConfig) error {
 
 var ErrFatalPushError = errors.New("fatal push error")
 
+// ErrPushToBaseBranch is returned when ralph is about to push directly to the base branch.
+var ErrPushToBaseBranch = errors.New("refusing to push to the base branch")
+
+// CheckPushBranch returns ErrPushToBaseBranch when branch is the base branch.
+// An empty base disables the check.
+func CheckPushBranch(branch, base string) error {
+	if base != "" && branch == base {
+		return fmt.Errorf("%w '%s': ralph pushes to its project branch; pass --allow-base-push to override", ErrPushToBaseBranch, branch)
+	}
+	return nil
+}
+
 func PullAndPush(isWorkflow bool, owner, repo string) error {
 	var auth *AuthConfig
 	if isWorkflow {
@@ -148,9 +244,9 @@ func PullAndPush(isWorkflow bool, owner, repo string) error {
 }
 
 func FetchBranch(branch string) error {
-	_, err := runGit("fetch", "origin", branch+":"+branch)
+	_, err := runRemoteGit("fetch", "origin", branch+":"+branch)
 	if err != nil {
-		_, err = runGit("fetch", "origin", branch)
+		_, err = runRemoteGit("fetch", "origin", branch)
 		if err != nil {
 			return fmt.Errorf("failed to fetch branch %s: %w", branch, err)
 		}
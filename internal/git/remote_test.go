@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -157,6 +158,33 @@ func TestClone(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdateSubmodules(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	libDir, _ := setupBareRemoteRepo(t)
+
+	workDir, _ := setupBareRemoteRepo(t)
+	t.Chdir(workDir)
+	_, err := runGit("submodule", "add", libDir, "lib")
+	require.NoError(t, err)
+	_, err = runGit("commit", "-m", "add submodule")
+	require.NoError(t, err)
+
+	cloneDir := t.TempDir()
+	require.NoError(t, Clone(workDir, "", cloneDir))
+	t.Chdir(cloneDir)
+
+	_, err = os.Stat(filepath.Join(cloneDir, "lib", "README.md"))
+	require.True(t, os.IsNotExist(err), "submodule should not be checked out by a plain clone")
+
+	require.NoError(t, UpdateSubmodules())
+
+	_, err = os.Stat(filepath.Join(cloneDir, "lib", "README.md"))
+	assert.NoError(t, err)
+}
+
 func TestRemoteURL(t *testing.T) {
 	t.Run("returns remote URL from config", func(t *testing.T) {
 		workDir, remoteDir := setupBareRemoteRepo(t)
@@ -230,3 +258,147 @@ func TestFetchBranch(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to fetch branch")
 	})
 }
+
+func TestIsNonFastForwardError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   bool
+	}{
+		{name: "non-fast-forward", output: " ! [rejected]        main -> main (non-fast-forward)", want: true},
+		{name: "fetch first", output: " ! [rejected]        main -> main (fetch first)", want: true},
+		{name: "permission denied", output: "remote: Permission to owner/repo.git denied", want: false},
+		{name: "empty", output: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNonFastForwardError(tt.output))
+		})
+	}
+}
+
+// setupDivergedClone returns a clone whose branch is one commit behind the remote and
+// one local commit ahead, so a plain push is rejected as non-fast-forward.
+func setupDivergedClone(t *testing.T, branchName, otherFile string) string {
+	t.Helper()
+	workDir, remoteDir := setupBareRemoteRepo(t)
+	t.Chdir(workDir)
+	require.NoError(t, CheckoutOrCreateBranch(branchName))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "base.txt"), []byte("base\n"), 0644))
+	require.NoError(t, StageAll())
+	require.NoError(t, Commit("base"))
+	_, err := Push(nil, branchName)
+	require.NoError(t, err)
+
+	otherDir := t.TempDir()
+	for _, args := range [][]string{
+		{"clone", "-b", branchName, remoteDir, otherDir},
+		{"-C", otherDir, "config", "--local", "user.email", "other@example.com"},
+		{"-C", otherDir, "config", "--local", "user.name", "Other"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(otherDir, otherFile), []byte("other\n"), 0644))
+	for _, args := range [][]string{
+		{"-C", otherDir, "add", "."},
+		{"-C", otherDir, "commit", "-m", "other pod"},
+		{"-C", otherDir, "push", "origin", branchName},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "local.txt"), []byte("local\n"), 0644))
+	require.NoError(t, StageAll())
+	require.NoError(t, Commit("local"))
+	return workDir
+}
+
+func TestPush_RetriesAfterRebaseOnRejection(t *testing.T) {
+	workDir := setupDivergedClone(t, "feature/retry", "other.txt")
+
+	_, err := Push(nil, "feature/retry")
+	require.NoError(t, err)
+
+	out, err := exec.Command("git", "-C", workDir, "log", "-3", "--format=%s", "origin/feature/retry").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "local\nother pod\nbase", strings.TrimSpace(string(out)), "local commit should be rebased on top of the remote commit")
+}
+
+func TestPush_ReturnsOriginalErrorWhenRetryFails(t *testing.T) {
+	workDir := setupDivergedClone(t, "feature/conflict", "local.txt")
+
+	_, err := Push(nil, "feature/conflict")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to push branch 'feature/conflict'")
+	assert.Contains(t, err.Error(), "rejected")
+
+	out, err := exec.Command("git", "-C", workDir, "log", "-1", "--format=%s").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "local", strings.TrimSpace(string(out)), "a failed rebase should be aborted")
+	_, err = os.Stat(filepath.Join(workDir, ".git", "rebase-merge"))
+	assert.True(t, os.IsNotExist(err), "no rebase should be left in progress")
+}
+
+func TestForcePushArgs(t *testing.T) {
+	assert.Equal(t, []string{"push", "--force-with-lease", "--set-upstream", "origin", "feature/x"}, forcePushArgs("feature/x"))
+}
+
+func TestForcePushWithLease_ReplacesRewrittenHistory(t *testing.T) {
+	workDir, _ := setupBareRemoteRepo(t)
+	t.Chdir(workDir)
+	require.NoError(t, CheckoutOrCreateBranch("feature/rewrite"))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "feature.txt"), []byte("v1\n"), 0644))
+	require.NoError(t, StageAll())
+	require.NoError(t, Commit("first draft"))
+	_, err := Push(nil, "feature/rewrite")
+	require.NoError(t, err)
+
+	out, err := exec.Command("git", "-C", workDir, "commit", "--amend", "-m", "rewritten").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	_, err = ForcePushWithLease(nil, "")
+	require.NoError(t, err)
+
+	local, err := exec.Command("git", "-C", workDir, "rev-parse", "HEAD").CombinedOutput()
+	require.NoError(t, err)
+	remote, err := exec.Command("git", "-C", workDir, "ls-remote", "origin", "refs/heads/feature/rewrite").CombinedOutput()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(remote), strings.TrimSpace(string(local))), "remote branch should point at the rewritten commit")
+}
+
+func TestForcePushWithLease_RefusesUnseenRemoteCommits(t *testing.T) {
+	setupDivergedClone(t, "feature/lease", "other.txt")
+
+	_, err := ForcePushWithLease(nil, "feature/lease")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to force push branch 'feature/lease'")
+	assert.Contains(t, err.Error(), "stale info")
+}
+
+func TestCheckPushBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		base    string
+		wantErr bool
+	}{
+		{name: "feature branch", branch: "ralph/feature", base: "main"},
+		{name: "base branch", branch: "main", base: "main", wantErr: true},
+		{name: "no base configured", branch: "main", base: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPushBranch(tt.branch, tt.base)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrPushToBaseBranch)
+				assert.Contains(t, err.Error(), "'main'")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
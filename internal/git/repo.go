@@ -71,3 +71,37 @@ func RevParse(args ...string) (string, error) {
 	}
 	return strings.TrimSpace(out), nil
 }
+
+// ExcludeLocally adds pattern to the repository's info/exclude file, unless already listed, so
+// files that ralph keeps in the working tree stay out of git status and git add -A without
+// changing any tracked .gitignore.
+func ExcludeLocally(pattern string) error {
+	excludePath, err := RevParse("--git-path", "info/exclude")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", excludePath, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(excludePath), err)
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", excludePath, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(pattern + "\n"); err != nil {
+		return fmt.Errorf("failed to write %s: %w", excludePath, err)
+	}
+	return nil
+}
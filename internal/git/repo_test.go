@@ -1,6 +1,9 @@
 package git
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,3 +69,20 @@ func TestRevParse(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, branch == "master" || branch == "main")
 }
+
+func TestExcludeLocally(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	t.Chdir(tempDir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, ".ralph"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".ralph", "project.lock"), nil, 0644))
+	require.True(t, HasUncommittedChanges())
+
+	require.NoError(t, ExcludeLocally("**/.ralph/project.lock"))
+	require.NoError(t, ExcludeLocally("**/.ralph/project.lock"))
+
+	assert.False(t, HasUncommittedChanges(), "the excluded file should not show in git status")
+	data, err := os.ReadFile(filepath.Join(tempDir, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "**/.ralph/project.lock"))
+}
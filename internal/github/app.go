@@ -299,14 +299,15 @@ func ConfigureTokenAuth(ctx context.Context, token string) error {
 }
 
 func ValidateAppCredentials(ctx context.Context, keyPath, appID string) error {
-	privateKeyBytes, err := os.ReadFile(keyPath)
+	privateKeyBytes, err := ReadGitHubAppCredentials(keyPath)
 	if err != nil {
-		return fmt.Errorf("failed to read private key file: %w", err)
-	}
-	if len(privateKeyBytes) == 0 {
-		return fmt.Errorf("private key file is empty")
+		return err
 	}
+	return ValidateAppKey(ctx, privateKeyBytes, appID)
+}
 
+// ValidateAppKey checks that the app's private key can get an installation token for the current repository.
+func ValidateAppKey(ctx context.Context, privateKeyBytes []byte, appID string) error {
 	repo, err := GetRepo(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to detect GitHub repository: %w", err)
@@ -4,12 +4,16 @@ import (
 	gocontext "context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/ai"
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
 
-	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/project"
 )
 
@@ -28,21 +32,37 @@ type Client struct {
 	ctx               *context.Context
 	baseBranch        string
 	gh                GHClient
-	oc                opencode.OCClient
+	backend           agent.Backend
+	cfg               *config.RalphConfig
 	gitAuthConfigurer GitAuthConfigurer
 }
 
-func NewClient(ctx *context.Context, baseBranch string, gh GHClient, oc opencode.OCClient) *Client {
+// NewClient returns a Client that opens pull requests as cfg describes. A nil cfg uses the
+// defaults: no upstream, and a pull request titled after the project with nothing else set.
+func NewClient(ctx *context.Context, baseBranch string, gh GHClient, backend agent.Backend, cfg *config.RalphConfig) *Client {
+	if cfg == nil {
+		cfg = &config.RalphConfig{}
+	}
 	return &Client{
 		ctx:               ctx,
 		baseBranch:        baseBranch,
 		gh:                gh,
-		oc:                oc,
+		backend:           backend,
+		cfg:               cfg,
 		gitAuthConfigurer: &realGitAuthConfigurer{},
 	}
 }
 
 func (a *Client) CreatePR(proj *project.Project) error {
+	if a.ctx.IsNoPush() {
+		flag := "--no-push"
+		if a.ctx.IsOffline() {
+			flag = "--offline"
+		}
+		a.ctx.Output().Infof("Skipping pull request creation (%s); commits are on local branch %s", flag, git.SanitizeBranchName(proj.Slug))
+		return nil
+	}
+
 	commitLog, err := git.GetCommitLog(a.baseBranch, 100)
 	if err != nil {
 		return fmt.Errorf("failed to get commit log: %w", err)
@@ -51,12 +71,18 @@ func (a *Client) CreatePR(proj *project.Project) error {
 	allComplete, passingCount, failingCount := project.CheckCompletion(proj)
 	projectStatus := fmt.Sprintf("%d passing, %d failing (complete: %v)", passingCount, failingCount, allComplete)
 
-	prSummary, err := ai.GeneratePRSummary(a.ctx, a.oc, proj.Title, projectStatus, a.baseBranch, commitLog)
+	prSummary, err := ai.GeneratePRSummary(a.ctx, a.backend, proj.Title, projectStatus, a.baseBranch, commitLog)
 	if err != nil {
 		return fmt.Errorf("failed to generate PR summary: %w", err)
 	}
 
+	prConfig := &a.cfg.PullRequest
+	if prConfig.IncludeProject {
+		prSummary = a.withProjectFile(prSummary, proj)
+	}
+
 	branchName := git.SanitizeBranchName(proj.Slug)
+	target := a.prTarget(branchName)
 
 	if a.ctx.IsWorkflowExecution() {
 		owner, repoName := a.ctx.RepoOwnerAndName()
@@ -65,7 +91,7 @@ func (a *Client) CreatePR(proj *project.Project) error {
 		}
 	}
 
-	prURL, err := CreatePullRequest(a.ctx.Output(), a.gh, proj, branchName, a.baseBranch, prSummary)
+	prURL, err := CreatePullRequest(a.ctx.Output(), a.gh, proj, target.head, target.base, target.repo, prSummary, prConfig, a.ctx.WebhookLogin())
 	if err != nil {
 		if errors.Is(err, ErrNoCommitsBetweenBranches) {
 			a.ctx.Output().Debug("No commits ahead of base branch — all requirements were already passing; skipping PR creation")
@@ -77,3 +103,69 @@ func (a *Client) CreatePR(proj *project.Project) error {
 	a.ctx.Output().Info(prURL)
 	return nil
 }
+
+// withProjectFile appends the project file to summary, named by its path in the repository. A
+// file that cannot be read leaves summary unchanged, since the pull request is still worth opening.
+func (a *Client) withProjectFile(summary string, proj *project.Project) string {
+	content, err := os.ReadFile(proj.Path)
+	if err != nil {
+		a.ctx.Output().Warnf("Could not read %s for the pull request description: %v", proj.Path, err)
+		return summary
+	}
+	name := filepath.Base(proj.Path)
+	if root, err := git.FindRepoRoot(); err == nil {
+		if rel, err := filepath.Rel(root, proj.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = filepath.ToSlash(rel)
+		}
+	}
+	return PRBodyWithProject(summary, name, content)
+}
+
+// prTarget is where a pull request is opened. An empty repo opens it in the current repository.
+type prTarget struct {
+	repo string
+	base string
+	head string
+}
+
+// prTarget targets the configured upstream repository, or otherwise the parent of the current
+// repository when it is a fork. A pull request into another repository names
+// its head as owner:branch, since the branch is pushed to the fork.
+func (a *Client) prTarget(branchName string) prTarget {
+	target := prTarget{base: a.baseBranch, head: branchName}
+	upstream := a.cfg.Upstream
+
+	if upstream.Repo != "" {
+		target.repo = upstream.Repo
+		if upstream.Base != "" {
+			target.base = upstream.Base
+		}
+		if owner := a.forkOwner(); owner != "" {
+			target.head = owner + ":" + branchName
+		}
+		return target
+	}
+	fork, err := a.gh.ViewFork(gocontext.Background())
+	if err != nil {
+		a.ctx.Output().Debugf("Could not detect an upstream repository, opening the pull request in origin: %v", err)
+		return target
+	}
+	if fork.Parent == nil {
+		return target
+	}
+	target.repo = fork.Parent.Owner + "/" + fork.Parent.Name
+	target.head = fork.Repo.Owner + ":" + branchName
+	return target
+}
+
+// forkOwner returns the owner of the repository the branch is pushed to.
+func (a *Client) forkOwner() string {
+	if owner, _ := a.ctx.RepoOwnerAndName(); owner != "" {
+		return owner
+	}
+	repo, err := GetRepo(gocontext.Background())
+	if err != nil {
+		return ""
+	}
+	return repo.Owner
+}
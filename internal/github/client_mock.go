@@ -8,12 +8,15 @@ import (
 
 type MockGH struct {
 	IsReadyFn           func() bool
-	FindExistingPRFn    func(head string) (string, error)
-	CreatePRFn          func(title, body, base, head string) (string, error)
+	FindExistingPRFn    func(head, repo string) (string, error)
+	CreatePRFn          func(title, body, base, head, repo string, meta PRMetadata) (string, error)
+	ViewForkFn          func(ctx context.Context) (Fork, error)
 	GetPRHeadRefOidFn   func(pr string) (string, error)
 	MergePRFn           func(pr, repo string) error
 	ListCollaboratorsFn func(ctx context.Context, owner, repo string) ([]string, error)
+	ListApproversFn     func(ctx context.Context, owner, repo string, pr int) ([]string, error)
 	RegisterWebhookFn   func(ctx context.Context, owner, repo, webhookURL, secret string) error
+	DeleteWebhookFn     func(ctx context.Context, owner, repo, webhookURL string) error
 }
 
 func (m *MockGH) IsReady() bool {
@@ -23,20 +26,27 @@ func (m *MockGH) IsReady() bool {
 	return false
 }
 
-func (m *MockGH) FindExistingPR(head string) (string, error) {
+func (m *MockGH) FindExistingPR(head, repo string) (string, error) {
 	if m.FindExistingPRFn != nil {
-		return m.FindExistingPRFn(head)
+		return m.FindExistingPRFn(head, repo)
 	}
 	return "", nil
 }
 
-func (m *MockGH) CreatePR(title, body, base, head string) (string, error) {
+func (m *MockGH) CreatePR(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 	if m.CreatePRFn != nil {
-		return m.CreatePRFn(title, body, base, head)
+		return m.CreatePRFn(title, body, base, head, repo, meta)
 	}
 	return "", nil
 }
 
+func (m *MockGH) ViewFork(ctx context.Context) (Fork, error) {
+	if m.ViewForkFn != nil {
+		return m.ViewForkFn(ctx)
+	}
+	return Fork{}, nil
+}
+
 func (m *MockGH) GetPRHeadRefOid(pr string) (string, error) {
 	if m.GetPRHeadRefOidFn != nil {
 		return m.GetPRHeadRefOidFn(pr)
@@ -58,6 +68,13 @@ func (m *MockGH) ListCollaborators(ctx context.Context, owner, repo string) ([]s
 	return nil, nil
 }
 
+func (m *MockGH) ListApprovers(ctx context.Context, owner, repo string, pr int) ([]string, error) {
+	if m.ListApproversFn != nil {
+		return m.ListApproversFn(ctx, owner, repo, pr)
+	}
+	return nil, nil
+}
+
 func (m *MockGH) RegisterWebhook(ctx context.Context, owner, repo, webhookURL, secret string) error {
 	if m.RegisterWebhookFn != nil {
 		return m.RegisterWebhookFn(ctx, owner, repo, webhookURL, secret)
@@ -65,6 +82,13 @@ func (m *MockGH) RegisterWebhook(ctx context.Context, owner, repo, webhookURL, s
 	return nil
 }
 
+func (m *MockGH) DeleteWebhook(ctx context.Context, owner, repo, webhookURL string) error {
+	if m.DeleteWebhookFn != nil {
+		return m.DeleteWebhookFn(ctx, owner, repo, webhookURL)
+	}
+	return nil
+}
+
 type MockClient struct {
 	CreatePRFunc func(*project.Project) error
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zon/ralph/internal/agent"
+	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/opencode"
 	orchestrationRun "github.com/zon/ralph/internal/orchestration/run"
@@ -20,7 +22,7 @@ import (
 
 func TestGitHubClientNew(t *testing.T) {
 	ctx := execcontext.NewContext()
-	client := NewClient(ctx, "main", NewGH(nil), &opencode.MockOC{})
+	client := NewClient(ctx, "main", NewGH(nil), &agent.MockBackend{}, nil)
 	require.NotNil(t, client)
 	var _ orchestrationRun.GitHubClient = client
 }
@@ -29,7 +31,7 @@ func TestClientCreatePR_DelegatesToCreatePullRequest(t *testing.T) {
 	createPRCalled := false
 	mock := &MockGH{
 		IsReadyFn: func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			createPRCalled = true
 			assert.Equal(t, "Test Title", title)
 			assert.Equal(t, "main", base)
@@ -55,7 +57,7 @@ func TestClientCreatePR_DelegatesToCreatePullRequest(t *testing.T) {
 			return opencode.Stats{}, nil
 		},
 	}
-	client := NewClient(ctx, "main", mock, mockOC)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(mockOC), nil)
 	proj := &project.Project{Slug: "some-branch", Title: "Test Title"}
 
 	err := client.CreatePR(proj)
@@ -63,6 +65,81 @@ func TestClientCreatePR_DelegatesToCreatePullRequest(t *testing.T) {
 	assert.True(t, createPRCalled, "expected GHClient.CreatePR to be called")
 }
 
+func TestClientCreatePR_UsesInjectedPullRequestConfig(t *testing.T) {
+	var gotTitle string
+	var gotMeta PRMetadata
+	mock := &MockGH{
+		IsReadyFn: func() bool { return true },
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+			gotTitle, gotMeta = title, meta
+			return "https://github.com/owner/repo/pull/1", nil
+		},
+	}
+	ctx := execcontext.NewContext()
+	ctx.SetOutput(output.NewClient(io.Discard, io.Discard, false))
+	backend := &agent.MockBackend{
+		RunFunc: func(call agent.Call) (string, error) {
+			if _, rest, ok := strings.Cut(call.Prompt, "Write your summary to the file:"); ok {
+				path, _, _ := strings.Cut(strings.TrimSpace(rest), "\n")
+				os.WriteFile(strings.TrimSpace(path), []byte("Mock PR summary"), 0644)
+			}
+			return "", nil
+		},
+	}
+	cfg := &config.RalphConfig{PullRequest: config.PullRequestConfig{
+		TitleTemplate: "[ralph] {{.Project}}",
+		Labels:        []string{"automated"},
+	}}
+	// HEAD as the base keeps the commit log empty wherever the test runs
+	client := NewClient(ctx, "HEAD", mock, backend, cfg)
+
+	err := client.CreatePR(&project.Project{Slug: "some-branch", Title: "Test Title"})
+	require.NoError(t, err)
+	assert.Equal(t, "[ralph] Test Title", gotTitle)
+	assert.Equal(t, []string{"automated"}, gotMeta.Labels)
+}
+
+func TestClientCreatePR_SkippedWithNoPush(t *testing.T) {
+	mock := &MockGH{
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+			t.Fatal("GHClient.CreatePR should not be called with --no-push")
+			return "", nil
+		},
+	}
+	mockOC := &opencode.MockOC{
+		RunCommandFunc: func(_ context.Context, _, _, _ string, _, _ io.Writer) error {
+			t.Fatal("no PR summary should be generated with --no-push")
+			return nil
+		},
+	}
+	var stdout strings.Builder
+	ctx := execcontext.NewContext()
+	ctx.SetOutput(output.NewClient(&stdout, &stdout, false))
+	ctx.SetNoPush(true)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(mockOC), nil)
+
+	require.NoError(t, client.CreatePR(&project.Project{Slug: "some-branch", Title: "Test Title"}))
+	assert.Contains(t, stdout.String(), "local branch some-branch")
+}
+
+func TestClientCreatePR_SkippedOffline(t *testing.T) {
+	mock := &MockGH{
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+			t.Fatal("GHClient.CreatePR should not be called with --offline")
+			return "", nil
+		},
+	}
+	var stdout strings.Builder
+	ctx := execcontext.NewContext()
+	ctx.SetOutput(output.NewClient(&stdout, &stdout, false))
+	ctx.SetNoPush(true)
+	ctx.SetOffline(true)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(&opencode.MockOC{}), nil)
+
+	require.NoError(t, client.CreatePR(&project.Project{Slug: "some-branch", Title: "Test Title"}))
+	assert.Contains(t, stdout.String(), "Skipping pull request creation (--offline)")
+}
+
 type mockGitAuthConfigurer struct {
 	configureGitAuthFn func(ctx context.Context, owner, repo, secretsDir string) error
 }
@@ -85,8 +162,10 @@ func TestClientCreatePR_WorkflowExecutionCallsConfigureGitAuth(t *testing.T) {
 		},
 	}
 	mock := &MockGH{
-		IsReadyFn:  func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) { return "https://github.com/o/r/p/1", nil },
+		IsReadyFn: func() bool { return true },
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+			return "https://github.com/o/r/p/1", nil
+		},
 	}
 	ctx := execcontext.NewContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
@@ -106,7 +185,7 @@ func TestClientCreatePR_WorkflowExecutionCallsConfigureGitAuth(t *testing.T) {
 		},
 		GetStatsFunc: func() (opencode.Stats, error) { return opencode.Stats{}, nil },
 	}
-	client := NewClient(ctx, "main", mock, mockOC)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(mockOC), nil)
 	client.gitAuthConfigurer = mockGitAuth
 	proj := &project.Project{Slug: "some-branch", Title: "Test Title"}
 
@@ -120,7 +199,7 @@ func TestClientCreatePR_SkipsConfigureGitAuthWhenNotWorkflow(t *testing.T) {
 	createPRCalled := false
 	mock := &MockGH{
 		IsReadyFn: func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			createPRCalled = true
 			return "https://github.com/o/r/p/1", nil
 		},
@@ -140,7 +219,7 @@ func TestClientCreatePR_SkipsConfigureGitAuthWhenNotWorkflow(t *testing.T) {
 		},
 		GetStatsFunc: func() (opencode.Stats, error) { return opencode.Stats{}, nil },
 	}
-	client := NewClient(ctx, "main", mock, mockOC)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(mockOC), nil)
 	proj := &project.Project{Slug: "some-branch", Title: "Test Title"}
 
 	err := client.CreatePR(proj)
@@ -151,7 +230,7 @@ func TestClientCreatePR_SkipsConfigureGitAuthWhenNotWorkflow(t *testing.T) {
 func TestClientCreatePR_PropagatesCreatePullRequestError(t *testing.T) {
 	mock := &MockGH{
 		IsReadyFn:  func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) { return "", assert.AnError },
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) { return "", assert.AnError },
 	}
 	ctx := execcontext.NewContext()
 	ctx.SetOutput(output.NewClient(os.Stdout, os.Stderr, false))
@@ -168,10 +247,68 @@ func TestClientCreatePR_PropagatesCreatePullRequestError(t *testing.T) {
 		},
 		GetStatsFunc: func() (opencode.Stats, error) { return opencode.Stats{}, nil },
 	}
-	client := NewClient(ctx, "main", mock, mockOC)
+	client := NewClient(ctx, "main", mock, agent.NewOpenCode(mockOC), nil)
 	proj := &project.Project{Slug: "some-branch", Title: "Test Title"}
 
 	err := client.CreatePR(proj)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to create pull request")
 }
+
+func TestClientPRTarget(t *testing.T) {
+	upstream := MakeRepo("upstream-org", "repo")
+
+	tests := []struct {
+		name     string
+		upstream config.UpstreamConfig
+		fork     Fork
+		err      error
+		want     prTarget
+	}{
+		{
+			name: "current repository when not a fork",
+			fork: Fork{Repo: MakeRepo("owner", "repo")},
+			want: prTarget{base: "main", head: "ralph/feature"},
+		},
+		{
+			name: "detected parent of a fork",
+			fork: Fork{Repo: MakeRepo("fork-owner", "repo"), Parent: &upstream},
+			want: prTarget{repo: "upstream-org/repo", base: "main", head: "fork-owner:ralph/feature"},
+		},
+		{
+			name: "current repository when detection fails",
+			err:  errors.New("gh repo view failed"),
+			want: prTarget{base: "main", head: "ralph/feature"},
+		},
+		{
+			name:     "configured upstream and base",
+			upstream: config.UpstreamConfig{Repo: "configured-org/repo", Base: "develop"},
+			want:     prTarget{repo: "configured-org/repo", base: "develop", head: "fork-owner:ralph/feature"},
+		},
+		{
+			name:     "configured upstream keeps the run's base",
+			upstream: config.UpstreamConfig{Repo: "configured-org/repo"},
+			want:     prTarget{repo: "configured-org/repo", base: "main", head: "fork-owner:ralph/feature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := execcontext.NewContext()
+			ctx.SetOutput(output.NewClient(io.Discard, io.Discard, false))
+			ctx.SetRepoOwner("fork-owner")
+			mock := &MockGH{
+				ViewForkFn: func(context.Context) (Fork, error) {
+					if tt.upstream.Repo != "" {
+						t.Fatal("the fork should not be detected")
+					}
+					return tt.fork, tt.err
+				},
+			}
+			cfg := &config.RalphConfig{Upstream: tt.upstream}
+			client := NewClient(ctx, "main", mock, &agent.MockBackend{}, cfg)
+
+			assert.Equal(t, tt.want, client.prTarget("ralph/feature"))
+		})
+	}
+}
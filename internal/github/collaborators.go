@@ -8,9 +8,12 @@ import (
 	"strings"
 )
 
+// ListCollaborators returns the logins of every collaborator of owner/repo, following all
+// pages of the API response.
 func (g *GH) ListCollaborators(ctx context.Context, owner, repo string) ([]string, error) {
 	cmd := exec.CommandContext(ctx, "gh", "api",
-		fmt.Sprintf("repos/%s/%s/collaborators", owner, repo),
+		fmt.Sprintf("repos/%s/%s/collaborators?per_page=100", owner, repo),
+		"--paginate",
 		"--jq", ".[].login",
 	)
 	var stdout, stderr bytes.Buffer
@@ -23,8 +26,10 @@ func (g *GH) ListCollaborators(ctx context.Context, owner, repo string) ([]strin
 	}
 
 	var logins []string
+	seen := make(map[string]bool)
 	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
-		if line = strings.TrimSpace(line); line != "" {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
 			logins = append(logins, line)
 		}
 	}
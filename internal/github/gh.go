@@ -23,12 +23,22 @@ var ErrNoCommitsBetweenBranches = errors.New("no commits between branches")
 // GHClient is the interface for GitHub CLI operations.
 type GHClient interface {
 	IsReady() bool
-	FindExistingPR(head string) (string, error)
-	CreatePR(title, body, base, head string) (string, error)
+	FindExistingPR(head, repo string) (string, error)
+	CreatePR(title, body, base, head, repo string, meta PRMetadata) (string, error)
+	ViewFork(ctx context.Context) (Fork, error)
 	GetPRHeadRefOid(pr string) (string, error)
 	MergePR(pr, repo string) error
 	ListCollaborators(ctx context.Context, owner, repo string) ([]string, error)
+	ListApprovers(ctx context.Context, owner, repo string, pr int) ([]string, error)
 	RegisterWebhook(ctx context.Context, owner, repo, webhookURL, secret string) error
+	DeleteWebhook(ctx context.Context, owner, repo, webhookURL string) error
+}
+
+// PRMetadata is what ralph sets on a pull request besides its title and body.
+type PRMetadata struct {
+	Labels    []string
+	Reviewers []string // User logins or org/team slugs
+	Assignees []string
 }
 
 // GH implements GHClient by shelling out to the gh CLI.
@@ -54,13 +64,25 @@ func (g *GH) IsReady() bool {
 	return true
 }
 
-func (g *GH) FindExistingPR(head string) (string, error) {
-	cmd := exec.Command("gh", "pr", "list",
-		"--head", head,
+// FindExistingPR returns the URL of the open pull request from head, or an empty string when there is none.
+// repo, as owner/name, selects the repository to search instead of the current one. gh pr list matches the
+// branch name only, so the results are filtered on where the branch lives: an owner:branch head must come
+// from that owner's fork, and a bare branch from the repository itself. Another fork's pull request from a
+// branch of the same name is never returned.
+func (g *GH) FindExistingPR(head, repo string) (string, error) {
+	owner, branch, fromFork := strings.Cut(head, ":")
+	if !fromFork {
+		owner, branch = "", head
+	}
+	args := []string{"pr", "list",
+		"--head", branch,
 		"--state", "open",
-		"--json", "url",
-		"--limit", "1",
-	)
+		"--json", "url,headRepositoryOwner,isCrossRepository",
+	}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	cmd := exec.Command("gh", args...)
 
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -69,38 +91,42 @@ func (g *GH) FindExistingPR(head string) (string, error) {
 		return "", fmt.Errorf("failed to check for existing PRs: %w", err)
 	}
 
-	output := out.String()
-	if !strings.Contains(output, "url") {
-		return "", nil
+	var prs []struct {
+		URL                 string `json:"url"`
+		HeadRepositoryOwner struct {
+			Login string `json:"login"`
+		} `json:"headRepositoryOwner"`
+		IsCrossRepository bool `json:"isCrossRepository"`
 	}
-
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "http") {
-			return trimmed, nil
+	if err := json.Unmarshal(out.Bytes(), &prs); err != nil {
+		return "", fmt.Errorf("failed to parse existing PRs: %w", err)
+	}
+	for _, pr := range prs {
+		if fromFork && !strings.EqualFold(pr.HeadRepositoryOwner.Login, owner) {
+			continue
 		}
+		if !fromFork && pr.IsCrossRepository {
+			continue
+		}
+		return pr.URL, nil
 	}
-
 	return "", nil
 }
 
-func (g *GH) CreatePR(title, body, base, head string) (string, error) {
-	existingPR, err := g.FindExistingPR(head)
+// CreatePR opens a pull request from head into base, or updates the open one from head.
+// repo, as owner/name, opens it in that repository instead of the current one, with head
+// written as owner:branch when the branch lives in a fork.
+func (g *GH) CreatePR(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+	existingPR, err := g.FindExistingPR(head, repo)
 	if err != nil {
 		return "", err
 	}
 
 	if existingPR != "" {
-		return updateExistingPR(g.out, existingPR, title, body)
+		return updateExistingPR(g.out, existingPR, title, body, meta)
 	}
 
-	cmd := exec.Command("gh", "pr", "create",
-		"--title", title,
-		"--body", body,
-		"--base", base,
-		"--head", head,
-	)
+	cmd := exec.Command("gh", createPRArgs(title, body, base, head, repo, meta)...)
 
 	var out bytes.Buffer
 	var errOut bytes.Buffer
@@ -108,12 +134,72 @@ func (g *GH) CreatePR(title, body, base, head string) (string, error) {
 	cmd.Stderr = &errOut
 
 	if createErr := cmd.Run(); createErr != nil {
-		return handleExistingPR(g.out, createErr, errOut.String(), out.String(), title, body)
+		return handleExistingPR(g.out, createErr, errOut.String(), out.String(), title, body, meta)
 	}
 
 	return parsePRURL(g.out, out.String())
 }
 
+func createPRArgs(title, body, base, head, repo string, meta PRMetadata) []string {
+	args := []string{"pr", "create",
+		"--title", title,
+		"--body", body,
+		"--base", base,
+		"--head", head,
+	}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	for _, label := range meta.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, reviewer := range meta.Reviewers {
+		args = append(args, "--reviewer", reviewer)
+	}
+	for _, assignee := range meta.Assignees {
+		args = append(args, "--assignee", assignee)
+	}
+	return args
+}
+
+// Fork describes the current repository and, when it is a fork, the repository it was forked from.
+type Fork struct {
+	Repo   Repo
+	Parent *Repo
+}
+
+// ViewFork reads the current repository and its parent with gh repo view.
+func (g *GH) ViewFork(ctx context.Context) (Fork, error) {
+	cmd := exec.CommandContext(ctx, "gh", "repo", "view", "--json", "name,owner,parent")
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return Fork{}, fmt.Errorf("failed to view repository: %w (output: %s)", err, strings.TrimSpace(errOut.String()))
+	}
+
+	type repoJSON struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	}
+	var result struct {
+		repoJSON
+		Parent *repoJSON `json:"parent"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return Fork{}, fmt.Errorf("failed to parse repository view: %w", err)
+	}
+
+	fork := Fork{Repo: MakeRepo(result.Owner.Login, result.Name)}
+	if result.Parent != nil && result.Parent.Name != "" {
+		parent := MakeRepo(result.Parent.Owner.Login, result.Parent.Name)
+		fork.Parent = &parent
+	}
+	return fork, nil
+}
+
 func (g *GH) GetPRHeadRefOid(pr string) (string, error) {
 	cmd := exec.Command("gh", "pr", "view", pr, "--json", "headRefOid")
 	var out bytes.Buffer
@@ -2,12 +2,16 @@ package github
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/output"
 )
 
 // writeFakeGHScript writes a fake gh executable script to a temp directory
@@ -44,28 +48,249 @@ func TestGH_IsReady(t *testing.T) {
 
 func TestGH_FindExistingPR(t *testing.T) {
 	t.Run("returns URL when present", func(t *testing.T) {
-		writeFakeGHScript(t, `printf '[{"url":"https://github.com/owner/repo/pull/123"}]\nhttps://github.com/owner/repo/pull/123\n'`)
+		writeFakeGHScript(t, `echo '[{"url":"https://github.com/owner/repo/pull/123","headRepositoryOwner":{"login":"owner"},"isCrossRepository":false}]'`)
 		g := NewGH(nil)
-		url, err := g.FindExistingPR("my-branch")
+		url, err := g.FindExistingPR("my-branch", "")
 		require.NoError(t, err)
 		assert.Equal(t, "https://github.com/owner/repo/pull/123", url)
 	})
 
-	t.Run("returns empty when url field missing", func(t *testing.T) {
-		writeFakeGHScript(t, `echo '[{"number":1}]'`)
+	t.Run("returns empty when there are no pull requests", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '[]'`)
 		g := NewGH(nil)
-		url, err := g.FindExistingPR("my-branch")
+		url, err := g.FindExistingPR("my-branch", "")
 		require.NoError(t, err)
 		assert.Empty(t, url)
 	})
 
-	t.Run("returns empty when no http line", func(t *testing.T) {
-		writeFakeGHScript(t, `echo '[{"url":"not-http-value"}]'`)
+	t.Run("skips a fork's pull request for a bare branch", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '[{"url":"https://github.com/owner/repo/pull/1","headRepositoryOwner":{"login":"someone"},"isCrossRepository":true}]'`)
 		g := NewGH(nil)
-		url, err := g.FindExistingPR("my-branch")
+		url, err := g.FindExistingPR("my-branch", "")
 		require.NoError(t, err)
 		assert.Empty(t, url)
 	})
+
+	t.Run("matches the head owner of a fork branch", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '[
+			{"url":"https://github.com/upstream-org/repo/pull/1","headRepositoryOwner":{"login":"someone"},"isCrossRepository":true},
+			{"url":"https://github.com/upstream-org/repo/pull/2","headRepositoryOwner":{"login":"Fork-Owner"},"isCrossRepository":true}
+		]'`)
+		g := NewGH(nil)
+		url, err := g.FindExistingPR("fork-owner:my-branch", "upstream-org/repo")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/2", url)
+	})
+
+	t.Run("returns empty when no pull request is from the head owner", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '[{"url":"https://github.com/upstream-org/repo/pull/1","headRepositoryOwner":{"login":"someone"},"isCrossRepository":true}]'`)
+		g := NewGH(nil)
+		url, err := g.FindExistingPR("fork-owner:my-branch", "upstream-org/repo")
+		require.NoError(t, err)
+		assert.Empty(t, url)
+	})
+
+	t.Run("fails on unparseable output", func(t *testing.T) {
+		writeFakeGHScript(t, `echo 'not json'`)
+		g := NewGH(nil)
+		_, err := g.FindExistingPR("my-branch", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestGH_CreatePR(t *testing.T) {
+	t.Run("targets the upstream repository", func(t *testing.T) {
+		argsFile := filepath.Join(t.TempDir(), "args")
+		writeFakeGHScript(t, `echo "$@" >> `+argsFile+`
+			case "$2" in
+				list) echo '[]';;
+				create) echo 'https://github.com/upstream-org/repo/pull/5';;
+			esac`)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "fork-owner:ralph/feature", "upstream-org/repo", PRMetadata{})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/5", url)
+
+		data, err := os.ReadFile(argsFile)
+		require.NoError(t, err)
+		calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr list --head ralph/feature --state open --json url,headRepositoryOwner,isCrossRepository --repo upstream-org/repo", calls[0])
+		assert.Equal(t, "pr create --title Title --body Body --base main --head fork-owner:ralph/feature --repo upstream-org/repo", calls[1])
+	})
+
+	t.Run("omits --repo for the current repository", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"pr", "create", "--title", "Title", "--body", "Body", "--base", "main", "--head", "ralph/feature"},
+			createPRArgs("Title", "Body", "main", "ralph/feature", "", PRMetadata{}))
+	})
+
+	t.Run("passes labels to the create command", func(t *testing.T) {
+		argsFile := filepath.Join(t.TempDir(), "args")
+		writeFakeGHScript(t, `echo "$@" >> `+argsFile+`
+			case "$2" in
+				list) echo '[]';;
+				create) echo 'https://github.com/owner/repo/pull/7';;
+			esac`)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		_, err := g.CreatePR("Title", "Body", "main", "ralph/feature", "", PRMetadata{Labels: []string{"automated", "ralph"}})
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(argsFile)
+		require.NoError(t, err)
+		calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr create --title Title --body Body --base main --head ralph/feature --label automated --label ralph", calls[1])
+	})
+
+	t.Run("passes reviewers to the create command", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"pr", "create", "--title", "Title", "--body", "Body", "--base", "main", "--head", "ralph/feature",
+				"--reviewer", "alice", "--reviewer", "acme/platform"},
+			createPRArgs("Title", "Body", "main", "ralph/feature", "", PRMetadata{Reviewers: []string{"alice", "acme/platform"}}))
+	})
+
+	t.Run("passes assignees to the create command", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"pr", "create", "--title", "Title", "--body", "Body", "--base", "main", "--head", "ralph/feature",
+				"--assignee", "octocat"},
+			createPRArgs("Title", "Body", "main", "ralph/feature", "", PRMetadata{Assignees: []string{"octocat"}}))
+		assert.Equal(t,
+			[]string{"pr", "edit", "https://github.com/owner/repo/pull/3", "--title", "Title", "--body", "Body",
+				"--add-assignee", "octocat"},
+			editPRArgs("https://github.com/owner/repo/pull/3", "Title", "Body", PRMetadata{Assignees: []string{"octocat"}}))
+	})
+
+	t.Run("adds reviewers to an existing pull request", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"pr", "edit", "https://github.com/owner/repo/pull/3", "--title", "Title", "--body", "Body",
+				"--add-label", "ralph", "--add-reviewer", "alice", "--add-reviewer", "acme/platform"},
+			editPRArgs("https://github.com/owner/repo/pull/3", "Title", "Body", PRMetadata{Labels: []string{"ralph"}, Reviewers: []string{"alice", "acme/platform"}}))
+	})
+
+	t.Run("adds labels to an existing pull request", func(t *testing.T) {
+		argsFile := filepath.Join(t.TempDir(), "args")
+		writeFakeGHScript(t, `echo "$@" >> `+argsFile+`
+			case "$2" in
+				list) echo '[]';;
+				create) printf 'a pull request for branch "ralph/feature" into branch "main" already exists:\nhttps://github.com/owner/repo/pull/3\n' >&2; exit 1;;
+			esac`)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "ralph/feature", "", PRMetadata{Labels: []string{"automated"}})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/owner/repo/pull/3", url)
+
+		data, err := os.ReadFile(argsFile)
+		require.NoError(t, err)
+		calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, calls, 3)
+		assert.Equal(t, "pr edit https://github.com/owner/repo/pull/3 --title Title --body Body --add-label automated", calls[2])
+	})
+}
+
+// fakeForkPRs fakes gh with two open pull requests from branch ralph/feature into
+// upstream-org/repo, one from someone else's fork and one from fork-owner's, and returns the
+// file recording each gh call.
+func fakeForkPRs(t *testing.T) string {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	writeFakeGHScript(t, `echo "$@" >> `+argsFile+`
+		case "$2" in
+			list) echo '[
+				{"url":"https://github.com/upstream-org/repo/pull/1","headRepositoryOwner":{"login":"someone"},"isCrossRepository":true},
+				{"url":"https://github.com/upstream-org/repo/pull/2","headRepositoryOwner":{"login":"fork-owner"},"isCrossRepository":true}
+			]';;
+			create) echo 'https://github.com/upstream-org/repo/pull/3';;
+		esac`)
+	return argsFile
+}
+
+// ghCalls returns the gh calls recorded in argsFile, one per line.
+func ghCalls(t *testing.T, argsFile string) []string {
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestGH_CreatePR_HeadOwner(t *testing.T) {
+	t.Run("adds labels only to the head owner's pull request", func(t *testing.T) {
+		argsFile := fakeForkPRs(t)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "fork-owner:ralph/feature", "upstream-org/repo", PRMetadata{Labels: []string{"automated"}})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/2", url)
+
+		calls := ghCalls(t, argsFile)
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr edit https://github.com/upstream-org/repo/pull/2 --title Title --body Body --add-label automated", calls[1])
+	})
+
+	t.Run("creates a labelled pull request rather than label another fork's", func(t *testing.T) {
+		argsFile := fakeForkPRs(t)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "ralph/feature", "upstream-org/repo", PRMetadata{Labels: []string{"automated"}})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/3", url)
+
+		calls := ghCalls(t, argsFile)
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr create --title Title --body Body --base main --head ralph/feature --repo upstream-org/repo --label automated", calls[1])
+	})
+
+	t.Run("adds reviewers only to the head owner's pull request", func(t *testing.T) {
+		argsFile := fakeForkPRs(t)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "fork-owner:ralph/feature", "upstream-org/repo", PRMetadata{Reviewers: []string{"alice"}})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/2", url)
+
+		calls := ghCalls(t, argsFile)
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr edit https://github.com/upstream-org/repo/pull/2 --title Title --body Body --add-reviewer alice", calls[1])
+	})
+
+	t.Run("adds assignees only to the head owner's pull request", func(t *testing.T) {
+		argsFile := fakeForkPRs(t)
+		g := NewGH(output.NewClient(io.Discard, io.Discard, false))
+
+		url, err := g.CreatePR("Title", "Body", "main", "fork-owner:ralph/feature", "upstream-org/repo", PRMetadata{Assignees: []string{"octocat"}})
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/upstream-org/repo/pull/2", url)
+
+		calls := ghCalls(t, argsFile)
+		require.Len(t, calls, 2)
+		assert.Equal(t, "pr edit https://github.com/upstream-org/repo/pull/2 --title Title --body Body --add-assignee octocat", calls[1])
+	})
+}
+
+func TestGH_ViewFork(t *testing.T) {
+	t.Run("returns the parent of a fork", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '{"name":"repo","owner":{"login":"fork-owner"},"parent":{"name":"repo","owner":{"login":"upstream-org"}}}'`)
+		fork, err := NewGH(nil).ViewFork(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, MakeRepo("fork-owner", "repo"), fork.Repo)
+		require.NotNil(t, fork.Parent)
+		assert.Equal(t, MakeRepo("upstream-org", "repo"), *fork.Parent)
+	})
+
+	t.Run("has no parent when not a fork", func(t *testing.T) {
+		writeFakeGHScript(t, `echo '{"name":"repo","owner":{"login":"owner"},"parent":null}'`)
+		fork, err := NewGH(nil).ViewFork(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, fork.Parent)
+	})
+
+	t.Run("returns error on non-zero exit", func(t *testing.T) {
+		writeFakeGHScript(t, `echo 'no git remotes found' >&2; exit 1`)
+		_, err := NewGH(nil).ViewFork(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no git remotes found")
+	})
 }
 
 func TestGH_ListCollaborators(t *testing.T) {
@@ -85,6 +310,19 @@ func TestGH_ListCollaborators(t *testing.T) {
 		assert.Equal(t, []string{"alice", "bob"}, logins)
 	})
 
+	t.Run("collects and de-duplicates logins from every page", func(t *testing.T) {
+		writeFakeGHScript(t, `
+			case "$*" in
+				*--paginate*) printf 'alice\nbob\n'; printf 'bob\ncharlie\n'; exit 0;;
+				*) printf 'alice\nbob\n'; exit 0;;
+			esac
+		`)
+		g := NewGH(nil)
+		logins, err := g.ListCollaborators(context.Background(), "owner", "repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob", "charlie"}, logins)
+	})
+
 	t.Run("returns error on non-zero exit", func(t *testing.T) {
 		writeFakeGHScript(t, `exit 1`)
 		g := NewGH(nil)
@@ -94,6 +332,32 @@ func TestGH_ListCollaborators(t *testing.T) {
 	})
 }
 
+func TestGH_ListApprovers(t *testing.T) {
+	t.Run("returns reviewers whose latest review approves", func(t *testing.T) {
+		writeFakeGHScript(t, `printf 'alice APPROVED\nbob COMMENTED\nbob APPROVED\nalice COMMENTED\ncarol APPROVED\ncarol CHANGES_REQUESTED\n'`)
+		g := NewGH(nil)
+		approvers, err := g.ListApprovers(context.Background(), "owner", "repo", 7)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, approvers)
+	})
+
+	t.Run("counts a reviewer who approves again after a dismissal", func(t *testing.T) {
+		writeFakeGHScript(t, `printf 'alice APPROVED\nalice DISMISSED\nalice APPROVED\n'`)
+		g := NewGH(nil)
+		approvers, err := g.ListApprovers(context.Background(), "owner", "repo", 7)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice"}, approvers)
+	})
+
+	t.Run("returns error on non-zero exit", func(t *testing.T) {
+		writeFakeGHScript(t, `exit 1`)
+		g := NewGH(nil)
+		_, err := g.ListApprovers(context.Background(), "owner", "repo", 7)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list reviews for owner/repo#7")
+	})
+}
+
 func TestGH_RegisterWebhook(t *testing.T) {
 	t.Run("creates new webhook when no existing hook", func(t *testing.T) {
 		writeFakeGHScript(t, `
@@ -149,3 +413,54 @@ func TestGH_RegisterWebhook(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to update webhook")
 	})
 }
+
+func TestGH_DeleteWebhook(t *testing.T) {
+	t.Run("deletes the matching hook", func(t *testing.T) {
+		log := filepath.Join(t.TempDir(), "calls")
+		writeFakeGHScript(t, `
+			echo "$*" >> `+log+`
+			case "$*" in
+				*--method*DELETE*) exit 0;;
+				*) echo "42"; exit 0;;
+			esac
+		`)
+		g := NewGH(nil)
+		err := g.DeleteWebhook(context.Background(), "owner", "repo", "https://example.com/hook")
+		require.NoError(t, err)
+
+		calls, err := os.ReadFile(log)
+		require.NoError(t, err)
+		assert.Contains(t, string(calls), "api repos/owner/repo/hooks/42 --method DELETE")
+	})
+
+	t.Run("skips delete when no hook matches", func(t *testing.T) {
+		log := filepath.Join(t.TempDir(), "calls")
+		writeFakeGHScript(t, `
+			echo "$*" >> `+log+`
+			case "$*" in
+				*--method*DELETE*) exit 1;;
+				*) echo ""; exit 0;;
+			esac
+		`)
+		g := NewGH(nil)
+		err := g.DeleteWebhook(context.Background(), "owner", "repo", "https://example.com/hook")
+		require.NoError(t, err)
+
+		calls, err := os.ReadFile(log)
+		require.NoError(t, err)
+		assert.NotContains(t, string(calls), "DELETE")
+	})
+
+	t.Run("returns error when delete fails", func(t *testing.T) {
+		writeFakeGHScript(t, `
+			case "$*" in
+				*--method*DELETE*) exit 1;;
+				*) echo "42"; exit 0;;
+			esac
+		`)
+		g := NewGH(nil)
+		err := g.DeleteWebhook(context.Background(), "owner", "repo", "https://example.com/hook")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete webhook 42")
+	})
+}
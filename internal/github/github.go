@@ -9,7 +9,7 @@ import (
 	"github.com/zon/ralph/internal/output"
 )
 
-func handleExistingPR(out *output.Client, err error, errStr, outStr, title, body string) (string, error) {
+func handleExistingPR(out *output.Client, err error, errStr, outStr, title, body string, meta PRMetadata) (string, error) {
 	// GitHub rejects the PR when the head branch has no commits ahead of base.
 	// Treat this as a sentinel so callers can decide how to proceed.
 	if strings.Contains(errStr, "No commits between") {
@@ -25,7 +25,7 @@ func handleExistingPR(out *output.Client, err error, errStr, outStr, title, body
 		return "", fmt.Errorf("failed to create PR: %w (output: %s, stderr: %s)", err, outStr, errStr)
 	}
 
-	return updateExistingPR(out, existingURL, title, body)
+	return updateExistingPR(out, existingURL, title, body, meta)
 }
 
 func extractExistingPRURL(errStr string) string {
@@ -38,11 +38,8 @@ func extractExistingPRURL(errStr string) string {
 	return ""
 }
 
-func updateExistingPR(out *output.Client, prURL, title, body string) (string, error) {
-	editCmd := exec.Command("gh", "pr", "edit", prURL,
-		"--title", title,
-		"--body", body,
-	)
+func updateExistingPR(out *output.Client, prURL, title, body string, meta PRMetadata) (string, error) {
+	editCmd := exec.Command("gh", editPRArgs(prURL, title, body, meta)...)
 	var editOut bytes.Buffer
 	var editErrOut bytes.Buffer
 	editCmd.Stdout = &editOut
@@ -54,6 +51,25 @@ func updateExistingPR(out *output.Client, prURL, title, body string) (string, er
 	return prURL, nil
 }
 
+// editPRArgs adds labels, reviewers and assignees rather than replacing them, so those set by
+// people are kept.
+func editPRArgs(prURL, title, body string, meta PRMetadata) []string {
+	args := []string{"pr", "edit", prURL,
+		"--title", title,
+		"--body", body,
+	}
+	for _, label := range meta.Labels {
+		args = append(args, "--add-label", label)
+	}
+	for _, reviewer := range meta.Reviewers {
+		args = append(args, "--add-reviewer", reviewer)
+	}
+	for _, assignee := range meta.Assignees {
+		args = append(args, "--add-assignee", assignee)
+	}
+	return args
+}
+
 func parsePRURL(out *output.Client, output string) (string, error) {
 	lines := strings.Split(output, "\n")
 
@@ -1,27 +1,134 @@
 package github
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/project"
 )
 
-func CreatePullRequest(out *output.Client, ghClient GHClient, proj *project.Project, branchName, baseBranch, prSummary string) (string, error) {
+// ErrGHNotReady is returned when the gh CLI is missing or not authenticated.
+var ErrGHNotReady = errors.New("gh CLI is not ready")
+
+// CreatePullRequest opens or updates the pull request for proj from branchName into baseBranch.
+// repo, as owner/name, targets that repository instead of the current one, e.g. a fork's upstream.
+// prConfig supplies the title template, labels and reviewers; nil titles the pull request after
+// the project and sets nothing else. A non-empty assignee is assigned the pull request.
+func CreatePullRequest(out *output.Client, ghClient GHClient, proj *project.Project, branchName, baseBranch, repo, prSummary string, prConfig *config.PullRequestConfig, assignee string) (string, error) {
 	if !ghClient.IsReady() {
-		return "", fmt.Errorf("gh CLI is not ready, please install and authenticate with 'gh auth login'")
+		return "", fmt.Errorf("%w, please install and authenticate with 'gh auth login'", ErrGHNotReady)
 	}
 
-	prTitle := proj.Title
-	if prTitle == "" {
-		prTitle = proj.Slug
+	prTitle, err := PRTitle(prConfig, proj, branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to render pull request title: %w", err)
 	}
 
 	out.Debug("Creating GitHub pull request...")
-	prURL, err := ghClient.CreatePR(prTitle, prSummary, baseBranch, branchName)
+	meta := prMetadata(prConfig)
+	if assignee != "" {
+		meta.Assignees = []string{assignee}
+	}
+	prURL, err := ghClient.CreatePR(prTitle, prSummary, baseBranch, branchName, repo, meta)
 	if err != nil {
 		return "", fmt.Errorf("failed to create pull request: %w", err)
 	}
 
 	return prURL, nil
 }
+
+// prMetadata collects the labels and reviewers from prConfig. Blank entries are skipped, and a
+// leading @ is dropped since gh takes bare logins and org/team slugs.
+func prMetadata(prConfig *config.PullRequestConfig) PRMetadata {
+	var meta PRMetadata
+	if prConfig == nil {
+		return meta
+	}
+	meta.Labels = nonBlank(prConfig.Labels, "")
+	meta.Reviewers = append(nonBlank(prConfig.Reviewers, "@"), nonBlank(prConfig.TeamReviewers, "@")...)
+	return meta
+}
+
+func nonBlank(values []string, prefix string) []string {
+	var kept []string
+	for _, v := range values {
+		v = strings.TrimPrefix(strings.TrimSpace(v), prefix)
+		if v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// PRTitle renders the pull request title for proj on branchName. A head named owner:branch, as
+// used for a fork's pull request, is given to the template as the branch alone.
+func PRTitle(prConfig *config.PullRequestConfig, proj *project.Project, branchName string) (string, error) {
+	name := proj.Title
+	if name == "" {
+		name = proj.Slug
+	}
+	if prConfig == nil {
+		return name, nil
+	}
+	if i := strings.LastIndex(branchName, ":"); i >= 0 {
+		branchName = branchName[i+1:]
+	}
+	_, passing, failing := project.CheckCompletion(proj)
+	return prConfig.Title(config.PRTitleData{
+		Project:      name,
+		Slug:         proj.Slug,
+		Branch:       branchName,
+		PassingCount: passing,
+		FailingCount: failing,
+	})
+}
+
+// maxProjectDetailsBytes bounds the project file appended to a pull request description, well
+// under GitHub's limit on the size of a description
+const maxProjectDetailsBytes = 32 * 1024
+
+// PRBodyWithProject appends the project file content to summary in a collapsible <details>
+// block titled with name. Content past maxProjectDetailsBytes is cut at a line boundary, or at a
+// rune boundary when the kept part has no line break, and a note points reviewers at the file on
+// the branch.
+func PRBodyWithProject(summary, name string, content []byte) string {
+	text := strings.TrimRight(string(content), "\n")
+	truncated := 0
+	if len(text) > maxProjectDetailsBytes {
+		cut := strings.LastIndex(text[:maxProjectDetailsBytes], "\n")
+		if cut < 0 {
+			cut = maxProjectDetailsBytes
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		truncated = len(text) - cut
+		text = strings.TrimRight(text[:cut], "\n")
+	}
+
+	lang := "yaml"
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		lang = "json"
+	}
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(summary, "\n"))
+	b.WriteString("\n\n<details>\n<summary>Project file: ")
+	b.WriteString(name)
+	b.WriteString("</summary>\n\n")
+	b.WriteString(fence + lang + "\n" + text + "\n" + fence + "\n")
+	if truncated > 0 {
+		fmt.Fprintf(&b, "\n_Truncated %d bytes; see %s on the branch for the full file._\n", truncated, name)
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
@@ -1,12 +1,17 @@
 package github
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/agent"
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
-	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/project"
 )
@@ -19,18 +24,18 @@ var testOut = output.NewClient(os.Stdout, os.Stderr, false)
 func TestCreatePullRequest_UsesTitleAsPRTitle(t *testing.T) {
 	mock := &MockGH{
 		IsReadyFn: func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			assert.Equal(t, "This is a detailed title", title)
 			return "https://github.com/mock/repo/pull/1", nil
 		},
 	}
-	NewClient(context.NewContext(), "main", mock, &opencode.MockOC{})
+	NewClient(context.NewContext(), "main", mock, &agent.MockBackend{}, nil)
 
 	proj := &project.Project{
 		Slug:  "test-project",
 		Title: "This is a detailed title",
 	}
-	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "PR body")
+	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "", "PR body", nil, "")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, prURL)
 	assert.Contains(t, prURL, "github.com")
@@ -39,18 +44,18 @@ func TestCreatePullRequest_UsesTitleAsPRTitle(t *testing.T) {
 func TestCreatePullRequest_UsesSlugWhenTitleEmpty(t *testing.T) {
 	mock := &MockGH{
 		IsReadyFn: func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			assert.Equal(t, "my-project", title)
 			return "https://github.com/mock/repo/pull/1", nil
 		},
 	}
-	NewClient(context.NewContext(), "main", mock, &opencode.MockOC{})
+	NewClient(context.NewContext(), "main", mock, &agent.MockBackend{}, nil)
 
 	proj := &project.Project{
 		Slug:  "my-project",
 		Title: "",
 	}
-	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "PR body")
+	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "", "PR body", nil, "")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, prURL)
 }
@@ -58,17 +63,17 @@ func TestCreatePullRequest_UsesSlugWhenTitleEmpty(t *testing.T) {
 func TestCreatePullRequest_UsesSlugWhenTitleMissing(t *testing.T) {
 	mock := &MockGH{
 		IsReadyFn: func() bool { return true },
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			assert.Equal(t, "fallback-project", title)
 			return "https://github.com/mock/repo/pull/1", nil
 		},
 	}
-	NewClient(context.NewContext(), "main", mock, &opencode.MockOC{})
+	NewClient(context.NewContext(), "main", mock, &agent.MockBackend{}, nil)
 
 	proj := &project.Project{
 		Slug: "fallback-project",
 	}
-	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "PR body")
+	prURL, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "", "PR body", nil, "")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, prURL)
 }
@@ -80,14 +85,215 @@ func TestCreatePullRequest_DelegatesToIsReady(t *testing.T) {
 			called = true
 			return true
 		},
-		CreatePRFn: func(title, body, base, head string) (string, error) {
+		CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
 			return "https://github.com/mock/repo/pull/1", nil
 		},
 	}
-	NewClient(context.NewContext(), "main", mock, &opencode.MockOC{})
+	NewClient(context.NewContext(), "main", mock, &agent.MockBackend{}, nil)
 
 	proj := &project.Project{Slug: "test", Title: "Test"}
-	_, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "PR body")
+	_, err := CreatePullRequest(testOut, mock, proj, "feature-branch", "main", "", "PR body", nil, "")
 	assert.NoError(t, err)
 	assert.True(t, called, "expected GHClient.IsReady to be called")
 }
+
+func TestCreatePullRequest_GHNotReady(t *testing.T) {
+	mock := &MockGH{IsReadyFn: func() bool { return false }}
+
+	_, err := CreatePullRequest(testOut, mock, &project.Project{Slug: "test-project"}, "feature-branch", "main", "", "PR body", nil, "")
+	assert.ErrorIs(t, err, ErrGHNotReady)
+	assert.Contains(t, err.Error(), "gh auth login")
+}
+
+func TestPRTitle(t *testing.T) {
+	proj := &project.Project{
+		Slug:  "add-login",
+		Title: "Add login",
+		Requirements: []project.Requirement{
+			{Description: "Users can sign in", Passing: true},
+			{Description: "Users can sign out", Passing: true},
+			{Description: "Sessions expire", Passing: false},
+		},
+	}
+	t.Run("renders the template", func(t *testing.T) {
+		prConfig := &config.PullRequestConfig{TitleTemplate: "[ralph] {{.Project}}: {{.PassingCount}} passing, {{.FailingCount}} failing on {{.Branch}}"}
+		title, err := PRTitle(prConfig, proj, "add-login")
+		require.NoError(t, err)
+		assert.Equal(t, "[ralph] Add login: 2 passing, 1 failing on add-login", title)
+	})
+
+	t.Run("drops the owner from a fork head", func(t *testing.T) {
+		prConfig := &config.PullRequestConfig{TitleTemplate: "{{.Slug}} from {{.Branch}}"}
+		title, err := PRTitle(prConfig, proj, "fork-owner:add-login")
+		require.NoError(t, err)
+		assert.Equal(t, "add-login from add-login", title)
+	})
+
+	t.Run("uses the slug when the title is empty", func(t *testing.T) {
+		title, err := PRTitle(&config.PullRequestConfig{TitleTemplate: "[ralph] {{.Project}}"}, &project.Project{Slug: "add-login"}, "add-login")
+		require.NoError(t, err)
+		assert.Equal(t, "[ralph] add-login", title)
+	})
+
+	t.Run("defaults to the project name", func(t *testing.T) {
+		title, err := PRTitle(nil, proj, "add-login")
+		require.NoError(t, err)
+		assert.Equal(t, "Add login", title)
+	})
+
+	t.Run("passes the rendered title to gh", func(t *testing.T) {
+		var gotTitle string
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				gotTitle = title
+				return "https://github.com/mock/repo/pull/1", nil
+			},
+		}
+		prConfig := &config.PullRequestConfig{TitleTemplate: "[ralph] {{.Project}}"}
+		_, err := CreatePullRequest(testOut, mock, proj, "add-login", "main", "", "PR body", prConfig, "")
+		require.NoError(t, err)
+		assert.Equal(t, "[ralph] Add login", gotTitle)
+	})
+
+	t.Run("passes the configured labels to gh", func(t *testing.T) {
+		var gotLabels []string
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				gotLabels = meta.Labels
+				return "https://github.com/mock/repo/pull/1", nil
+			},
+		}
+		prConfig := &config.PullRequestConfig{Labels: []string{"automated", "ralph"}}
+		_, err := CreatePullRequest(testOut, mock, proj, "add-login", "main", "", "PR body", prConfig, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"automated", "ralph"}, gotLabels)
+	})
+
+	t.Run("an invalid template fails before gh is called", func(t *testing.T) {
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				t.Fatal("CreatePR should not be called")
+				return "", nil
+			},
+		}
+		_, err := CreatePullRequest(testOut, mock, proj, "add-login", "main", "", "PR body", &config.PullRequestConfig{TitleTemplate: "{{.Summary}}"}, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to render pull request title")
+	})
+}
+
+func TestPRMetadata(t *testing.T) {
+	t.Run("passes reviewers and teams through", func(t *testing.T) {
+		var got PRMetadata
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				got = meta
+				return "https://github.com/mock/repo/pull/1", nil
+			},
+		}
+		prConfig := &config.PullRequestConfig{
+			Labels:        []string{"ralph"},
+			Reviewers:     []string{"alice", "@bob"},
+			TeamReviewers: []string{"@acme/platform", "acme/security"},
+		}
+		_, err := CreatePullRequest(testOut, mock, &project.Project{Slug: "add-login"}, "add-login", "main", "", "PR body", prConfig, "")
+		require.NoError(t, err)
+		assert.Equal(t, PRMetadata{
+			Labels:    []string{"ralph"},
+			Reviewers: []string{"alice", "bob", "acme/platform", "acme/security"},
+		}, got)
+	})
+
+	t.Run("assigns the triggering user", func(t *testing.T) {
+		var got PRMetadata
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				got = meta
+				return "https://github.com/mock/repo/pull/1", nil
+			},
+		}
+		ctx := context.NewContext()
+		ctx.SetActor(context.WebhookActor("octocat"))
+		_, err := CreatePullRequest(testOut, mock, &project.Project{Slug: "add-login"}, "add-login", "main", "", "PR body", nil, ctx.WebhookLogin())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"octocat"}, got.Assignees)
+	})
+
+	t.Run("leaves command line runs unassigned", func(t *testing.T) {
+		var got PRMetadata
+		mock := &MockGH{
+			IsReadyFn: func() bool { return true },
+			CreatePRFn: func(title, body, base, head, repo string, meta PRMetadata) (string, error) {
+				got = meta
+				return "https://github.com/mock/repo/pull/1", nil
+			},
+		}
+		ctx := context.NewContext()
+		ctx.SetActor(context.LocalActor())
+		_, err := CreatePullRequest(testOut, mock, &project.Project{Slug: "add-login"}, "add-login", "main", "", "PR body", nil, ctx.WebhookLogin())
+		require.NoError(t, err)
+		assert.Empty(t, got.Assignees)
+	})
+
+	t.Run("skips blank entries", func(t *testing.T) {
+		meta := prMetadata(&config.PullRequestConfig{Labels: []string{" "}, Reviewers: []string{"", "alice"}, TeamReviewers: []string{"@"}})
+		assert.Empty(t, meta.Labels)
+		assert.Equal(t, []string{"alice"}, meta.Reviewers)
+	})
+
+	t.Run("sets nothing when unconfigured", func(t *testing.T) {
+		assert.Equal(t, PRMetadata{}, prMetadata(nil))
+		assert.Equal(t, PRMetadata{}, prMetadata(&config.PullRequestConfig{Reviewers: []string{}}))
+	})
+}
+
+func TestPRBodyWithProject(t *testing.T) {
+	content := []byte("slug: add-login\ntitle: Add login\nrequirements:\n  - description: Users can sign in\n    passing: false\n")
+
+	t.Run("appends the project file in a details block", func(t *testing.T) {
+		body := PRBodyWithProject("## Summary\n\nAdds login.\n", "projects/add-login.yaml", content)
+
+		assert.Equal(t, "## Summary\n\nAdds login.\n\n"+
+			"<details>\n<summary>Project file: projects/add-login.yaml</summary>\n\n"+
+			"```yaml\n"+string(content)+"```\n"+
+			"\n</details>\n", body)
+	})
+
+	t.Run("fences json projects as json", func(t *testing.T) {
+		body := PRBodyWithProject("Summary", "projects/add-login.json", []byte(`{"slug": "add-login"}`))
+		assert.Contains(t, body, "```json\n{\"slug\": \"add-login\"}\n```\n")
+	})
+
+	t.Run("widens the fence around content holding one", func(t *testing.T) {
+		body := PRBodyWithProject("Summary", "p.yaml", []byte("notes: |\n  ```sh\n  make\n  ```\n"))
+		assert.Contains(t, body, "````yaml\n")
+		assert.Contains(t, body, "  ```\n````\n")
+	})
+
+	t.Run("truncates large projects at a line", func(t *testing.T) {
+		line := strings.Repeat("x", 99) + "\n"
+		large := []byte(strings.Repeat(line, 400))
+
+		body := PRBodyWithProject("Summary", "p.yaml", large)
+
+		kept := maxProjectDetailsBytes / len(line) * len(line)
+		assert.Less(t, len(body), maxProjectDetailsBytes+500)
+		assert.Contains(t, body, "x\n```\n")
+		assert.Contains(t, body, fmt.Sprintf("_Truncated %d bytes; see p.yaml on the branch for the full file._", len(large)-kept))
+		assert.True(t, strings.HasSuffix(body, "</details>\n"))
+	})
+
+	t.Run("truncates a single long line at a rune", func(t *testing.T) {
+		large := []byte("x" + strings.Repeat("é", maxProjectDetailsBytes))
+
+		body := PRBodyWithProject("Summary", "p.yaml", large)
+
+		assert.True(t, utf8.ValidString(body))
+		assert.Contains(t, body, fmt.Sprintf("_Truncated %d bytes;", len(large)-maxProjectDetailsBytes+1))
+	})
+}
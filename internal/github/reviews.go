@@ -0,0 +1,56 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ListApprovers returns the logins whose latest review of pull request pr in owner/repo is an
+// approval, in the order they first approved. Comments do not change a reviewer's state, while
+// a later change request or a dismissal withdraws an approval.
+func (g *GH) ListApprovers(ctx context.Context, owner, repo string, pr int) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "gh", "api",
+		fmt.Sprintf("repos/%s/%s/pulls/%d/reviews?per_page=100", owner, repo, pr),
+		"--paginate",
+		"--jq", `.[] | "\(.user.login) \(.state)"`,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list reviews for %s/%s#%d: %w (stderr: %s)",
+			owner, repo, pr, err, stderr.String())
+	}
+
+	var order []string
+	approved := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		login, state, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || login == "" {
+			continue
+		}
+		switch state {
+		case "APPROVED":
+			if _, seen := approved[login]; !seen {
+				order = append(order, login)
+			}
+			approved[login] = true
+		case "CHANGES_REQUESTED", "DISMISSED":
+			if _, seen := approved[login]; seen {
+				approved[login] = false
+			}
+		}
+	}
+
+	var approvers []string
+	for _, login := range order {
+		if approved[login] {
+			approvers = append(approvers, login)
+		}
+	}
+	return approvers, nil
+}
@@ -70,3 +70,34 @@ func (g *GH) RegisterWebhook(ctx context.Context, owner, repo, webhookURL, secre
 
 	return nil
 }
+
+// DeleteWebhook deletes every hook on owner/repo whose URL is webhookURL. It is a no-op
+// when no hook matches.
+func (g *GH) DeleteWebhook(ctx context.Context, owner, repo, webhookURL string) error {
+	listCmd := exec.CommandContext(ctx, "gh", "api",
+		fmt.Sprintf("repos/%s/%s/hooks", owner, repo),
+		"--jq", fmt.Sprintf(`.[] | select(.config.url == "%s") | .id`, webhookURL),
+	)
+	var listOut, listErr bytes.Buffer
+	listCmd.Stdout = &listOut
+	listCmd.Stderr = &listErr
+	if err := listCmd.Run(); err != nil {
+		return fmt.Errorf("failed to list webhooks for %s/%s: %w (stderr: %s)",
+			owner, repo, err, listErr.String())
+	}
+
+	for _, id := range strings.Fields(listOut.String()) {
+		deleteCmd := exec.CommandContext(ctx, "gh", "api",
+			fmt.Sprintf("repos/%s/%s/hooks/%s", owner, repo, id),
+			"--method", "DELETE",
+		)
+		var deleteErr bytes.Buffer
+		deleteCmd.Stderr = &deleteErr
+		if err := deleteCmd.Run(); err != nil {
+			return fmt.Errorf("failed to delete webhook %s for %s/%s: %w (stderr: %s)",
+				id, owner, repo, err, deleteErr.String())
+		}
+	}
+
+	return nil
+}
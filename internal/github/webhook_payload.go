@@ -10,6 +10,7 @@ import (
 type WebhookPayload struct {
 	Action string `json:"action"`
 	Issue  struct {
+		Number      int `json:"number"` // The PR number for comments on a pull request's conversation, which carry no pull_request object
 		PullRequest *struct {
 			URL string `json:"url"`
 		} `json:"pull_request"`
@@ -33,6 +34,12 @@ type WebhookPayload struct {
 			Login string `json:"login"`
 		} `json:"user"`
 	} `json:"review"`
+	Label struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
 	Repository struct {
 		Name  string `json:"name"`
 		Owner struct {
@@ -67,8 +74,8 @@ type EventFields struct {
 // Call webhookconfig.IsAcceptable first to ensure the payload is valid.
 func (p *WebhookPayload) ToEvent(eventType string) EventFields {
 	prNumber := ""
-	if p.PullRequest.Number != 0 {
-		prNumber = fmt.Sprintf("%d", p.PullRequest.Number)
+	if n := p.PRNumber(); n != 0 {
+		prNumber = fmt.Sprintf("%d", n)
 	}
 	branch := p.PullRequest.Head.Ref
 	owner := p.RepoOwner()
@@ -84,6 +91,16 @@ func (p *WebhookPayload) ToEvent(eventType string) EventFields {
 			PRNumber:  prNumber,
 			Author:    p.Comment.User.Login,
 		}
+	case "pull_request":
+		// a labeled event reaching here carries the configured merge label
+		return EventFields{
+			Approved:  true,
+			PRBranch:  branch,
+			RepoOwner: owner,
+			RepoName:  repoName,
+			PRNumber:  prNumber,
+			Author:    p.Sender.Login,
+		}
 	case "pull_request_review":
 		return EventFields{
 			Body:      p.Review.Body,
@@ -98,14 +115,26 @@ func (p *WebhookPayload) ToEvent(eventType string) EventFields {
 	return EventFields{}
 }
 
+// LabelName returns the name of the label added or removed by a pull_request event.
+func (p *WebhookPayload) LabelName() string {
+	return p.Label.Name
+}
+
 // EventAction returns the action field from the payload.
 func (p *WebhookPayload) EventAction() string {
 	return p.Action
 }
 
-// PRNumber returns the pull request number.
+// PRNumber returns the pull request number, falling back to the issue number
+// when the payload is a comment on a pull request's conversation.
 func (p *WebhookPayload) PRNumber() int {
-	return p.PullRequest.Number
+	if p.PullRequest.Number != 0 {
+		return p.PullRequest.Number
+	}
+	if p.Issue.PullRequest != nil {
+		return p.Issue.Number
+	}
+	return 0
 }
 
 // PRHeadRef returns the head branch ref of the pull request.
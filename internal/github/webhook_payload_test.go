@@ -85,6 +85,59 @@ func TestToEvent_NoPRNumber_EmptyString(t *testing.T) {
 	assert.Equal(t, "", e.PRNumber)
 }
 
+func TestToEvent_ReviewPayload_PRNumberAndHeadRefExtracted(t *testing.T) {
+	body := []byte(`{
+		"action": "submitted",
+		"review": {"state": "approved", "user": {"login": "carol"}},
+		"pull_request": {"number": 99, "head": {"ref": "ralph/my-feature"}},
+		"repository": {"name": "myrepo", "owner": {"login": "acme"}}
+	}`)
+	p, err := ParseWebhookPayload(body)
+	require.NoError(t, err)
+
+	e := p.ToEvent("pull_request_review")
+
+	assert.Equal(t, "99", e.PRNumber)
+	assert.Equal(t, "ralph/my-feature", e.PRBranch)
+	assert.True(t, e.Approved)
+}
+
+func TestToEvent_IssueComment_PRNumberFromIssue(t *testing.T) {
+	p := minimalPayload("acme", "myrepo")
+	p.Comment.Body = "hello"
+	p.Issue.Number = 7
+	p.Issue.PullRequest = &struct {
+		URL string `json:"url"`
+	}{URL: "https://api.github.com/repos/acme/myrepo/pulls/7"}
+
+	e := p.ToEvent("issue_comment")
+
+	assert.Equal(t, "7", e.PRNumber)
+}
+
+func TestToEvent_IssueNumberIgnoredForPlainIssues(t *testing.T) {
+	p := minimalPayload("acme", "myrepo")
+	p.Issue.Number = 7
+
+	assert.Equal(t, 0, p.PRNumber())
+}
+
+func TestToEvent_PullRequestLabeled_MergesAsSender(t *testing.T) {
+	p := minimalPayload("acme", "myrepo")
+	p.Action = "labeled"
+	p.Label.Name = "ready-to-merge"
+	p.Sender.Login = "dave"
+	p.PullRequest.Number = 12
+	p.PullRequest.Head.Ref = "ralph/my-feature"
+
+	e := p.ToEvent("pull_request")
+
+	assert.True(t, e.Approved)
+	assert.Equal(t, "dave", e.Author)
+	assert.Equal(t, "12", e.PRNumber)
+	assert.Equal(t, "ralph/my-feature", e.PRBranch)
+}
+
 func TestToEvent_UnknownEventType_ReturnsEmptyEvent(t *testing.T) {
 	p := minimalPayload("acme", "myrepo")
 	p.Comment.Body = "test"
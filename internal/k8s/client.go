@@ -1,6 +1,9 @@
 package k8s
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Client interface {
 	GetCurrentContext(ctx context.Context) (Context, error)
@@ -8,12 +11,27 @@ type Client interface {
 	CreateOrUpdateSecret(ctx context.Context, name, namespace, kubeContext string, data map[string]string) error
 	SecretExists(ctx context.Context, name, namespace, kubeContext string) (bool, error)
 	GetConfigMapData(ctx context.Context, name, namespace, kubeContext string) (string, error)
+	GetSecretData(ctx context.Context, name, namespace, kubeContext string) (string, error)
+	NamespaceExists(ctx context.Context, namespace, kubeContext string) (bool, error)
+	CreateNamespace(ctx context.Context, namespace, kubeContext string) error
 }
 
-type client struct{}
+type client struct {
+	timeout time.Duration // Limit for each kubectl command; zero waits forever
+}
+
+// NewClient returns a Client whose kubectl commands time out after TimeoutFromEnv.
+func NewClient() (Client, error) {
+	timeout, err := TimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithTimeout(timeout), nil
+}
 
-func NewClient() Client {
-	return &client{}
+// NewClientWithTimeout returns a Client whose kubectl commands time out after timeout; zero waits forever.
+func NewClientWithTimeout(timeout time.Duration) Client {
+	return &client{timeout: timeout}
 }
 
 var _ Client = (*client)(nil)
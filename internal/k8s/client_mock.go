@@ -8,6 +8,9 @@ type MockClient struct {
 	CreateOrUpdateSecretFunc    func(ctx context.Context, name, namespace, kubeContext string, data map[string]string) error
 	SecretExistsFunc            func(ctx context.Context, name, namespace, kubeContext string) (bool, error)
 	GetConfigMapDataFunc        func(ctx context.Context, name, namespace, kubeContext string) (string, error)
+	GetSecretDataFunc           func(ctx context.Context, name, namespace, kubeContext string) (string, error)
+	NamespaceExistsFunc         func(ctx context.Context, namespace, kubeContext string) (bool, error)
+	CreateNamespaceFunc         func(ctx context.Context, namespace, kubeContext string) error
 }
 
 func (m *MockClient) GetCurrentContext(ctx context.Context) (Context, error) {
@@ -46,3 +49,25 @@ func (m *MockClient) GetConfigMapData(ctx context.Context, name, namespace, kube
 }
 
 var _ Client = (*MockClient)(nil)
+
+func (m *MockClient) GetSecretData(ctx context.Context, name, namespace, kubeContext string) (string, error) {
+	if m.GetSecretDataFunc != nil {
+		return m.GetSecretDataFunc(ctx, name, namespace, kubeContext)
+	}
+	return "", nil
+}
+
+// NamespaceExists reports every namespace as existing unless NamespaceExistsFunc is set
+func (m *MockClient) NamespaceExists(ctx context.Context, namespace, kubeContext string) (bool, error) {
+	if m.NamespaceExistsFunc != nil {
+		return m.NamespaceExistsFunc(ctx, namespace, kubeContext)
+	}
+	return true, nil
+}
+
+func (m *MockClient) CreateNamespace(ctx context.Context, namespace, kubeContext string) error {
+	if m.CreateNamespaceFunc != nil {
+		return m.CreateNamespaceFunc(ctx, namespace, kubeContext)
+	}
+	return nil
+}
@@ -4,9 +4,17 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewClient(t *testing.T) {
-	client := NewClient()
+	client, err := NewClient()
+	require.NoError(t, err)
 	assert.NotNil(t, client)
 }
+
+func TestNewClient_InvalidTimeout(t *testing.T) {
+	t.Setenv(TimeoutEnv, "soon")
+	_, err := NewClient()
+	assert.Error(t, err)
+}
@@ -37,12 +37,12 @@ func buildConfigMapApplyArgs(kubeContext string) []string {
 }
 
 func (c *client) CreateOrUpdateConfigMap(ctx context.Context, name, namespace, kubeContext string, data map[string]string) error {
-	stdout, err := runKubectl(ctx, nil, buildConfigMapArgs(name, namespace, kubeContext, data)...)
+	stdout, err := runKubectl(ctx, c.timeout, nil, buildConfigMapArgs(name, namespace, kubeContext, data)...)
 	if err != nil {
 		return fmt.Errorf("failed to generate configmap YAML: %w", err)
 	}
 
-	_, err = runKubectl(ctx, stdout, buildConfigMapApplyArgs(kubeContext)...)
+	_, err = runKubectl(ctx, c.timeout, stdout, buildConfigMapApplyArgs(kubeContext)...)
 	if err != nil {
 		return fmt.Errorf("failed to apply configmap: %w", err)
 	}
@@ -53,7 +53,7 @@ func (c *client) CreateOrUpdateConfigMap(ctx context.Context, name, namespace, k
 func (c *client) GetConfigMapData(ctx context.Context, name, namespace, kubeContext string) (string, error) {
 	args := buildGetConfigMapDataArgs(name, namespace, kubeContext)
 
-	stdout, err := runKubectl(ctx, nil, args...)
+	stdout, err := runKubectl(ctx, c.timeout, nil, args...)
 	if err != nil {
 		return "", fmt.Errorf("failed to read configmap '%s' from namespace '%s': %w", name, namespace, err)
 	}
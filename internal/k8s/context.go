@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -12,7 +13,7 @@ type Context struct {
 }
 
 func (c *client) GetCurrentContext(ctx context.Context) (Context, error) {
-	stdout, err := runKubectl(ctx, nil, "config", "current-context")
+	stdout, err := runKubectl(ctx, c.timeout, nil, "config", "current-context")
 	if err != nil {
 		return Context{}, fmt.Errorf("failed to get current context: %w", err)
 	}
@@ -20,7 +21,10 @@ func (c *client) GetCurrentContext(ctx context.Context) (Context, error) {
 	name := strings.TrimSpace(stdout.String())
 
 	args := []string{"config", "view", "-o", fmt.Sprintf("jsonpath='{.contexts[?(@.name==\"%s\")].context.namespace}'", name)}
-	stdout, err = runKubectl(ctx, nil, args...)
+	stdout, err = runKubectl(ctx, c.timeout, nil, args...)
+	if errors.Is(err, ErrKubectlTimeout) {
+		return Context{}, fmt.Errorf("failed to get namespace of context '%s': %w", name, err)
+	}
 
 	var namespace string
 	if err == nil {
@@ -3,13 +3,52 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"time"
 )
 
-func runKubectl(ctx context.Context, stdin *bytes.Buffer, args ...string) (*bytes.Buffer, error) {
+// ErrKubectlNotInstalled is returned when kubectl is not on the PATH.
+var ErrKubectlNotInstalled = errors.New("kubectl not found in PATH")
+
+// ErrKubectlTimeout is returned when a kubectl command runs longer than the client's timeout,
+// which usually means the cluster is unreachable.
+var ErrKubectlTimeout = errors.New("kubectl command timed out")
+
+// TimeoutEnv overrides how long each kubectl command may run (e.g. "1m"; "0" waits forever)
+const TimeoutEnv = "RALPH_KUBECTL_TIMEOUT"
+
+// DefaultTimeout is how long each kubectl command may run when TimeoutEnv is unset
+const DefaultTimeout = 30 * time.Second
+
+// TimeoutFromEnv returns the timeout set by RALPH_KUBECTL_TIMEOUT, or DefaultTimeout when it
+// is unset. A value that is not a duration, or is negative, is an error rather than a silent
+// fallback so a typo does not go unnoticed.
+func TimeoutFromEnv() (time.Duration, error) {
+	val := os.Getenv(TimeoutEnv)
+	if val == "" {
+		return DefaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(val)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration such as 1m, or 0 to wait forever", TimeoutEnv, val)
+	}
+	return timeout, nil
+}
+
+// runKubectl runs kubectl with args, stopping it when ctx is cancelled or, if timeout is
+// positive, once timeout passes.
+func runKubectl(ctx context.Context, timeout time.Duration, stdin *bytes.Buffer, args ...string) (*bytes.Buffer, error) {
 	if _, err := exec.LookPath("kubectl"); err != nil {
-		return nil, fmt.Errorf("kubectl not found in PATH - please install kubectl")
+		return nil, fmt.Errorf("%w - please install kubectl", ErrKubectlNotInstalled)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	cmd := exec.CommandContext(ctx, "kubectl", args...)
@@ -22,6 +61,12 @@ func runKubectl(ctx context.Context, stdin *bytes.Buffer, args ...string) (*byte
 	}
 
 	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s - check that the cluster is reachable (set %s to wait longer)", ErrKubectlTimeout, timeout, TimeoutEnv)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("kubectl command cancelled: %w", ctxErr)
+		}
 		return nil, fmt.Errorf("kubectl command failed: %w (stderr: %s)", err, stderr.String())
 	}
 
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunKubectl_NotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := runKubectl(context.Background(), DefaultTimeout, nil, "version")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrKubectlNotInstalled)
+	assert.Contains(t, err.Error(), "please install kubectl")
+}
+
+// fakeKubectl puts a kubectl on the PATH that runs script.
+func fakeKubectl(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunKubectl_Timeout(t *testing.T) {
+	fakeKubectl(t, "exec sleep 10")
+
+	start := time.Now()
+	_, err := runKubectl(context.Background(), 100*time.Millisecond, nil, "get", "secret", "x")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrKubectlTimeout)
+	assert.Contains(t, err.Error(), TimeoutEnv)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRunKubectl_Cancelled(t *testing.T) {
+	fakeKubectl(t, "exec sleep 10")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := runKubectl(ctx, 0, nil, "get", "secret", "x")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrKubectlTimeout)
+}
+
+func TestClientSecretExists_Timeout(t *testing.T) {
+	fakeKubectl(t, "exec sleep 10")
+
+	_, err := NewClientWithTimeout(100*time.Millisecond).SecretExists(context.Background(), "github-credentials", "argo", "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrKubectlTimeout)
+}
+
+func TestRunKubectl_FastCommandWithinTimeout(t *testing.T) {
+	fakeKubectl(t, "echo ok")
+
+	stdout, err := runKubectl(context.Background(), time.Second, nil, "version")
+	require.NoError(t, err)
+	assert.Equal(t, "ok\n", stdout.String())
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset", val: "", want: DefaultTimeout},
+		{name: "duration", val: "2m", want: 2 * time.Minute},
+		{name: "zero waits forever", val: "0", want: 0},
+		{name: "invalid", val: "soon", wantErr: true},
+		{name: "negative", val: "-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TimeoutEnv, tt.val)
+			got, err := TimeoutFromEnv()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid "+TimeoutEnv+" "+`"`+tt.val+`"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
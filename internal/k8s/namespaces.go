@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNamespaceNotFound is returned by EnsureNamespace when the namespace is missing and was not created.
+var ErrNamespaceNotFound = errors.New("namespace does not exist")
+
+// NamespaceOptions decide what EnsureNamespace does with a missing namespace.
+type NamespaceOptions struct {
+	Create  bool                                // Create a missing namespace without asking (--create-namespace)
+	Confirm func(question string) (bool, error) // Asks whether to create a missing namespace; nil when not interactive
+}
+
+func (c *client) NamespaceExists(ctx context.Context, namespace, kubeContext string) (bool, error) {
+	_, err := runKubectl(ctx, c.timeout, nil, withContext([]string{"get", "namespace", namespace}, kubeContext)...)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check for namespace '%s': %w", namespace, err)
+	}
+	return true, nil
+}
+
+func (c *client) CreateNamespace(ctx context.Context, namespace, kubeContext string) error {
+	if _, err := runKubectl(ctx, c.timeout, nil, withContext([]string{"create", "namespace", namespace}, kubeContext)...); err != nil {
+		return fmt.Errorf("failed to create namespace '%s': %w", namespace, err)
+	}
+	return nil
+}
+
+// EnsureNamespace checks that namespace exists before secrets are written into it. A missing
+// namespace is created when opts.Create is set or opts.Confirm approves; otherwise it is an
+// ErrNamespaceNotFound error. It reports whether the namespace was created.
+func EnsureNamespace(ctx context.Context, c Client, namespace, kubeContext string, opts NamespaceOptions) (bool, error) {
+	if namespace == "" || namespace == "default" {
+		return false, nil
+	}
+
+	exists, err := c.NamespaceExists(ctx, namespace, kubeContext)
+	if err != nil || exists {
+		return false, err
+	}
+
+	create := opts.Create
+	if !create && opts.Confirm != nil {
+		create, err = opts.Confirm(fmt.Sprintf("Namespace '%s' does not exist. Create it?", namespace))
+		if err != nil {
+			return false, err
+		}
+	}
+	if !create {
+		return false, fmt.Errorf("%w: '%s'; create it with `kubectl create namespace %s` or rerun with --create-namespace", ErrNamespaceNotFound, namespace, namespace)
+	}
+
+	if err := c.CreateNamespace(ctx, namespace, kubeContext); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func withContext(args []string, kubeContext string) []string {
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
+	}
+	return args
+}
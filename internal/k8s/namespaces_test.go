@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNamespaceKubectl puts a kubectl on the PATH that knows only the given namespaces and logs
+// every command it runs; it returns the path of the log.
+func fakeNamespaceKubectl(t *testing.T, namespaces ...string) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "kubectl.log")
+	script := `echo "$@" >> ` + log + `
+if [ "$1" = "get" ] && [ "$2" = "namespace" ]; then
+	case " ` + strings.Join(namespaces, " ") + ` " in
+		*" $3 "*) exit 0 ;;
+	esac
+	echo "Error from server (NotFound): namespaces \"$3\" not found" >&2
+	exit 1
+fi`
+	fakeKubectl(t, script)
+	return log
+}
+
+func kubectlLog(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestEnsureNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []string
+		opts        NamespaceOptions
+		wantCreated bool
+		wantErr     error
+		wantPrompt  bool
+		wantCommand []string
+	}{
+		{
+			name:        "existing namespace is left alone",
+			existing:    []string{"argo"},
+			wantCommand: []string{"get namespace argo --context prod"},
+		},
+		{
+			name:        "missing namespace is created with --create-namespace",
+			opts:        NamespaceOptions{Create: true},
+			wantCreated: true,
+			wantCommand: []string{"get namespace argo --context prod", "create namespace argo --context prod"},
+		},
+		{
+			name:        "missing namespace is created when the prompt is accepted",
+			opts:        NamespaceOptions{Confirm: func(string) (bool, error) { return true, nil }},
+			wantCreated: true,
+			wantPrompt:  true,
+			wantCommand: []string{"get namespace argo --context prod", "create namespace argo --context prod"},
+		},
+		{
+			name:        "missing namespace is an error when the prompt is declined",
+			opts:        NamespaceOptions{Confirm: func(string) (bool, error) { return false, nil }},
+			wantErr:     ErrNamespaceNotFound,
+			wantPrompt:  true,
+			wantCommand: []string{"get namespace argo --context prod"},
+		},
+		{
+			name:        "missing namespace is an error without a prompt",
+			wantErr:     ErrNamespaceNotFound,
+			wantCommand: []string{"get namespace argo --context prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := fakeNamespaceKubectl(t, tt.existing...)
+			prompted := false
+			if confirm := tt.opts.Confirm; confirm != nil {
+				tt.opts.Confirm = func(question string) (bool, error) {
+					prompted = true
+					assert.Equal(t, "Namespace 'argo' does not exist. Create it?", question)
+					return confirm(question)
+				}
+			}
+
+			created, err := EnsureNamespace(context.Background(), NewClientWithTimeout(DefaultTimeout), "argo", "prod", tt.opts)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), "--create-namespace")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantPrompt, prompted)
+			assert.Equal(t, tt.wantCommand, kubectlLog(t, log))
+		})
+	}
+}
+
+func TestEnsureNamespaceSkipsDefault(t *testing.T) {
+	log := fakeNamespaceKubectl(t)
+
+	for _, namespace := range []string{"", "default"} {
+		created, err := EnsureNamespace(context.Background(), NewClientWithTimeout(DefaultTimeout), namespace, "", NamespaceOptions{})
+		require.NoError(t, err)
+		assert.False(t, created)
+	}
+	assert.Empty(t, kubectlLog(t, log))
+}
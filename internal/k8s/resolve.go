@@ -1,4 +1,4 @@
-package cmd
+package k8s
 
 import (
 	"context"
@@ -6,16 +6,19 @@ import (
 	"io"
 
 	"github.com/zon/ralph/internal/config"
-	"github.com/zon/ralph/internal/k8s"
 	"github.com/zon/ralph/internal/output"
 )
 
-func resolveKubeContext(ctx context.Context, client k8s.Client, ralphConfig *config.RalphConfig, out *output.Client, flagContext, flagNamespace string) (k8s.Context, error) {
+// ResolveContext picks the Kubernetes context and namespace a command talks to. Each comes from
+// its flag, then workflow.context or workflow.namespace in ralphConfig, then the current kubectl
+// context. ralphConfig may be nil when no .ralph directory was found. Every ralph and
+// ralph-webhook command resolves its context here so the precedence is the same everywhere.
+func ResolveContext(ctx context.Context, client Client, ralphConfig *config.RalphConfig, out *output.Client, flagContext, flagNamespace string) (Context, error) {
 	if out == nil {
 		out = output.NewClient(io.Discard, io.Discard, false)
 	}
 
-	var k8sCtx k8s.Context
+	var k8sCtx Context
 
 	// 1. Resolve Context Name
 	if flagContext != "" {
@@ -27,7 +30,7 @@ func resolveKubeContext(ctx context.Context, client k8s.Client, ralphConfig *con
 	} else {
 		current, err := client.GetCurrentContext(ctx)
 		if err != nil {
-			return k8s.Context{}, fmt.Errorf("failed to get current Kubernetes context: %w\n\nMake sure kubectl is installed and configured.", err)
+			return Context{}, fmt.Errorf("failed to get current Kubernetes context: %w\n\nMake sure kubectl is installed and configured.", err)
 		}
 		out.Debugf("Using current Kubernetes context: %s", current.Name)
 		k8sCtx.Name = current.Name
@@ -1,4 +1,4 @@
-package cmd
+package k8s
 
 import (
 	"context"
@@ -10,10 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zon/ralph/internal/config"
-	"github.com/zon/ralph/internal/k8s"
 )
 
-func TestResolveKubeContext(t *testing.T) {
+func TestResolveContext(t *testing.T) {
 	tests := []struct {
 		name              string
 		flagContext       string
@@ -116,13 +115,13 @@ func TestResolveKubeContext(t *testing.T) {
 			expectedNamespace: "config",
 		},
 		{
-			name:        "mock context error returns error when no flag or config context",
-			flagContext: "",
-			flagNamespace: "",
-			configContext: "",
+			name:            "mock context error returns error when no flag or config context",
+			flagContext:     "",
+			flagNamespace:   "",
+			configContext:   "",
 			configNamespace: "",
-			mockError:   true,
-			expectError: true,
+			mockError:       true,
+			expectError:     true,
 		},
 		{
 			name:        "error when .ralph directory is missing",
@@ -160,14 +159,14 @@ func TestResolveKubeContext(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			mockClient := &k8s.MockClient{}
+			mockClient := &MockClient{}
 			if tt.mockError {
-				mockClient.GetCurrentContextFunc = func(ctx context.Context) (k8s.Context, error) {
-					return k8s.Context{}, fmt.Errorf("mock error")
+				mockClient.GetCurrentContextFunc = func(ctx context.Context) (Context, error) {
+					return Context{}, fmt.Errorf("mock error")
 				}
 			} else if tt.mockContext != "" {
-				mockClient.GetCurrentContextFunc = func(ctx context.Context) (k8s.Context, error) {
-					return k8s.Context{Name: tt.mockContext, Namespace: tt.mockNamespace}, nil
+				mockClient.GetCurrentContextFunc = func(ctx context.Context) (Context, error) {
+					return Context{Name: tt.mockContext, Namespace: tt.mockNamespace}, nil
 				}
 			}
 
@@ -176,12 +175,12 @@ func TestResolveKubeContext(t *testing.T) {
 					require.Error(t, err)
 					return
 				}
-				_, err = resolveKubeContext(ctx, mockClient, cfg, nil, tt.flagContext, tt.flagNamespace)
+				_, err = ResolveContext(ctx, mockClient, cfg, nil, tt.flagContext, tt.flagNamespace)
 				require.Error(t, err)
 				return
 			}
 
-			k8sCtx, err := resolveKubeContext(ctx, mockClient, cfg, nil, tt.flagContext, tt.flagNamespace)
+			k8sCtx, err := ResolveContext(ctx, mockClient, cfg, nil, tt.flagContext, tt.flagNamespace)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedContext, k8sCtx.Name)
 			assert.Equal(t, tt.expectedNamespace, k8sCtx.Namespace)
@@ -189,7 +188,7 @@ func TestResolveKubeContext(t *testing.T) {
 	}
 }
 
-func TestResolveKubeContext_UpwardSearch(t *testing.T) {
+func TestResolveContext_UpwardSearch(t *testing.T) {
 	ctx := context.Background()
 	dir := t.TempDir()
 
@@ -209,12 +208,12 @@ func TestResolveKubeContext_UpwardSearch(t *testing.T) {
 	cfg, err := config.LoadConfig()
 	require.NoError(t, err)
 
-	mockClient := &k8s.MockClient{}
-	mockClient.GetCurrentContextFunc = func(ctx context.Context) (k8s.Context, error) {
-		return k8s.Context{Name: "test-ctx", Namespace: "default"}, nil
+	mockClient := &MockClient{}
+	mockClient.GetCurrentContextFunc = func(ctx context.Context) (Context, error) {
+		return Context{Name: "test-ctx", Namespace: "default"}, nil
 	}
 
-	k8sCtx, err := resolveKubeContext(ctx, mockClient, cfg, nil, "", "")
+	k8sCtx, err := ResolveContext(ctx, mockClient, cfg, nil, "", "")
 
 	require.NoError(t, err)
 	assert.Equal(t, "test-ctx", k8sCtx.Name)
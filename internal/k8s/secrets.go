@@ -3,7 +3,9 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -42,12 +44,12 @@ func buildSecretApplyArgs(kubeContext string) []string {
 }
 
 func (c *client) CreateOrUpdateSecret(ctx context.Context, name, namespace, kubeContext string, data map[string]string) error {
-	stdout, err := runKubectl(ctx, nil, buildSecretArgs(name, namespace, kubeContext, data)...)
+	stdout, err := runKubectl(ctx, c.timeout, nil, buildSecretArgs(name, namespace, kubeContext, data)...)
 	if err != nil {
 		return fmt.Errorf("failed to generate secret YAML: %w", err)
 	}
 
-	_, err = runKubectl(ctx, stdout, buildSecretApplyArgs(kubeContext)...)
+	_, err = runKubectl(ctx, c.timeout, stdout, buildSecretApplyArgs(kubeContext)...)
 	if err != nil {
 		return fmt.Errorf("failed to apply secret: %w", err)
 	}
@@ -60,7 +62,7 @@ func (c *client) SecretExists(ctx context.Context, name, namespace, kubeContext
 	if kubeContext != "" {
 		args = append(args, "--context", kubeContext)
 	}
-	_, err := runKubectl(ctx, nil, args...)
+	_, err := runKubectl(ctx, c.timeout, nil, args...)
 	if err != nil {
 		if bytes.Contains([]byte(err.Error()), []byte("not found")) {
 			return false, nil
@@ -69,3 +71,31 @@ func (c *client) SecretExists(ctx context.Context, name, namespace, kubeContext
 	}
 	return true, nil
 }
+
+// GetSecretData returns the decoded secrets.yaml key of the named secret.
+func (c *client) GetSecretData(ctx context.Context, name, namespace, kubeContext string) (string, error) {
+	stdout, err := runKubectl(ctx, c.timeout, nil, buildGetSecretDataArgs(name, namespace, kubeContext)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret '%s' from namespace '%s': %w", name, namespace, err)
+	}
+
+	encoded := strings.TrimSpace(stdout.String())
+	if encoded == "" {
+		return "", fmt.Errorf("secret '%s' exists but secrets.yaml key is empty", name)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret '%s': %w", name, err)
+	}
+
+	return string(decoded), nil
+}
+
+func buildGetSecretDataArgs(name, namespace, kubeContext string) []string {
+	args := []string{"get", "secret", name, "-n", namespace, "-o", `jsonpath={.data.secrets\.yaml}`}
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
+	}
+	return args
+}
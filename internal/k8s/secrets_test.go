@@ -146,3 +146,33 @@ func TestBuildSecretApplyArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildGetSecretDataArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		secretName   string
+		namespace    string
+		kubeContext  string
+		expectedArgs []string
+	}{
+		{
+			name:         "basic secret read",
+			secretName:   "webhook-secrets",
+			namespace:    "default",
+			expectedArgs: []string{"get", "secret", "webhook-secrets", "-n", "default", "-o", `jsonpath={.data.secrets\.yaml}`},
+		},
+		{
+			name:         "secret read with context",
+			secretName:   "webhook-secrets",
+			namespace:    "production",
+			kubeContext:  "prod-cluster",
+			expectedArgs: []string{"get", "secret", "webhook-secrets", "-n", "production", "-o", `jsonpath={.data.secrets\.yaml}`, "--context", "prod-cluster"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedArgs, buildGetSecretDataArgs(tt.secretName, tt.namespace, tt.kubeContext))
+		})
+	}
+}
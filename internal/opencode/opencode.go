@@ -13,7 +13,6 @@ import (
 
 type OCClient interface {
 	RunCommand(ctx context.Context, model, variant, prompt string, stdoutWriter, stderrWriter io.Writer) error
-	RunAgent(ctx context.Context, model, variant, prompt string) error
 	GetStats() (Stats, error)
 	DisplayStats() error
 }
@@ -53,21 +52,6 @@ func (c *Client) RunCommand(ctx context.Context, model, variant, prompt string,
 	return execOpenCode(ctx, args, stdoutWriter, stderrWriter)
 }
 
-func (c *Client) RunAgent(ctx context.Context, model, variant, prompt string) error {
-	args := []string{"run", "--model", model}
-	if variant != "" {
-		args = append(args, "--variant", variant)
-	}
-	args = append(args, prompt)
-	ring := &ringWriter{n: 10}
-	stdout := io.MultiWriter(os.Stdout, ring)
-	stderr := io.MultiWriter(os.Stderr, ring)
-	if err := execOpenCode(ctx, args, stdout, stderr); err != nil {
-		return fmt.Errorf("opencode execution failed: %w\n\nLast 10 lines of output:\n%s", err, ring.Tail())
-	}
-	return nil
-}
-
 type Stats struct {
 	InputTokens  int64
 	OutputTokens int64
@@ -179,33 +163,6 @@ func parseCostValue(s string) (float64, error) {
 	return strconv.ParseFloat(s, 64)
 }
 
-type ringWriter struct {
-	n     int
-	lines []string
-	buf   string
-}
-
-func (r *ringWriter) Write(p []byte) (int, error) {
-	s := r.buf + string(p)
-	parts := strings.Split(s, "\n")
-	r.buf = parts[len(parts)-1]
-	for _, line := range parts[:len(parts)-1] {
-		r.lines = append(r.lines, line)
-		if len(r.lines) > r.n {
-			r.lines = r.lines[1:]
-		}
-	}
-	return len(p), nil
-}
-
-func (r *ringWriter) Tail() string {
-	lines := r.lines
-	if r.buf != "" {
-		lines = append(lines, r.buf)
-	}
-	return strings.Join(lines, "\n")
-}
-
 var fatalOpenCodePatterns = []string{
 	"Insufficient Balance",
 	"insufficient balance",
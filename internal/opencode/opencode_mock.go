@@ -7,7 +7,6 @@ import (
 
 type MockOC struct {
 	RunCommandFunc   func(ctx context.Context, model, variant, prompt string, stdoutWriter, stderrWriter io.Writer) error
-	RunAgentFunc     func(ctx context.Context, model, variant, prompt string) error
 	GetStatsFunc     func() (Stats, error)
 	DisplayStatsFunc func() error
 }
@@ -19,13 +18,6 @@ func (m *MockOC) RunCommand(ctx context.Context, model, variant, prompt string,
 	return nil
 }
 
-func (m *MockOC) RunAgent(ctx context.Context, model, variant, prompt string) error {
-	if m.RunAgentFunc != nil {
-		return m.RunAgentFunc(ctx, model, variant, prompt)
-	}
-	return nil
-}
-
 func (m *MockOC) GetStats() (Stats, error) {
 	if m.GetStatsFunc != nil {
 		return m.GetStatsFunc()
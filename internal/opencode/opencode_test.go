@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,48 +96,6 @@ func TestIsFatalError(t *testing.T) {
 	}
 }
 
-func TestCaptureWriterTail(t *testing.T) {
-	tests := []struct {
-		name     string
-		lines    []string
-		buf      string
-		expected string
-	}{
-		{
-			name:     "empty",
-			lines:    []string{},
-			buf:      "",
-			expected: "",
-		},
-		{
-			name:     "fewer than n lines",
-			lines:    []string{"line1", "line2", "line3"},
-			buf:      "",
-			expected: "line1\nline2\nline3",
-		},
-		{
-			name:     "with partial line",
-			lines:    []string{"line1", "line2"},
-			buf:      "line3",
-			expected: "line1\nline2\nline3",
-		},
-		{
-			name:     "exactly n lines",
-			lines:    []string{"line1", "line2", "line3"},
-			buf:      "",
-			expected: "line1\nline2\nline3",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			cw := &ringWriter{n: 10, lines: tt.lines, buf: tt.buf}
-			result := cw.Tail()
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestRunCommand(t *testing.T) {
 	tmpDir := t.TempDir()
 	scriptPath := filepath.Join(tmpDir, "fake-opencode.sh")
@@ -189,76 +146,6 @@ exit 1
 	assert.Contains(t, err.Error(), "opencode command failed")
 }
 
-func TestRunAgent(t *testing.T) {
-	tmpDir := t.TempDir()
-	scriptPath := filepath.Join(tmpDir, "fake-opencode.sh")
-	outputPath := filepath.Join(tmpDir, "output.txt")
-
-	scriptContent := fmt.Sprintf(`#!/bin/bash
-echo "agent ran successfully"
-echo "done" > '%s'
-exit 0
-`, outputPath)
-	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
-	require.NoError(t, err)
-
-	opencodePath := filepath.Join(tmpDir, "opencode")
-	err = os.Symlink(scriptPath, opencodePath)
-	require.NoError(t, err)
-
-	origPath := os.Getenv("PATH")
-	t.Setenv("PATH", tmpDir+":"+origPath)
-
-	client := New()
-	err = client.RunAgent(context.Background(), "test-model", "", "test-prompt")
-	require.NoError(t, err)
-
-	data, err := os.ReadFile(outputPath)
-	require.NoError(t, err)
-	assert.Equal(t, "done\n", string(data))
-}
-
-func TestRunAgentFailure(t *testing.T) {
-	tmpDir := t.TempDir()
-	scriptPath := filepath.Join(tmpDir, "fake-opencode.sh")
-
-	scriptContent := `#!/bin/bash
-for i in $(seq 1 15); do
-  echo "line $i"
-done
-exit 1
-`
-	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
-	require.NoError(t, err)
-
-	opencodePath := filepath.Join(tmpDir, "opencode")
-	err = os.Symlink(scriptPath, opencodePath)
-	require.NoError(t, err)
-
-	origPath := os.Getenv("PATH")
-	t.Setenv("PATH", tmpDir+":"+origPath)
-
-	client := New()
-	err = client.RunAgent(context.Background(), "test-model", "", "test-prompt")
-	require.Error(t, err)
-
-	errMsg := err.Error()
-	assert.Contains(t, errMsg, "opencode execution failed")
-	assert.Contains(t, errMsg, "Last 10 lines of output:")
-
-	prefix := "Last 10 lines of output:\n"
-	idx := strings.Index(errMsg, prefix)
-	require.NotEqual(t, -1, idx)
-	tail := errMsg[idx+len(prefix):]
-
-	var expectedLines []string
-	for i := 6; i <= 15; i++ {
-		expectedLines = append(expectedLines, fmt.Sprintf("line %d", i))
-	}
-	expectedTail := strings.Join(expectedLines, "\n")
-	assert.Equal(t, expectedTail, tail)
-}
-
 func TestGetStats(t *testing.T) {
 	tmpDir := t.TempDir()
 	scriptPath := filepath.Join(tmpDir, "fake-opencode.sh")
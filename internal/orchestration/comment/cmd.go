@@ -38,6 +38,9 @@ type GitClient interface {
 
 type GitHubClient interface {
 	PostComment(prNumber int, body string) error
+	// AssignPR assigns login to the pull request. A failure is reported rather than returned,
+	// since the comment is still worth handling.
+	AssignPR(prNumber int, login string)
 }
 
 type CommentContext struct {
@@ -80,6 +83,7 @@ type WorkflowCommentFlags struct {
 	RepoName         string
 	NoServices       bool
 	InstructionsFile string
+	Assignee         string // Commenter who triggered the run, assigned the pull request so they own follow-up
 }
 
 func (f WorkflowCommentFlags) WorkspaceFlags() wksp.WorkspaceFlags {
@@ -108,6 +112,9 @@ func (w *WorkflowCommentCmd) Run(flags WorkflowCommentFlags) error {
 	if err := w.workspace.Setup(flags.WorkspaceFlags()); err != nil {
 		return err
 	}
+	if flags.Assignee != "" {
+		w.github.AssignPR(flags.PRNumber, flags.Assignee)
+	}
 	cfg, err := w.config.LoadOptional()
 	if err != nil {
 		return err
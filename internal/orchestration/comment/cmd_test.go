@@ -65,6 +65,20 @@ func TestRunReplyPostedAfterCommit(t *testing.T) {
 	require.True(t, github.commentPosted())
 }
 
+func TestRunAssignsTriggeringUser(t *testing.T) {
+	cmd := comment.withMocks()
+	err := cmd.Run(flags.withAssignee("octocat"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"#42 octocat"}, github.assigned())
+}
+
+func TestRunWithoutAssigneeLeavesPRUnassigned(t *testing.T) {
+	cmd := comment.withMocks()
+	err := cmd.Run(flags.any())
+	require.NoError(t, err)
+	require.Empty(t, github.assigned())
+}
+
 func TestRunReplyPostedWhenNoChanges(t *testing.T) {
 	cmd := comment.withMocks(
 		comment.withGit(git.withNoChanges()),
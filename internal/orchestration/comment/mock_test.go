@@ -1,6 +1,8 @@
 package comment
 
 import (
+	"fmt"
+
 	ralphcfg "github.com/zon/ralph/internal/config"
 	wksp "github.com/zon/ralph/internal/orchestration/workspace"
 	ralphsvc "github.com/zon/ralph/internal/services"
@@ -144,6 +146,7 @@ func (m *mockGitClient) CommitAndPushFromReport() error {
 type mockGitHubClient struct {
 	postCommentFunc func(int, string) error
 	commentPosted   bool
+	assigned        []string
 }
 
 func (m *mockGitHubClient) PostComment(prNumber int, body string) error {
@@ -154,6 +157,10 @@ func (m *mockGitHubClient) PostComment(prNumber int, body string) error {
 	return nil
 }
 
+func (m *mockGitHubClient) AssignPR(prNumber int, login string) {
+	m.assigned = append(m.assigned, fmt.Sprintf("#%d %s", prNumber, login))
+}
+
 var mockWksp *mockWorkspaceSetupClient
 var mockCfg *mockConfigClient
 var mockAI *mockAIClient
@@ -285,6 +292,13 @@ func (h *githubHelper) commentPosted() bool {
 	return mockGH != nil && mockGH.commentPosted
 }
 
+func (h *githubHelper) assigned() []string {
+	if mockGH == nil {
+		return nil
+	}
+	return mockGH.assigned
+}
+
 type flagsHelper struct{}
 
 var flags = &flagsHelper{}
@@ -314,3 +328,9 @@ func (h *flagsHelper) withNoServices() WorkflowCommentFlags {
 	f.NoServices = true
 	return f
 }
+
+func (h *flagsHelper) withAssignee(login string) WorkflowCommentFlags {
+	f := h.any()
+	f.Assignee = login
+	return f
+}
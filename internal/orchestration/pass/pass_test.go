@@ -1,10 +1,13 @@
 package pass
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/project"
 )
 
@@ -40,3 +43,25 @@ func TestPassCmd_SlugNotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, proj)
 }
+
+func TestPassCmd_BackupBeforeSave(t *testing.T) {
+	path := project.FileWithRequirement(t, "my-req", false)
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	configDir := t.TempDir()
+	project.SetBackup(&config.RalphConfig{ConfigDir: configDir, Backup: config.BackupConfig{Enabled: true, Keep: 3}})
+	t.Cleanup(func() { project.SetBackup(nil) })
+
+	backupDir := filepath.Join(configDir, "backups")
+	cmd := &PassCmd{ProjectFile: path, Slug: "my-req"}
+	_, err = cmd.Run()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	backup, err := os.ReadFile(filepath.Join(backupDir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, original, backup)
+}
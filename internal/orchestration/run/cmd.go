@@ -1,10 +1,10 @@
 package run
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/zon/ralph/internal/config"
-	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/project"
 )
 
@@ -15,10 +15,14 @@ type RunCmd struct {
 	config    config.Loader
 	local     LocalRunnerClient
 	remote    RemoteRunnerClient
+	plans     PlanPrinter
 }
 
 type WorkspaceClient interface {
 	ChangeDirectory(path string) error
+	// Isolate clones the repository into a temporary directory, changes into it and carries the
+	// input file across. It returns the input file's path in the clone and a func that removes it.
+	Isolate(inputPath string) (string, func(), error)
 }
 
 type ProjectRepo interface {
@@ -52,6 +56,23 @@ type RunFlags struct {
 	Base            string
 	Model           string
 	Context         string
+	Namespace       string // Kubernetes namespace the workflow is submitted to
+	Image           string // Container image reference that replaces workflow.image
+	SummaryJSON     string
+	Events          string
+	AllowIncomplete bool // Treat a local run that ends incomplete as a success
+	ForcePush       bool
+	AllowBasePush   bool              // Allow pushing while the base branch is checked out
+	Params          map[string]string // Custom workflow parameters from --param
+	DryRun          bool              // Print and lint the workflow instead of submitting it
+	Plan            bool              // Print the run plan without running the agent or changing git state
+	KeepFailed      bool              // Keep the pods of failed workflows for debugging
+	NoPush          bool              // Commit locally without pushing or creating a pull request
+	Offline         bool              // Skip fetches and remote checks; implies NoPush
+	SquashBeforePR  bool              // Squash the project branch into one commit before creating the pull request
+	Stream          bool              // Relay agent output line by line under iteration headers
+	Isolated        bool              // Run in a temporary clone instead of the working copy
+	PRIfComplete    bool              // Open the pull request for a project that already passes instead of stopping
 }
 
 func (f RunFlags) Validate() error {
@@ -61,10 +82,79 @@ func (f RunFlags) Validate() error {
 	if f.Debug != "" && f.Local {
 		return fmt.Errorf("--debug flag is not applicable with --local flag")
 	}
+	if f.InputFile == project.StdinPath && !f.Local {
+		return fmt.Errorf("reading the project from stdin is only supported with --local flag")
+	}
+	if f.SummaryJSON != "" && !f.Local {
+		return fmt.Errorf("--summary-json flag is only applicable with --local flag")
+	}
+	if f.Events != "" && !f.Local {
+		return fmt.Errorf("--events flag is only applicable with --local flag")
+	}
+	if f.AllowIncomplete && !f.Local {
+		return fmt.Errorf("--allow-incomplete flag is only applicable with --local flag")
+	}
+	if f.ForcePush && !f.Local {
+		return fmt.Errorf("--force-push flag is only applicable with --local flag")
+	}
+	if f.AllowBasePush && !f.Local {
+		return fmt.Errorf("--allow-base-push flag is only applicable with --local flag")
+	}
+	if f.NoPush && !f.Local {
+		return fmt.Errorf("--no-push flag is only applicable with --local flag")
+	}
+	if f.Offline && !f.Local {
+		return fmt.Errorf("--offline flag is only applicable with --local flag")
+	}
+	if f.Offline && f.ForcePush {
+		return fmt.Errorf("--force-push flag is not applicable with --offline flag")
+	}
+	if f.NoPush && f.ForcePush {
+		return fmt.Errorf("--force-push flag is not applicable with --no-push flag")
+	}
+	if f.SquashBeforePR && !f.Local {
+		return fmt.Errorf("--squash-before-pr flag is only applicable with --local flag")
+	}
+	if f.Isolated && !f.Local {
+		return fmt.Errorf("--isolated flag is only applicable with --local flag")
+	}
+	if f.Isolated && (f.NoPush || f.Offline) {
+		return fmt.Errorf("--isolated flag is not applicable with --no-push or --offline flag, since the clone and its commits are removed when the run ends")
+	}
+	if f.Stream && !f.Local {
+		return fmt.Errorf("--stream flag is only applicable with --local flag")
+	}
+	if f.PRIfComplete && !f.Local {
+		return fmt.Errorf("--pr-if-complete flag is only applicable with --local flag")
+	}
+	if f.Namespace != "" && f.Local {
+		return fmt.Errorf("--namespace flag is not applicable with --local flag")
+	}
+	if f.Image != "" && f.Local {
+		return fmt.Errorf("--image flag is not applicable with --local flag")
+	}
+	if len(f.Params) > 0 && f.Local {
+		return fmt.Errorf("--param flag is not applicable with --local flag")
+	}
+	if f.KeepFailed && f.Local {
+		return fmt.Errorf("--keep-failed flag is not applicable with --local flag")
+	}
+	if f.DryRun && f.Local {
+		return fmt.Errorf("--dry-run flag is not applicable with --local flag")
+	}
+	if f.DryRun && f.Follow {
+		return fmt.Errorf("--dry-run flag is not applicable with --follow flag")
+	}
+	if f.Plan && f.DryRun {
+		return fmt.Errorf("--plan flag is not applicable with --dry-run flag")
+	}
+	if f.Plan && f.Follow {
+		return fmt.Errorf("--plan flag is not applicable with --follow flag")
+	}
 	return nil
 }
 
-func NewRunCmd(workspace WorkspaceClient, project ProjectRepo, git GitClient, config config.Loader, local LocalRunnerClient, remote RemoteRunnerClient) *RunCmd {
+func NewRunCmd(workspace WorkspaceClient, project ProjectRepo, git GitClient, config config.Loader, local LocalRunnerClient, remote RemoteRunnerClient, plans PlanPrinter) *RunCmd {
 	return &RunCmd{
 		workspace: workspace,
 		project:   project,
@@ -72,10 +162,14 @@ func NewRunCmd(workspace WorkspaceClient, project ProjectRepo, git GitClient, co
 		config:    config,
 		local:     local,
 		remote:    remote,
+		plans:     plans,
 	}
 }
 
 func (r *RunCmd) Run(flags RunFlags) error {
+	if err := flags.Validate(); err != nil {
+		return err
+	}
 	if err := r.workspace.ChangeDirectory(flags.WorkingDir); err != nil {
 		return err
 	}
@@ -83,17 +177,38 @@ func (r *RunCmd) Run(flags RunFlags) error {
 	if err != nil {
 		return err
 	}
-	if err := flags.Validate(); err != nil {
-		return err
+	defer input.Cleanup()
+	if !flags.Plan && !flags.PRIfComplete && isComplete(input) {
+		r.plans.PrintNothingToDo(input.Project(), flags.Local)
+		return nil
+	}
+	if flags.Isolated && !flags.Plan {
+		path, remove, err := r.workspace.Isolate(input.Path())
+		if err != nil {
+			return err
+		}
+		defer remove()
+		if input, err = r.project.ResolveInputFile(path); err != nil {
+			return err
+		}
 	}
 	setup, err := r.prepareSetup(flags, input)
 	if err != nil {
 		return err
 	}
+	if flags.Plan {
+		r.plans.PrintPlan(buildPlan(input, setup, flags))
+		return nil
+	}
 	if flags.Local {
-		return r.local.RunLocal(input, setup.Config, setup.BaseBranch)
+		err := r.local.RunLocal(input, setup.Config, setup.BaseBranch)
+		var incomplete *IncompleteError
+		if flags.AllowIncomplete && errors.As(err, &incomplete) {
+			return nil
+		}
+		return err
 	}
-	return r.remote.Run(input, RunRemoteFlags{Follow: flags.Follow, Debug: flags.Debug, BaseBranch: setup.BaseBranch})
+	return r.remote.Run(input, RunRemoteFlags{Follow: flags.Follow, Debug: flags.Debug, BaseBranch: setup.BaseBranch, DryRun: flags.DryRun})
 }
 
 func (r *RunCmd) prepareSetup(flags RunFlags, input *project.InputFile) (ExecutionSetup, error) {
@@ -105,7 +220,7 @@ func (r *RunCmd) prepareSetup(flags RunFlags, input *project.InputFile) (Executi
 	if err != nil {
 		return ExecutionSetup{}, err
 	}
-	projectBranch := git.SanitizeBranchName(input.Slug())
+	projectBranch := input.BranchName()
 	baseBranch := resolveBaseBranch(flags.Base, currentBranch, projectBranch, cfg.DefaultBranch)
 	if flags.ExtraIterations != 0 {
 		v := flags.ExtraIterations
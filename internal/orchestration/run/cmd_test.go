@@ -19,6 +19,9 @@ type mockWorkspaceClient struct {
 	ChangeDirectoryFunc func(string) error
 	ChangedDir          string
 	ChangeDirCalled     bool
+	IsolateFunc         func(string) (string, error)
+	IsolatedInput       string
+	Removed             bool
 }
 
 func (m *mockWorkspaceClient) ChangeDirectory(path string) error {
@@ -30,6 +33,18 @@ func (m *mockWorkspaceClient) ChangeDirectory(path string) error {
 	return nil
 }
 
+func (m *mockWorkspaceClient) Isolate(inputPath string) (string, func(), error) {
+	m.IsolatedInput = inputPath
+	if m.IsolateFunc != nil {
+		path, err := m.IsolateFunc(inputPath)
+		if err != nil {
+			return "", nil, err
+		}
+		return path, func() { m.Removed = true }, nil
+	}
+	return inputPath, func() { m.Removed = true }, nil
+}
+
 type mockProjectRepo struct {
 	ResolveInputFileFunc  func(string) (*project.InputFile, error)
 	ResolveInputFileCalled bool
@@ -70,6 +85,19 @@ func (m *mockLocalRunnerClient) RunLocal(input *project.InputFile, cfg *config.R
 	return nil
 }
 
+type mockPlanPrinter struct {
+	Plans   []Plan
+	Notices []string
+}
+
+func (m *mockPlanPrinter) PrintPlan(plan Plan) {
+	m.Plans = append(m.Plans, plan)
+}
+
+func (m *mockPlanPrinter) PrintNothingToDo(proj *project.Project, _ bool) {
+	m.Notices = append(m.Notices, proj.Slug)
+}
+
 type mockRemoteRunnerClient struct {
 	RunFunc    func(*project.InputFile, RunRemoteFlags) error
 	LastInput  *project.InputFile
@@ -125,6 +153,7 @@ func cmdWithMocks(opts ...cmdOption) *RunCmd {
 		git:       &git.MockClient{},
 		local:     &mockLocalRunnerClient{},
 		remote:    &mockRemoteRunnerClient{},
+		plans:     &mockPlanPrinter{},
 	}
 	for _, opt := range opts {
 		opt(cmd)
@@ -252,6 +281,20 @@ func remoteRunCalled(cmd *RunCmd) bool {
 	return false
 }
 
+func printedPlans(cmd *RunCmd) []Plan {
+	if m, ok := cmd.plans.(*mockPlanPrinter); ok {
+		return m.Plans
+	}
+	return nil
+}
+
+func printedNotices(cmd *RunCmd) []string {
+	if m, ok := cmd.plans.(*mockPlanPrinter); ok {
+		return m.Notices
+	}
+	return nil
+}
+
 func localLastInput(cmd *RunCmd) *project.InputFile {
 	if m, ok := cmd.local.(*mockLocalRunnerClient); ok {
 		return m.LastInput
@@ -399,6 +442,184 @@ func TestRunDebugWithLocalRejected(t *testing.T) {
 	require.Contains(t, err.Error(), "--debug flag is not applicable with --local flag")
 }
 
+func TestRunAllowIncompleteRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", AllowIncomplete: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--allow-incomplete flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunForcePushRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", ForcePush: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--force-push flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunAllowBasePushRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", AllowBasePush: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--allow-base-push flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunParamRejectedWithLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, Params: map[string]string{"env": "staging"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--param flag is not applicable with --local flag")
+}
+
+func TestRunNamespaceRejectedWithLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, Namespace: "staging"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--namespace flag is not applicable with --local flag")
+}
+
+func TestRunImageRejectedWithLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, Image: "ghcr.io/me/ralph:dev"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--image flag is not applicable with --local flag")
+}
+
+func TestRunKeepFailedRejectedWithLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, KeepFailed: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--keep-failed flag is not applicable with --local flag")
+}
+
+func TestRunNoPushFlagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   RunFlags
+		wantErr string
+	}{
+		{name: "requires local", flags: RunFlags{InputFile: "/fake/project.yaml", NoPush: true}, wantErr: "--no-push flag is only applicable with --local flag"},
+		{name: "conflicts with force push", flags: RunFlags{InputFile: "/fake/project.yaml", Local: true, NoPush: true, ForcePush: true}, wantErr: "--force-push flag is not applicable with --no-push flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := cmdWithMocks()
+			err := cmd.Run(tt.flags)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+			require.False(t, remoteRunCalled(cmd))
+		})
+	}
+}
+
+func TestRunSquashBeforePRRejectedWithoutLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", SquashBeforePR: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--squash-before-pr flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunIsolatedFlagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   RunFlags
+		wantErr string
+	}{
+		{name: "requires local", flags: RunFlags{InputFile: "/fake/project.yaml", Isolated: true}, wantErr: "--isolated flag is only applicable with --local flag"},
+		{name: "conflicts with no push", flags: RunFlags{InputFile: "/fake/project.yaml", Local: true, Isolated: true, NoPush: true}, wantErr: "--isolated flag is not applicable with --no-push or --offline flag"},
+		{name: "conflicts with offline", flags: RunFlags{InputFile: "/fake/project.yaml", Local: true, Isolated: true, Offline: true}, wantErr: "--isolated flag is not applicable with --no-push or --offline flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &mockWorkspaceClient{}
+			cmd := cmdWithMocks(cmdWithWorkspace(ws))
+			err := cmd.Run(tt.flags)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+			require.Empty(t, ws.IsolatedInput, "no clone is made for rejected flags")
+		})
+	}
+}
+
+func TestRunIsolatedRunsInClone(t *testing.T) {
+	ws := &mockWorkspaceClient{
+		IsolateFunc: func(string) (string, error) { return "/tmp/ralph-isolated-1/repo/project.yaml", nil },
+	}
+	var resolved []string
+	proj := &mockProjectRepo{
+		ResolveInputFileFunc: func(path string) (*project.InputFile, error) {
+			resolved = append(resolved, path)
+			p := project.Any()
+			p.Path = path
+			return project.ForProjectInput(p), nil
+		},
+	}
+	local := &mockLocalRunnerClient{
+		RunLocalFunc: func(input *project.InputFile, _ *config.RalphConfig, _ string) error {
+			require.Equal(t, "/tmp/ralph-isolated-1/repo/project.yaml", input.Path())
+			require.False(t, ws.Removed, "the clone stays until the run ends")
+			return nil
+		},
+	}
+	cmd := cmdWithMocks(cmdWithWorkspace(ws), cmdWithProject(proj), cmdWithLocal(local))
+
+	require.NoError(t, cmd.Run(RunFlags{InputFile: "/repo/project.yaml", Local: true, Isolated: true}))
+	require.Equal(t, "/repo/project.yaml", ws.IsolatedInput)
+	require.Equal(t, []string{"/repo/project.yaml", "/tmp/ralph-isolated-1/repo/project.yaml"}, resolved)
+	require.True(t, local.RunLocalCalled)
+	require.True(t, ws.Removed)
+}
+
+func TestRunIsolatedRemovesCloneWhenRunFails(t *testing.T) {
+	ws := &mockWorkspaceClient{}
+	local := &mockLocalRunnerClient{
+		RunLocalFunc: func(*project.InputFile, *config.RalphConfig, string) error { return errors.New("agent failed") },
+	}
+	cmd := cmdWithMocks(cmdWithWorkspace(ws), cmdWithLocal(local))
+
+	require.Error(t, cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, Isolated: true}))
+	require.True(t, ws.Removed)
+}
+
+func TestRunIsolatedCloneFailureStopsRun(t *testing.T) {
+	ws := &mockWorkspaceClient{
+		IsolateFunc: func(string) (string, error) { return "", errors.New("failed to clone repository") },
+	}
+	local := &mockLocalRunnerClient{}
+	cmd := cmdWithMocks(cmdWithWorkspace(ws), cmdWithLocal(local))
+
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, Isolated: true})
+	require.ErrorContains(t, err, "failed to clone repository")
+	require.False(t, local.RunLocalCalled)
+}
+
+func TestRunStreamRejectedWithoutLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Stream: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--stream flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunDryRunRejectedWithLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Local: true, DryRun: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--dry-run flag is not applicable with --local flag")
+}
+
+func TestRunDryRunRejectedWithFollow(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Follow: true, DryRun: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--dry-run flag is not applicable with --follow flag")
+}
+
 // ---------------------------------------------------------------------------
 // Tests: Model and Context included in ExecutionSetup (item)
 // ---------------------------------------------------------------------------
@@ -550,21 +771,18 @@ func TestRunRemoteDispatchesWithSpecInput(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Input file not found aborts before flag validation and setup
+// Tests: Flag validation rejects a run before the input is resolved
 // ---------------------------------------------------------------------------
 
-func TestRunInputFileNotFoundAbortsBeforeFlagValidation(t *testing.T) {
-	proj := &mockProjectRepo{
-		ResolveInputFileFunc: func(string) (*project.InputFile, error) {
-			return nil, errors.New("input file not found: /nonexistent.yaml")
-		},
-	}
+func TestRunFlagValidationAbortsBeforeInputResolution(t *testing.T) {
+	proj := &mockProjectRepo{}
 	cmd := cmdWithMocks(
 		cmdWithProject(proj),
 	)
 	err := cmd.Run(flagsWithFollowAndLocal())
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "input file not found")
+	require.Contains(t, err.Error(), "--follow flag is not applicable with --local flag")
+	require.False(t, proj.ResolveInputFileCalled, "stdin must not be read before the flags are validated")
 }
 
 // ---------------------------------------------------------------------------
@@ -598,3 +816,155 @@ func TestPrepareSetupWithSpecInputResolvesBaseBranch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "main", setup.BaseBranch)
 }
+
+func TestRunLocalIncompleteHonoursAllowIncomplete(t *testing.T) {
+	incomplete := &IncompleteError{Err: project.ErrExtraIterationsReached}
+	tests := []struct {
+		name            string
+		allowIncomplete bool
+		runErr          error
+		wantCode        int
+	}{
+		{name: "fail on incomplete", allowIncomplete: false, runErr: incomplete, wantCode: ExitIncomplete},
+		{name: "allow incomplete", allowIncomplete: true, runErr: incomplete, wantCode: ExitComplete},
+		{name: "allow incomplete still fails on errors", allowIncomplete: true, runErr: errors.New("agent failed"), wantCode: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := cmdWithMocks(
+				cmdWithLocal(&mockLocalRunnerClient{
+					RunLocalFunc: func(*project.InputFile, *config.RalphConfig, string) error { return tt.runErr },
+				}),
+			)
+			flags := flagsWithLocal()
+			flags.AllowIncomplete = tt.allowIncomplete
+
+			err := cmd.Run(flags)
+			require.Equal(t, tt.wantCode, ExitCode(err))
+		})
+	}
+}
+
+func TestRunStdinInputRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: project.StdinPath})
+	require.Error(t, err)
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunPlanPrintsPlanWithoutRunning(t *testing.T) {
+	proj := &project.Project{
+		Slug: "my-project",
+		Requirements: []project.Requirement{
+			{Slug: "setup", Passing: true},
+			{Slug: "api", DependsOn: []string{"schema"}},
+			{Slug: "schema"},
+		},
+	}
+	cmd := cmdWithMocks(
+		cmdWithGit(gitOnBranch("main")),
+		cmdWithProject(&mockProjectRepo{InputFile: project.ForProjectInput(proj)}),
+	)
+	flags := flagsAny()
+	flags.Plan = true
+
+	require.NoError(t, cmd.Run(flags))
+
+	require.False(t, localRunLocalCalled(cmd))
+	require.False(t, remoteRunCalled(cmd))
+	plans := printedPlans(cmd)
+	require.Len(t, plans, 1)
+	require.Equal(t, "schema", plans[0].Next)
+	require.Equal(t, "my-project", plans[0].Branch)
+	require.Equal(t, "main", plans[0].BaseBranch)
+	require.Equal(t, 2, plans[0].Failing)
+	require.Equal(t, "main", plans[0].CurrentBranch)
+}
+
+func TestRunPlanCarriesPushFlags(t *testing.T) {
+	cmd := cmdWithMocks(
+		cmdWithGit(gitOnBranch("main")),
+	)
+	flags := flagsWithLocal()
+	flags.Plan = true
+	flags.Offline = true
+	flags.SquashBeforePR = true
+
+	require.NoError(t, cmd.Run(flags))
+
+	plans := printedPlans(cmd)
+	require.Len(t, plans, 1)
+	require.True(t, plans[0].NoPush, "offline implies no push")
+	require.True(t, plans[0].Offline)
+	require.True(t, plans[0].SquashBeforePR)
+}
+
+func TestRunCompleteProjectHasNothingToDo(t *testing.T) {
+	for _, local := range []bool{true, false} {
+		cmd := cmdWithMocks(
+			cmdWithProject(&mockProjectRepo{InputFile: project.ForProjectInput(project.WithAllPassing())}),
+		)
+		flags := flagsAny()
+		flags.Local = local
+
+		require.NoError(t, cmd.Run(flags))
+
+		require.False(t, localRunLocalCalled(cmd))
+		require.False(t, remoteRunCalled(cmd))
+		notices := printedNotices(cmd)
+		require.Len(t, notices, 1)
+		require.Equal(t, []string{"test-project"}, notices)
+	}
+}
+
+func TestRunCompleteProjectOpensPRWithPRIfComplete(t *testing.T) {
+	cmd := cmdWithMocks(
+		cmdWithProject(&mockProjectRepo{InputFile: project.ForProjectInput(project.WithAllPassing())}),
+	)
+	flags := flagsWithLocal()
+	flags.PRIfComplete = true
+
+	require.NoError(t, cmd.Run(flags))
+
+	require.True(t, localRunLocalCalled(cmd))
+	require.Empty(t, printedNotices(cmd))
+}
+
+func TestRunPRIfCompleteRejectedWithoutLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", PRIfComplete: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--pr-if-complete flag is only applicable with --local flag")
+	require.False(t, remoteRunCalled(cmd))
+}
+
+func TestRunPlanRejectsDryRunAndFollow(t *testing.T) {
+	for _, flags := range []RunFlags{
+		{InputFile: "/fake/project.yaml", Plan: true, DryRun: true},
+		{InputFile: "/fake/project.yaml", Plan: true, Follow: true},
+	} {
+		cmd := cmdWithMocks()
+		require.Error(t, cmd.Run(flags))
+		require.Empty(t, printedPlans(cmd))
+	}
+}
+
+func TestRunOfflineFlagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   RunFlags
+		wantErr string
+	}{
+		{name: "requires --local", flags: RunFlags{InputFile: "/fake/project.yaml", Offline: true}, wantErr: "--offline flag is only applicable with --local flag"},
+		{name: "rejects --force-push", flags: RunFlags{InputFile: "/fake/project.yaml", Local: true, Offline: true, ForcePush: true}, wantErr: "--force-push flag is not applicable with --offline flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cmdWithMocks().Run(tt.flags)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
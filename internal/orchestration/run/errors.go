@@ -3,3 +3,43 @@ package run
 import "errors"
 
 var ErrBlocked = errors.New("blocked")
+
+// ErrAllStuck is returned when every failing requirement has reached the per-requirement attempt cap.
+var ErrAllStuck = errors.New("all failing requirements are stuck")
+
+// Process exit codes reported by run entrypoints.
+const (
+	ExitComplete   = 0 // All requirements are passing
+	ExitError      = 1 // The run failed with an error
+	ExitIncomplete = 2 // The iteration limit was reached without an error, but requirements are still failing
+)
+
+// IncompleteError is returned when a run exhausts its iterations with requirements still
+// failing. It implements kong.ExitCoder so the process exits with ExitIncomplete.
+type IncompleteError struct {
+	Err error
+}
+
+func (e *IncompleteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *IncompleteError) Unwrap() error {
+	return e.Err
+}
+
+func (e *IncompleteError) ExitCode() int {
+	return ExitIncomplete
+}
+
+// ExitCode returns the process exit code for an error returned by a run.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitComplete
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitError
+}
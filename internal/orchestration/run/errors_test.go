@@ -0,0 +1,68 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/project"
+)
+
+func TestRunLocalExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		runner *Runner
+		proj   *project.Project
+		want   int
+	}{
+		{
+			name:   "complete",
+			runner: withMocks(withProject(newProjectThatReportsAllPassing())),
+			proj:   project.WithAllPassing(),
+			want:   ExitComplete,
+		},
+		{
+			name:   "incomplete after iteration limit",
+			runner: withMocks(withProject(newProjectThatAlwaysReportsFailures())),
+			proj:   project.WithFailingRequirements(),
+			want:   ExitIncomplete,
+		},
+		{
+			name:   "agent failure",
+			runner: withMocks(withAI(newAIThatAlwaysFails())),
+			proj:   project.WithFailingRequirements(),
+			want:   ExitError,
+		},
+		{
+			name:   "blocked",
+			runner: withMocks(withGit(newGitWithBlockedFile())),
+			proj:   project.WithFailingRequirements(),
+			want:   ExitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.runner.RunLocal(project.ForProjectInput(tt.proj), config.Any())
+			assert.Equal(t, tt.want, ExitCode(err))
+		})
+	}
+}
+
+func TestIncompleteErrorWrapsIterationLimit(t *testing.T) {
+	runner := withMocks(withProject(newProjectThatAlwaysReportsFailures()))
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirements()), config.Any())
+
+	var incomplete *IncompleteError
+	assert.True(t, errors.As(err, &incomplete))
+	assert.ErrorIs(t, err, project.ErrExtraIterationsReached)
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitComplete, ExitCode(nil))
+	assert.Equal(t, ExitError, ExitCode(errors.New("boom")))
+	assert.Equal(t, ExitIncomplete, ExitCode(fmt.Errorf("run: %w", &IncompleteError{Err: project.ErrExtraIterationsReached})))
+}
@@ -0,0 +1,44 @@
+package run
+
+import "github.com/zon/ralph/internal/project"
+
+// SetEventsPath makes RunLocal append a project.Event to path as each step of the loop happens.
+func (r *Runner) SetEventsPath(path string) {
+	r.eventsPath = path
+}
+
+// emit records event in the events file, naming the project when the event does not.
+func (r *Runner) emit(event project.Event) {
+	if r.eventsPath == "" {
+		return
+	}
+	if event.Project == "" && r.proj != nil {
+		event.Project = r.proj.Slug
+	}
+	r.project.AppendEvent(r.eventsPath, event)
+}
+
+func (r *Runner) emitIterationFinished(err error) {
+	event := project.Event{Type: project.EventIterationFinished, Iteration: r.iterations}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	r.emit(event)
+}
+
+// emitRequirementsPassed reports each requirement that passes in after but did not in before.
+func (r *Runner) emitRequirementsPassed(before, after *project.Project) {
+	for _, req := range after.Requirements {
+		if req.Passing && !project.IsRequirementPassing(before, req.Slug) {
+			r.emit(project.Event{Type: project.EventRequirementPassed, Iteration: r.iterations, Requirement: req.Slug})
+		}
+	}
+}
+
+func (r *Runner) emitRunComplete(summary project.Summary, err error) {
+	event := project.Event{Type: project.EventRunComplete, Project: summary.Project, Iteration: summary.Iterations, Summary: &summary}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	r.emit(event)
+}
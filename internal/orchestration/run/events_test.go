@@ -0,0 +1,123 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/project"
+)
+
+// newProjectPassingOneRequirementPerIteration returns a project client backed by proj in which
+// each developer run makes the next failing requirement pass.
+func newProjectPassingOneRequirementPerIteration(proj *project.Project) (*project.MockClient, *mockAIClient) {
+	state := *proj
+	state.Requirements = append([]project.Requirement(nil), proj.Requirements...)
+	snapshot := func() *project.Project {
+		p := state
+		p.Requirements = append([]project.Requirement(nil), state.Requirements...)
+		return &p
+	}
+	projectClient := &project.MockClient{
+		AllPassingFunc: func() bool {
+			complete, _, _ := project.CheckCompletion(&state)
+			return complete
+		},
+		ReloadFunc: func(*project.Project) *project.Project { return snapshot() },
+	}
+	ai := &mockAIClient{
+		runDeveloperFunc: func(string) error {
+			for i := range state.Requirements {
+				if !state.Requirements[i].Passing {
+					state.Requirements[i].Passing = true
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+	return projectClient, ai
+}
+
+func recordedEvents(r *Runner) []project.Event {
+	if m, ok := r.project.(*project.MockClient); ok {
+		return m.Events
+	}
+	return nil
+}
+
+type eventStep struct {
+	Type        string
+	Iteration   int
+	Requirement string
+}
+
+func eventSteps(events []project.Event) []eventStep {
+	steps := make([]eventStep, len(events))
+	for i, event := range events {
+		steps[i] = eventStep{Type: event.Type, Iteration: event.Iteration, Requirement: event.Requirement}
+	}
+	return steps
+}
+
+func TestRunLocalEmitsEvents(t *testing.T) {
+	projectClient, ai := newProjectPassingOneRequirementPerIteration(project.WithFailingRequirementsCount(2))
+	runner := withMocks(withProject(projectClient), withAI(ai))
+	runner.SetEventsPath("events.ndjson")
+
+	require.NoError(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(2)), config.Any()))
+
+	events := recordedEvents(runner)
+	assert.Equal(t, []eventStep{
+		{Type: project.EventIterationStarted, Iteration: 1},
+		{Type: project.EventIterationFinished, Iteration: 1},
+		{Type: project.EventRequirementPassed, Iteration: 1, Requirement: "req-1"},
+		{Type: project.EventIterationStarted, Iteration: 2},
+		{Type: project.EventIterationFinished, Iteration: 2},
+		{Type: project.EventRequirementPassed, Iteration: 2, Requirement: "req-2"},
+		{Type: project.EventRunComplete, Iteration: 2},
+	}, eventSteps(events))
+	for _, event := range events {
+		assert.Equal(t, "test-project", event.Project)
+		assert.Empty(t, event.Error)
+	}
+
+	complete := events[len(events)-1]
+	require.NotNil(t, complete.Summary)
+	assert.True(t, complete.Summary.Complete)
+	assert.Equal(t, 2, complete.Summary.Passing)
+	assert.Equal(t, 0, complete.Summary.Failing)
+}
+
+func TestRunLocalEmitsIterationError(t *testing.T) {
+	runner := withMocks(withAI(newAIThatAlwaysFails()))
+	runner.SetEventsPath("events.ndjson")
+
+	require.Error(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirements()), config.Any()))
+
+	events := recordedEvents(runner)
+	assert.Equal(t, []eventStep{
+		{Type: project.EventIterationStarted, Iteration: 1},
+		{Type: project.EventIterationFinished, Iteration: 1},
+		{Type: project.EventRunComplete, Iteration: 1},
+	}, eventSteps(events))
+	assert.Equal(t, errNonFatal.Error(), events[1].Error)
+	assert.Equal(t, errNonFatal.Error(), events[2].Error)
+	require.NotNil(t, events[2].Summary)
+	assert.False(t, events[2].Summary.Complete)
+}
+
+func TestRunLocalWithoutEventsPathRecordsNothing(t *testing.T) {
+	runner := withMocks(withProject(newProjectThatReportsAllPassing()))
+	require.NoError(t, runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any()))
+	assert.Empty(t, recordedEvents(runner))
+}
+
+func TestRunEventsRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", Events: "events.ndjson"})
+	require.Error(t, err)
+	require.False(t, remoteRunCalled(cmd))
+}
@@ -0,0 +1,40 @@
+package run
+
+import (
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/project"
+)
+
+// ErrGuardrailExceeded ends an iteration whose changes broke a guardrail with the abort action.
+// The loop moves on to the next iteration instead of stopping the run.
+var ErrGuardrailExceeded = project.ErrGuardrailExceeded
+
+// checkGuardrails measures the iteration's changes against the configured guardrails. When a limit
+// is broken, the next iteration is told why; with the abort action the changes are stashed first
+// and ErrGuardrailExceeded is returned.
+func (r *Runner) checkGuardrails(proj *project.Project, cfg *config.RalphConfig, slug string) error {
+	if !cfg.Guardrails.Enabled() || slug == "" {
+		return nil
+	}
+	stat, err := r.git.DiffStat(proj.Path)
+	if err != nil {
+		return err
+	}
+	breach := cfg.Guardrails.Exceeded(stat.Files, stat.Lines)
+	if breach == "" {
+		return nil
+	}
+	if cfg.Guardrails.Action == config.GuardrailWarn {
+		r.ai.AddNote(project.GuardrailNote(slug, breach, false))
+		return nil
+	}
+	if err := r.git.StashChanges(project.GuardrailStashMessage(r.iterations, slug, breach), proj.Path); err != nil {
+		return err
+	}
+	// The project file is kept out of the stash, so restore it to its state before the iteration
+	if err := r.project.Restore(proj); err != nil {
+		return err
+	}
+	r.ai.AddNote(project.GuardrailNote(slug, breach, true))
+	return project.GuardrailError(slug, breach)
+}
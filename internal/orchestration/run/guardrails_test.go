@@ -0,0 +1,120 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/git"
+	"github.com/zon/ralph/internal/project"
+)
+
+// restoreRecorder counts the projects the runner writes back after discarding an iteration.
+type restoreRecorder struct {
+	restored []*project.Project
+}
+
+func (n *restoreRecorder) project() *project.MockClient {
+	proj := newProjectThatAlwaysReportsFailures()
+	proj.RestoreFunc = func(p *project.Project) error {
+		n.restored = append(n.restored, p)
+		return nil
+	}
+	return proj
+}
+
+func aiThatPicks(slug string) *mockAIClient {
+	return &mockAIClient{runPickerFunc: func() (string, error) { return "slug: " + slug, nil }}
+}
+
+func withGuardrails(g config.GuardrailConfig) *config.RalphConfig {
+	cfg := config.WithExtraIterations(1)
+	cfg.Guardrails = g
+	return cfg
+}
+
+func TestGuardrailAbortsLargeIteration(t *testing.T) {
+	gc := newGitWithDiff(120, 5000)
+	restores := &restoreRecorder{}
+	ai := aiThatPicks("req-1")
+	runner := withMocks(withGit(gc), withProject(restores.project()), withAI(ai))
+
+	input := project.WithFailingRequirementsCount(1)
+	err := runner.RunLocal(project.ForProjectInput(input), withGuardrails(config.GuardrailConfig{MaxFiles: 50}))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrGuardrailExceeded, "a discarded iteration does not end the run")
+
+	require.Len(t, aiPickCalls(runner), 2, "the loop moves on after a discarded iteration")
+	require.Len(t, gc.StashedMessages, 2)
+	assert.Equal(t, "ralph: iteration 1 on req-1 changed 120 files, more than the guardrail of 50", gc.StashedMessages[0])
+	assert.False(t, gitCommittedFromReport(runner), "discarded changes are never committed")
+	assert.Equal(t, []*project.Project{input, input}, restores.restored, "the project file is put back after each discarded iteration")
+	require.NotEmpty(t, ai.addedNotes)
+	assert.Equal(t, project.GuardrailNote("req-1", "changed 120 files, more than the guardrail of 50", true), ai.addedNotes[0])
+}
+
+func TestGuardrailLineLimit(t *testing.T) {
+	gc := newGitWithDiff(3, 5000)
+	runner := withMocks(withGit(gc), withAI(aiThatPicks("req-1")))
+
+	require.Error(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(1)), withGuardrails(config.GuardrailConfig{MaxFiles: 50, MaxLines: 1000})))
+	require.NotEmpty(t, gc.StashedMessages)
+	assert.Contains(t, gc.StashedMessages[0], "changed 5000 lines, more than the guardrail of 1000")
+}
+
+func TestGuardrailPassesSmallIteration(t *testing.T) {
+	gc := newGitWithDiff(3, 40)
+	restores := &restoreRecorder{}
+	ai := aiThatPicks("req-1")
+	runner := withMocks(withGit(gc), withProject(restores.project()), withAI(ai))
+
+	require.Error(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(1)), withGuardrails(config.GuardrailConfig{MaxFiles: 50, MaxLines: 1000})))
+	assert.Empty(t, gc.StashedMessages)
+	assert.Empty(t, restores.restored)
+	assert.Empty(t, ai.addedNotes)
+	assert.True(t, gitCommittedFromReport(runner))
+}
+
+func TestGuardrailWarnCommitsAndNotes(t *testing.T) {
+	gc := newGitWithDiff(120, 5000)
+	restores := &restoreRecorder{}
+	ai := aiThatPicks("req-1")
+	runner := withMocks(withGit(gc), withProject(restores.project()), withAI(ai))
+
+	require.Error(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(1)), withGuardrails(config.GuardrailConfig{MaxFiles: 50, Action: config.GuardrailWarn})))
+	assert.Empty(t, gc.StashedMessages)
+	assert.True(t, gitCommittedFromReport(runner))
+	assert.Empty(t, restores.restored)
+	require.NotEmpty(t, ai.addedNotes)
+	assert.Equal(t, project.GuardrailNote("req-1", "changed 120 files, more than the guardrail of 50", false), ai.addedNotes[0])
+}
+
+func TestGuardrailDisabledSkipsDiff(t *testing.T) {
+	gc := newGitWithDiff(120, 5000)
+	measured := false
+	gc.DiffStatFunc = func(...string) (git.DiffStat, error) {
+		measured = true
+		return git.DiffStat{Files: 120, Lines: 5000}, nil
+	}
+	runner := withMocks(withGit(gc), withAI(aiThatPicks("req-1")))
+
+	require.Error(t, runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(1)), config.WithExtraIterations(0)))
+	assert.False(t, measured)
+	assert.Empty(t, gc.StashedMessages)
+	assert.True(t, gitCommittedFromReport(runner))
+}
+
+func TestGuardrailDiscardedIterationsCountTowardsStuck(t *testing.T) {
+	gc := newGitWithDiff(120, 5000)
+	cfg := config.WithExtraIterations(3)
+	cfg.Guardrails = config.GuardrailConfig{MaxFiles: 50}
+	cfg.RequirementAttempts = 2
+	ai := aiThatPicks("req-1")
+	runner := withMocks(withGit(gc), withAI(ai))
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(1)), cfg)
+	require.ErrorIs(t, err, ErrAllStuck)
+	assert.Contains(t, ai.addedNotes, project.StuckNote("req-1", 2))
+}
@@ -0,0 +1,79 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/project"
+	"github.com/zon/ralph/internal/services"
+)
+
+func configWithRunHooks() *config.RalphConfig {
+	cfg := config.WithExtraIterations(0)
+	cfg.PreRun = &config.Hook{Command: "./provision.sh"}
+	cfg.PostRun = &config.Hook{Command: "./teardown.sh"}
+	return cfg
+}
+
+func TestRunHooksRunAroundIterations(t *testing.T) {
+	svc := &services.MockClient{}
+	ai := &mockAIClient{}
+	var events []string
+	svc.RunHookFunc = func(name string, _ *config.Hook) {
+		events = append(events, name)
+	}
+	ai.runDeveloperFunc = func(string) error {
+		events = append(events, "iteration")
+		return nil
+	}
+	runner := withMocks(
+		withServices(svc),
+		withAI(ai),
+		withProject(newProjectThatReportsPassingAfterIterations(1)),
+	)
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirements()), configWithRunHooks())
+	require.NoError(t, err)
+	require.Equal(t, []string{HookPreRun, "iteration", HookPostRun}, events)
+}
+
+func TestPostRunHookRunsWhenLoopFails(t *testing.T) {
+	svc := &services.MockClient{}
+	runner := withMocks(
+		withServices(svc),
+		withAI(newAIThatReturnsFatalError()),
+	)
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirements()), configWithRunHooks())
+	require.Error(t, err)
+	require.Equal(t, []string{HookPreRun, HookPostRun}, svc.Hooks)
+}
+
+func TestRunHooksSkippedWhenNotConfigured(t *testing.T) {
+	svc := &services.MockClient{}
+	runner := withMocks(
+		withServices(svc),
+		withProject(newProjectThatReportsAllPassing()),
+	)
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any())
+	require.NoError(t, err)
+	require.Empty(t, svc.Hooks)
+}
+
+func TestAfterIterationHookRunsOncePerIteration(t *testing.T) {
+	svc := &services.MockClient{}
+	cfg := config.WithExtraIterations(1)
+	cfg.AfterIteration = &config.Hook{Command: "gofmt"}
+	runner := withMocks(
+		withServices(svc),
+		withProject(newProjectThatReportsPassingAfterIterations(2)),
+	)
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirements()), cfg)
+	require.NoError(t, err)
+	require.Equal(t, []string{HookAfterIteration, HookAfterIteration}, svc.Hooks)
+	require.Len(t, aiDevelopCalls(runner), 2)
+}
@@ -153,3 +153,75 @@ func TestRunLocalSkipsPRWhenIterationLimitReached(t *testing.T) {
 	require.Error(t, err)
 	require.False(t, prCalled, "PR should not be created when iteration limit is reached")
 }
+
+func TestIterateNotesRegressionsForNextIteration(t *testing.T) {
+	before := project.WithFailingRequirementsCount(2)
+	before.Requirements[0].Passing = true
+	after := project.WithFailingRequirementsCount(2)
+
+	reloads := 0
+	proj := newProjectThatAlwaysReportsFailures()
+	proj.ReloadFunc = func(p *project.Project) *project.Project {
+		reloads++
+		if reloads == 2 {
+			return after
+		}
+		return p
+	}
+	ai := &mockAIClient{}
+	var notes [][]string
+	ai.runPickerFunc = func() (string, error) {
+		notes = append(notes, ai.notes)
+		return "mock-requirement", nil
+	}
+	runner := withMocks(withProject(proj), withAI(ai))
+	_ = runner.RunLocal(project.ForProjectInput(before), config.WithExtraIterations(0))
+	require.Len(t, notes, 2)
+	require.Empty(t, notes[0])
+	require.Equal(t, []string{project.RegressionNote(before.Requirements[0].Slug)}, notes[1])
+	require.Empty(t, ai.notes, "notes are cleared once the iteration they were meant for has run")
+}
+
+func TestIterateShiftsFocusFromStuckRequirement(t *testing.T) {
+	ai := &mockAIClient{}
+	ai.runPickerFunc = func() (string, error) {
+		picked := ai.pickCalls[len(ai.pickCalls)-1]
+		return "slug: " + picked.Requirements[0].Slug, nil
+	}
+	cfg := config.WithExtraIterations(3)
+	cfg.RequirementAttempts = 2
+	runner := withMocks(withAI(ai))
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(2)), cfg)
+	require.ErrorIs(t, err, ErrAllStuck)
+	require.Equal(t, ExitIncomplete, ExitCode(err))
+	require.Equal(t, []string{project.StuckNote("req-1", 2), project.StuckNote("req-2", 2)}, ai.addedNotes)
+
+	var picked []int
+	for _, p := range aiPickCalls(runner) {
+		picked = append(picked, len(p.Requirements))
+	}
+	require.Equal(t, []int{2, 2, 1, 1}, picked, "picker should stop seeing req-1 once it is stuck")
+	for _, p := range aiDevelopCalls(runner) {
+		require.Len(t, p.Requirements, 2, "developer still sees the full project")
+	}
+}
+
+type headerRecorder struct {
+	headers [][2]int
+}
+
+func (h *headerRecorder) IterationHeader(iteration, limit int) {
+	h.headers = append(h.headers, [2]int{iteration, limit})
+}
+
+func TestIteratePrintsIterationHeaders(t *testing.T) {
+	projectClient, ai := newProjectPassingOneRequirementPerIteration(project.WithFailingRequirementsCount(2))
+	runner := withMocks(withProject(projectClient), withAI(ai))
+	headers := &headerRecorder{}
+	runner.SetHeaders(headers)
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithFailingRequirementsCount(2)), config.WithExtraIterations(1))
+	require.NoError(t, err)
+	require.Equal(t, [][2]int{{1, 3}, {2, 3}}, headers.headers)
+}
@@ -42,6 +42,8 @@ type mockAIClient struct {
 	isFatalFunc              func(err error) bool
 	changelogFunc            func() error
 	fixServiceFunc           func(*config.RalphConfig, error) error
+	notes                    []string
+	addedNotes               []string
 	pickCalls                []*project.Project
 	developCalls             []*project.Project
 	changelogCalls           []*project.Project
@@ -82,6 +84,15 @@ func (m *mockAIClient) RunDeveloper(proj *project.Project, req string) error {
 	return nil
 }
 
+func (m *mockAIClient) AddNote(note string) {
+	m.notes = append(m.notes, note)
+	m.addedNotes = append(m.addedNotes, note)
+}
+
+func (m *mockAIClient) ClearNotes() {
+	m.notes = nil
+}
+
 func (m *mockAIClient) IsFatal(err error) bool {
 	if m.isFatalFunc != nil {
 		return m.isFatalFunc(err)
@@ -175,6 +186,15 @@ func newGitWithChangesAndReport() *git.MockClient {
 	}
 }
 
+// newGitWithDiff returns a git client whose iterations leave a report and changes of the given size.
+func newGitWithDiff(files, lines int) *git.MockClient {
+	return &git.MockClient{
+		HasChangesFunc:   func() bool { return true },
+		ReportExistsFunc: func() bool { return true },
+		DiffStatFunc:     func(...string) (git.DiffStat, error) { return git.DiffStat{Files: files, Lines: lines}, nil },
+	}
+}
+
 func newGitWithChangesButNoReport() *git.MockClient {
 	return &git.MockClient{
 		HasChangesFunc:   func() bool { return true },
@@ -216,6 +236,8 @@ type mockServicesClient struct {
 
 func (m *mockServicesClient) RunBeforeCommands(_ *config.RalphConfig) error { return nil }
 
+func (m *mockServicesClient) RunHook(_ string, _ *config.Hook) {}
+
 func (m *mockServicesClient) Start(_ *config.RalphConfig) (*services.Manager, error) {
 	m.startCount++
 	if m.startFunc != nil {
@@ -477,6 +499,13 @@ func projectOrchestrationRemoved(r *Runner) bool {
 	return false
 }
 
+func projectSummaries(r *Runner) []project.Summary {
+	if m, ok := r.project.(*project.MockClient); ok {
+		return m.Summaries
+	}
+	return nil
+}
+
 func gitOrchestrationRemovalCommitted(r *Runner) bool {
 	if m, ok := r.git.(*trackingGitClient); ok {
 		return m.commitOrchestrationRemovalCalled
@@ -541,6 +570,13 @@ func remoteWorkflowSubmitted(runner *RemoteRunner) bool {
 	return false
 }
 
+func remoteWorkflowDryRunCalled(runner *RemoteRunner) bool {
+	if m, ok := runner.workflow.(*workflow.MockClient); ok {
+		return m.DryRunCalled
+	}
+	return false
+}
+
 func remoteWorkflowLogHintPrinted(runner *RemoteRunner) bool {
 	if m, ok := runner.workflow.(*workflow.MockClient); ok {
 		return m.PrintLogHintCalled
@@ -602,6 +638,14 @@ func runRemoteFlagsWithoutFollow() RunRemoteFlags {
 	return RunRemoteFlags{}
 }
 
+func runRemoteFlagsWithDryRun() RunRemoteFlags {
+	return RunRemoteFlags{DryRun: true}
+}
+
 func runRemoteFlagsWithDebug(branch string) RunRemoteFlags {
 	return RunRemoteFlags{Debug: branch}
 }
+
+func runRemoteFlagsWithBase(branch string) RunRemoteFlags {
+	return RunRemoteFlags{BaseBranch: branch}
+}
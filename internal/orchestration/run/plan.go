@@ -0,0 +1,66 @@
+package run
+
+import "github.com/zon/ralph/internal/project"
+
+// Plan describes what a run would do, printed by --plan instead of running.
+type Plan struct {
+	Project        string
+	InputPath      string
+	Generated      bool // The project file is written from a spec or orchestration before the first iteration
+	Local          bool
+	Branch         string
+	CurrentBranch  string
+	BaseBranch     string
+	Requirements   int
+	Failing        int
+	Next           string // Slug of the first requirement to be worked on; empty when all pass
+	IterationLimit int
+	NoPush         bool // Commits stay on the local branch, with no push or pull request
+	Offline        bool // Nothing is fetched or pushed; implies NoPush
+	SquashBeforePR bool // The branch is squashed into one commit before the pull request
+}
+
+type PlanPrinter interface {
+	PrintPlan(plan Plan)
+	// PrintNothingToDo reports that proj already passes, so the run stopped before doing any
+	// work. local tells whether the run was local, where --pr-if-complete applies.
+	PrintNothingToDo(proj *project.Project, local bool)
+}
+
+// isComplete reports whether input is a project whose requirements already all pass, so a run
+// of it has nothing to do. Specs and orchestrations are never complete, since their project is
+// written by the run.
+func isComplete(input *project.InputFile) bool {
+	if !input.IsProject() || input.Project() == nil {
+		return false
+	}
+	complete, _, _ := project.CheckCompletion(input.Project())
+	return complete
+}
+
+func buildPlan(input *project.InputFile, setup ExecutionSetup, flags RunFlags) Plan {
+	plan := Plan{
+		Project:        input.Slug(),
+		InputPath:      input.Path(),
+		Generated:      !input.IsProject(),
+		Local:          flags.Local,
+		Branch:         setup.BranchName,
+		CurrentBranch:  setup.CurrentBranch,
+		BaseBranch:     setup.BaseBranch,
+		NoPush:         flags.NoPush || flags.Offline,
+		Offline:        flags.Offline,
+		SquashBeforePR: flags.SquashBeforePR,
+	}
+	if proj := input.Project(); input.IsProject() && proj != nil {
+		if plan.InputPath == "" {
+			plan.InputPath = proj.Path
+		}
+		plan.Requirements = len(proj.Requirements)
+		_, _, plan.Failing = project.CheckCompletion(proj)
+		if next, ok := proj.NextRequirement(); ok {
+			plan.Next = next.Slug
+		}
+		plan.IterationLimit = plan.Requirements + project.ExtraIterations(proj, setup.Config)
+	}
+	return plan
+}
@@ -1,11 +1,16 @@
 package run
 
-import "github.com/zon/ralph/internal/project"
+import (
+	"fmt"
+
+	"github.com/zon/ralph/internal/project"
+)
 
 type RunRemoteFlags struct {
 	Follow     bool
 	Debug      string
 	BaseBranch string
+	DryRun     bool
 }
 
 type RemoteRunner struct {
@@ -27,9 +32,15 @@ func (r *RemoteRunner) Run(input *project.InputFile, flags RunRemoteFlags) error
 	if err != nil {
 		return err
 	}
+	if flags.DryRun {
+		return r.workflow.DryRun(input, branch, flags.Debug, flags.BaseBranch)
+	}
 	if err := r.git.IsBranchSyncedWithRemote(branch); err != nil {
 		return err
 	}
+	if err := r.checkBaseBranch(flags.BaseBranch); err != nil {
+		return err
+	}
 	workflowName, err := r.workflow.Submit(input, branch, flags.Debug, flags.BaseBranch)
 	if err != nil {
 		return err
@@ -45,3 +56,18 @@ func (r *RemoteRunner) Run(input *project.InputFile, flags RunRemoteFlags) error
 	r.notify.Success(input.Slug())
 	return nil
 }
+
+// checkBaseBranch fails before a workflow is submitted whose pull request could never be opened
+func (r *RemoteRunner) checkBaseBranch(base string) error {
+	if base == "" {
+		return nil
+	}
+	exists, err := r.git.RemoteBranchExists(base)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("base branch '%s' does not exist on the remote - push it or pick another with --base", base)
+	}
+	return nil
+}
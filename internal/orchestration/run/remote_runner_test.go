@@ -37,6 +37,48 @@ func TestRunBranchNotInSyncAbortsBeforeSubmit(t *testing.T) {
 	require.False(t, remoteWorkflowSubmitted(runner))
 }
 
+func TestRunExistingBaseBranchSubmits(t *testing.T) {
+	var checked string
+	runner := withRemoteMocks(
+		withRemoteGit(&git.MockClient{
+			RemoteBranchExistsFunc: func(branch string) (bool, error) {
+				checked = branch
+				return true, nil
+			},
+		}),
+	)
+	err := runner.Run(project.ForProjectInput(project.Any()), runRemoteFlagsWithBase("main"))
+	require.NoError(t, err)
+	require.Equal(t, "main", checked)
+	require.True(t, remoteWorkflowSubmitted(runner))
+}
+
+func TestRunMissingBaseBranchAbortsBeforeSubmit(t *testing.T) {
+	runner := withRemoteMocks(
+		withRemoteGit(&git.MockClient{
+			RemoteBranchExistsFunc: func(branch string) (bool, error) {
+				return false, nil
+			},
+		}),
+	)
+	err := runner.Run(project.ForProjectInput(project.Any()), runRemoteFlagsWithBase("release"))
+	require.EqualError(t, err, "base branch 'release' does not exist on the remote - push it or pick another with --base")
+	require.False(t, remoteWorkflowSubmitted(runner))
+}
+
+func TestRunBaseBranchCheckFailureAbortsBeforeSubmit(t *testing.T) {
+	runner := withRemoteMocks(
+		withRemoteGit(&git.MockClient{
+			RemoteBranchExistsFunc: func(branch string) (bool, error) {
+				return false, fmt.Errorf("failed to check remote branch '%s': could not read from remote", branch)
+			},
+		}),
+	)
+	err := runner.Run(project.ForProjectInput(project.Any()), runRemoteFlagsWithBase("main"))
+	require.Error(t, err)
+	require.False(t, remoteWorkflowSubmitted(runner))
+}
+
 func TestRunSubmitFailureReturnsError(t *testing.T) {
 	runner := withRemoteMocks(
 		withRemoteWorkflow(workflow.ThatFailsOnSubmit()),
@@ -76,3 +118,18 @@ func TestRunDebugBranchPassedToSubmit(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "my-fix", remoteWorkflowLastDebugBranch(runner))
 }
+
+func TestRunDryRunSkipsSubmitAndSyncCheck(t *testing.T) {
+	runner := withRemoteMocks(
+		withRemoteGit(&git.MockClient{
+			IsBranchSyncedWithRemoteFunc: func(branch string) error {
+				return fmt.Errorf("branch '%s' has not been pushed to remote", branch)
+			},
+		}),
+	)
+	err := runner.Run(project.ForProjectInput(project.Any()), runRemoteFlagsWithDryRun())
+	require.NoError(t, err)
+	require.True(t, remoteWorkflowDryRunCalled(runner))
+	require.False(t, remoteWorkflowSubmitted(runner))
+	require.False(t, remoteWorkflowLogHintPrinted(runner))
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/git"
+	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/project"
+	"github.com/zon/ralph/internal/services"
 )
 
 func TestRunLocalBeforeCommandFailureAbortsEarly(t *testing.T) {
@@ -40,6 +42,65 @@ func TestRunLocalAllRequirementsPassCreatesPR(t *testing.T) {
 	require.NotEmpty(t, notifySuccesses(runner))
 }
 
+func TestRunLocalCompleteProjectSkipsLoop(t *testing.T) {
+	var hooks []string
+	beforeCommandsRan := false
+	runner := withMocks(
+		withServices(&services.MockClient{
+			RunBeforeFunc: func(_ *config.RalphConfig) error {
+				beforeCommandsRan = true
+				return nil
+			},
+			RunHookFunc: func(name string, _ *config.Hook) { hooks = append(hooks, name) },
+		}),
+		withGitHub(newGitHubWithCommitsAhead()),
+	)
+	err := runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any())
+	require.NoError(t, err)
+	require.Empty(t, aiPickCalls(runner))
+	require.False(t, beforeCommandsRan)
+	require.Empty(t, hooks)
+	require.True(t, githubPRCreated(runner))
+	require.NotEmpty(t, notifySuccesses(runner))
+}
+
+func TestRunLocalSquashesBeforeCreatingPR(t *testing.T) {
+	order := []string{}
+	gitMock := &git.MockClient{
+		SquashForPRFunc: func(slug string) error {
+			order = append(order, "squash")
+			return nil
+		},
+	}
+	runner := newRunnerWithMocks(
+		withGit(gitMock),
+		withProject(newProjectThatReportsAllPassing()),
+		withGitHub(&github.MockClient{CreatePRFunc: func(*project.Project) error {
+			order = append(order, "pr")
+			return nil
+		}}),
+	)
+	err := runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any())
+	require.NoError(t, err)
+	require.Equal(t, []string{"squash", "pr"}, order)
+}
+
+func TestRunLocalSquashFailureSkipsPR(t *testing.T) {
+	prCreated := false
+	runner := withMocks(
+		withGit(&git.MockClient{SquashForPRFunc: func(string) error { return errors.New("squash failed") }}),
+		withProject(newProjectThatReportsAllPassing()),
+		withGitHub(&github.MockClient{CreatePRFunc: func(*project.Project) error {
+			prCreated = true
+			return nil
+		}}),
+	)
+	err := runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any())
+	require.ErrorContains(t, err, "squash failed")
+	require.False(t, prCreated)
+	require.NotEmpty(t, notifyErrors(runner))
+}
+
 func TestRunLocalNoCommitsSkipsPR(t *testing.T) {
 	runner := withMocks(
 		withProject(newProjectThatReportsAllPassing()),
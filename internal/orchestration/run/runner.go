@@ -1,13 +1,20 @@
 package run
 
 import (
+	"errors"
+	"time"
+
 	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/project"
 	"github.com/zon/ralph/internal/services"
 )
 
 type ProjectClient interface {
 	Reload(proj *project.Project) *project.Project
+	Restore(proj *project.Project) error
+	AppendEvent(path string, event project.Event)
+	WriteSummary(path string, summary project.Summary) error
 	AllRequirementsPassing(proj *project.Project) bool
 	ExtraIterations(proj *project.Project, cfg *config.RalphConfig) int
 	ExtraIterationsError(proj *project.Project) error
@@ -21,6 +28,8 @@ type ProjectClient interface {
 type AIClient interface {
 	RunPicker(proj *project.Project) (string, error)
 	RunDeveloper(proj *project.Project, req string) error
+	AddNote(note string)
+	ClearNotes()
 	IsFatal(err error) bool
 	GenerateChangelog(proj *project.Project) error
 	FixServiceStartup(cfg *config.RalphConfig, err error) error
@@ -42,12 +51,17 @@ type GitClient interface {
 	CommitFromReport(slug string) error
 	CurrentBranch() (string, error)
 	IsBranchSyncedWithRemote(branch string) error
+	RemoteBranchExists(branch string) (bool, error)
 	CommitOrchestrationRemoval(slug string) error
 	CommitGeneratedArtifacts(slug string) error
+	SquashForPR(slug string) error
+	DiffStat(exclude ...string) (git.DiffStat, error)
+	StashChanges(message string, keep ...string) error
 }
 
 type WorkflowClient interface {
 	Submit(input *project.InputFile, cloneBranch string, debug string, baseBranch string) (string, error)
+	DryRun(input *project.InputFile, cloneBranch string, debug string, baseBranch string) error
 	FollowLogs(workflowName string) error
 	PrintLogHint(workflowName string)
 }
@@ -58,11 +72,17 @@ type GitHubClient interface {
 
 type ServicesClient interface {
 	RunBeforeCommands(cfg *config.RalphConfig) error
+	RunHook(name string, hook *config.Hook)
 	Start(cfg *config.RalphConfig) (*services.Manager, error)
 	Stop(svc *services.Manager)
 	RemoveLogs(cfg *config.RalphConfig)
 }
 
+// HeaderPrinter writes the section headers that separate iterations when agent output is streamed
+type HeaderPrinter interface {
+	IterationHeader(iteration, limit int)
+}
+
 type NotifyClient interface {
 	Error(slug string)
 	Success(slug string)
@@ -76,6 +96,13 @@ type Runner struct {
 	services ServicesClient
 	notify   NotifyClient
 	env      EnvClient
+
+	summaryPath string
+	eventsPath  string
+	headers     HeaderPrinter
+	iterations  int
+	proj        *project.Project
+	attempts    *project.AttemptTracker
 }
 
 func NewRunner(project ProjectClient, ai AIClient, git GitClient, github GitHubClient, services ServicesClient, notify NotifyClient, env EnvClient) *Runner {
@@ -94,12 +121,35 @@ func (r *Runner) Env() EnvClient {
 	return r.env
 }
 
-func (r *Runner) RunLocal(input *project.InputFile, cfg *config.RalphConfig) error {
+// SetHeaders makes the loop print a header to h as each iteration starts, so streamed agent
+// output can be told apart by iteration.
+func (r *Runner) SetHeaders(h HeaderPrinter) {
+	r.headers = h
+}
+
+func (r *Runner) RunLocal(input *project.InputFile, cfg *config.RalphConfig) (err error) {
+	if r.summaryPath != "" || r.eventsPath != "" {
+		start := time.Now()
+		defer func() {
+			summary := r.buildSummary(input, start)
+			r.emitRunComplete(summary, err)
+			if r.summaryPath != "" {
+				if writeErr := r.project.WriteSummary(r.summaryPath, summary); err == nil {
+					err = writeErr
+				}
+			}
+		}()
+	}
 	if r.env.InWorkflow() {
 		defer r.ai.PrintStats()
 	}
-	if err := r.services.RunBeforeCommands(cfg); err != nil {
-		return err
+	// a project that already passes goes straight to its pull request, without the before
+	// commands, hooks or services the loop would need
+	complete := isComplete(input)
+	if !complete {
+		if err := r.services.RunBeforeCommands(cfg); err != nil {
+			return err
+		}
 	}
 	if err := r.git.SwitchToBranch(input.Slug()); err != nil {
 		return err
@@ -109,7 +159,9 @@ func (r *Runner) RunLocal(input *project.InputFile, cfg *config.RalphConfig) err
 		r.notify.Error(input.Slug())
 		return err
 	}
-	if err := r.iterate(proj, cfg); err != nil {
+	if complete {
+		r.proj = proj
+	} else if err := r.iterateWithHooks(proj, cfg); err != nil {
 		r.notify.Error(proj.Slug)
 		return err
 	}
@@ -117,6 +169,10 @@ func (r *Runner) RunLocal(input *project.InputFile, cfg *config.RalphConfig) err
 		r.notify.Error(proj.Slug)
 		return err
 	}
+	if err := r.git.SquashForPR(proj.Slug); err != nil {
+		r.notify.Error(proj.Slug)
+		return err
+	}
 	if err := r.github.CreatePR(proj); err != nil {
 		r.notify.Error(proj.Slug)
 		return err
@@ -141,29 +197,70 @@ func (r *Runner) generateArtifacts(input *project.InputFile) (*project.Project,
 	return proj, r.git.CommitGeneratedArtifacts(proj.Slug)
 }
 
+// Hook names reported in logs and failure notes.
+const (
+	HookPreRun         = "preRun"
+	HookPostRun        = "postRun"
+	HookAfterIteration = "afterIteration"
+)
+
+// iterateWithHooks runs the preRun hook, the iteration loop, and then the postRun hook,
+// which runs even when the loop fails.
+func (r *Runner) iterateWithHooks(proj *project.Project, cfg *config.RalphConfig) error {
+	r.services.RunHook(HookPreRun, cfg.PreRun)
+	defer r.services.RunHook(HookPostRun, cfg.PostRun)
+	return r.iterate(proj, cfg)
+}
+
 func (r *Runner) iterate(proj *project.Project, cfg *config.RalphConfig) error {
 	extra := r.project.ExtraIterations(proj, cfg)
 	limit := len(proj.Requirements) + extra
+	r.attempts = project.NewAttemptTracker(cfg.RequirementAttempts)
 	for i := 0; i < limit; i++ {
-		proj = r.project.Reload(proj)
+		proj = r.reload(proj)
 		if r.project.AllRequirementsPassing(proj) {
 			return nil
 		}
 		if r.git.BlockedFileExists() {
 			return ErrBlocked
 		}
-		if err := r.runIteration(proj, cfg); err != nil {
-			return err
+		if r.attempts.AllStuck(proj) {
+			return &IncompleteError{Err: ErrAllStuck}
+		}
+		r.iterations++
+		r.emit(project.Event{Type: project.EventIterationStarted, Iteration: r.iterations})
+		if r.headers != nil {
+			r.headers.IterationHeader(r.iterations, limit)
+		}
+		err := r.runIteration(proj, cfg)
+		if err == nil {
+			err = r.commitIteration(proj)
 		}
-		if err := r.commitIteration(proj); err != nil {
+		r.emitIterationFinished(err)
+		if errors.Is(err, ErrGuardrailExceeded) {
+			continue
+		}
+		if err != nil {
 			return err
 		}
 	}
-	proj = r.project.Reload(proj)
+	proj = r.reload(proj)
 	if r.project.AllRequirementsPassing(proj) {
 		return nil
 	}
-	return r.project.ExtraIterationsError(proj)
+	if err := r.project.ExtraIterationsError(proj); err != nil {
+		return &IncompleteError{Err: err}
+	}
+	return nil
+}
+
+// reload rereads the project between iterations and reports the requirements the last
+// iteration made pass.
+func (r *Runner) reload(proj *project.Project) *project.Project {
+	after := r.project.Reload(proj)
+	r.proj = after
+	r.emitRequirementsPassed(proj, after)
+	return after
 }
 
 func (r *Runner) runIteration(proj *project.Project, cfg *config.RalphConfig) error {
@@ -179,16 +276,47 @@ func (r *Runner) runIteration(proj *project.Project, cfg *config.RalphConfig) er
 	}
 	defer r.services.Stop(svc)
 	defer r.services.RemoveLogs(cfg)
-	req, err := r.ai.RunPicker(proj)
+	req, err := r.ai.RunPicker(r.attempts.Pickable(proj))
 	if err != nil {
 		return r.blockAndReturn(err)
 	}
 	if err := r.ai.RunDeveloper(proj, req); err != nil {
 		return r.blockAndReturn(err)
 	}
+	// notes gathered so far were meant for this iteration's prompts; what happens from here on
+	// is noted for the next one
+	r.ai.ClearNotes()
+	r.services.RunHook(HookAfterIteration, cfg.AfterIteration)
+	slug := project.PickedSlug(proj, req)
+	if err := r.checkGuardrails(proj, cfg, slug); err != nil {
+		if errors.Is(err, ErrGuardrailExceeded) {
+			// A discarded iteration still counts as an attempt, so a runaway requirement gets stuck
+			r.recordAttempt(proj, slug)
+		}
+		return err
+	}
+	after := r.project.Reload(proj)
+	r.noteRegressions(proj, after)
+	r.recordAttempt(after, slug)
 	return r.cleanup(proj)
 }
 
+// noteRegressions compares the project before the iteration with the reloaded project and
+// warns the next iteration about any requirement that stopped passing, so it is fixed first.
+func (r *Runner) noteRegressions(before, after *project.Project) {
+	for _, slug := range project.Regressions(before, after) {
+		r.ai.AddNote(project.RegressionNote(slug))
+	}
+}
+
+// recordAttempt counts the iteration against the picked requirement and tells the next
+// iteration once it reaches the attempt cap, so later pickers no longer see it.
+func (r *Runner) recordAttempt(after *project.Project, slug string) {
+	if r.attempts.Record(slug, project.IsRequirementPassing(after, slug)) {
+		r.ai.AddNote(project.StuckNote(slug, r.attempts.Limit()))
+	}
+}
+
 func (r *Runner) cleanup(proj *project.Project) error {
 	if r.project.HasChanges(proj) {
 		r.project.NormalizeAndStage(proj)
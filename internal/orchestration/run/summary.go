@@ -0,0 +1,28 @@
+package run
+
+import (
+	"time"
+
+	"github.com/zon/ralph/internal/project"
+)
+
+// SetSummaryPath makes RunLocal write a project.Summary to path when it returns.
+func (r *Runner) SetSummaryPath(path string) {
+	r.summaryPath = path
+}
+
+func (r *Runner) buildSummary(input *project.InputFile, start time.Time) project.Summary {
+	summary := project.Summary{
+		Project:         input.Slug(),
+		Iterations:      r.iterations,
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+	if branch, err := r.git.CurrentBranch(); err == nil {
+		summary.Branch = branch
+	}
+	if r.proj != nil {
+		summary.Project = r.proj.Slug
+		summary.Complete, summary.Passing, summary.Failing = project.CheckCompletion(r.proj)
+	}
+	return summary
+}
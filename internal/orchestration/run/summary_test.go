@@ -0,0 +1,89 @@
+package run
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/project"
+)
+
+func TestRunLocalWritesSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		runner  *Runner
+		proj    *project.Project
+		wantErr bool
+		want    project.Summary
+	}{
+		{
+			name:   "complete",
+			runner: withMocks(withProject(newProjectThatReportsAllPassing())),
+			proj:   project.WithAllPassing(),
+			want:   project.Summary{Project: "test-project", Branch: "main", Iterations: 0, Passing: 1, Failing: 0, Complete: true},
+		},
+		{
+			name:    "iteration failure",
+			runner:  withMocks(withAI(newAIThatAlwaysFails())),
+			proj:    project.WithFailingRequirements(),
+			wantErr: true,
+			want:    project.Summary{Project: "test-project", Branch: "main", Iterations: 1, Passing: 0, Failing: 1, Complete: false},
+		},
+		{
+			name: "iterations exhausted",
+			runner: withMocks(withProject(&project.MockClient{
+				AllPassingFunc:      func() bool { return false },
+				ExtraIterationsFunc: func() int { return 1 },
+			})),
+			proj:    project.WithFailingRequirementsCount(2),
+			wantErr: true,
+			want:    project.Summary{Project: "test-project", Branch: "main", Iterations: 3, Passing: 0, Failing: 2, Complete: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.runner.SetSummaryPath("summary.json")
+
+			err := tt.runner.RunLocal(project.ForProjectInput(tt.proj), config.Any())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			summaries := projectSummaries(tt.runner)
+			require.Len(t, summaries, 1)
+			got := summaries[0]
+			assert.GreaterOrEqual(t, got.DurationSeconds, 0.0)
+			got.DurationSeconds = 0
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunLocalReturnsSummaryWriteError(t *testing.T) {
+	proj := newProjectThatReportsAllPassing()
+	proj.WriteSummaryFunc = func(string, project.Summary) error { return errors.New("failed to write run summary") }
+	runner := withMocks(withProject(proj))
+	runner.SetSummaryPath("summary.json")
+
+	err := runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any())
+	require.EqualError(t, err, "failed to write run summary")
+}
+
+func TestRunLocalWithoutSummaryPathWritesNothing(t *testing.T) {
+	runner := withMocks(withProject(newProjectThatReportsAllPassing()))
+	require.NoError(t, runner.RunLocal(project.ForProjectInput(project.WithAllPassing()), config.Any()))
+	assert.Empty(t, projectSummaries(runner))
+}
+
+func TestRunSummaryJSONRequiresLocal(t *testing.T) {
+	cmd := cmdWithMocks()
+	err := cmd.Run(RunFlags{InputFile: "/fake/project.yaml", SummaryJSON: "summary.json"})
+	require.Error(t, err)
+	require.False(t, remoteRunCalled(cmd))
+}
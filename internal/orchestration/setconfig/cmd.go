@@ -13,26 +13,34 @@ type ContextClient interface {
 	Resolve(flagContext, flagNamespace string) (K8sContext, error)
 }
 
+type NamespaceClient interface {
+	Ensure(k8sCtx K8sContext) error
+}
+
 type GitHubCredentialsClient interface {
 	SecretExists(k8sCtx K8sContext) (bool, error)
 	Validate(keyPath string) error
 	Configure(k8sCtx K8sContext, keyPath string) error
+	PrintPlan(k8sCtx K8sContext, keyPath string) error
 }
 
 type OpenCodeCredentialsClient interface {
 	Configure(k8sCtx K8sContext) error
+	PrintPlan(k8sCtx K8sContext) error
 }
 
 type SetConfigCmd struct {
-	Ctx      ContextClient
-	GitHub   GitHubCredentialsClient
-	OpenCode OpenCodeCredentialsClient
+	Ctx        ContextClient
+	Namespaces NamespaceClient
+	GitHub     GitHubCredentialsClient
+	OpenCode   OpenCodeCredentialsClient
 }
 
 type Flags struct {
 	Context   string
 	Namespace string
 	GithubKey string
+	DryRun    bool
 }
 
 func (c *SetConfigCmd) Run(flags Flags) error {
@@ -41,6 +49,14 @@ func (c *SetConfigCmd) Run(flags Flags) error {
 		return err
 	}
 
+	if flags.DryRun {
+		return c.printPlan(k8sCtx, flags.GithubKey)
+	}
+
+	if err := c.Namespaces.Ensure(k8sCtx); err != nil {
+		return err
+	}
+
 	if err := c.configureGitHub(k8sCtx, flags.GithubKey); err != nil {
 		return err
 	}
@@ -50,14 +66,7 @@ func (c *SetConfigCmd) Run(flags Flags) error {
 
 func (c *SetConfigCmd) configureGitHub(k8sCtx K8sContext, keyPath string) error {
 	if keyPath == "" {
-		exists, err := c.GitHub.SecretExists(k8sCtx)
-		if err != nil {
-			return err
-		}
-		if !exists {
-			return ErrNoGitHubKey
-		}
-		return nil
+		return c.requireGitHubSecret(k8sCtx)
 	}
 
 	if err := c.GitHub.Validate(keyPath); err != nil {
@@ -66,3 +75,29 @@ func (c *SetConfigCmd) configureGitHub(k8sCtx K8sContext, keyPath string) error
 
 	return c.GitHub.Configure(k8sCtx, keyPath)
 }
+
+// printPlan reports the secrets Run would write without writing them. The GitHub key is not
+// validated against the GitHub API, so a dry run only needs read access to the cluster.
+func (c *SetConfigCmd) printPlan(k8sCtx K8sContext, keyPath string) error {
+	if keyPath == "" {
+		if err := c.requireGitHubSecret(k8sCtx); err != nil {
+			return err
+		}
+	} else if err := c.GitHub.PrintPlan(k8sCtx, keyPath); err != nil {
+		return err
+	}
+
+	return c.OpenCode.PrintPlan(k8sCtx)
+}
+
+// requireGitHubSecret checks that an existing GitHub credentials secret can be reused when no key is given.
+func (c *SetConfigCmd) requireGitHubSecret(k8sCtx K8sContext) error {
+	exists, err := c.GitHub.SecretExists(k8sCtx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNoGitHubKey
+	}
+	return nil
+}
@@ -10,11 +10,22 @@ func TestRunConfiguresGitHubAndOpenCode(t *testing.T) {
 	cmd := setconfig.withMocks()
 	err := cmd.Run(flags.withKey())
 	require.NoError(t, err)
+	require.True(t, namespace.ensureCalled())
 	require.True(t, github.validateCalled())
 	require.True(t, github.configureCalled())
 	require.True(t, opencode.configureCalled())
 }
 
+func TestRunHaltsWhenNamespaceIsMissing(t *testing.T) {
+	cmd := setconfig.withMocks(
+		setconfig.withNamespaces(namespace.thatFails()),
+	)
+	err := cmd.Run(flags.withKey())
+	require.Error(t, err)
+	require.False(t, github.validateCalled())
+	require.False(t, opencode.configureCalled())
+}
+
 func TestRunHaltsOnGitHubValidationFailure(t *testing.T) {
 	cmd := setconfig.withMocks(
 		setconfig.withGitHub(github.thatFailsValidation()),
@@ -75,3 +86,34 @@ func TestRunHaltsOnGitHubConfigureFailure(t *testing.T) {
 	err := cmd.Run(flags.withKey())
 	require.Error(t, err)
 }
+
+func TestRunDryRunPrintsPlanWithoutConfiguring(t *testing.T) {
+	cmd := setconfig.withMocks()
+	err := cmd.Run(flags.dryRun(flags.withKey()))
+	require.NoError(t, err)
+	require.True(t, github.printPlanCalled())
+	require.True(t, opencode.printPlanCalled())
+	require.False(t, namespace.ensureCalled())
+	require.False(t, github.validateCalled())
+	require.False(t, github.configureCalled())
+	require.False(t, opencode.configureCalled())
+}
+
+func TestRunDryRunReusesExistingSecretWhenNoKeyProvided(t *testing.T) {
+	cmd := setconfig.withMocks(
+		setconfig.withGitHub(github.withExistingSecret()),
+	)
+	err := cmd.Run(flags.dryRun(flags.withoutKey()))
+	require.NoError(t, err)
+	require.False(t, github.printPlanCalled())
+	require.True(t, opencode.printPlanCalled())
+}
+
+func TestRunDryRunErrorsWhenNoKeyAndNoExistingSecret(t *testing.T) {
+	cmd := setconfig.withMocks(
+		setconfig.withGitHub(github.withNoExistingSecret()),
+	)
+	err := cmd.Run(flags.dryRun(flags.withoutKey()))
+	require.ErrorIs(t, err, ErrNoGitHubKey)
+	require.False(t, opencode.printPlanCalled())
+}
@@ -19,13 +19,28 @@ func (m *mockContextClient) Resolve(flagContext, flagNamespace string) (K8sConte
 	return K8sContext{Name: "test-context", Namespace: "test-ns"}, nil
 }
 
+type mockNamespaceClient struct {
+	ensureFunc   func(K8sContext) error
+	ensureCalled bool
+}
+
+func (m *mockNamespaceClient) Ensure(k8sCtx K8sContext) error {
+	m.ensureCalled = true
+	if m.ensureFunc != nil {
+		return m.ensureFunc(k8sCtx)
+	}
+	return nil
+}
+
 type mockGitHubCredentialsClient struct {
 	secretExistsFunc   func(K8sContext) (bool, error)
 	validateFunc       func(string) error
 	configureFunc      func(K8sContext, string) error
+	printPlanFunc      func(K8sContext, string) error
 	secretExistsCalled bool
 	validateCalled     bool
 	configureCalled    bool
+	printPlanCalled    bool
 }
 
 func (m *mockGitHubCredentialsClient) SecretExists(k8sCtx K8sContext) (bool, error) {
@@ -52,9 +67,19 @@ func (m *mockGitHubCredentialsClient) Configure(k8sCtx K8sContext, keyPath strin
 	return nil
 }
 
+func (m *mockGitHubCredentialsClient) PrintPlan(k8sCtx K8sContext, keyPath string) error {
+	m.printPlanCalled = true
+	if m.printPlanFunc != nil {
+		return m.printPlanFunc(k8sCtx, keyPath)
+	}
+	return nil
+}
+
 type mockOpenCodeCredentialsClient struct {
 	configureFunc   func(K8sContext) error
+	printPlanFunc   func(K8sContext) error
 	configureCalled bool
+	printPlanCalled bool
 }
 
 func (m *mockOpenCodeCredentialsClient) Configure(k8sCtx K8sContext) error {
@@ -65,7 +90,16 @@ func (m *mockOpenCodeCredentialsClient) Configure(k8sCtx K8sContext) error {
 	return nil
 }
 
+func (m *mockOpenCodeCredentialsClient) PrintPlan(k8sCtx K8sContext) error {
+	m.printPlanCalled = true
+	if m.printPlanFunc != nil {
+		return m.printPlanFunc(k8sCtx)
+	}
+	return nil
+}
+
 var mockCtx *mockContextClient
+var mockNS *mockNamespaceClient
 var mockGH *mockGitHubCredentialsClient
 var mockOC *mockOpenCodeCredentialsClient
 
@@ -77,12 +111,14 @@ var setconfig = &setconfigHelper{}
 
 func (h *setconfigHelper) withMocks(opts ...setconfigOption) *SetConfigCmd {
 	mockCtx = &mockContextClient{}
+	mockNS = &mockNamespaceClient{}
 	mockGH = &mockGitHubCredentialsClient{}
 	mockOC = &mockOpenCodeCredentialsClient{}
 	cmd := &SetConfigCmd{
-		Ctx:      mockCtx,
-		GitHub:   mockGH,
-		OpenCode: mockOC,
+		Ctx:        mockCtx,
+		Namespaces: mockNS,
+		GitHub:     mockGH,
+		OpenCode:   mockOC,
 	}
 	for _, opt := range opts {
 		opt(cmd)
@@ -99,6 +135,15 @@ func (h *setconfigHelper) withContext(cc ContextClient) setconfigOption {
 	}
 }
 
+func (h *setconfigHelper) withNamespaces(nc NamespaceClient) setconfigOption {
+	return func(cmd *SetConfigCmd) {
+		cmd.Namespaces = nc
+		if m, ok := nc.(*mockNamespaceClient); ok {
+			mockNS = m
+		}
+	}
+}
+
 func (h *setconfigHelper) withGitHub(gc GitHubCredentialsClient) setconfigOption {
 	return func(cmd *SetConfigCmd) {
 		cmd.GitHub = gc
@@ -117,6 +162,20 @@ func (h *setconfigHelper) withOpenCode(oc OpenCodeCredentialsClient) setconfigOp
 	}
 }
 
+type namespaceHelper struct{}
+
+var namespace = &namespaceHelper{}
+
+func (h *namespaceHelper) ensureCalled() bool {
+	return mockNS != nil && mockNS.ensureCalled
+}
+
+func (h *namespaceHelper) thatFails() *mockNamespaceClient {
+	return &mockNamespaceClient{
+		ensureFunc: func(K8sContext) error { return errMock },
+	}
+}
+
 type githubHelper struct{}
 
 var github = &githubHelper{}
@@ -129,6 +188,10 @@ func (h *githubHelper) configureCalled() bool {
 	return mockGH != nil && mockGH.configureCalled
 }
 
+func (h *githubHelper) printPlanCalled() bool {
+	return mockGH != nil && mockGH.printPlanCalled
+}
+
 func (h *githubHelper) thatFailsSecretExists() *mockGitHubCredentialsClient {
 	return &mockGitHubCredentialsClient{
 		secretExistsFunc: func(K8sContext) (bool, error) { return false, errMock },
@@ -168,6 +231,10 @@ func (h *opencodeHelper) configureCalled() bool {
 	return mockOC != nil && mockOC.configureCalled
 }
 
+func (h *opencodeHelper) printPlanCalled() bool {
+	return mockOC != nil && mockOC.printPlanCalled
+}
+
 func (h *opencodeHelper) thatFails() *mockOpenCodeCredentialsClient {
 	return &mockOpenCodeCredentialsClient{
 		configureFunc: func(K8sContext) error { return errMock },
@@ -202,3 +269,8 @@ func (h *flagsHelper) withoutKey() Flags {
 		Namespace: "test-ns",
 	}
 }
+
+func (h *flagsHelper) dryRun(f Flags) Flags {
+	f.DryRun = true
+	return f
+}
@@ -3,10 +3,10 @@ package validate
 import (
 	"os"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/ai"
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
-	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/project"
 )
 
@@ -29,8 +29,8 @@ func (projectClient) Remove(path string) error {
 }
 
 type agentClient struct {
-	ctx *context.Context
-	oc  opencode.OCClient
+	ctx     *context.Context
+	backend agent.Backend
 }
 
 func (a *agentClient) FixProject(path string, loadErr error, model string) error {
@@ -38,7 +38,7 @@ func (a *agentClient) FixProject(path string, loadErr error, model string) error
 	if err != nil {
 		return err
 	}
-	return ai.RunAgentWithModel(a.ctx, a.oc, prompt, model)
+	return ai.RunAgentWithModel(a.ctx, a.backend, prompt, model)
 }
 
 func resolveConfigModel() string {
@@ -52,10 +52,10 @@ func resolveConfigModel() string {
 	return ralphConfig.Model
 }
 
-func New(ctx *context.Context, oc opencode.OCClient) *Validator {
+func New(ctx *context.Context, backend agent.Backend) *Validator {
 	return &Validator{
 		project: &projectClient{},
-		agent:   &agentClient{ctx: ctx, oc: oc},
+		agent:   &agentClient{ctx: ctx, backend: backend},
 		model:   resolveConfigModel(),
 	}
 }
@@ -353,3 +353,27 @@ func TestValidateFallsBackToMainModel(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "main-model", FixCalls()[0].model)
 }
+
+func TestValidateStdinLoadsWithoutSavingOrFixing(t *testing.T) {
+	ResetFixCalls()
+	proj := project.Any()
+	pc := &mockProjectClient{
+		loadFunc: func(path string) (*project.Project, error) {
+			return proj, nil
+		},
+	}
+	svc := withMocks(withProject(pc))
+	result, err := svc.Validate(project.StdinPath)
+	require.NoError(t, err)
+	require.Equal(t, proj, result)
+	require.Empty(t, pc.savedPath)
+	require.Empty(t, FixCalls())
+}
+
+func TestValidateStdinReturnsLoadError(t *testing.T) {
+	ResetFixCalls()
+	svc := withMocks(withProject(thatAlwaysFailsToLoad()))
+	_, err := svc.Validate(project.StdinPath)
+	require.Error(t, err)
+	require.Empty(t, FixCalls())
+}
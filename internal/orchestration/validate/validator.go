@@ -34,6 +34,10 @@ type Validator struct {
 }
 
 func (v *Validator) Validate(path string) (*project.Project, error) {
+	if path == project.StdinPath {
+		// A project piped on stdin can be checked but not fixed or rewritten
+		return v.project.Load(path)
+	}
 	for attempt := 1; attempt <= MaxAttempts; attempt++ {
 		proj, loadErr := v.project.Load(path)
 		if loadErr == nil {
@@ -0,0 +1,75 @@
+package webhookrotatesecret
+
+import "github.com/zon/ralph/internal/webhookconfig"
+
+type K8sContext struct {
+	Name      string
+	Namespace string
+}
+
+type ContextClient interface {
+	Resolve(flagContext, flagNamespace string) (K8sContext, error)
+}
+
+type ConfigClient interface {
+	WebhookURL(k8sCtx K8sContext, env string) (string, error)
+}
+
+// WebhookSecrets is the whole webhook secret, so the trigger and GitHub tokens survive a rotation
+type WebhookSecrets = webhookconfig.Secrets
+
+type SecretsClient interface {
+	Read(k8sCtx K8sContext) (WebhookSecrets, error)
+	Rotate(secrets WebhookSecrets, repo string) (WebhookSecrets, WebhookSecrets, error)
+	Write(k8sCtx K8sContext, secrets WebhookSecrets) error
+}
+
+type GitHubClient interface {
+	RegisterWebhooks(webhookURL string, secrets WebhookSecrets)
+}
+
+type RotateSecretCmd struct {
+	Ctx     ContextClient
+	Config  ConfigClient
+	Secrets SecretsClient
+	GitHub  GitHubClient
+}
+
+type Flags struct {
+	Context   string
+	Namespace string
+	Repo      string
+	Env       string // Environment from the app config whose hostname the webhooks are registered at; empty uses the default
+}
+
+// Run rotates the webhook secrets. The secret is written before GitHub is updated so the
+// service, which accepts both the new and the previous secret, never sees an unknown one.
+func (c *RotateSecretCmd) Run(flags Flags) error {
+	k8sCtx, err := c.Ctx.Resolve(flags.Context, flags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	webhookURL, err := c.Config.WebhookURL(k8sCtx, flags.Env)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.Secrets.Read(k8sCtx)
+	if err != nil {
+		return err
+	}
+
+	updated, rotated, err := c.Secrets.Rotate(current, flags.Repo)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Secrets.Write(k8sCtx, updated); err != nil {
+		return err
+	}
+
+	c.GitHub.RegisterWebhooks(webhookURL, rotated)
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package webhookrotatesecret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/webhookconfig"
+)
+
+func TestRunRotatesEveryRepoByDefault(t *testing.T) {
+	cmd := rotate.withMocks()
+	err := cmd.Run(flags.all())
+
+	require.NoError(t, err)
+	require.Equal(t, []webhookconfig.RepoSecret{
+		{Owner: "acme", Name: "api", WebhookSecret: "new", PreviousSecret: "old-api"},
+		{Owner: "acme", Name: "web", WebhookSecret: "new", PreviousSecret: "old-web"},
+	}, secrets.written())
+	require.Len(t, github.registered(), 2)
+}
+
+func TestRunRotatesOnlyTheNamedRepo(t *testing.T) {
+	cmd := rotate.withMocks()
+	err := cmd.Run(flags.forRepo("acme/web"))
+
+	require.NoError(t, err)
+	require.Equal(t, []webhookconfig.RepoSecret{
+		{Owner: "acme", Name: "api", WebhookSecret: "old-api"},
+		{Owner: "acme", Name: "web", WebhookSecret: "new", PreviousSecret: "old-web"},
+	}, secrets.written())
+	require.Equal(t, []webhookconfig.RepoSecret{
+		{Owner: "acme", Name: "web", WebhookSecret: "new", PreviousSecret: "old-web"},
+	}, github.registered())
+}
+
+func TestRunHaltsOnUnknownRepo(t *testing.T) {
+	cmd := rotate.withMocks()
+	err := cmd.Run(flags.forRepo("acme/missing"))
+
+	require.Error(t, err)
+	require.Nil(t, secrets.written())
+	require.Nil(t, github.registered())
+}
+
+func TestRunHaltsOnSecretsReadFailure(t *testing.T) {
+	cmd := rotate.withMocks(
+		rotate.withSecrets(secrets.thatFailsRead()),
+	)
+	err := cmd.Run(flags.all())
+
+	require.Error(t, err)
+	require.False(t, mockSec.writeCalled)
+	require.Nil(t, github.registered())
+}
+
+func TestRunSkipsGitHubWhenSecretWriteFails(t *testing.T) {
+	cmd := rotate.withMocks(
+		rotate.withSecrets(secrets.thatFailsWrite()),
+	)
+	err := cmd.Run(flags.all())
+
+	require.Error(t, err)
+	require.Nil(t, github.registered())
+}
+
+func TestRunRegistersWebhooksAtEnvironmentURL(t *testing.T) {
+	cmd := rotate.withMocks(
+		rotate.withConfig(config.thatResolvesURL("https://ralph-staging.example.com/webhook")),
+	)
+	err := cmd.Run(flags.forEnv("staging"))
+
+	require.NoError(t, err)
+	require.Equal(t, "staging", config.lastEnv())
+	require.Equal(t, "https://ralph-staging.example.com/webhook", github.webhookURL())
+}
+
+func TestRunHaltsOnUnknownEnvironment(t *testing.T) {
+	cmd := rotate.withMocks(
+		rotate.withConfig(config.thatFailsURL()),
+	)
+	err := cmd.Run(flags.forEnv("dev"))
+
+	require.Error(t, err)
+	require.Nil(t, secrets.written())
+	require.Nil(t, github.registered())
+}
+
+func TestRunKeepsTokens(t *testing.T) {
+	cmd := rotate.withMocks(
+		rotate.withSecrets(secrets.withTokens("trigger", "gh-token")),
+	)
+	err := cmd.Run(flags.all())
+
+	require.NoError(t, err)
+	require.Equal(t, "trigger", mockSec.written.TriggerToken)
+	require.Equal(t, "gh-token", mockSec.written.GithubToken)
+}
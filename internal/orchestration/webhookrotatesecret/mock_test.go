@@ -0,0 +1,225 @@
+package webhookrotatesecret
+
+import "github.com/zon/ralph/internal/webhookconfig"
+
+var errMock = &mockError{"mock error"}
+
+type mockError struct{ msg string }
+
+func (e *mockError) Error() string { return e.msg }
+
+type mockContextClient struct {
+	resolveFunc func(string, string) (K8sContext, error)
+}
+
+func (m *mockContextClient) Resolve(flagContext, flagNamespace string) (K8sContext, error) {
+	if m.resolveFunc != nil {
+		return m.resolveFunc(flagContext, flagNamespace)
+	}
+	return K8sContext{Name: "test-context", Namespace: "test-ns"}, nil
+}
+
+type mockConfigClient struct {
+	urlFunc func(K8sContext, string) (string, error)
+	lastEnv string
+}
+
+func (m *mockConfigClient) WebhookURL(k8sCtx K8sContext, env string) (string, error) {
+	m.lastEnv = env
+	if m.urlFunc != nil {
+		return m.urlFunc(k8sCtx, env)
+	}
+	return "https://test-host/webhook", nil
+}
+
+type mockSecretsClient struct {
+	readFunc    func(K8sContext) (WebhookSecrets, error)
+	rotateFunc  func(WebhookSecrets, string) (WebhookSecrets, WebhookSecrets, error)
+	writeFunc   func(K8sContext, WebhookSecrets) error
+	lastRepo    string
+	written     *WebhookSecrets
+	writeCalled bool
+}
+
+func (m *mockSecretsClient) Read(k8sCtx K8sContext) (WebhookSecrets, error) {
+	if m.readFunc != nil {
+		return m.readFunc(k8sCtx)
+	}
+	return WebhookSecrets{Repos: []webhookconfig.RepoSecret{
+		{Owner: "acme", Name: "api", WebhookSecret: "old-api"},
+		{Owner: "acme", Name: "web", WebhookSecret: "old-web"},
+	}}, nil
+}
+
+func (m *mockSecretsClient) Rotate(secrets WebhookSecrets, repo string) (WebhookSecrets, WebhookSecrets, error) {
+	m.lastRepo = repo
+	if m.rotateFunc != nil {
+		return m.rotateFunc(secrets, repo)
+	}
+	rotated, err := webhookconfig.RotateWebhookSecrets(&secrets, repo, func() (string, error) { return "new", nil })
+	if err != nil {
+		return WebhookSecrets{}, WebhookSecrets{}, err
+	}
+	return secrets, WebhookSecrets{Repos: rotated}, nil
+}
+
+func (m *mockSecretsClient) Write(k8sCtx K8sContext, secrets WebhookSecrets) error {
+	m.writeCalled = true
+	m.written = &secrets
+	if m.writeFunc != nil {
+		return m.writeFunc(k8sCtx, secrets)
+	}
+	return nil
+}
+
+type mockGitHubClient struct {
+	registered *WebhookSecrets
+	webhookURL string
+}
+
+func (m *mockGitHubClient) RegisterWebhooks(webhookURL string, secrets WebhookSecrets) {
+	m.registered = &secrets
+	m.webhookURL = webhookURL
+}
+
+var mockCtx *mockContextClient
+var mockCfg *mockConfigClient
+var mockSec *mockSecretsClient
+var mockGH *mockGitHubClient
+
+type rotateOption func(*RotateSecretCmd)
+
+type rotateHelper struct{}
+
+var rotate = &rotateHelper{}
+
+func (h *rotateHelper) withMocks(opts ...rotateOption) *RotateSecretCmd {
+	mockCtx = &mockContextClient{}
+	mockCfg = &mockConfigClient{}
+	mockSec = &mockSecretsClient{}
+	mockGH = &mockGitHubClient{}
+	cmd := &RotateSecretCmd{
+		Ctx:     mockCtx,
+		Config:  mockCfg,
+		Secrets: mockSec,
+		GitHub:  mockGH,
+	}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+	return cmd
+}
+
+func (h *rotateHelper) withConfig(cc ConfigClient) rotateOption {
+	return func(cmd *RotateSecretCmd) {
+		cmd.Config = cc
+		if m, ok := cc.(*mockConfigClient); ok {
+			mockCfg = m
+		}
+	}
+}
+
+func (h *rotateHelper) withSecrets(sc SecretsClient) rotateOption {
+	return func(cmd *RotateSecretCmd) {
+		cmd.Secrets = sc
+		if m, ok := sc.(*mockSecretsClient); ok {
+			mockSec = m
+		}
+	}
+}
+
+type configHelper struct{}
+
+var config = &configHelper{}
+
+func (h *configHelper) lastEnv() string {
+	if mockCfg == nil {
+		return ""
+	}
+	return mockCfg.lastEnv
+}
+
+func (h *configHelper) thatResolvesURL(webhookURL string) *mockConfigClient {
+	return &mockConfigClient{
+		urlFunc: func(K8sContext, string) (string, error) { return webhookURL, nil },
+	}
+}
+
+func (h *configHelper) thatFailsURL() *mockConfigClient {
+	return &mockConfigClient{
+		urlFunc: func(K8sContext, string) (string, error) { return "", errMock },
+	}
+}
+
+type secretsHelper struct{}
+
+var secrets = &secretsHelper{}
+
+func (h *secretsHelper) thatFailsRead() *mockSecretsClient {
+	return &mockSecretsClient{
+		readFunc: func(K8sContext) (WebhookSecrets, error) { return WebhookSecrets{}, errMock },
+	}
+}
+
+func (h *secretsHelper) withTokens(triggerToken, githubToken string) *mockSecretsClient {
+	return &mockSecretsClient{
+		readFunc: func(K8sContext) (WebhookSecrets, error) {
+			return WebhookSecrets{
+				Repos:        []webhookconfig.RepoSecret{{Owner: "acme", Name: "api", WebhookSecret: "old-api"}},
+				TriggerToken: triggerToken,
+				GithubToken:  githubToken,
+			}, nil
+		},
+	}
+}
+
+func (h *secretsHelper) thatFailsWrite() *mockSecretsClient {
+	return &mockSecretsClient{
+		writeFunc: func(K8sContext, WebhookSecrets) error { return errMock },
+	}
+}
+
+func (h *secretsHelper) written() []webhookconfig.RepoSecret {
+	if mockSec == nil || mockSec.written == nil {
+		return nil
+	}
+	return mockSec.written.Repos
+}
+
+type githubHelper struct{}
+
+var github = &githubHelper{}
+
+func (h *githubHelper) registered() []webhookconfig.RepoSecret {
+	if mockGH == nil || mockGH.registered == nil {
+		return nil
+	}
+	return mockGH.registered.Repos
+}
+
+func (h *githubHelper) webhookURL() string {
+	if mockGH == nil {
+		return ""
+	}
+	return mockGH.webhookURL
+}
+
+type flagsHelper struct{}
+
+var flags = &flagsHelper{}
+
+func (h *flagsHelper) all() Flags {
+	return Flags{Context: "test-context", Namespace: "test-ns"}
+}
+
+func (h *flagsHelper) forRepo(repo string) Flags {
+	f := h.all()
+	f.Repo = repo
+	return f
+}
+
+func (h *flagsHelper) forEnv(env string) Flags {
+	f := h.all()
+	f.Env = env
+	return f
+}
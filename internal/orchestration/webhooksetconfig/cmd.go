@@ -11,36 +11,49 @@ type ContextClient interface {
 	Resolve(flagContext, flagNamespace string) (K8sContext, error)
 }
 
+type NamespaceClient interface {
+	Ensure(k8sCtx K8sContext) error
+}
+
 type ConfigClient interface {
-	Build(k8sCtx K8sContext, configPath string) webhookconfig.AppConfig
+	Build(k8sCtx K8sContext, configPath string, remove []string) (webhookconfig.AppConfig, error)
 	Write(k8sCtx K8sContext, cfg webhookconfig.AppConfig) error
 	Read(k8sCtx K8sContext) (webhookconfig.AppConfig, error)
+	PrintDiff(k8sCtx K8sContext, configPath string, remove []string) error
+	WebhookURL(cfg webhookconfig.AppConfig, env string) (string, error)
 }
 
-type WebhookSecrets struct {
-	Repos []webhookconfig.RepoSecret
-}
+// WebhookSecrets is the whole webhook secret, so the trigger and GitHub tokens survive a rewrite
+type WebhookSecrets = webhookconfig.Secrets
 
 type SecretsClient interface {
-	Generate(cfg webhookconfig.AppConfig) (WebhookSecrets, error)
+	Read(k8sCtx K8sContext) (WebhookSecrets, error)
+	Generate(cfg webhookconfig.AppConfig, existing WebhookSecrets) (WebhookSecrets, error)
 	Write(k8sCtx K8sContext, secrets WebhookSecrets) error
+	Save(path string, secrets WebhookSecrets) error
 }
 
 type GitHubClient interface {
-	RegisterWebhooks(secrets WebhookSecrets)
+	RegisterWebhooks(webhookURL string, secrets WebhookSecrets)
+	UnregisterWebhooks(webhookURL string, fullNames []string)
 }
 
 type SetConfigCmd struct {
-	Ctx     ContextClient
-	Config  ConfigClient
-	Secrets SecretsClient
-	GitHub  GitHubClient
+	Ctx        ContextClient
+	Namespaces NamespaceClient
+	Config     ConfigClient
+	Secrets    SecretsClient
+	GitHub     GitHubClient
 }
 
 type Flags struct {
 	Context    string
 	Namespace  string
 	ConfigPath string
+	Remove     []string
+	DryRun     bool
+	Env        string // Environment from the app config whose hostname the webhooks are registered at; empty uses the default
+	Output     string // Local file that also receives the generated secrets; empty writes none
 }
 
 func (c *SetConfigCmd) Run(flags Flags) error {
@@ -49,7 +62,28 @@ func (c *SetConfigCmd) Run(flags Flags) error {
 		return err
 	}
 
-	appCfg := c.Config.Build(k8sCtx, flags.ConfigPath)
+	if flags.DryRun {
+		return c.Config.PrintDiff(k8sCtx, flags.ConfigPath, flags.Remove)
+	}
+
+	if err := c.Namespaces.Ensure(k8sCtx); err != nil {
+		return err
+	}
+
+	appCfg, err := c.Config.Build(k8sCtx, flags.ConfigPath, flags.Remove)
+	if err != nil {
+		return err
+	}
+
+	webhookURL, err := c.Config.WebhookURL(appCfg, flags.Env)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.Secrets.Read(k8sCtx)
+	if err != nil {
+		return err
+	}
 
 	if err := c.Config.Write(k8sCtx, appCfg); err != nil {
 		return err
@@ -60,12 +94,22 @@ func (c *SetConfigCmd) Run(flags Flags) error {
 		return err
 	}
 
-	secrets, err := c.Secrets.Generate(appCfg)
+	secrets, err := c.Secrets.Generate(appCfg, existing)
 	if err != nil {
 		return err
 	}
 
-	c.GitHub.RegisterWebhooks(secrets)
+	c.GitHub.RegisterWebhooks(webhookURL, secrets)
+	if len(flags.Remove) > 0 {
+		c.GitHub.UnregisterWebhooks(webhookURL, flags.Remove)
+	}
+
+	// saved before the k8s write so the secrets just registered on GitHub survive a failed write
+	if flags.Output != "" {
+		if err := c.Secrets.Save(flags.Output, secrets); err != nil {
+			return err
+		}
+	}
 
 	return c.Secrets.Write(k8sCtx, secrets)
 }
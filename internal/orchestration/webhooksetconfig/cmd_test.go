@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/webhookconfig"
 )
 
 func TestRunWritesConfigThenSecrets(t *testing.T) {
@@ -11,10 +13,22 @@ func TestRunWritesConfigThenSecrets(t *testing.T) {
 	err := cmd.Run(flags.any())
 
 	require.NoError(t, err)
+	require.True(t, namespace.ensureCalled())
 	require.True(t, config.writeCalled())
 	require.True(t, secrets.writeCalled())
 }
 
+func TestRunHaltsWhenNamespaceIsMissing(t *testing.T) {
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withNamespaces(namespace.thatFails()),
+	)
+	err := cmd.Run(flags.any())
+
+	require.Error(t, err)
+	require.False(t, config.writeCalled())
+	require.False(t, secrets.writeCalled())
+}
+
 func TestRunHaltsOnConfigWriteFailure(t *testing.T) {
 	cmd := webhooksetconfig.withMocks(
 		webhooksetconfig.withConfig(config.thatFailsWrite()),
@@ -73,3 +87,133 @@ func TestRunPropagatesContextResolutionFailure(t *testing.T) {
 	require.Error(t, err)
 	require.False(t, config.writeCalled())
 }
+
+func TestRunDryRunPrintsDiffWithoutWriting(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	err := cmd.Run(flags.dryRun())
+
+	require.NoError(t, err)
+	require.True(t, config.diffCalled())
+	require.False(t, namespace.ensureCalled())
+	require.False(t, config.writeCalled())
+	require.False(t, secrets.writeCalled())
+	require.False(t, github.registerCalled())
+}
+
+func TestRunPassesRemovedReposToBuild(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	err := cmd.Run(flags.removing("acme/api"))
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme/api"}, config.lastRemove())
+	require.True(t, config.writeCalled())
+}
+
+func TestRunUnregistersWebhooksOfRemovedRepos(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	err := cmd.Run(flags.removing("acme/api", "acme/web"))
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme/api", "acme/web"}, github.unregistered())
+	require.True(t, secrets.writeCalled())
+}
+
+func TestRunSkipsUnregisterWithoutRemovedRepos(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	err := cmd.Run(flags.any())
+
+	require.NoError(t, err)
+	require.Empty(t, github.unregistered())
+}
+
+func TestRunDryRunUnregistersNothing(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	f := flags.removing("acme/api")
+	f.DryRun = true
+	err := cmd.Run(f)
+
+	require.NoError(t, err)
+	require.Empty(t, github.unregistered())
+}
+
+func TestRunHaltsOnConfigBuildFailure(t *testing.T) {
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withConfig(config.thatFailsBuild()),
+	)
+	err := cmd.Run(flags.removing("not-a-repo"))
+
+	require.Error(t, err)
+	require.False(t, config.writeCalled())
+	require.False(t, secrets.writeCalled())
+}
+
+func TestRunSavesGeneratedSecretsToOutput(t *testing.T) {
+	generated := WebhookSecrets{Repos: []webhookconfig.RepoSecret{{Owner: "acme", Name: "app", WebhookSecret: "s3kr3t"}}}
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withSecrets(secrets.thatGenerate(generated)),
+	)
+	err := cmd.Run(flags.withOutput("/tmp/secrets.yaml"))
+
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/secrets.yaml", secrets.savedPath())
+	require.Equal(t, generated, secrets.saved())
+	require.True(t, secrets.writeCalled())
+}
+
+func TestRunWithoutOutputSavesNothing(t *testing.T) {
+	cmd := webhooksetconfig.withMocks()
+	err := cmd.Run(flags.any())
+
+	require.NoError(t, err)
+	require.Empty(t, secrets.savedPath())
+}
+
+func TestRunRegistersWebhooksAtEnvironmentURL(t *testing.T) {
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withConfig(config.thatResolvesURL("https://ralph-staging.example.com/webhook")),
+	)
+	f := flags.forEnv("staging")
+	f.Remove = []string{"acme/api"}
+	err := cmd.Run(f)
+
+	require.NoError(t, err)
+	require.Equal(t, "staging", config.lastEnv())
+	require.Equal(t, []string{"https://ralph-staging.example.com/webhook", "https://ralph-staging.example.com/webhook"}, github.webhookURLs())
+}
+
+func TestRunHaltsOnUnknownEnvironment(t *testing.T) {
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withConfig(config.thatFailsURL()),
+	)
+	err := cmd.Run(flags.forEnv("dev"))
+
+	require.Error(t, err)
+	require.False(t, config.writeCalled())
+	require.False(t, github.registerCalled())
+	require.False(t, secrets.writeCalled())
+}
+
+func TestRunGeneratesFromExistingSecrets(t *testing.T) {
+	existing := WebhookSecrets{
+		Repos:        []webhookconfig.RepoSecret{{Owner: "acme", Name: "app", WebhookSecret: "current", PreviousSecret: "old"}},
+		TriggerToken: "trigger",
+	}
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withSecrets(secrets.thatRead(existing)),
+	)
+	err := cmd.Run(flags.any())
+
+	require.NoError(t, err)
+	require.Equal(t, existing, secrets.existing())
+}
+
+func TestRunHaltsOnSecretsReadFailure(t *testing.T) {
+	cmd := webhooksetconfig.withMocks(
+		webhooksetconfig.withSecrets(secrets.thatFailsRead()),
+	)
+	err := cmd.Run(flags.any())
+
+	require.Error(t, err)
+	require.False(t, config.writeCalled())
+	require.False(t, secrets.writeCalled())
+}
@@ -21,21 +21,40 @@ func (m *mockContextClient) Resolve(flagContext, flagNamespace string) (K8sConte
 	return K8sContext{Name: "test-context", Namespace: "test-ns"}, nil
 }
 
+type mockNamespaceClient struct {
+	ensureFunc   func(K8sContext) error
+	ensureCalled bool
+}
+
+func (m *mockNamespaceClient) Ensure(k8sCtx K8sContext) error {
+	m.ensureCalled = true
+	if m.ensureFunc != nil {
+		return m.ensureFunc(k8sCtx)
+	}
+	return nil
+}
+
 type mockConfigClient struct {
-	buildFunc     func(K8sContext, string) webhookconfig.AppConfig
+	buildFunc     func(K8sContext, string, []string) (webhookconfig.AppConfig, error)
 	writeFunc     func(K8sContext, webhookconfig.AppConfig) error
 	readFunc      func(K8sContext) (webhookconfig.AppConfig, error)
+	diffFunc      func(K8sContext, string, []string) error
+	urlFunc       func(webhookconfig.AppConfig, string) (string, error)
 	buildCalled   bool
 	writeCalled   bool
 	readCalled    bool
+	diffCalled    bool
+	lastRemove    []string
+	lastEnv       string
 }
 
-func (m *mockConfigClient) Build(k8sCtx K8sContext, configPath string) webhookconfig.AppConfig {
+func (m *mockConfigClient) Build(k8sCtx K8sContext, configPath string, remove []string) (webhookconfig.AppConfig, error) {
 	m.buildCalled = true
+	m.lastRemove = remove
 	if m.buildFunc != nil {
-		return m.buildFunc(k8sCtx, configPath)
+		return m.buildFunc(k8sCtx, configPath, remove)
 	}
-	return webhookconfig.AppConfig{Port: 8080}
+	return webhookconfig.AppConfig{Port: 8080}, nil
 }
 
 func (m *mockConfigClient) Write(k8sCtx K8sContext, cfg webhookconfig.AppConfig) error {
@@ -54,15 +73,44 @@ func (m *mockConfigClient) Read(k8sCtx K8sContext) (webhookconfig.AppConfig, err
 	return webhookconfig.AppConfig{Port: 8080}, nil
 }
 
+func (m *mockConfigClient) PrintDiff(k8sCtx K8sContext, configPath string, remove []string) error {
+	m.diffCalled = true
+	m.lastRemove = remove
+	if m.diffFunc != nil {
+		return m.diffFunc(k8sCtx, configPath, remove)
+	}
+	return nil
+}
+
+func (m *mockConfigClient) WebhookURL(cfg webhookconfig.AppConfig, env string) (string, error) {
+	m.lastEnv = env
+	if m.urlFunc != nil {
+		return m.urlFunc(cfg, env)
+	}
+	return "https://test-host/webhook", nil
+}
+
 type mockSecretsClient struct {
+	readFunc       func(K8sContext) (WebhookSecrets, error)
 	generateFunc   func(webhookconfig.AppConfig) (WebhookSecrets, error)
 	writeFunc      func(K8sContext, WebhookSecrets) error
 	generateCalled bool
 	writeCalled    bool
+	existing       WebhookSecrets
+	savedPath      string
+	saved          WebhookSecrets
 }
 
-func (m *mockSecretsClient) Generate(cfg webhookconfig.AppConfig) (WebhookSecrets, error) {
+func (m *mockSecretsClient) Read(k8sCtx K8sContext) (WebhookSecrets, error) {
+	if m.readFunc != nil {
+		return m.readFunc(k8sCtx)
+	}
+	return WebhookSecrets{}, nil
+}
+
+func (m *mockSecretsClient) Generate(cfg webhookconfig.AppConfig, existing WebhookSecrets) (WebhookSecrets, error) {
 	m.generateCalled = true
+	m.existing = existing
 	if m.generateFunc != nil {
 		return m.generateFunc(cfg)
 	}
@@ -77,19 +125,34 @@ func (m *mockSecretsClient) Write(k8sCtx K8sContext, secrets WebhookSecrets) err
 	return nil
 }
 
+func (m *mockSecretsClient) Save(path string, secrets WebhookSecrets) error {
+	m.savedPath = path
+	m.saved = secrets
+	return nil
+}
+
 type mockGitHubClient struct {
 	registerWebhooksFunc func(WebhookSecrets)
 	registerCalled       bool
+	unregistered         []string
+	webhookURLs          []string
 }
 
-func (m *mockGitHubClient) RegisterWebhooks(secrets WebhookSecrets) {
+func (m *mockGitHubClient) RegisterWebhooks(webhookURL string, secrets WebhookSecrets) {
 	m.registerCalled = true
+	m.webhookURLs = append(m.webhookURLs, webhookURL)
 	if m.registerWebhooksFunc != nil {
 		m.registerWebhooksFunc(secrets)
 	}
 }
 
+func (m *mockGitHubClient) UnregisterWebhooks(webhookURL string, fullNames []string) {
+	m.webhookURLs = append(m.webhookURLs, webhookURL)
+	m.unregistered = append(m.unregistered, fullNames...)
+}
+
 var mockCtx *mockContextClient
+var mockNS *mockNamespaceClient
 var mockCfg *mockConfigClient
 var mockSec *mockSecretsClient
 var mockGH *mockGitHubClient
@@ -102,14 +165,16 @@ var webhooksetconfig = &webhooksetconfigHelper{}
 
 func (h *webhooksetconfigHelper) withMocks(opts ...webhooksetconfigOption) *SetConfigCmd {
 	mockCtx = &mockContextClient{}
+	mockNS = &mockNamespaceClient{}
 	mockCfg = &mockConfigClient{}
 	mockSec = &mockSecretsClient{}
 	mockGH = &mockGitHubClient{}
 	cmd := &SetConfigCmd{
-		Ctx:     mockCtx,
-		Config:  mockCfg,
-		Secrets: mockSec,
-		GitHub:  mockGH,
+		Ctx:        mockCtx,
+		Namespaces: mockNS,
+		Config:     mockCfg,
+		Secrets:    mockSec,
+		GitHub:     mockGH,
 	}
 	for _, opt := range opts {
 		opt(cmd)
@@ -126,6 +191,15 @@ func (h *webhooksetconfigHelper) withContext(cc ContextClient) webhooksetconfigO
 	}
 }
 
+func (h *webhooksetconfigHelper) withNamespaces(nc NamespaceClient) webhooksetconfigOption {
+	return func(cmd *SetConfigCmd) {
+		cmd.Namespaces = nc
+		if m, ok := nc.(*mockNamespaceClient); ok {
+			mockNS = m
+		}
+	}
+}
+
 func (h *webhooksetconfigHelper) withConfig(cc ConfigClient) webhooksetconfigOption {
 	return func(cmd *SetConfigCmd) {
 		cmd.Config = cc
@@ -153,6 +227,20 @@ func (h *webhooksetconfigHelper) withGitHub(gc GitHubClient) webhooksetconfigOpt
 	}
 }
 
+type namespaceHelper struct{}
+
+var namespace = &namespaceHelper{}
+
+func (h *namespaceHelper) ensureCalled() bool {
+	return mockNS != nil && mockNS.ensureCalled
+}
+
+func (h *namespaceHelper) thatFails() *mockNamespaceClient {
+	return &mockNamespaceClient{
+		ensureFunc: func(K8sContext) error { return errMock },
+	}
+}
+
 type configHelper struct{}
 
 var config = &configHelper{}
@@ -161,6 +249,44 @@ func (h *configHelper) writeCalled() bool {
 	return mockCfg != nil && mockCfg.writeCalled
 }
 
+func (h *configHelper) diffCalled() bool {
+	return mockCfg != nil && mockCfg.diffCalled
+}
+
+func (h *configHelper) lastRemove() []string {
+	if mockCfg == nil {
+		return nil
+	}
+	return mockCfg.lastRemove
+}
+
+func (h *configHelper) lastEnv() string {
+	if mockCfg == nil {
+		return ""
+	}
+	return mockCfg.lastEnv
+}
+
+func (h *configHelper) thatResolvesURL(webhookURL string) *mockConfigClient {
+	return &mockConfigClient{
+		urlFunc: func(webhookconfig.AppConfig, string) (string, error) { return webhookURL, nil },
+	}
+}
+
+func (h *configHelper) thatFailsURL() *mockConfigClient {
+	return &mockConfigClient{
+		urlFunc: func(webhookconfig.AppConfig, string) (string, error) { return "", errMock },
+	}
+}
+
+func (h *configHelper) thatFailsBuild() *mockConfigClient {
+	return &mockConfigClient{
+		buildFunc: func(K8sContext, string, []string) (webhookconfig.AppConfig, error) {
+			return webhookconfig.AppConfig{}, errMock
+		},
+	}
+}
+
 func (h *configHelper) thatFailsWrite() *mockConfigClient {
 	return &mockConfigClient{
 		writeFunc: func(K8sContext, webhookconfig.AppConfig) error { return errMock },
@@ -187,6 +313,25 @@ func (h *secretsHelper) thatFailsGenerate() *mockSecretsClient {
 	}
 }
 
+func (h *secretsHelper) thatRead(existing WebhookSecrets) *mockSecretsClient {
+	return &mockSecretsClient{
+		readFunc: func(K8sContext) (WebhookSecrets, error) { return existing, nil },
+	}
+}
+
+func (h *secretsHelper) thatFailsRead() *mockSecretsClient {
+	return &mockSecretsClient{
+		readFunc: func(K8sContext) (WebhookSecrets, error) { return WebhookSecrets{}, errMock },
+	}
+}
+
+func (h *secretsHelper) existing() WebhookSecrets {
+	if mockSec == nil {
+		return WebhookSecrets{}
+	}
+	return mockSec.existing
+}
+
 func (h *secretsHelper) thatFailsWrite() *mockSecretsClient {
 	return &mockSecretsClient{
 		writeFunc: func(K8sContext, WebhookSecrets) error { return errMock },
@@ -197,6 +342,26 @@ func (h *secretsHelper) writeCalled() bool {
 	return mockSec != nil && mockSec.writeCalled
 }
 
+func (h *secretsHelper) savedPath() string {
+	if mockSec == nil {
+		return ""
+	}
+	return mockSec.savedPath
+}
+
+func (h *secretsHelper) saved() WebhookSecrets {
+	if mockSec == nil {
+		return WebhookSecrets{}
+	}
+	return mockSec.saved
+}
+
+func (h *secretsHelper) thatGenerate(generated WebhookSecrets) *mockSecretsClient {
+	return &mockSecretsClient{
+		generateFunc: func(webhookconfig.AppConfig) (WebhookSecrets, error) { return generated, nil },
+	}
+}
+
 type githubHelper struct{}
 
 var github = &githubHelper{}
@@ -205,6 +370,20 @@ func (h *githubHelper) registerCalled() bool {
 	return mockGH != nil && mockGH.registerCalled
 }
 
+func (h *githubHelper) unregistered() []string {
+	if mockGH == nil {
+		return nil
+	}
+	return mockGH.unregistered
+}
+
+func (h *githubHelper) webhookURLs() []string {
+	if mockGH == nil {
+		return nil
+	}
+	return mockGH.webhookURLs
+}
+
 type ctxHelper struct{}
 
 var ctx = &ctxHelper{}
@@ -226,3 +405,27 @@ func (h *flagsHelper) any() Flags {
 		ConfigPath: "/path/to/config.yaml",
 	}
 }
+
+func (h *flagsHelper) dryRun() Flags {
+	f := h.any()
+	f.DryRun = true
+	return f
+}
+
+func (h *flagsHelper) withOutput(path string) Flags {
+	f := h.any()
+	f.Output = path
+	return f
+}
+
+func (h *flagsHelper) forEnv(env string) Flags {
+	f := h.any()
+	f.Env = env
+	return f
+}
+
+func (h *flagsHelper) removing(fullNames ...string) Flags {
+	f := h.any()
+	f.Remove = fullNames
+	return f
+}
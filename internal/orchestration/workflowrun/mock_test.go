@@ -108,12 +108,22 @@ func (m *mockConfigClient) LoadOptional() (*ralphcfg.RalphConfig, error) {
 }
 
 type mockProjectClient struct {
+	locateFunc func(string, string) (string, error)
 	loadFunc   func(string) (*ralphproj.Project, error)
 	loadCalled bool
+	loadedPath string
+}
+
+func (m *mockProjectClient) Locate(path, branch string) (string, error) {
+	if m.locateFunc != nil {
+		return m.locateFunc(path, branch)
+	}
+	return path, nil
 }
 
 func (m *mockProjectClient) Load(path string) (*ralphproj.Project, error) {
 	m.loadCalled = true
+	m.loadedPath = path
 	if m.loadFunc != nil {
 		return m.loadFunc(path)
 	}
@@ -287,6 +297,16 @@ func (h *configHelper) thatFailsParsing() *mockConfigClient {
 	}
 }
 
+func (h *configHelper) thatUsesCloneStrategy(strategy string) *mockConfigClient {
+	return &mockConfigClient{
+		loadOptionalFunc: func() (*ralphcfg.RalphConfig, error) {
+			cfg := ralphcfg.Any()
+			cfg.Workflow.CloneStrategy = strategy
+			return cfg, nil
+		},
+	}
+}
+
 func (h *configHelper) loadCalled() bool {
 	return mockCfg != nil && mockCfg.loadOptionalCalled
 }
@@ -362,6 +382,13 @@ func (h *projectHelper) loadCalled() bool {
 	return mockProj != nil && mockProj.loadCalled
 }
 
+func (h *projectHelper) loadedPath() string {
+	if mockProj == nil {
+		return ""
+	}
+	return mockProj.loadedPath
+}
+
 type outputHelper struct{}
 
 var output = &outputHelper{}
@@ -407,6 +434,13 @@ func (h *flagsHelper) withNoProjectPath() WorkflowRunFlags {
 	return f
 }
 
+func (h *flagsHelper) withProjectBranch(path, branch string) WorkflowRunFlags {
+	f := h.any()
+	f.ProjectPath = path
+	f.ProjectBranch = branch
+	return f
+}
+
 func (h *flagsHelper) withDebugBranch(branch string) WorkflowRunFlags {
 	f := h.any()
 	f.Debug = branch
@@ -34,6 +34,9 @@ type ConfigClient interface {
 }
 
 type ProjectClient interface {
+	// Locate returns path when it holds the project for branch, or else the project file in the
+	// checkout that works on branch.
+	Locate(path, branch string) (string, error)
 	Load(path string) (*ralphproj.Project, error)
 }
 
@@ -109,13 +112,22 @@ func (w *WorkflowRunCmd) Run(flags WorkflowRunFlags) error {
 	if err != nil {
 		return err
 	}
-	proj, err := w.project.Load(flags.ProjectPath)
+	projectPath := flags.ProjectPath
+	if flags.ProjectBranch != "" {
+		projectPath, err = w.project.Locate(flags.ProjectPath, flags.ProjectBranch)
+		if err != nil {
+			return err
+		}
+	}
+	proj, err := w.project.Load(projectPath)
 	if err != nil {
 		return err
 	}
 	w.applyFlags(proj, cfg, flags)
-	if err := w.syncBaseBranch(flags.BaseBranch, flags.ProjectBranch); err != nil {
-		return err
+	if cfg.Workflow.SyncsBaseBranch() {
+		if err := w.syncBaseBranch(flags.BaseBranch, flags.ProjectBranch); err != nil {
+			return err
+		}
 	}
 	return w.runner.RunLocal(proj, cfg)
 }
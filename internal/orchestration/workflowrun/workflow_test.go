@@ -61,6 +61,33 @@ func TestRunProjectLoadFailureAbortsBeforeSync(t *testing.T) {
 	require.False(t, git.fetchCalled())
 }
 
+func TestRunLoadsProjectLocatedForBranch(t *testing.T) {
+	located := &mockProjectClient{
+		locateFunc: func(path, branch string) (string, error) {
+			require.Equal(t, "projects/add-login.yaml", path)
+			require.Equal(t, "add-login", branch)
+			return "projects/login.yaml", nil
+		},
+	}
+	cmd := run.withMocks(
+		run.withProject(located),
+	)
+	err := cmd.Run(flags.withProjectBranch("projects/add-login.yaml", "add-login"))
+	require.NoError(t, err)
+	require.Equal(t, "projects/login.yaml", project.loadedPath())
+}
+
+func TestRunLocateFailureAbortsBeforeLoad(t *testing.T) {
+	cmd := run.withMocks(
+		run.withProject(&mockProjectClient{
+			locateFunc: func(string, string) (string, error) { return "", errMock },
+		}),
+	)
+	err := cmd.Run(flags.withProjectBranch("projects/add-login.yaml", "add-login"))
+	require.Error(t, err)
+	require.False(t, project.loadCalled())
+}
+
 func TestSyncBaseBranchFetchFailureContinues(t *testing.T) {
 	cmd := run.withMocks(
 		run.withGit(git.thatFailsFetch()),
@@ -90,6 +117,30 @@ func TestSyncBaseBranchConflictsAbortAndInvokeAI(t *testing.T) {
 	require.True(t, ai.conflictsResolved())
 }
 
+func TestRunCloneStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		wantFetch bool
+	}{
+		{name: "default fetches base", strategy: "", wantFetch: true},
+		{name: "base-then-branch fetches base", strategy: ralphcfg.CloneStrategyBaseThenBranch, wantFetch: true},
+		{name: "branch-only skips base", strategy: ralphcfg.CloneStrategyBranchOnly, wantFetch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := run.withMocks(
+				run.withConfig(config.thatUsesCloneStrategy(tt.strategy)),
+			)
+			err := cmd.Run(flags.any())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFetch, git.fetchCalled())
+			require.True(t, runner.runLocalCalled())
+		})
+	}
+}
+
 func TestRunDelegatesToLocalRunner(t *testing.T) {
 	cmd := run.withMocks()
 	err := cmd.Run(flags.any())
@@ -1,18 +1,69 @@
 package output
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
 var (
-	warnColor    = color.New(color.FgYellow)
-	errorColor   = color.New(color.FgRed)
-	successColor = color.New(color.FgGreen)
+	warnColor    = color.FgYellow
+	errorColor   = color.FgRed
+	successColor = color.FgGreen
+	headerColor  = color.Bold
 )
 
+// ColorMode selects when warnings, errors and successes are written in color
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"   // Color only when writing to a terminal and NO_COLOR is unset
+	ColorAlways ColorMode = "always" // Color even when writing to a pipe or file
+	ColorNever  ColorMode = "never"  // Never color
+)
+
+var colorMode = ColorAuto
+
+// SetColorMode sets the color mode of every Client.
+func SetColorMode(mode ColorMode) {
+	colorMode = mode
+}
+
+// isTTY reports whether w is a terminal; replaced in tests
+var isTTY = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
+func colorEnabled(w io.Writer) bool {
+	switch colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTTY(w)
+}
+
+// colorFor returns a printer for attr that colors its output only when w should be colored.
+func colorFor(w io.Writer, attr color.Attribute) *color.Color {
+	c := color.New(attr)
+	if colorEnabled(w) {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return c
+}
+
 type Client struct {
 	out       io.Writer
 	err       io.Writer
@@ -27,44 +78,80 @@ func (c *Client) Debug(msg string) {
 	if !c.debugging {
 		return
 	}
-	fmt.Fprintln(c.out, msg)
+	fmt.Fprintln(c.out, Redact(msg))
 }
 
 func (c *Client) Debugf(format string, a ...any) {
 	if !c.debugging {
 		return
 	}
-	fmt.Fprintf(c.out, format+"\n", a...)
+	fmt.Fprintln(c.out, Redact(fmt.Sprintf(format, a...)))
 }
 
 func (c *Client) Info(msg string) {
-	fmt.Fprintln(c.out, msg)
+	fmt.Fprintln(c.out, Redact(msg))
 }
 
 func (c *Client) Infof(format string, a ...any) {
-	fmt.Fprintf(c.out, format+"\n", a...)
+	fmt.Fprintln(c.out, Redact(fmt.Sprintf(format, a...)))
 }
 
 func (c *Client) Warn(msg string) {
-	warnColor.Fprintln(c.out, msg)
+	colorFor(c.out, warnColor).Fprintln(c.out, Redact(msg))
 }
 
 func (c *Client) Warnf(format string, a ...any) {
-	warnColor.Fprintf(c.out, format+"\n", a...)
+	colorFor(c.out, warnColor).Fprintln(c.out, Redact(fmt.Sprintf(format, a...)))
 }
 
 func (c *Client) Error(msg string) {
-	errorColor.Fprintln(c.err, msg)
+	colorFor(c.err, errorColor).Fprintln(c.err, Redact(msg))
 }
 
 func (c *Client) Errorf(format string, a ...any) {
-	errorColor.Fprintf(c.err, format+"\n", a...)
+	colorFor(c.err, errorColor).Fprintln(c.err, Redact(fmt.Sprintf(format, a...)))
 }
 
 func (c *Client) Success(msg string) {
-	successColor.Fprintln(c.out, "✓ "+msg)
+	colorFor(c.out, successColor).Fprintln(c.out, "✓ "+Redact(msg))
 }
 
 func (c *Client) Successf(format string, a ...any) {
-	successColor.Fprintf(c.out, "✓ "+format+"\n", a...)
+	colorFor(c.out, successColor).Fprintln(c.out, "✓ "+Redact(fmt.Sprintf(format, a...)))
+}
+
+// Header writes msg as a section header that stands out from streamed agent output.
+func (c *Client) Header(msg string) {
+	colorFor(c.out, headerColor).Fprintln(c.out, "==> "+Redact(msg))
+}
+
+// IterationHeader writes the header that starts an iteration of the run loop.
+func (c *Client) IterationHeader(iteration, limit int) {
+	c.Header(fmt.Sprintf("Iteration %d of at most %d", iteration, limit))
+}
+
+// Confirm writes question to out and reads the answer from in; only "y" or "yes" confirm, so an
+// empty answer or end of input declines.
+func (c *Client) Confirm(in io.Reader, question string) (bool, error) {
+	fmt.Fprintf(c.out, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// TerminalConfirm returns a Confirm that reads the answer from stdin, or nil when stdin is not a
+// terminal and so cannot be prompted.
+func (c *Client) TerminalConfirm() func(question string) (bool, error) {
+	if !isTTY(os.Stdin) {
+		return nil
+	}
+	return func(question string) (bool, error) {
+		return c.Confirm(os.Stdin, question)
+	}
 }
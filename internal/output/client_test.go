@@ -2,6 +2,8 @@ package output
 
 import (
 	"bytes"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -240,3 +242,101 @@ func TestClientSuccessf(t *testing.T) {
 		})
 	}
 }
+
+func TestClientHeader(t *testing.T) {
+	tests := []testCase{
+		{
+			name:        "writes marked header to out",
+			debugging:   false,
+			msg:         "Iteration 1 of at most 3",
+			outContains: "==> Iteration 1 of at most 3",
+			errContains: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err bytes.Buffer
+			NewClient(&out, &err, tt.debugging).Header(tt.msg)
+			checkOutput(t, out.String(), err.String(), tt.outContains, tt.errContains)
+		})
+	}
+}
+
+func TestClientIterationHeader(t *testing.T) {
+	var out, err bytes.Buffer
+	NewClient(&out, &err, false).IterationHeader(2, 5)
+	checkOutput(t, out.String(), err.String(), "==> Iteration 2 of at most 5", "")
+}
+
+func withColor(t *testing.T, mode ColorMode, tty bool) {
+	t.Helper()
+	origMode, origTTY := colorMode, isTTY
+	t.Cleanup(func() { colorMode, isTTY = origMode, origTTY })
+	SetColorMode(mode)
+	isTTY = func(io.Writer) bool { return tty }
+}
+
+func TestClientColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      ColorMode
+		tty       bool
+		noColor   bool
+		wantColor bool
+	}{
+		{name: "auto without a terminal", mode: ColorAuto, tty: false, wantColor: false},
+		{name: "auto on a terminal", mode: ColorAuto, tty: true, wantColor: true},
+		{name: "auto with NO_COLOR on a terminal", mode: ColorAuto, tty: true, noColor: true, wantColor: false},
+		{name: "never on a terminal", mode: ColorNever, tty: true, wantColor: false},
+		{name: "always without a terminal", mode: ColorAlways, tty: false, wantColor: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withColor(t, tt.mode, tt.tty)
+			t.Setenv("TERM", "xterm")
+			t.Setenv("NO_COLOR", "")
+			if tt.noColor {
+				t.Setenv("NO_COLOR", "1")
+			}
+
+			var out, err bytes.Buffer
+			c := NewClient(&out, &err, false)
+			c.Warn("careful")
+			c.Success("done")
+			c.Error("failed")
+
+			for _, got := range []string{out.String(), err.String()} {
+				if tt.wantColor {
+					assert.Contains(t, got, "\x1b[")
+				} else {
+					assert.NotContains(t, got, "\x1b[")
+				}
+			}
+			assert.Contains(t, out.String(), "✓ done")
+		})
+	}
+}
+
+func TestClientConfirm(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   bool
+	}{
+		{name: "y confirms", answer: "y\n", want: true},
+		{name: "yes confirms in any case", answer: " YES \n", want: true},
+		{name: "n declines", answer: "n\n"},
+		{name: "empty answer declines", answer: "\n"},
+		{name: "end of input declines", answer: ""},
+		{name: "answer without newline is read", answer: "y", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := NewClient(&out, io.Discard, false).Confirm(strings.NewReader(tt.answer), "Create it?")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "Create it? [y/N] ", out.String())
+		})
+	}
+}
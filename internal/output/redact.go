@@ -0,0 +1,72 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces every secret masked by Redact
+const Redacted = "[REDACTED]"
+
+// minSecretLength keeps short values, which could appear in ordinary text, from being registered
+const minSecretLength = 8
+
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`), Redacted},
+	{regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`), Redacted},
+	{regexp.MustCompile(`(x-access-token:)[^@\s/]+`), "${1}" + Redacted},
+}
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// RegisterSecret makes Redact mask value, such as a webhook secret, wherever it appears.
+// Values shorter than eight characters are ignored.
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+}
+
+// Redact masks GitHub tokens, x-access-token credentials and registered secrets in s.
+func Redact(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	secretsMu.RUnlock()
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string { return Redact(e.err.Error()) }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// RedactError returns err with Redact applied to its message, keeping err in its chain so
+// errors.Is and errors.As, and exit codes derived from them, still work. A nil err stays nil.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LineWriter relays a child process's output to w one complete line at a time, redacting each
+// line, so streamed output never splits a log line written by a Client around it.
+type LineWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	partial []byte
+}
+
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+// Write writes every complete line in p and holds back a trailing partial line until it is
+// completed or flushed.
+func (l *LineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.partial = append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if _, err := fmt.Fprintln(l.w, Redact(string(l.partial[:i]))); err != nil {
+			return len(p), err
+		}
+		l.partial = l.partial[i+1:]
+	}
+}
+
+// Flush writes a held back partial line, ending it with a newline.
+func (l *LineWriter) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.partial) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(l.w, Redact(string(l.partial)))
+	l.partial = nil
+	return err
+}
+
+// LineWriters returns line writers for a child process's stdout and stderr that write to the
+// Client's out and err.
+func (c *Client) LineWriters() (stdout, stderr *LineWriter) {
+	return NewLineWriter(c.out), NewLineWriter(c.err)
+}
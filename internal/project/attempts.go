@@ -0,0 +1,61 @@
+package project
+
+// AttemptTracker counts consecutive iterations spent on the same failing requirement and
+// remembers the requirements that reached the limit. A limit of 0 disables tracking.
+type AttemptTracker struct {
+	limit int
+	slug  string
+	count int
+	stuck map[string]bool
+}
+
+func NewAttemptTracker(limit int) *AttemptTracker {
+	return &AttemptTracker{limit: limit, stuck: map[string]bool{}}
+}
+
+// Limit returns the number of consecutive iterations after which a requirement is stuck.
+func (t *AttemptTracker) Limit() int {
+	return t.limit
+}
+
+// Record counts an iteration focused on slug and reports whether the requirement just became stuck.
+func (t *AttemptTracker) Record(slug string, passing bool) bool {
+	if t == nil || t.limit <= 0 || slug == "" {
+		return false
+	}
+	if passing {
+		t.slug, t.count = "", 0
+		return false
+	}
+	if slug != t.slug {
+		t.slug, t.count = slug, 0
+	}
+	t.count++
+	if t.count < t.limit {
+		return false
+	}
+	t.stuck[slug] = true
+	t.slug, t.count = "", 0
+	return true
+}
+
+// Pickable returns the project the picker should choose from, without stuck requirements.
+func (t *AttemptTracker) Pickable(proj *Project) *Project {
+	if t == nil || len(t.stuck) == 0 {
+		return proj
+	}
+	return WithoutRequirements(proj, t.stuck)
+}
+
+// AllStuck reports whether every failing requirement in proj is stuck.
+func (t *AttemptTracker) AllStuck(proj *Project) bool {
+	if t == nil || len(t.stuck) == 0 {
+		return false
+	}
+	for _, req := range proj.Requirements {
+		if !req.Passing && !t.stuck[req.Slug] {
+			return false
+		}
+	}
+	return true
+}
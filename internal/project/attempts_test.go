@@ -0,0 +1,77 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttemptTrackerRecord(t *testing.T) {
+	type attempt struct {
+		slug    string
+		passing bool
+	}
+	tests := []struct {
+		name      string
+		limit     int
+		attempts  []attempt
+		wantStuck []bool
+	}{
+		{
+			name:      "disabled",
+			limit:     0,
+			attempts:  []attempt{{"a", false}, {"a", false}, {"a", false}},
+			wantStuck: []bool{false, false, false},
+		},
+		{
+			name:      "stuck after consecutive failures",
+			limit:     2,
+			attempts:  []attempt{{"a", false}, {"a", false}},
+			wantStuck: []bool{false, true},
+		},
+		{
+			name:      "switching requirements resets the count",
+			limit:     2,
+			attempts:  []attempt{{"a", false}, {"b", false}, {"a", false}, {"a", false}},
+			wantStuck: []bool{false, false, false, true},
+		},
+		{
+			name:      "passing resets the count",
+			limit:     2,
+			attempts:  []attempt{{"a", false}, {"a", true}, {"a", false}},
+			wantStuck: []bool{false, false, false},
+		},
+		{
+			name:      "unknown slug is not tracked",
+			limit:     1,
+			attempts:  []attempt{{"", false}},
+			wantStuck: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewAttemptTracker(tt.limit)
+			for i, a := range tt.attempts {
+				assert.Equal(t, tt.wantStuck[i], tracker.Record(a.slug, a.passing), "attempt %d", i)
+			}
+		})
+	}
+}
+
+func TestAttemptTrackerPickableAndAllStuck(t *testing.T) {
+	proj := WithFailingRequirementsCount(2)
+	tracker := NewAttemptTracker(1)
+	assert.Same(t, proj, tracker.Pickable(proj))
+	assert.False(t, tracker.AllStuck(proj))
+
+	tracker.Record("req-1", false)
+	pickable := tracker.Pickable(proj)
+	assert.Len(t, pickable.Requirements, 1)
+	assert.Equal(t, "req-2", pickable.Requirements[0].Slug)
+	assert.Len(t, proj.Requirements, 2)
+	assert.False(t, tracker.AllStuck(proj))
+
+	tracker.Record("req-2", false)
+	assert.True(t, tracker.AllStuck(proj))
+}
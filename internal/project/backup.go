@@ -0,0 +1,117 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zon/ralph/internal/config"
+)
+
+const backupTimeFormat = "20060102T150405.000000000"
+
+// BackupOptions controls the copies kept of a project file before it is overwritten.
+// The zero value disables backups.
+type BackupOptions struct {
+	Dir  string // Directory backups are written to
+	Keep int    // Number of backups retained per project file
+}
+
+// BackupOptionsFromConfig returns the backup options configured in .ralph/config.yaml.
+func BackupOptionsFromConfig(cfg *config.RalphConfig) BackupOptions {
+	if cfg == nil || !cfg.Backup.Enabled {
+		return BackupOptions{}
+	}
+	return BackupOptions{Dir: cfg.BackupDir(), Keep: cfg.Backup.Keep}
+}
+
+func (o BackupOptions) enabled() bool {
+	return o.Dir != "" && o.Keep > 0
+}
+
+var (
+	backupMu sync.RWMutex
+	backup   BackupOptions
+)
+
+// SetBackup makes every later rewrite of a project file first back it up with the backup
+// settings of cfg. A nil cfg, or one with backups disabled, turns backups off.
+func SetBackup(cfg *config.RalphConfig) {
+	opts := BackupOptionsFromConfig(cfg)
+	backupMu.Lock()
+	defer backupMu.Unlock()
+	backup = opts
+}
+
+func currentBackup() BackupOptions {
+	backupMu.RLock()
+	defer backupMu.RUnlock()
+	return backup
+}
+
+// BackupProject copies the project file into the backup directory under a timestamped name and
+// prunes older backups of the same file beyond the retention limit. It returns the backup path,
+// or an empty string when backups are disabled or the file does not exist yet.
+func BackupProject(path string, opts BackupOptions) (string, error) {
+	return backupProjectAt(path, opts, time.Now())
+}
+
+func backupProjectAt(path string, opts BackupOptions, now time.Time) (string, error) {
+	if !opts.enabled() {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read project file for backup: %w", err)
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	base := filepath.Base(path)
+	backupPath := filepath.Join(opts.Dir, fmt.Sprintf("%s.%s.bak", base, now.UTC().Format(backupTimeFormat)))
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write project backup: %w", err)
+	}
+
+	if err := pruneBackups(opts.Dir, base, opts.Keep); err != nil {
+		return "", err
+	}
+
+	return backupPath, nil
+}
+
+// pruneBackups removes the oldest backups of base in dir so that at most keep remain.
+func pruneBackups(dir, base string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base+".") || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+		backups = append(backups, name)
+	}
+	sort.Strings(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
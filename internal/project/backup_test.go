@@ -0,0 +1,129 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/config"
+)
+
+func TestBackupProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    func(dir string) BackupOptions
+		create  bool
+		backups int
+	}{
+		{
+			name:    "disabled by zero options",
+			opts:    func(string) BackupOptions { return BackupOptions{} },
+			create:  true,
+			backups: 0,
+		},
+		{
+			name:    "copies existing file",
+			opts:    func(dir string) BackupOptions { return BackupOptions{Dir: dir, Keep: 2} },
+			create:  true,
+			backups: 1,
+		},
+		{
+			name:    "skips missing file",
+			opts:    func(dir string) BackupOptions { return BackupOptions{Dir: dir, Keep: 2} },
+			create:  false,
+			backups: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			backupDir := filepath.Join(dir, "backups")
+			path := filepath.Join(dir, "project.yaml")
+			if tt.create {
+				require.NoError(t, os.WriteFile(path, []byte("slug: test\n"), 0644))
+			}
+
+			backupPath, err := BackupProject(path, tt.opts(backupDir))
+			require.NoError(t, err)
+
+			entries, _ := os.ReadDir(backupDir)
+			assert.Len(t, entries, tt.backups)
+			if tt.backups == 0 {
+				assert.Empty(t, backupPath)
+				return
+			}
+
+			data, err := os.ReadFile(backupPath)
+			require.NoError(t, err)
+			assert.Equal(t, "slug: test\n", string(data))
+		})
+	}
+}
+
+func TestBackupProjectPrunesOldest(t *testing.T) {
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	path := filepath.Join(dir, "project.yaml")
+	other := filepath.Join(dir, "other.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("slug: test\n"), 0644))
+	require.NoError(t, os.WriteFile(other, []byte("slug: other\n"), 0644))
+
+	opts := BackupOptions{Dir: backupDir, Keep: 2}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err := backupProjectAt(other, opts, start)
+	require.NoError(t, err)
+
+	var paths []string
+	for i := 0; i < 4; i++ {
+		backupPath, err := backupProjectAt(path, opts, start.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		paths = append(paths, backupPath)
+	}
+
+	for _, removed := range paths[:2] {
+		assert.NoFileExists(t, removed)
+	}
+	for _, kept := range paths[2:] {
+		assert.FileExists(t, kept)
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3, "backups of other project files are not pruned")
+}
+
+func TestSaveProject_Backup(t *testing.T) {
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	SetBackup(&config.RalphConfig{ConfigDir: dir, Backup: config.BackupConfig{Enabled: true, Keep: 1}})
+	t.Cleanup(func() { SetBackup(nil) })
+	path := filepath.Join(dir, "project.yaml")
+	original := "slug: test\ntitle: Test\nrequirements:\n  - slug: a\n    description: A\n    items:\n      - Works\n    passing: false\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	proj, err := LoadProject(path)
+	require.NoError(t, err)
+	proj.Requirements[0].Passing = true
+
+	require.NoError(t, SaveProject(path, proj))
+
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	backup, err := os.ReadFile(filepath.Join(backupDir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, original, string(backup))
+	assert.True(t, RequirementStatus(t, path, "a"))
+}
+
+func TestBackupOptionsFromConfig(t *testing.T) {
+	assert.Equal(t, BackupOptions{}, BackupOptionsFromConfig(nil))
+	assert.Equal(t, BackupOptions{}, BackupOptionsFromConfig(&config.RalphConfig{ConfigDir: ".ralph"}))
+
+	cfg := &config.RalphConfig{ConfigDir: ".ralph", Backup: config.BackupConfig{Enabled: true, Keep: 5}}
+	assert.Equal(t, BackupOptions{Dir: filepath.Join(".ralph", "backups"), Keep: 5}, BackupOptionsFromConfig(cfg))
+}
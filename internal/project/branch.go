@@ -0,0 +1,89 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zon/ralph/internal/git"
+)
+
+// ProjectsDir is where runs started from a pull request look for project files, relative to the
+// repository root
+const ProjectsDir = "projects"
+
+// legacyBranchPrefix is the prefix older releases gave project branches; such branches still map
+// back to their project file
+const legacyBranchPrefix = "ralph/"
+
+// BranchName returns the branch a run of the input works on: its slug made branch-safe.
+func (f *InputFile) BranchName() string {
+	return git.SanitizeBranchName(f.Slug())
+}
+
+// DeriveBranchName returns the branch a run of projectFile works on. It is the single source
+// of the project branch name, used when a run starts and when a pull request is matched back
+// to its project file.
+func DeriveBranchName(projectFile string) (string, error) {
+	input, err := ResolveInputFile(projectFile)
+	if err != nil {
+		return "", err
+	}
+	return input.BranchName(), nil
+}
+
+// ProjectFileFromBranch returns the conventional project file for branch,
+// projects/<name>.yaml, without reading the repository.
+//
+// A legacy "ralph/<name>" branch maps to projects/<name>.yaml. Any other branch uses its full
+// name with slashes replaced by dashes. The guess is right when the file is named after its
+// slug; FindProjectFile confirms it against the checkout.
+func ProjectFileFromBranch(branch string) string {
+	projectName := strings.TrimPrefix(branch, legacyBranchPrefix)
+	if !strings.HasPrefix(branch, legacyBranchPrefix) {
+		projectName = strings.ReplaceAll(branch, "/", "-")
+	}
+	return filepath.Join(ProjectsDir, projectName+".yaml")
+}
+
+// FindProjectFile returns the project file under root whose run works on branch. The
+// conventional file from ProjectFileFromBranch is used when it derives branch; otherwise every
+// project file in the projects directory is checked, in name order, so a file named apart from
+// its slug is still found.
+func FindProjectFile(root, branch string) (string, error) {
+	conventional := filepath.Join(root, ProjectFileFromBranch(branch))
+	if worksOnBranch(conventional, branch) {
+		return conventional, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, ProjectsDir))
+	if err != nil {
+		return "", fmt.Errorf("no project file works on branch %q: %w", branch, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && IsProjectFile(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(root, ProjectsDir, name)
+		if worksOnBranch(path, branch) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no project file in %s works on branch %q", ProjectsDir, branch)
+}
+
+// worksOnBranch reports whether path is a project whose derived branch is branch, with or
+// without the legacy prefix.
+func worksOnBranch(path, branch string) bool {
+	derived, err := DeriveBranchName(path)
+	if err != nil {
+		return false
+	}
+	return derived == branch || legacyBranchPrefix+derived == branch
+}
@@ -0,0 +1,92 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectFileFromBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"ralph/my-feature", "projects/my-feature.yaml"},
+		{"ralph/github-webhook-service", "projects/github-webhook-service.yaml"},
+		{"feature/something", "projects/feature-something.yaml"},
+		{"", "projects/.yaml"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.branch, func(t *testing.T) {
+			assert.Equal(t, tc.want, ProjectFileFromBranch(tc.branch))
+		})
+	}
+}
+
+// TestRunAndMergeAgreeOnBranch checks that the branch a run works on maps back to the project
+// file it ran, which is how runs started from a pull request find their project.
+func TestRunAndMergeAgreeOnBranch(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		slug   string
+		branch string
+	}{
+		{name: "file named after slug", file: "add-login.yaml", slug: "add-login", branch: "add-login"},
+		{name: "file named apart from slug", file: "login.yaml", slug: "add-login", branch: "add-login"},
+		{name: "slug needing sanitizing", file: "Auth_Flow.yml", slug: "Auth Flow v2", branch: "auth-flow-v2"},
+		{name: "json project", file: "billing.json", slug: "billing-export", branch: "billing-export"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, ProjectsDir), 0755))
+			// a neighbouring project that sorts first must not be picked up
+			writeBranchProject(t, root, "aaa.yaml", "aaa")
+			path := writeBranchProject(t, root, tt.file, tt.slug)
+
+			branch, err := DeriveBranchName(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.branch, branch)
+
+			found, err := FindProjectFile(root, branch)
+			require.NoError(t, err)
+			assert.Equal(t, path, found)
+
+			found, err = FindProjectFile(root, "ralph/"+branch)
+			require.NoError(t, err)
+			assert.Equal(t, path, found, "legacy ralph/ branches map to the same file")
+		})
+	}
+
+	t.Run("no project works on the branch", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ProjectsDir), 0755))
+		writeBranchProject(t, root, "add-login.yaml", "add-login")
+
+		_, err := FindProjectFile(root, "remove-login")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no project file in projects works on branch "remove-login"`)
+	})
+
+	t.Run("no projects directory", func(t *testing.T) {
+		_, err := FindProjectFile(t.TempDir(), "add-login")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no project file works on branch "add-login"`)
+	})
+}
+
+func writeBranchProject(t *testing.T, root, name, slug string) string {
+	t.Helper()
+	path := filepath.Join(root, ProjectsDir, name)
+	content := "slug: " + slug + "\nrequirements:\n  - description: Do it\n    items: [Do it]\n    passing: false\n"
+	if filepath.Ext(name) == ".json" {
+		content = `{"slug": "` + slug + `", "requirements": [{"description": "Do it", "items": ["Do it"], "passing": false}]}`
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
@@ -9,9 +9,12 @@ import (
 
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/git"
+	"github.com/zon/ralph/internal/output"
 )
 
-type Client struct{}
+type Client struct {
+	Out *output.Client // Receives warnings about run events that could not be written
+}
 
 func (c *Client) Load(path string) (*Project, error) {
 	return LoadProject(path)
@@ -49,7 +52,31 @@ func (c *Client) AllRequirementsPassing(proj *Project) bool {
 	return allComplete
 }
 
+// AppendEvent appends event to the events file at path. A failed write is only a warning, so a
+// broken stream never interrupts the run.
+func (c *Client) AppendEvent(path string, event Event) {
+	if err := AppendEvent(path, event); err != nil && c.Out != nil {
+		c.Out.Warnf("Could not record run event: %v", err)
+	}
+}
+
+// WriteSummary writes the outcome of a local run to path.
+func (c *Client) WriteSummary(path string, summary Summary) error {
+	return WriteSummary(path, summary)
+}
+
+// Restore writes proj back to its file, undoing the changes an iteration made to it.
+func (c *Client) Restore(proj *Project) error {
+	return SaveProject(proj.Path, proj)
+}
+
 func (c *Client) ExtraIterations(proj *Project, cfg *config.RalphConfig) int {
+	return ExtraIterations(proj, cfg)
+}
+
+// ExtraIterations returns how many iterations a run allows beyond one per requirement: the
+// configured extraIterations, or 20% of the requirement count rounded up when unset.
+func ExtraIterations(proj *Project, cfg *config.RalphConfig) int {
 	if cfg.ExtraIterations != nil {
 		return *cfg.ExtraIterations
 	}
@@ -102,7 +129,7 @@ func (c *Client) NormalizeAndStage(proj *Project) {
 	}
 	normalized := []byte(strings.TrimRight(string(data), "\n") + "\n")
 	if len(normalized) != len(data) {
-		os.WriteFile(proj.Path, normalized, 0644)
+		replaceProjectFile(proj.Path, normalized)
 	}
 	git.StageFile(proj.Path)
 }
@@ -16,9 +16,17 @@ type MockClient struct {
 	NormalizeAndStageCalled      bool
 	ExtraIterationsFunc          func() int
 	ExtraIterationsErrorFunc     func() error
+	ReloadFunc                   func(*Project) *Project
+	RestoreFunc                  func(proj *Project) error
+	WriteSummaryFunc             func(path string, summary Summary) error
+	Summaries                    []Summary
+	Events                       []Event
 }
 
 func (m *MockClient) Reload(proj *Project) *Project {
+	if m.ReloadFunc != nil {
+		return m.ReloadFunc(proj)
+	}
 	return proj
 }
 
@@ -26,6 +34,25 @@ func (m *MockClient) AllRequirementsPassing(_ *Project) bool {
 	return m.AllPassingFunc()
 }
 
+func (m *MockClient) AppendEvent(_ string, event Event) {
+	m.Events = append(m.Events, event)
+}
+
+func (m *MockClient) WriteSummary(path string, summary Summary) error {
+	m.Summaries = append(m.Summaries, summary)
+	if m.WriteSummaryFunc != nil {
+		return m.WriteSummaryFunc(path, summary)
+	}
+	return nil
+}
+
+func (m *MockClient) Restore(proj *Project) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(proj)
+	}
+	return nil
+}
+
 func (m *MockClient) HasChanges(proj *Project) bool {
 	if m.HasChangesFunc != nil {
 		return m.HasChangesFunc(proj)
@@ -0,0 +1,213 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// renderProject produces the bytes SaveProject writes to path.
+// It patches the existing document in place when possible and falls back to a full marshal otherwise.
+// JSON projects carry no comments worth preserving, so they are always marshalled in full.
+func renderProject(path string, p *Project) ([]byte, error) {
+	if isJSONProject(path) {
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal project: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	if existing, err := os.ReadFile(path); err == nil {
+		if data, ok := patchProjectDocument(existing, p); ok {
+			return data, nil
+		}
+	}
+
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project: %w", err)
+	}
+	return data, nil
+}
+
+// patchProjectDocument updates the passing and notes fields of each requirement in the existing
+// YAML document, adding any slug generated on load. It reports false when the document's requirements do not line up with the
+// project, or when the patched document would not decode back to the same project, so the caller
+// can fall back to a full rewrite.
+func patchProjectDocument(existing []byte, p *Project) ([]byte, bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(existing, &doc); err != nil || len(doc.Content) == 0 {
+		return nil, false
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, false
+	}
+
+	reqs := mappingValue(root, "requirements")
+	if reqs == nil || reqs.Kind != yaml.SequenceNode || len(reqs.Content) != len(p.Requirements) {
+		return nil, false
+	}
+
+	for i, reqNode := range reqs.Content {
+		req := p.Requirements[i]
+		if reqNode.Kind != yaml.MappingNode {
+			return nil, false
+		}
+		slug := mappingValue(reqNode, "slug")
+		if slug == nil && req.Slug != "" {
+			// Persist a slug generated on load
+			reqNode.Content = append([]*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "slug"},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: req.Slug},
+			}, reqNode.Content...)
+		} else if slug == nil || slug.Value != req.Slug {
+			return nil, false
+		}
+
+		setMappingScalar(reqNode, "passing", strconv.FormatBool(req.Passing), "!!bool")
+		if req.Notes != "" {
+			setMappingScalar(reqNode, "notes", req.Notes, "!!str")
+		} else {
+			removeMappingKey(reqNode, "notes")
+		}
+	}
+
+	var patched Project
+	if err := doc.Decode(&patched); err != nil {
+		return nil, false
+	}
+	patched.Path = p.Path
+	patched.BaseBranch = p.BaseBranch
+	if !reflect.DeepEqual(&patched, p) {
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectIndent(existing))
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, false
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingScalar sets key to a scalar value, keeping the existing node (and its comments) when present.
+func setMappingScalar(node *yaml.Node, key, value, tag string) {
+	style := yaml.Style(0)
+	if strings.Contains(value, "\n") {
+		style = yaml.LiteralStyle
+	}
+
+	if existing := mappingValue(node, key); existing != nil {
+		if existing.Kind == yaml.ScalarNode && existing.Value == value {
+			return
+		}
+		existing.Kind = yaml.ScalarNode
+		existing.Tag = tag
+		existing.Value = value
+		existing.Style = style
+		existing.Content = nil
+		return
+	}
+
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Style: style},
+	)
+}
+
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// detectIndent returns the indentation width of the first indented line, defaulting to 2.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent > 0 {
+			if indent > 8 {
+				return 2
+			}
+			return indent
+		}
+	}
+	return 2
+}
+
+// replaceProjectFile backs up the project file at path with the settings given to SetBackup and then
+// atomically replaces it with data. Every rewrite of a project file goes through here.
+func replaceProjectFile(path string, data []byte) error {
+	if _, err := BackupProject(path, currentBackup()); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeFileAtomic writes a file by streaming to a temp file in the same directory and renaming it
+// into place. The original file's permissions are kept; new files are created with mode 0644.
+// If write fails, the temp file is removed and the original file is left untouched.
+func writeFileAtomic(path string, write func(io.Writer) error) (err error) {
+	mode := os.FileMode(0644)
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,152 @@
+package project
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentedProject = `# Project header comment
+title: Commented project
+slug: commented
+requirements:
+  # First requirement comment
+  - slug: first
+    passing: false # flip me
+    description: First requirement
+    items:
+      - Item A
+  - description: Second requirement
+    slug: second
+    items:
+      - Item B # trailing item comment
+    passing: true
+# Footer comment
+`
+
+func TestSaveProjectPreservesCommentsAndOrder(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "commented.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(commentedProject), 0644))
+
+	proj, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	require.NoError(t, UpdateRequirementStatus(proj, "first", true))
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	expected := `# Project header comment
+title: Commented project
+slug: commented
+requirements:
+  # First requirement comment
+  - slug: first
+    passing: true # flip me
+    description: First requirement
+    items:
+      - Item A
+  - description: Second requirement
+    slug: second
+    items:
+      - Item B # trailing item comment
+    passing: true
+# Footer comment
+`
+	assert.Equal(t, expected, string(data))
+}
+
+func TestSaveProjectAddsMissingFieldsInPlace(t *testing.T) {
+	content := `slug: sparse
+requirements:
+  - slug: only
+    items:
+      - Item A # keep
+`
+	projectPath := filepath.Join(t.TempDir(), "sparse.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(content), 0644))
+
+	proj, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	proj.Requirements[0].Passing = true
+	proj.Requirements[0].Notes = "verified via TestOnly"
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "- Item A # keep")
+	assert.Contains(t, string(data), "passing: true")
+	assert.Contains(t, string(data), "notes: verified via TestOnly")
+
+	reloaded, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	assert.True(t, reloaded.Requirements[0].Passing)
+	assert.Equal(t, "verified via TestOnly", reloaded.Requirements[0].Notes)
+}
+
+func TestSaveProjectFallsBackToFullRewrite(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "rewrite.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(commentedProject), 0644))
+
+	proj, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	proj.Title = "Retitled"
+	proj.Requirements = append(proj.Requirements, validRequirement("third"))
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	reloaded, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Retitled", reloaded.Title)
+	require.Len(t, reloaded.Requirements, 3)
+	assert.Equal(t, "third", reloaded.Requirements[2].Slug)
+}
+
+func TestSaveProjectPreservesFileMode(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "mode.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(commentedProject), 0600))
+
+	proj, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	require.NoError(t, UpdateRequirementStatus(proj, "first", true))
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	info, err := os.Stat(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestSaveProjectCreatesNewFileWithDefaultMode(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "new.yaml")
+	require.NoError(t, SaveProject(projectPath, Any()))
+
+	info, err := os.Stat(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestWriteFileAtomicFailureLeavesOriginalIntact(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(commentedProject), 0644))
+
+	err := writeFileAtomic(projectPath, func(w io.Writer) error {
+		if _, err := w.Write([]byte("slug: trunc")); err != nil {
+			return err
+		}
+		return errors.New("encoder failed")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "encoder failed")
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, commentedProject, string(data))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp file must be cleaned up")
+}
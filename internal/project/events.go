@@ -0,0 +1,45 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Event types written by --events.
+const (
+	EventIterationStarted  = "iteration_started"
+	EventIterationFinished = "iteration_finished"
+	EventRequirementPassed = "requirement_passed"
+	EventRunComplete       = "run_complete"
+)
+
+// Event is one line of the newline-delimited JSON stream written by --events.
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	Project     string    `json:"project"`
+	Iteration   int       `json:"iteration,omitempty"`
+	Requirement string    `json:"requirement,omitempty"` // Slug of the requirement that started passing
+	Error       string    `json:"error,omitempty"`       // Why the iteration or run failed
+	Summary     *Summary  `json:"summary,omitempty"`     // Outcome of the run, set on run_complete
+}
+
+// AppendEvent stamps event with the current time and appends it to path as one JSON line.
+func AppendEvent(path string, event Event) error {
+	event.Time = time.Now().UTC()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run event: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/output"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestAppendEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"earlier","time":"2026-01-02T03:04:05Z"}`+"\n"), 0644))
+
+	require.NoError(t, AppendEvent(path, Event{Type: EventRunComplete, Project: "test-project", Summary: &Summary{Complete: true}}))
+
+	events := readEvents(t, path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "earlier", events[0].Type)
+	assert.Equal(t, EventRunComplete, events[1].Type)
+	assert.Equal(t, "test-project", events[1].Project)
+	assert.False(t, events[1].Time.IsZero())
+	require.NotNil(t, events[1].Summary)
+	assert.True(t, events[1].Summary.Complete)
+}
+
+func TestClientAppendEventWarnsOnWriteError(t *testing.T) {
+	var out bytes.Buffer
+	client := &Client{Out: output.NewClient(&out, &out, false)}
+
+	client.AppendEvent(filepath.Join(t.TempDir(), "missing", "events.ndjson"), Event{Type: EventRunComplete})
+	assert.Contains(t, out.String(), "failed to open events file")
+}
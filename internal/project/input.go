@@ -1,7 +1,9 @@
 package project
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,9 +12,10 @@ import (
 )
 
 type InputFile struct {
-	path    string
-	kind    inputFileKind
-	project *Project
+	path      string
+	kind      inputFileKind
+	project   *Project
+	temporary bool // path is a copy of a project read from stdin, removed by Cleanup
 }
 
 type inputFileKind int
@@ -29,6 +32,14 @@ func (f *InputFile) IsOrchestration() bool { return f.kind == inputOrchestration
 func (f *InputFile) Project() *Project     { return f.project }
 func (f *InputFile) Path() string          { return f.path }
 
+// Cleanup removes the file a project read from stdin was copied to. It does nothing for an
+// input that names a file of its own, so callers can always defer it.
+func (f *InputFile) Cleanup() {
+	if f.temporary {
+		os.Remove(f.path)
+	}
+}
+
 func (f *InputFile) Slug() string {
 	if f.kind == inputProject {
 		return f.project.Slug
@@ -39,6 +50,10 @@ func (f *InputFile) Slug() string {
 }
 
 func ResolveInputFile(path string) (*InputFile, error) {
+	if path == StdinPath {
+		return resolveStdinInput(os.Stdin, os.TempDir())
+	}
+
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve input file path: %w", err)
@@ -48,9 +63,8 @@ func ResolveInputFile(path string) (*InputFile, error) {
 	}
 
 	base := filepath.Base(absPath)
-	ext := strings.ToLower(filepath.Ext(base))
 
-	if ext == ".yaml" || ext == ".yml" {
+	if IsProjectFile(base) {
 		proj, err := LoadProject(absPath)
 		if err != nil {
 			return nil, err
@@ -78,3 +92,45 @@ func ResolveInputFile(path string) (*InputFile, error) {
 
 	return nil, fmt.Errorf("unrecognized input file type: %s", absPath)
 }
+
+// resolveStdinInput reads a project from r and copies it into a file under dir, since a run
+// reloads and updates the project file as requirements pass. A JSON project gets a .json copy
+// so it is read and saved as JSON. The caller removes the copy with Cleanup once the run is over.
+func resolveStdinInput(r io.Reader, dir string) (*InputFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project from stdin: %w", err)
+	}
+	ext := ".yaml"
+	if looksLikeJSON(data) {
+		ext = ".json"
+	}
+	proj, err := parseProject(data, StdinPath+ext)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(dir, fmt.Sprintf("ralph-%s-*%s", git.SanitizeBranchName(proj.Slug), ext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project file for stdin: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to write project file for stdin: %w", err)
+	}
+
+	proj.Path = f.Name()
+	return &InputFile{
+		path:      f.Name(),
+		kind:      inputProject,
+		project:   proj,
+		temporary: true,
+	}, nil
+}
+
+// looksLikeJSON reports whether data is a JSON object rather than YAML
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
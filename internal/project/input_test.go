@@ -3,6 +3,7 @@ package project
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,6 +102,17 @@ func TestResolveInputFile(t *testing.T) {
 		assert.Equal(t, "my-project", f.Slug())
 	})
 
+	t.Run("loads project from .json file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "project.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"slug": "my-project", "requirements": [{"slug": "req-1", "items": ["item 1"], "passing": false}]}`), 0644))
+
+		f, err := ResolveInputFile(path)
+		require.NoError(t, err)
+		assert.True(t, f.IsProject())
+		assert.Equal(t, "my-project", f.Slug())
+	})
+
 	t.Run("detects orchestration.md file", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "orchestration.md")
@@ -187,3 +199,62 @@ func TestTestHelpers(t *testing.T) {
 		assert.Nil(t, f.Project())
 	})
 }
+
+func TestResolveStdinInput(t *testing.T) {
+	t.Run("copies piped project into a file", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "slug: piped\nrequirements:\n  - slug: req-1\n    items:\n      - item 1\n    passing: false\n"
+
+		f, err := resolveStdinInput(strings.NewReader(content), dir)
+		require.NoError(t, err)
+		assert.True(t, f.IsProject())
+		assert.Equal(t, "piped", f.Slug())
+		assert.Equal(t, dir, filepath.Dir(f.Path()))
+		assert.Equal(t, f.Path(), f.Project().Path)
+
+		data, err := os.ReadFile(f.Path())
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+
+		f.Cleanup()
+		_, err = os.Stat(f.Path())
+		assert.True(t, os.IsNotExist(err), "Cleanup removes the copy")
+	})
+
+	t.Run("copies a piped JSON project into a .json file", func(t *testing.T) {
+		dir := t.TempDir()
+		content := `{"slug": "piped", "requirements": [{"slug": "req-1", "items": ["item 1"], "passing": false}]}`
+
+		f, err := resolveStdinInput(strings.NewReader(content), dir)
+		require.NoError(t, err)
+		defer f.Cleanup()
+		assert.Equal(t, ".json", filepath.Ext(f.Path()))
+		assert.Equal(t, "piped", f.Slug())
+
+		f.Project().Requirements[0].Passing = true
+		require.NoError(t, SaveProject(f.Path(), f.Project()))
+		data, err := os.ReadFile(f.Path())
+		require.NoError(t, err)
+		assert.True(t, looksLikeJSON(data), "saved back as JSON: %s", data)
+	})
+
+	t.Run("cleanup keeps a named project file", func(t *testing.T) {
+		path := FileWithRequirement(t, "req-1", false)
+		f, err := ResolveInputFile(path)
+		require.NoError(t, err)
+
+		f.Cleanup()
+		_, err = os.Stat(path)
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error for invalid project", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := resolveStdinInput(strings.NewReader("slug: \nrequirements: []\n"), dir)
+		require.Error(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
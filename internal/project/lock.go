@@ -0,0 +1,54 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// LockFileName is the file in the .ralph directory that a local run holds locked while it runs.
+const LockFileName = "project.lock"
+
+// ErrRunInProgress is returned by AcquireLock when another process holds the project lock.
+var ErrRunInProgress = errors.New("another ralph run is in progress")
+
+// Lock is an exclusive flock on the .ralph/project.lock file, keeping concurrent local runs
+// in the same repository from writing the project file at the same time.
+type Lock struct {
+	file *os.File
+}
+
+// AcquireLock locks the project lock file in configDir without waiting, returning
+// ErrRunInProgress when another run already holds it. The operating system releases the
+// lock if the process exits without calling Release.
+func AcquireLock(configDir string) (*Lock, error) {
+	path := filepath.Join(configDir, LockFileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w (%s is locked)", ErrRunInProgress, path)
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release unlocks and closes the lock file. The file itself is left in place so that a
+// process waiting to open it never locks a file that has already been removed.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	defer func() { l.file = nil }()
+	if err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.file.Name(), err)
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,45 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLock(t *testing.T) {
+	t.Run("second acquire fails fast while held", func(t *testing.T) {
+		dir := t.TempDir()
+		held := make(chan *Lock)
+		go func() {
+			lock, err := AcquireLock(dir)
+			assert.NoError(t, err)
+			held <- lock
+		}()
+		lock := <-held
+		require.NotNil(t, lock)
+		defer lock.Release()
+
+		_, err := AcquireLock(dir)
+		require.ErrorIs(t, err, ErrRunInProgress)
+		assert.Contains(t, err.Error(), filepath.Join(dir, LockFileName))
+	})
+
+	t.Run("acquire succeeds after release", func(t *testing.T) {
+		dir := t.TempDir()
+		first, err := AcquireLock(dir)
+		require.NoError(t, err)
+		require.NoError(t, first.Release())
+
+		second, err := AcquireLock(dir)
+		require.NoError(t, err)
+		assert.NoError(t, second.Release())
+		assert.FileExists(t, filepath.Join(dir, LockFileName))
+	})
+
+	t.Run("release of a nil lock is a no-op", func(t *testing.T) {
+		var lock *Lock
+		assert.NoError(t, lock.Release())
+	})
+}
@@ -17,8 +17,7 @@ func LoadAll(dir string) ([]*Project, error) {
 		if d.IsDir() {
 			return nil
 		}
-		ext := filepath.Ext(path)
-		if ext != ".yaml" && ext != ".yml" {
+		if !IsProjectFile(path) {
 			return nil
 		}
 		proj, err := LoadProject(path)
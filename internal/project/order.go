@@ -0,0 +1,191 @@
+package project
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zon/ralph/internal/ai"
+)
+
+// validateDependencies checks that every dependsOn entry names another requirement
+// in the project and that the dependencies contain no cycles.
+func validateDependencies(p *Project) error {
+	index := make(map[string]int, len(p.Requirements))
+	for i, req := range p.Requirements {
+		index[req.Slug] = i
+	}
+
+	for _, req := range p.Requirements {
+		for _, dep := range req.DependsOn {
+			if dep == req.Slug {
+				return fmt.Errorf("requirement %q cannot depend on itself", req.Slug)
+			}
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("requirement %q depends on unknown requirement %q", req.Slug, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(p.Requirements))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for j, slug := range path {
+				if slug == p.Requirements[i].Slug {
+					start = j
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), p.Requirements[i].Slug)
+			return fmt.Errorf("requirement dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, p.Requirements[i].Slug)
+		for _, dep := range p.Requirements[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range p.Requirements {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderRequirements returns the project's requirements in dependency and priority order.
+// Each requirement follows the requirements it depends on; among requirements whose dependencies
+// are satisfied, lower priority values come first and ties keep their file position.
+// Dependencies that cannot be satisfied (unknown slugs or cycles) are ignored so the result
+// always contains every requirement exactly once.
+func orderRequirements(p *Project) []Requirement {
+	emitted := make(map[string]bool, len(p.Requirements))
+	ordered := make([]Requirement, 0, len(p.Requirements))
+	remaining := append([]Requirement{}, p.Requirements...)
+
+	for len(remaining) > 0 {
+		next := -1
+		for i, req := range remaining {
+			if !dependenciesEmitted(req, emitted, p) {
+				continue
+			}
+			if next == -1 || priorityRank(req) < priorityRank(remaining[next]) {
+				next = i
+			}
+		}
+		if next == -1 {
+			next = 0
+		}
+
+		emitted[remaining[next].Slug] = true
+		ordered = append(ordered, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	return ordered
+}
+
+// NextRequirement returns the failing requirement ralph is expected to work on first: the earliest
+// failing requirement in dependency and priority order whose dependencies all pass, or the earliest
+// failing one when every failing requirement is blocked. It reports false when all requirements pass.
+func (p *Project) NextRequirement() (Requirement, bool) {
+	passing := make(map[string]bool, len(p.Requirements))
+	for _, req := range p.Requirements {
+		passing[req.Slug] = req.Passing
+	}
+
+	var first *Requirement
+	for _, req := range orderRequirements(p) {
+		if req.Passing {
+			continue
+		}
+		if first == nil {
+			first = &req
+		}
+		ready := true
+		for _, dep := range req.DependsOn {
+			if isPassing, ok := passing[dep]; ok && !isPassing {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return req, true
+		}
+	}
+	if first != nil {
+		return *first, true
+	}
+	return Requirement{}, false
+}
+
+// priorityRank maps a requirement's priority to a sort key where unset priorities sort last.
+func priorityRank(req Requirement) int {
+	if req.Priority == nil {
+		return math.MaxInt
+	}
+	return *req.Priority
+}
+
+func dependenciesEmitted(req Requirement, emitted map[string]bool, p *Project) bool {
+	for _, dep := range req.DependsOn {
+		if !emitted[dep] && hasRequirement(p, dep) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasRequirement(p *Project, slug string) bool {
+	for _, req := range p.Requirements {
+		if req.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedRequirements returns the failing requirements that depend on at least one failing requirement,
+// in dependency order.
+func blockedRequirements(p *Project) []ai.BlockedRequirement {
+	passing := make(map[string]bool, len(p.Requirements))
+	for _, req := range p.Requirements {
+		passing[req.Slug] = req.Passing
+	}
+
+	var blocked []ai.BlockedRequirement
+	for _, req := range orderRequirements(p) {
+		if req.Passing {
+			continue
+		}
+		var blockedBy []string
+		for _, dep := range req.DependsOn {
+			if isPassing, ok := passing[dep]; ok && !isPassing {
+				blockedBy = append(blockedBy, dep)
+			}
+		}
+		if len(blockedBy) > 0 {
+			blocked = append(blocked, ai.BlockedRequirement{Slug: req.Slug, BlockedBy: blockedBy})
+		}
+	}
+	return blocked
+}
@@ -0,0 +1,286 @@
+package project
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/ai"
+)
+
+func requirementWithDeps(slug string, passing bool, deps ...string) Requirement {
+	req := validRequirement(slug)
+	req.Passing = passing
+	req.DependsOn = deps
+	return req
+}
+
+func requirementWithPriority(slug string, priority int, deps ...string) Requirement {
+	req := validRequirement(slug)
+	req.Priority = &priority
+	req.DependsOn = deps
+	return req
+}
+
+func requirementSlugs(reqs []Requirement) []string {
+	slugs := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		slugs = append(slugs, req.Slug)
+	}
+	return slugs
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name          string
+		requirements  []Requirement
+		errorContains string
+	}{
+		{
+			name: "valid dependencies",
+			requirements: []Requirement{
+				requirementWithDeps("schema", false),
+				requirementWithDeps("api", false, "schema"),
+			},
+		},
+		{
+			name: "unknown dependency",
+			requirements: []Requirement{
+				requirementWithDeps("api", false, "schema"),
+			},
+			errorContains: `requirement "api" depends on unknown requirement "schema"`,
+		},
+		{
+			name: "self dependency",
+			requirements: []Requirement{
+				requirementWithDeps("api", false, "api"),
+			},
+			errorContains: `requirement "api" cannot depend on itself`,
+		},
+		{
+			name: "two requirement cycle",
+			requirements: []Requirement{
+				requirementWithDeps("schema", false, "api"),
+				requirementWithDeps("api", false, "schema"),
+			},
+			errorContains: "requirement dependency cycle: schema -> api -> schema",
+		},
+		{
+			name: "longer cycle",
+			requirements: []Requirement{
+				requirementWithDeps("ui", false, "api"),
+				requirementWithDeps("api", false, "schema"),
+				requirementWithDeps("schema", false, "api"),
+			},
+			errorContains: "requirement dependency cycle: api -> schema -> api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProject(&Project{Slug: "test-project", Requirements: tt.requirements})
+			if tt.errorContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestOrderRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements []Requirement
+		want         []string
+	}{
+		{
+			name: "no dependencies keeps file order",
+			requirements: []Requirement{
+				requirementWithDeps("a", false),
+				requirementWithDeps("b", false),
+				requirementWithDeps("c", false),
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "dependency moves ahead of dependent",
+			requirements: []Requirement{
+				requirementWithDeps("api", false, "schema"),
+				requirementWithDeps("docs", false),
+				requirementWithDeps("schema", false),
+			},
+			want: []string{"docs", "schema", "api"},
+		},
+		{
+			name: "chained dependencies",
+			requirements: []Requirement{
+				requirementWithDeps("ui", false, "api"),
+				requirementWithDeps("api", false, "schema"),
+				requirementWithDeps("schema", false),
+			},
+			want: []string{"schema", "api", "ui"},
+		},
+		{
+			name: "lower priority comes first",
+			requirements: []Requirement{
+				requirementWithPriority("low", 3),
+				requirementWithPriority("high", 1),
+				requirementWithPriority("medium", 2),
+			},
+			want: []string{"high", "medium", "low"},
+		},
+		{
+			name: "unset priority sorts after explicit priorities in file order",
+			requirements: []Requirement{
+				validRequirement("unset-a"),
+				requirementWithPriority("urgent", 1),
+				validRequirement("unset-b"),
+			},
+			want: []string{"urgent", "unset-a", "unset-b"},
+		},
+		{
+			name: "equal priorities keep file order",
+			requirements: []Requirement{
+				requirementWithPriority("first", 2),
+				requirementWithPriority("second", 2),
+			},
+			want: []string{"first", "second"},
+		},
+		{
+			name: "dependencies outrank priority",
+			requirements: []Requirement{
+				requirementWithPriority("api", 1, "schema"),
+				requirementWithPriority("schema", 5),
+				requirementWithPriority("docs", 2),
+			},
+			want: []string{"docs", "schema", "api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered := orderRequirements(&Project{Slug: "test-project", Requirements: tt.requirements})
+			assert.Equal(t, tt.want, requirementSlugs(ordered))
+		})
+	}
+}
+
+func TestBlockedRequirements(t *testing.T) {
+	proj := &Project{
+		Slug: "test-project",
+		Requirements: []Requirement{
+			requirementWithDeps("ui", false, "api", "auth"),
+			requirementWithDeps("api", false, "schema"),
+			requirementWithDeps("schema", false),
+			requirementWithDeps("auth", true),
+			requirementWithDeps("docs", true, "api"),
+		},
+	}
+
+	assert.Equal(t, []ai.BlockedRequirement{
+		{Slug: "api", BlockedBy: []string{"schema"}},
+		{Slug: "ui", BlockedBy: []string{"api"}},
+	}, blockedRequirements(proj))
+}
+
+func TestNextRequirement(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements []Requirement
+		want         string
+		wantOK       bool
+	}{
+		{
+			name: "first failing requirement whose dependencies pass",
+			requirements: []Requirement{
+				requirementWithDeps("api", false, "schema"),
+				requirementWithDeps("done", true),
+				requirementWithDeps("schema", false),
+			},
+			want:   "schema",
+			wantOK: true,
+		},
+		{
+			name: "lower priority value comes first",
+			requirements: []Requirement{
+				requirementWithPriority("later", 2),
+				requirementWithPriority("sooner", 1),
+			},
+			want:   "sooner",
+			wantOK: true,
+		},
+		{
+			name: "all passing",
+			requirements: []Requirement{
+				requirementWithDeps("done", true),
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj := &Project{Slug: "test-project", Requirements: tt.requirements}
+			next, ok := proj.NextRequirement()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, next.Slug)
+		})
+	}
+}
+
+func TestPickPromptListsRequirementsInDependencyOrder(t *testing.T) {
+	proj := &Project{
+		Slug: "test-project",
+		Requirements: []Requirement{
+			requirementWithDeps("api", false, "schema"),
+			requirementWithDeps("schema", false),
+		},
+	}
+
+	content, err := marshalProjectToString(proj)
+	require.NoError(t, err)
+
+	prompt, err := ai.BuildPickPrompt(ai.PickPromptData{
+		ProjectContent: content,
+		PickedReqPath:  "/path/to/picked-requirement.yaml",
+		Blocked:        blockedRequirements(proj),
+	})
+	require.NoError(t, err)
+
+	assert.Less(t, strings.Index(prompt, "slug: schema"), strings.Index(prompt, "slug: api"))
+	assert.Contains(t, prompt, "**Blocked Requirements:**")
+	assert.Contains(t, prompt, "- `api` is blocked by `schema` (not yet passing)")
+	assert.Equal(t, []string{"api", "schema"}, requirementSlugs(proj.Requirements), "project requirements must not be reordered in place")
+}
+
+func TestPickPromptListsFailingRequirementsByPriority(t *testing.T) {
+	proj := &Project{
+		Slug: "test-project",
+		Requirements: []Requirement{
+			validRequirement("nice-to-have"),
+			requirementWithPriority("important", 2),
+			requirementWithPriority("critical", 1),
+		},
+	}
+
+	content, err := marshalProjectToString(proj)
+	require.NoError(t, err)
+
+	prompt, err := ai.BuildPickPrompt(ai.PickPromptData{
+		ProjectContent: content,
+		PickedReqPath:  "/path/to/picked-requirement.yaml",
+	})
+	require.NoError(t, err)
+
+	section := prompt[strings.Index(prompt, "**Project Requirements:**"):]
+	critical := strings.Index(section, "slug: critical")
+	important := strings.Index(section, "slug: important")
+	niceToHave := strings.Index(section, "slug: nice-to-have")
+	require.NotEqual(t, -1, critical)
+	assert.Less(t, critical, important)
+	assert.Less(t, important, niceToHave)
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -10,68 +11,129 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/zon/ralph/internal/agent"
 	"github.com/zon/ralph/internal/ai"
 	"github.com/zon/ralph/internal/architecture"
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/git"
-	"github.com/zon/ralph/internal/opencode"
 	"github.com/zon/ralph/internal/services"
 )
 
 // ErrExtraIterationsReached is returned when the iteration limit is exhausted but requirements are still failing
 var ErrExtraIterationsReached = errors.New("iteration limit reached")
 
+// ErrGuardrailExceeded ends an iteration whose changes broke a guardrail with the abort action
+var ErrGuardrailExceeded = errors.New("iteration exceeded a guardrail")
 
-// Project represents a project YAML file with requirements
+// StdinPath is the project file path that reads the project from standard input
+const StdinPath = "-"
+
+
+// Project represents a project YAML or JSON file with requirements
 type Project struct {
-	Slug          string        `yaml:"slug"`
-	Title         string        `yaml:"title,omitempty"`
-	Feature       string        `yaml:"feature,omitempty"`
-	Requirements  []Requirement `yaml:"requirements"`
-	Path          string        `yaml:"-"`
-	BaseBranch    string        `yaml:"-"`
+	Slug          string        `yaml:"slug" json:"slug"`
+	Title         string        `yaml:"title,omitempty" json:"title,omitempty"`
+	Feature       string        `yaml:"feature,omitempty" json:"feature,omitempty"`
+	Requirements  []Requirement `yaml:"requirements" json:"requirements"`
+	Path          string        `yaml:"-" json:"-"`
+	BaseBranch    string        `yaml:"-" json:"-"`
 }
 
 // Requirement represents a single requirement in a project
 type Requirement struct {
-	Slug        string      `yaml:"slug"`
-	Description string      `yaml:"description,omitempty"`
-	Items       []string    `yaml:"items,omitempty"`
-	Scenarios   []Scenario  `yaml:"scenarios,omitempty"`
-	Code        []CodeEntry `yaml:"code,omitempty"`
-	Tests       []CodeEntry `yaml:"tests,omitempty"`
-	Passing     bool        `yaml:"passing"`
+	Slug        string      `yaml:"slug" json:"slug"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	DependsOn   []string    `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"` // Slugs of requirements that must pass first
+	Priority    *int        `yaml:"priority,omitempty" json:"priority,omitempty"`   // Lower values are worked on first; unset sorts after any explicit priority
+	Size        string      `yaml:"size,omitempty" json:"size,omitempty"`           // Estimated effort hint: small, medium, or large
+	Notes       string      `yaml:"notes,omitempty" json:"notes,omitempty"`         // Freeform record of how completion was verified
+	Items       []string    `yaml:"items,omitempty" json:"items,omitempty"`
+	Scenarios   []Scenario  `yaml:"scenarios,omitempty" json:"scenarios,omitempty"`
+	Code        []CodeEntry `yaml:"code,omitempty" json:"code,omitempty"`
+	Tests       []CodeEntry `yaml:"tests,omitempty" json:"tests,omitempty"`
+	Passing     bool        `yaml:"passing" json:"passing"`
+}
+
+// Requirement size hints
+const (
+	SizeSmall  = "small"
+	SizeMedium = "medium"
+	SizeLarge  = "large"
+)
+
+var validSizes = map[string]bool{
+	SizeSmall:  true,
+	SizeMedium: true,
+	SizeLarge:  true,
 }
 
 // Scenario is a GWT scenario copied from the spec document.
 type Scenario struct {
-	Title string   `yaml:"title"`
-	Items []string `yaml:"items"`
+	Title string   `yaml:"title" json:"title"`
+	Items []string `yaml:"items" json:"items"`
 }
 
 // CodeEntry describes a function or test the project should implement.
 // Used for both `code` (production code from orchestration.md) and `tests` (specific
 // tests the project must write).
 type CodeEntry struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
-	Module      string `yaml:"module"`
-	Body        string `yaml:"body"`
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+	Module      string `yaml:"module" json:"module"`
+	Body        string `yaml:"body" json:"body"`
+}
+
+// IsProjectFile reports whether path has a project file extension: .yaml, .yml or .json
+func IsProjectFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// isJSONProject reports whether the project at path is read and written as JSON instead of YAML
+func isJSONProject(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
 }
 
-// LoadProject loads and validates a project YAML file
+// LoadProject loads and validates a project file, parsing it as JSON when it ends in .json and as YAML otherwise
 func LoadProject(path string) (*Project, error) {
+	if path == StdinPath {
+		return LoadProjectFromReader(os.Stdin, path)
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read project file: %w", err)
 	}
 
+	return parseProject(data, path)
+}
+
+// LoadProjectFromReader loads and validates a project read from r, recording path as its location
+func LoadProjectFromReader(r io.Reader, path string) (*Project, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+
+	return parseProject(data, path)
+}
+
+func parseProject(data []byte, path string) (*Project, error) {
 	var proj Project
-	if err := yaml.Unmarshal(data, &proj); err != nil {
+	if isJSONProject(path) {
+		if err := config.DecodeJSON(data, &proj); err != nil {
+			return nil, fmt.Errorf("failed to parse project JSON: %w", err)
+		}
+	} else if err := config.DecodeYAML(data, &proj); err != nil {
 		return nil, fmt.Errorf("failed to parse project YAML: %w", err)
 	}
 
+	assignMissingSlugs(&proj)
+
 	if err := ValidateProject(&proj); err != nil {
 		return nil, err
 	}
@@ -81,6 +143,58 @@ func LoadProject(path string) (*Project, error) {
 	return &proj, nil
 }
 
+// maxGeneratedSlugWords caps how many words of the description a generated slug keeps
+const maxGeneratedSlugWords = 6
+
+// assignMissingSlugs derives a slug for each requirement that omits one from its description,
+// falling back to its first item. The slug only depends on the file contents, so it is stable
+// across loads; it is written to the file only when the project is saved.
+// Collisions with other slugs get a numeric suffix (-2, -3, ...).
+func assignMissingSlugs(p *Project) {
+	taken := make(map[string]struct{}, len(p.Requirements))
+	for _, req := range p.Requirements {
+		if req.Slug != "" {
+			taken[req.Slug] = struct{}{}
+		}
+	}
+
+	for i := range p.Requirements {
+		req := &p.Requirements[i]
+		if req.Slug != "" {
+			continue
+		}
+		base := generatedSlug(*req)
+		if base == "" {
+			continue
+		}
+		slug := base
+		for n := 2; ; n++ {
+			if _, dup := taken[slug]; !dup {
+				break
+			}
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[slug] = struct{}{}
+		req.Slug = slug
+	}
+}
+
+func generatedSlug(req Requirement) string {
+	source := req.Description
+	if strings.TrimSpace(source) == "" && len(req.Items) > 0 {
+		source = req.Items[0]
+	}
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+
+	slug := git.SanitizeBranchName(source)
+	if words := strings.Split(slug, "-"); len(words) > maxGeneratedSlugWords {
+		slug = strings.Join(words[:maxGeneratedSlugWords], "-")
+	}
+	return slug
+}
+
 // ValidateProject validates a project structure
 func ValidateProject(p *Project) error {
 	if p.Slug == "" {
@@ -101,6 +215,13 @@ func ValidateProject(p *Project) error {
 		}
 		seen[req.Slug] = struct{}{}
 
+		if req.Priority != nil && *req.Priority < 1 {
+			return fmt.Errorf("requirement %q priority must be a positive integer, got %d; omit priority to leave it unset", req.Slug, *req.Priority)
+		}
+		if req.Size != "" && !validSizes[req.Size] {
+			return fmt.Errorf("requirement %q has invalid size %q; valid sizes are: small, medium, large", req.Slug, req.Size)
+		}
+
 		if len(req.Items) == 0 && len(req.Scenarios) == 0 && len(req.Code) == 0 && len(req.Tests) == 0 {
 			return fmt.Errorf("requirement %q must define at least one of items, scenarios, code, or tests", req.Slug)
 		}
@@ -117,7 +238,7 @@ func ValidateProject(p *Project) error {
 		}
 	}
 
-	return nil
+	return validateDependencies(p)
 }
 
 func validateCodeEntry(reqSlug, field string, idx int, c CodeEntry) error {
@@ -140,14 +261,18 @@ func validateCodeEntry(reqSlug, field string, idx int, c CodeEntry) error {
 	return nil
 }
 
-// SaveProject saves a project to a YAML file
+// SaveProject saves a project to a YAML file, or a JSON file when path ends in .json.
+// When the file already exists, only the requirement fields that changed are rewritten
+// so hand-written comments and key ordering are preserved.
+// The file is replaced atomically, so an interrupted save never leaves a truncated project,
+// and is backed up first when backups are enabled.
 func SaveProject(path string, p *Project) error {
-	data, err := yaml.Marshal(p)
+	data, err := renderProject(path, p)
 	if err != nil {
-		return fmt.Errorf("failed to marshal project: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := replaceProjectFile(path, data); err != nil {
 		return fmt.Errorf("failed to write project file: %w", err)
 	}
 
@@ -173,6 +298,76 @@ func CheckCompletion(p *Project) (bool, int, int) {
 	return allComplete, passingCount, failingCount
 }
 
+// Regressions returns the slugs of requirements that were passing in before and are failing in after.
+func Regressions(before, after *Project) []string {
+	passing := make(map[string]bool, len(before.Requirements))
+	for _, req := range before.Requirements {
+		passing[req.Slug] = req.Passing
+	}
+
+	var regressed []string
+	for _, req := range after.Requirements {
+		if passing[req.Slug] && !req.Passing {
+			regressed = append(regressed, req.Slug)
+		}
+	}
+	return regressed
+}
+
+// RegressionNote returns the system note for the next iteration about a requirement that
+// stopped passing during the last one.
+func RegressionNote(slug string) string {
+	return fmt.Sprintf("WARNING: regression - requirement %s was passing before the last iteration and is now failing. Fix it before working on other requirements.", slug)
+}
+
+// StuckNote returns the system note for the next iteration about a requirement that stayed
+// failing for attempts consecutive iterations.
+func StuckNote(slug string, attempts int) string {
+	return fmt.Sprintf("STUCK: requirement %s is still failing after %d consecutive iterations focused on it; ralph moved on to other requirements.", slug, attempts)
+}
+
+// GuardrailNote returns the system note for the next iteration about a requirement whose
+// iteration broke a guardrail, where breach describes the limit, such as "changed 40 files,
+// more than the guardrail of 20".
+func GuardrailNote(slug, breach string, discarded bool) string {
+	if discarded {
+		return fmt.Sprintf("GUARDRAIL: the last iteration on requirement %s %s, so ralph discarded its changes (they are kept in git stash). Make a smaller, focused change.", slug, breach)
+	}
+	return fmt.Sprintf("GUARDRAIL: the last iteration on requirement %s %s. Keep further changes small and focused.", slug, breach)
+}
+
+// GuardrailStashMessage names the stash entry that holds the changes of a discarded iteration.
+func GuardrailStashMessage(iteration int, slug, breach string) string {
+	return fmt.Sprintf("ralph: iteration %d on %s %s", iteration, slug, breach)
+}
+
+// GuardrailError wraps ErrGuardrailExceeded with the requirement and the limit it broke.
+func GuardrailError(slug, breach string) error {
+	return fmt.Errorf("%w: %s %s", ErrGuardrailExceeded, slug, breach)
+}
+
+// IsRequirementPassing reports whether the requirement identified by slug is passing.
+func IsRequirementPassing(p *Project, slug string) bool {
+	for _, req := range p.Requirements {
+		if req.Slug == slug {
+			return req.Passing
+		}
+	}
+	return false
+}
+
+// WithoutRequirements returns a copy of the project that omits the requirements whose slugs are in skip.
+func WithoutRequirements(p *Project, skip map[string]bool) *Project {
+	filtered := *p
+	filtered.Requirements = nil
+	for _, req := range p.Requirements {
+		if !skip[req.Slug] {
+			filtered.Requirements = append(filtered.Requirements, req)
+		}
+	}
+	return &filtered
+}
+
 // UpdateRequirementStatus updates the passing status of the requirement
 // identified by its slug.
 func UpdateRequirementStatus(p *Project, reqSlug string, passing bool) error {
@@ -193,7 +388,7 @@ type IterationSetup struct {
 	ServiceMgr    *services.Manager
 }
 
-func PrepareIteration(ctx *context.Context, oc opencode.OCClient, cleanupRegistrar func(func())) (*IterationSetup, error) {
+func PrepareIteration(ctx *context.Context, backend agent.Backend, cleanupRegistrar func(func())) (*IterationSetup, error) {
 	absProjectFile, err := filepath.Abs(ctx.ProjectFile())
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve project file path: %w", err)
@@ -214,6 +409,9 @@ func PrepareIteration(ctx *context.Context, oc opencode.OCClient, cleanupRegistr
 	if proj.Title != "" && ctx.IsVerbose() {
 		ctx.Output().Debugf("Title: %s", proj.Title)
 	}
+	if ctx.Actor() != "" {
+		ctx.Output().Debugf("Triggered by: %s", ctx.Actor())
+	}
 
 	allComplete, passingCount, failingCount := CheckCompletion(proj)
 	ctx.Output().Debugf("Requirements: %d passing, %d failing (complete: %v)", passingCount, failingCount, allComplete)
@@ -223,7 +421,7 @@ func PrepareIteration(ctx *context.Context, oc opencode.OCClient, cleanupRegistr
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	svcMgr, err := handleServiceStartup(ctx, oc, cleanupRegistrar, ralphConfig)
+	svcMgr, err := handleServiceStartup(ctx, backend, cleanupRegistrar, ralphConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -243,8 +441,8 @@ func PrepareIteration(ctx *context.Context, oc opencode.OCClient, cleanupRegistr
 	}, nil
 }
 
-func ExecuteDevelopmentIteration(ctx *context.Context, oc opencode.OCClient, cleanupRegistrar func(func())) error {
-	setup, err := PrepareIteration(ctx, oc, cleanupRegistrar)
+func ExecuteDevelopmentIteration(ctx *context.Context, backend agent.Backend, cleanupRegistrar func(func())) error {
+	setup, err := PrepareIteration(ctx, backend, cleanupRegistrar)
 	if err != nil {
 		return err
 	}
@@ -253,15 +451,15 @@ func ExecuteDevelopmentIteration(ctx *context.Context, oc opencode.OCClient, cle
 			setup.ServiceMgr.Stop()
 		}
 	}()
-	req, err := PickRequirement(ctx, oc, setup)
+	req, err := PickRequirement(ctx, backend, setup)
 	if err != nil {
 		return err
 	}
-	return DevelopRequirement(ctx, oc, setup, req)
+	return DevelopRequirement(ctx, backend, setup, req)
 }
 
 // PickRequirement runs the picker agent and returns the selected requirement's YAML content.
-func PickRequirement(ctx *context.Context, oc opencode.OCClient, setup *IterationSetup) (string, error) {
+func PickRequirement(ctx *context.Context, backend agent.Backend, setup *IterationSetup) (string, error) {
 	ctx.Output().Debugf("Loading project file: %s", setup.Project.Path)
 
 	projectContent, err := marshalProjectToString(setup.Project)
@@ -274,6 +472,7 @@ func PickRequirement(ctx *context.Context, oc opencode.OCClient, setup *Iteratio
 		CommitLog:      setup.CommitLog,
 		ProjectContent: projectContent,
 		PickedReqPath:  setup.PickedReqPath,
+		Blocked:        blockedRequirements(setup.Project),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to build pick prompt: %w", err)
@@ -281,7 +480,7 @@ func PickRequirement(ctx *context.Context, oc opencode.OCClient, setup *Iteratio
 	ctx.Output().Debug("Pick prompt generated")
 
 	ctx.Output().Debug("Running picker agent...")
-	if err := ai.RunAgent(ctx, oc, pickPrompt); err != nil {
+	if err := ai.RunAgent(ctx, backend, pickPrompt); err != nil {
 		if writeBlockedMD(setup.Project.Path, err) == nil {
 			ctx.Output().Debugf("Wrote blocked.md due to picker agent failure")
 		} else {
@@ -307,12 +506,13 @@ func PickRequirement(ctx *context.Context, oc opencode.OCClient, setup *Iteratio
 }
 
 // DevelopRequirement runs the developer agent for the given requirement content.
-func DevelopRequirement(ctx *context.Context, oc opencode.OCClient, setup *IterationSetup, req string) error {
+func DevelopRequirement(ctx *context.Context, backend agent.Backend, setup *IterationSetup, req string) error {
 	projectContent, err := marshalProjectToString(setup.Project)
 	if err != nil {
 		return fmt.Errorf("failed to serialize project: %w", err)
 	}
 
+	picked := pickedRequirement(setup.Project, req)
 	devPrompt, err := ai.BuildDevelopPrompt(ai.DevelopPromptData{
 		Notes:               ctx.Notes(),
 		CommitLog:           setup.CommitLog,
@@ -321,6 +521,8 @@ func DevelopRequirement(ctx *context.Context, oc opencode.OCClient, setup *Itera
 		ProjectFilePath:     setup.Project.Path,
 		Services:            setup.Config.Services,
 		Instructions:        setup.Config.Instructions,
+		Size:                picked.Size,
+		AcceptanceNotes:     picked.Notes,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to build prompt: %w", err)
@@ -328,7 +530,7 @@ func DevelopRequirement(ctx *context.Context, oc opencode.OCClient, setup *Itera
 	ctx.Output().Debug("Development prompt generated")
 
 	ctx.Output().Debug("Running AI agent...")
-	if err := ai.RunAgent(ctx, oc, devPrompt); err != nil {
+	if err := ai.RunAgent(ctx, backend, devPrompt); err != nil {
 		if writeBlockedMD(setup.Project.Path, err) == nil {
 			ctx.Output().Debugf("Wrote blocked.md due to agent failure")
 		} else {
@@ -347,6 +549,30 @@ func DevelopRequirement(ctx *context.Context, oc opencode.OCClient, setup *Itera
 	return nil
 }
 
+// PickedSlug returns the slug of the requirement the picker selected, or "" when it cannot be determined.
+func PickedSlug(proj *Project, picked string) string {
+	return pickedRequirement(proj, picked).Slug
+}
+
+// pickedRequirement resolves the picker's YAML output to the matching requirement in the project.
+// If the slug cannot be matched, the requirement as written by the picker is returned.
+func pickedRequirement(proj *Project, picked string) Requirement {
+	var req Requirement
+	if err := yaml.Unmarshal([]byte(picked), &req); err != nil {
+		var list []Requirement
+		if yaml.Unmarshal([]byte(picked), &list) != nil || len(list) == 0 {
+			return Requirement{}
+		}
+		req = list[0]
+	}
+	for _, r := range proj.Requirements {
+		if r.Slug == req.Slug {
+			return r
+		}
+	}
+	return req
+}
+
 // checkBlockedFile checks if blocked.md exists and returns an error if it does
 func checkBlockedFile(absProjectFile string) error {
 	blockedPath := filepath.Join(filepath.Dir(absProjectFile), "blocked.md")
@@ -369,7 +595,7 @@ func writeBlockedMD(absProjectFile string, err error) error {
 // handleServiceStartup starts services if not disabled, and handles failure recovery.
 // Returns the service manager if services were started successfully (caller must stop it),
 // or nil if services were not started or a failure was handled.
-func handleServiceStartup(ctx *context.Context, oc opencode.OCClient, cleanupRegistrar func(func()), ralphConfig *config.RalphConfig) (*services.Manager, error) {
+func handleServiceStartup(ctx *context.Context, backend agent.Backend, cleanupRegistrar func(func()), ralphConfig *config.RalphConfig) (*services.Manager, error) {
 	svcMgr := services.NewManager(ctx.Output())
 
 	// Start services if not disabled
@@ -382,7 +608,7 @@ func handleServiceStartup(ctx *context.Context, oc opencode.OCClient, cleanupReg
 				return nil, fmt.Errorf("failed to build fix service prompt: %w", buildErr)
 			}
 
-			if agentErr := ai.RunAgent(ctx, oc, fixPrompt); agentErr != nil {
+			if agentErr := ai.RunAgent(ctx, backend, fixPrompt); agentErr != nil {
 				return nil, fmt.Errorf("agent execution failed while fixing service: %w", agentErr)
 			}
 			return nil, nil
@@ -415,7 +641,7 @@ func performPostAgentCleanup(ctx *context.Context, absProjectFile string, servic
 	if data, err := os.ReadFile(absProjectFile); err == nil {
 		normalized := []byte(strings.TrimRight(string(data), "\n") + "\n")
 		if len(normalized) != len(data) {
-			if writeErr := os.WriteFile(absProjectFile, normalized, 0644); writeErr != nil {
+			if writeErr := replaceProjectFile(absProjectFile, normalized); writeErr != nil {
 				ctx.Output().Debugf("Failed to normalize project file: %v", writeErr)
 			}
 		}
@@ -468,8 +694,11 @@ func migrateFeatureArchitecture(ctx *context.Context, feature string) {
 	}
 }
 
+// marshalProjectToString serializes the project for a prompt, listing requirements in dependency and priority order.
 func marshalProjectToString(proj *Project) (string, error) {
-	data, err := yaml.Marshal(proj)
+	ordered := *proj
+	ordered.Requirements = orderRequirements(proj)
+	data, err := yaml.Marshal(&ordered)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal project: %w", err)
 	}
@@ -509,8 +738,7 @@ func FindCompleteProjects(dir string) ([]string, error) {
 		if d.IsDir() {
 			return nil
 		}
-		ext := filepath.Ext(path)
-		if ext == ".yaml" || ext == ".yml" {
+		if IsProjectFile(path) {
 			allFiles = append(allFiles, path)
 		}
 		return nil
@@ -1,8 +1,10 @@
 package project
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,6 +70,38 @@ func TestValidateProject(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative priority",
+			project: &Project{
+				Slug:         "test-project",
+				Requirements: []Requirement{requirementWithPriority("req-1", -1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero priority",
+			project: &Project{
+				Slug:         "test-project",
+				Requirements: []Requirement{requirementWithPriority("req-1", 0)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid size",
+			project: &Project{
+				Slug:         "test-project",
+				Requirements: []Requirement{{Slug: "req-1", Items: []string{"Item 1"}, Size: "huge"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid size",
+			project: &Project{
+				Slug:         "test-project",
+				Requirements: []Requirement{{Slug: "req-1", Items: []string{"Item 1"}, Size: SizeLarge}},
+			},
+			wantErr: false,
+		},
 		{
 			name: "missing slug",
 			project: &Project{
@@ -217,6 +251,49 @@ func TestCheckCompletion(t *testing.T) {
 	}
 }
 
+func TestRegressions(t *testing.T) {
+	before := &Project{
+		Slug: "test",
+		Requirements: []Requirement{
+			{Slug: "a", Passing: true},
+			{Slug: "b", Passing: false},
+			{Slug: "c", Passing: true},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		after []Requirement
+		want  []string
+	}{
+		{
+			name:  "no change",
+			after: []Requirement{{Slug: "a", Passing: true}, {Slug: "b", Passing: false}, {Slug: "c", Passing: true}},
+		},
+		{
+			name:  "failing requirement now passes",
+			after: []Requirement{{Slug: "a", Passing: true}, {Slug: "b", Passing: true}, {Slug: "c", Passing: true}},
+		},
+		{
+			name:  "passing requirement now fails",
+			after: []Requirement{{Slug: "a", Passing: false}, {Slug: "b", Passing: true}, {Slug: "c", Passing: true}},
+			want:  []string{"a"},
+		},
+		{
+			name:  "new requirement is not a regression",
+			after: []Requirement{{Slug: "a", Passing: true}, {Slug: "b", Passing: false}, {Slug: "c", Passing: false}, {Slug: "d", Passing: false}},
+			want:  []string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := &Project{Slug: "test", Requirements: tt.after}
+			assert.Equal(t, tt.want, Regressions(before, after))
+		})
+	}
+}
+
 func TestUpdateRequirementStatus(t *testing.T) {
 	proj := &Project{
 		Slug: "test",
@@ -262,6 +339,193 @@ requirements:
 	assert.Equal(t, "first-requirement", proj.Requirements[0].Slug)
 }
 
+func TestLoadProject_GeneratesMissingSlugs(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantSlugs []string
+		wantErr   string
+	}{
+		{
+			name: "derived from description",
+			content: `slug: test-project
+requirements:
+  - description: Add CSV export to the reports page
+    items:
+      - Item A
+`,
+			wantSlugs: []string{"add-csv-export-to-the-reports"},
+		},
+		{
+			name: "falls back to first item",
+			content: `slug: test-project
+requirements:
+  - items:
+      - Users can log in
+`,
+			wantSlugs: []string{"users-can-log-in"},
+		},
+		{
+			name: "collision with explicit slug",
+			content: `slug: test-project
+requirements:
+  - description: Login form
+    items:
+      - Item A
+  - slug: login-form
+    description: Explicit
+    items:
+      - Item B
+  - description: Login form
+    items:
+      - Item C
+`,
+			wantSlugs: []string{"login-form-2", "login-form", "login-form-3"},
+		},
+		{
+			name: "nothing to derive from",
+			content: `slug: test-project
+requirements:
+  - scenarios:
+      - title: Happy path
+        items:
+          - GIVEN
+`,
+			wantErr: "requirement[0] slug is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := filepath.Join(t.TempDir(), "test-project.yaml")
+			require.NoError(t, os.WriteFile(projectPath, []byte(tt.content), 0644))
+
+			proj, err := LoadProject(projectPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var slugs []string
+			for _, req := range proj.Requirements {
+				slugs = append(slugs, req.Slug)
+			}
+			assert.Equal(t, tt.wantSlugs, slugs)
+
+			again, err := LoadProject(projectPath)
+			require.NoError(t, err)
+			assert.Equal(t, proj.Requirements, again.Requirements, "generated slugs should be stable across loads")
+
+			data, err := os.ReadFile(projectPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(data), "loading should not rewrite the file")
+		})
+	}
+}
+
+func TestSaveProjectPersistsGeneratedSlugs(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "test-project.yaml")
+	content := `slug: test-project
+requirements:
+  # Keep me
+  - description: Login form
+    items:
+      - Item A
+    passing: false
+`
+	require.NoError(t, os.WriteFile(projectPath, []byte(content), 0644))
+
+	proj, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	require.NoError(t, UpdateRequirementStatus(proj, "login-form", true))
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "slug: login-form")
+	assert.Contains(t, string(data), "# Keep me")
+
+	reloaded, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, "login-form", reloaded.Requirements[0].Slug)
+	assert.True(t, reloaded.Requirements[0].Passing)
+}
+
+func TestLoadProjectFromReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid project",
+			content: "slug: piped\nrequirements:\n  - slug: first\n    items:\n      - Item A\n    passing: false\n",
+		},
+		{
+			name:    "invalid YAML",
+			content: "slug: [unclosed\n",
+			wantErr: "failed to parse project YAML",
+		},
+		{
+			name:    "missing requirements",
+			content: "slug: piped\n",
+			wantErr: "at least one requirement",
+		},
+		{
+			name:    "zero priority",
+			content: "slug: piped\nrequirements:\n  - slug: first\n    priority: 0\n    items:\n      - Item A\n",
+			wantErr: `requirement "first" priority must be a positive integer, got 0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj, err := LoadProjectFromReader(strings.NewReader(tt.content), StdinPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "piped", proj.Slug)
+			assert.Equal(t, StdinPath, proj.Path)
+			require.Len(t, proj.Requirements, 1)
+		})
+	}
+}
+
+func TestLoadProject_UnknownFields(t *testing.T) {
+	projectContent := `slug: test-project
+requirements:
+  - slug: first-requirement
+    itmes:
+      - Item A
+    items:
+      - Item A
+    passing: false
+`
+
+	t.Run("unknown key is rejected", func(t *testing.T) {
+		projectPath := filepath.Join(t.TempDir(), "test-project.yaml")
+		require.NoError(t, os.WriteFile(projectPath, []byte(projectContent), 0644))
+
+		_, err := LoadProject(projectPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field itmes not found")
+	})
+
+	t.Run("unknown key is ignored with opt-out", func(t *testing.T) {
+		t.Setenv("RALPH_ALLOW_UNKNOWN_FIELDS", "true")
+		projectPath := filepath.Join(t.TempDir(), "test-project.yaml")
+		require.NoError(t, os.WriteFile(projectPath, []byte(projectContent), 0644))
+
+		proj, err := LoadProject(projectPath)
+		require.NoError(t, err)
+		assert.Equal(t, "test-project", proj.Slug)
+	})
+}
+
 func TestLoadProjectWithCodeAndTests(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -311,6 +575,124 @@ requirements:
 	assert.Equal(t, "TestDoThing", req.Tests[0].Name)
 }
 
+func TestLoadProject_JSONMatchesYAML(t *testing.T) {
+	yamlContent := `slug: both-formats
+title: Same project in two formats
+feature: specs/features/foo
+requirements:
+  - slug: first
+    description: First requirement
+    dependsOn: [second]
+    priority: 1
+    size: small
+    items:
+      - Item A
+    scenarios:
+      - title: Happy path
+        items:
+          - GIVEN a user
+    code:
+      - name: DoThing
+        description: performs the thing
+        module: internal/thing
+        body: func DoThing() error
+    passing: false
+  - slug: second
+    notes: verified by hand
+    items:
+      - Item B
+    passing: true
+`
+	jsonContent := `{
+  "slug": "both-formats",
+  "title": "Same project in two formats",
+  "feature": "specs/features/foo",
+  "requirements": [
+    {
+      "slug": "first",
+      "description": "First requirement",
+      "dependsOn": ["second"],
+      "priority": 1,
+      "size": "small",
+      "items": ["Item A"],
+      "scenarios": [{"title": "Happy path", "items": ["GIVEN a user"]}],
+      "code": [{"name": "DoThing", "description": "performs the thing", "module": "internal/thing", "body": "func DoThing() error"}],
+      "passing": false
+    },
+    {"slug": "second", "notes": "verified by hand", "items": ["Item B"], "passing": true}
+  ]
+}
+`
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "project.yaml")
+	jsonPath := filepath.Join(dir, "project.json")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(yamlContent), 0644))
+	require.NoError(t, os.WriteFile(jsonPath, []byte(jsonContent), 0644))
+
+	fromYAML, err := LoadProject(yamlPath)
+	require.NoError(t, err)
+	fromJSON, err := LoadProject(jsonPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonPath, fromJSON.Path)
+	fromJSON.Path = fromYAML.Path
+	assert.Equal(t, fromYAML, fromJSON)
+}
+
+func TestLoadProject_JSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown key", content: `{"slug": "p", "requirements": [{"slug": "r", "itmes": ["a"]}]}`, wantErr: `unknown field "itmes"`},
+		{name: "invalid syntax", content: `{"slug": "p",`, wantErr: "failed to parse project JSON"},
+		{name: "trailing value", content: `{"slug": "p", "requirements": [{"slug": "r", "items": ["a"]}]} {}`, wantErr: "unexpected data after the JSON value"},
+		{name: "ignored path", content: `{"slug": "p", "path": "/elsewhere", "requirements": [{"slug": "r", "items": ["a"]}]}`, wantErr: `unknown field "path"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "project.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := LoadProject(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSaveProject_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"slug": "p", "requirements": [{"slug": "r", "items": ["a"], "passing": false}]}`), 0644))
+
+	proj, err := LoadProject(path)
+	require.NoError(t, err)
+	proj.Requirements[0].Passing = true
+	require.NoError(t, SaveProject(path, proj))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data), "saved project should stay JSON: %s", data)
+	loaded, err := LoadProject(path)
+	require.NoError(t, err)
+	assert.Equal(t, proj, loaded)
+}
+
+func TestIsProjectFile(t *testing.T) {
+	for path, want := range map[string]bool{
+		"projects/a.yaml": true,
+		"projects/a.yml":  true,
+		"projects/a.json": true,
+		"projects/a.JSON": true,
+		"projects/a.md":   false,
+		"projects/a":      false,
+	} {
+		assert.Equal(t, want, IsProjectFile(path), path)
+	}
+}
+
 func TestSaveProjectRoundTrip(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -334,6 +716,36 @@ func TestSaveProjectRoundTrip(t *testing.T) {
 	assert.Equal(t, "req-1", loaded.Requirements[0].Slug)
 }
 
+func TestSaveProjectPreservesNotes(t *testing.T) {
+	projectPath := filepath.Join(t.TempDir(), "notes.yaml")
+	proj := &Project{
+		Slug: "notes",
+		Requirements: []Requirement{
+			{Slug: "req-1", Items: []string{"a"}, Passing: true, Notes: "verified via integration test TestExport\nand manual curl"},
+			{Slug: "req-2", Items: []string{"b"}},
+		},
+	}
+
+	require.NoError(t, SaveProject(projectPath, proj))
+
+	loaded, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	require.Len(t, loaded.Requirements, 2)
+	assert.Equal(t, "verified via integration test TestExport\nand manual curl", loaded.Requirements[0].Notes)
+	assert.Empty(t, loaded.Requirements[1].Notes)
+
+	require.NoError(t, UpdateRequirementStatus(loaded, "req-2", true))
+	require.NoError(t, SaveProject(projectPath, loaded))
+
+	reloaded, err := LoadProject(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, loaded.Requirements[0].Notes, reloaded.Requirements[0].Notes)
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "notes:"), "empty notes must be omitted")
+}
+
 func TestLoadProject_FileNotFound(t *testing.T) {
 	_, err := LoadProject("/nonexistent/path/project.yaml")
 	require.Error(t, err)
@@ -362,3 +774,51 @@ func TestCanonicalYAMLOmitsEmptyFields(t *testing.T) {
 	assert.NotContains(t, output, "code:")
 	assert.NotContains(t, output, "tests:")
 }
+
+func TestPickedRequirement(t *testing.T) {
+	proj := &Project{
+		Slug: "test-project",
+		Requirements: []Requirement{
+			{Slug: "small-one", Items: []string{"a"}, Size: SizeSmall},
+			{Slug: "big-one", Items: []string{"b"}, Size: SizeLarge},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		picked   string
+		wantSlug string
+		wantSize string
+	}{
+		{
+			name:     "mapping resolves to project requirement",
+			picked:   "slug: big-one\ndescription: Big\n",
+			wantSlug: "big-one",
+			wantSize: SizeLarge,
+		},
+		{
+			name:     "list form resolves to project requirement",
+			picked:   "- slug: small-one\n  passing: false\n",
+			wantSlug: "small-one",
+			wantSize: SizeSmall,
+		},
+		{
+			name:     "unknown slug returns picked requirement",
+			picked:   "slug: other\nsize: medium\n",
+			wantSlug: "other",
+			wantSize: SizeMedium,
+		},
+		{
+			name:   "invalid YAML returns empty requirement",
+			picked: ": : :",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := pickedRequirement(proj, tt.picked)
+			assert.Equal(t, tt.wantSlug, req.Slug)
+			assert.Equal(t, tt.wantSize, req.Size)
+		})
+	}
+}
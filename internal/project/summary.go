@@ -0,0 +1,30 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Summary is the machine-readable outcome of a local run written by --summary-json.
+type Summary struct {
+	Project         string  `json:"project"`
+	Branch          string  `json:"branch"`
+	Iterations      int     `json:"iterations"`
+	Passing         int     `json:"passing"`
+	Failing         int     `json:"failing"`
+	Complete        bool    `json:"complete"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// WriteSummary writes summary to path as indented JSON, replacing any earlier summary.
+func WriteSummary(path string, summary Summary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0644))
+
+	want := Summary{Project: "test-project", Branch: "main", Iterations: 2, Passing: 1, Failing: 1, DurationSeconds: 1.5}
+	require.NoError(t, WriteSummary(path, want))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got Summary
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestWriteSummaryMissingDir(t *testing.T) {
+	err := WriteSummary(filepath.Join(t.TempDir(), "missing", "summary.json"), Summary{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to write run summary")
+}
+
+func TestSummaryJSONFieldNames(t *testing.T) {
+	data, err := json.Marshal(Summary{})
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	for _, key := range []string{"project", "branch", "iterations", "passing", "failing", "complete", "durationSeconds"} {
+		assert.Contains(t, fields, key)
+	}
+}
@@ -1,19 +1,55 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/git"
 	"github.com/zon/ralph/internal/output"
 )
 
 type Client struct {
-	out *output.Client
+	out     *output.Client
+	addNote func(string)
+	ctx     context.Context
 }
 
 func NewClient(out *output.Client) *Client {
-	return &Client{out: out}
+	return &Client{out: out, ctx: context.Background()}
+}
+
+// WithContext runs hooks under ctx, so cancelling it, as Ctrl-C does, kills a hook still running.
+func (a *Client) WithContext(ctx context.Context) *Client {
+	a.ctx = ctx
+	return a
+}
+
+// WithNotes records hook failures through addNote so the next agent prompt includes them.
+func (a *Client) WithNotes(addNote func(string)) *Client {
+	a.addNote = addNote
+	return a
+}
+
+// RunHook runs hook from the repository root. A failure is logged and recorded as a note
+// instead of stopping the run. A nil hook is a no-op.
+func (a *Client) RunHook(name string, hook *config.Hook) {
+	if hook == nil || hook.Command == "" {
+		return
+	}
+	dir, err := git.FindRepoRoot()
+	if err != nil {
+		dir = ""
+	}
+	hookOutput, err := RunHook(a.ctx, a.out, name, hook, dir)
+	if err == nil {
+		return
+	}
+	a.out.Warnf("%v", err)
+	if a.addNote != nil {
+		a.addNote(HookFailureNote(err, hookOutput))
+	}
 }
 
 func (a *Client) RunBeforeCommands(cfg *config.RalphConfig) error {
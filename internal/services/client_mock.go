@@ -7,6 +7,8 @@ type MockClient struct {
 	StartFunc       func(cfg *config.RalphConfig) (*Manager, error)
 	StopFunc        func(svc *Manager)
 	RemoveLogsFunc  func(cfg *config.RalphConfig)
+	RunHookFunc     func(name string, hook *config.Hook)
+	Hooks           []string
 	startCount      int
 	stopCount       int
 	removeLogsCount int
@@ -19,6 +21,16 @@ func (m *MockClient) RunBeforeCommands(cfg *config.RalphConfig) error {
 	return nil
 }
 
+func (m *MockClient) RunHook(name string, hook *config.Hook) {
+	if hook == nil {
+		return
+	}
+	m.Hooks = append(m.Hooks, name)
+	if m.RunHookFunc != nil {
+		m.RunHookFunc(name, hook)
+	}
+}
+
 func (m *MockClient) Start(cfg *config.RalphConfig) (*Manager, error) {
 	m.startCount++
 	if m.StartFunc != nil {
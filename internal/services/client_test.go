@@ -1,8 +1,14 @@
 package services_test
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +60,84 @@ func TestServicesClientRunBeforeCommands(t *testing.T) {
 	})
 }
 
+func TestServicesClientRunHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		hook      *config.Hook
+		wantNotes []string
+	}{
+		{name: "nil hook", hook: nil},
+		{name: "successful hook", hook: &config.Hook{Command: "echo", Args: []string{"ok"}}},
+		{
+			name:      "failing hook becomes a note",
+			hook:      &config.Hook{Command: "sh", Args: []string{"-c", "echo db not ready; exit 3"}},
+			wantNotes: []string{"preRun hook failed: exit status 3\nOutput:\ndb not ready"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notes []string
+			client := services.NewClient(output.NewClient(os.Stdout, os.Stderr, false)).WithNotes(func(note string) {
+				notes = append(notes, note)
+			})
+			client.RunHook("preRun", tt.hook)
+			assert.Equal(t, tt.wantNotes, notes)
+		})
+	}
+}
+
+func TestServicesClientRunHookFailureDoesNotStopLaterRuns(t *testing.T) {
+	var notes []string
+	client := services.NewClient(output.NewClient(os.Stdout, os.Stderr, false)).WithNotes(func(note string) {
+		notes = append(notes, note)
+	})
+	failing := &config.Hook{Command: "sh", Args: []string{"-c", "echo lint error >&2; exit 1"}}
+
+	client.RunHook("afterIteration", failing)
+	client.RunHook("afterIteration", &config.Hook{Command: "true"})
+
+	require.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "afterIteration hook failed")
+	assert.Contains(t, notes[0], "lint error")
+}
+
+func TestServicesClientRunHookStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var notes []string
+	client := services.NewClient(output.NewClient(os.Stdout, os.Stderr, false)).WithContext(ctx).WithNotes(func(note string) {
+		notes = append(notes, note)
+	})
+
+	start := time.Now()
+	client.RunHook("preRun", &config.Hook{Command: "sleep", Args: []string{"30"}})
+
+	assert.Less(t, time.Since(start), 10*time.Second)
+	require.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "preRun hook failed")
+}
+
+func TestRunHookKeepsOutputTail(t *testing.T) {
+	hook := &config.Hook{Command: "sh", Args: []string{"-c", "head -c 20000 /dev/zero | tr '\\0' a; echo; echo end"}}
+	hookOutput, err := services.RunHook(context.Background(), output.NewClient(io.Discard, io.Discard, false), "preRun", hook, "")
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(hookOutput), 8000)
+	assert.True(t, strings.HasSuffix(hookOutput, "aaa\nend\n"))
+}
+
+func TestHookFailureNoteTruncatesAtRune(t *testing.T) {
+	// Each character is three bytes, so a cut at a fixed byte count lands inside one
+	hookOutput := strings.Repeat("界", 2000)
+	note := services.HookFailureNote(errors.New("afterIteration hook failed"), hookOutput)
+
+	require.True(t, utf8.ValidString(note))
+	assert.True(t, strings.HasPrefix(note, "afterIteration hook failed\nOutput:\n..."))
+	assert.True(t, strings.HasSuffix(note, "界"))
+	assert.LessOrEqual(t, len(note), len("afterIteration hook failed\nOutput:\n...")+4000)
+}
+
 func TestServicesClientImplementsInterface(t *testing.T) {
 	var _ orchestrationRun.ServicesClient = services.NewClient(output.NewClient(os.Stdout, os.Stderr, false))
 }
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/output"
+)
+
+// maxHookNoteOutput caps how much hook output is kept in a failure note
+const maxHookNoteOutput = 4000
+
+// maxHookCapture is how much of the end of a hook's output RunHook keeps. It leaves room for
+// the trailing whitespace HookFailureNote trims before cutting to maxHookNoteOutput.
+const maxHookCapture = 2 * maxHookNoteOutput
+
+// hookWaitDelay bounds how long a cancelled hook may keep its output open, since a child it
+// started can hold the pipes after the hook itself is killed
+const hookWaitDelay = 5 * time.Second
+
+// RunHook runs a hook command in dir, streaming its output to stdout and stderr. Cancelling
+// ctx kills the hook. It returns the tail of the combined output so callers can report it
+// when the hook fails.
+func RunHook(ctx context.Context, out *output.Client, name string, hook *config.Hook, dir string) (string, error) {
+	out.Infof("Running %s hook", name)
+	out.Debugf("Command: %s %s", hook.Command, strings.Join(hook.Args, " "))
+
+	captured := &tailWriter{limit: maxHookCapture}
+	c := exec.CommandContext(ctx, hook.Command, hook.Args...)
+	c.Dir = dir
+	c.Stdout = io.MultiWriter(os.Stdout, captured)
+	c.Stderr = io.MultiWriter(os.Stderr, captured)
+	c.WaitDelay = hookWaitDelay
+
+	if err := c.Run(); err != nil {
+		return captured.String(), fmt.Errorf("%s hook failed: %w", name, err)
+	}
+
+	out.Successf("%s hook completed successfully", name)
+	return captured.String(), nil
+}
+
+// tailWriter keeps only the last limit bytes written to it
+type tailWriter struct {
+	limit int
+	buf   []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if over := len(w.buf) - w.limit; over > 0 {
+		w.buf = append(w.buf[:0], w.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) String() string {
+	return string(w.buf)
+}
+
+// HookFailureNote describes a failed hook for the agent, including the tail of its output.
+// The tail starts on a rune boundary, so a cut never splits a multi-byte character.
+func HookFailureNote(err error, hookOutput string) string {
+	hookOutput = strings.TrimSpace(hookOutput)
+	if len(hookOutput) > maxHookNoteOutput {
+		start := len(hookOutput) - maxHookNoteOutput
+		for start < len(hookOutput) && !utf8.RuneStart(hookOutput[start]) {
+			start++
+		}
+		hookOutput = "..." + hookOutput[start:]
+	}
+	if hookOutput == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v\nOutput:\n%s", err, hookOutput)
+}
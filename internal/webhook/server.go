@@ -2,12 +2,16 @@ package webhook
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zon/ralph/internal/argo"
+	execcontext "github.com/zon/ralph/internal/context"
 	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/webhookconfig"
@@ -20,10 +24,12 @@ type Server struct {
 	router     *gin.Engine
 	out        *output.Client
 	argoClient argo.Client
+	gh         github.GHClient
 }
 
 // NewServer creates a new webhook Server with the given configuration.
-func NewServer(cfg *webhookconfig.Config, out *output.Client, argoClient argo.Client) *Server {
+// gh looks up pull request reviews when requiredApprovals is above one.
+func NewServer(cfg *webhookconfig.Config, out *output.Client, argoClient argo.Client, gh github.GHClient) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -33,9 +39,11 @@ func NewServer(cfg *webhookconfig.Config, out *output.Client, argoClient argo.Cl
 		router:     router,
 		out:        out,
 		argoClient: argoClient,
+		gh:         gh,
 	}
 
 	router.POST("/webhook", s.handleWebhook)
+	router.POST("/trigger", s.handleTrigger)
 
 	return s
 }
@@ -84,7 +92,8 @@ func (s *Server) handleWebhook(c *gin.Context) {
 	}
 
 	sig := c.GetHeader("X-Hub-Signature-256")
-	if !webhookconfig.ValidateSignature(body, secret, sig) {
+	previous := s.config.PreviousWebhookSecretForRepo(owner, repoName)
+	if !webhookconfig.ValidateSignature(body, secret, sig) && (previous == "" || !webhookconfig.ValidateSignature(body, previous, sig)) {
 		s.out.Debugf("rejected request: invalid signature for %s/%s", owner, repoName)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
 		return
@@ -99,7 +108,12 @@ func (s *Server) handleWebhook(c *gin.Context) {
 	}
 
 	fields := payload.ToEvent(eventType)
-	s.out.Debugf("dispatching %s for %s/%s", eventType, owner, repoName)
+	// a merge label counts as its author's approval, so it is held to requiredApprovals like a review
+	if fields.Approved && !s.hasRequiredApprovals(c.Request.Context(), fields) {
+		c.Status(http.StatusOK)
+		return
+	}
+	s.out.Debugf("dispatching %s for %s/%s triggered by %s", eventType, owner, repoName, execcontext.WebhookActor(fields.Author))
 
 	result, err := workflow.FromWebhookEventWithConfig(fields, s.config)
 	if err != nil {
@@ -112,6 +126,98 @@ func (s *Server) handleWebhook(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// hasRequiredApprovals reports whether the pull request of an approval or merge label has
+// requiredApprovals distinct approving users, counting only users allowed to interact with the
+// repo. The author of the event, the reviewer or the user who added the label, is one of them.
+func (s *Server) hasRequiredApprovals(ctx context.Context, fields github.EventFields) bool {
+	required := s.config.App.RequiredApprovals
+	if required <= 1 {
+		return true
+	}
+	pr, err := strconv.Atoi(fields.PRNumber)
+	if err != nil {
+		s.out.Debugf("ignoring approval for %s/%s: invalid pull request number %q", fields.RepoOwner, fields.RepoName, fields.PRNumber)
+		return false
+	}
+	approvers, err := s.gh.ListApprovers(ctx, fields.RepoOwner, fields.RepoName, pr)
+	if err != nil {
+		s.out.Warnf("ignoring approval for %s/%s#%d: %v", fields.RepoOwner, fields.RepoName, pr, err)
+		return false
+	}
+
+	repo := s.config.RepoByFullName(fields.RepoOwner, fields.RepoName)
+	counted := make(map[string]bool)
+	// the review that triggered this event may not be listed yet
+	for _, login := range append(approvers, fields.Author) {
+		if s.config.IsUserIgnored(repo, login) || (repo != nil && !repo.IsUserAllowed(login)) {
+			continue
+		}
+		counted[strings.ToLower(login)] = true
+	}
+	if len(counted) < required {
+		s.out.Debugf("waiting on approvals for %s/%s#%d: %d of %d", fields.RepoOwner, fields.RepoName, pr, len(counted), required)
+		return false
+	}
+	return true
+}
+
+// triggerRequest is the JSON body of POST /trigger.
+type triggerRequest struct {
+	Owner  string `json:"owner"`
+	Name   string `json:"name"`
+	Branch string `json:"branch"`
+	Base   string `json:"base"`
+}
+
+// handleTrigger is the Gin handler for POST /trigger. It submits a run workflow for the
+// project on the requested branch of a configured repo, authenticated by the trigger token.
+func (s *Server) handleTrigger(c *gin.Context) {
+	if !s.validTriggerToken(c.GetHeader("Authorization")) {
+		s.out.Debugf("rejected trigger: missing or invalid token")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or missing bearer token"})
+		return
+	}
+
+	var req triggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if req.Owner == "" || req.Name == "" || req.Branch == "" || req.Base == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "owner, name, branch and base are required"})
+		return
+	}
+
+	if s.config.RepoByFullName(req.Owner, req.Name) == nil {
+		s.out.Debugf("ignoring trigger: repo %s/%s not configured", req.Owner, req.Name)
+		c.JSON(http.StatusNotFound, gin.H{"error": "repository not configured"})
+		return
+	}
+
+	s.out.Debugf("dispatching trigger for %s/%s on %s", req.Owner, req.Name, req.Branch)
+
+	result, err := workflow.FromTriggerWithConfig(req.Owner, req.Name, req.Branch, req.Base, s.config)
+	if err != nil {
+		s.out.Debugf("failed to generate workflow for %s/%s: %v", req.Owner, req.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate workflow"})
+		return
+	}
+
+	go s.submitWorkflow(result, req.Owner, req.Name)
+	c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+}
+
+// validTriggerToken reports whether header carries the configured trigger token as a bearer
+// token. No token is valid when none is configured.
+func (s *Server) validTriggerToken(header string) bool {
+	want := s.config.Secrets.TriggerToken
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	if want == "" || !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
 // submitWorkflow submits a WorkflowResult asynchronously.
 func (s *Server) submitWorkflow(result *workflow.WorkflowResult, owner, repoName string) {
 	ctx := context.Background()
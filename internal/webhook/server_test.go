@@ -15,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zon/ralph/internal/argo"
+	"github.com/zon/ralph/internal/github"
 	"github.com/zon/ralph/internal/output"
 	"github.com/zon/ralph/internal/webhookconfig"
 )
@@ -80,7 +81,7 @@ func postWebhook(t *testing.T, s *Server, eventType string, body []byte, signatu
 // ──────────────────────────────────────────────────────────────────────────────
 
 func TestHandleWebhook_InvalidJSON_Returns400(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte("not json")))
 	req.Header.Set("X-Hub-Signature-256", "sha256=anything")
 	w := httptest.NewRecorder()
@@ -89,7 +90,7 @@ func TestHandleWebhook_InvalidJSON_Returns400(t *testing.T) {
 }
 
 func TestHandleWebhook_UnknownRepo_Returns401(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("unknown-org", "other-repo", nil)
 	sig := sign(body, "doesnotmatter")
 	w := postWebhook(t, s, "pull_request_review_comment", body, sig)
@@ -97,28 +98,40 @@ func TestHandleWebhook_UnknownRepo_Returns401(t *testing.T) {
 }
 
 func TestHandleWebhook_MissingSignature_Returns401(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("acme", "myrepo", nil)
 	w := postWebhook(t, s, "pull_request_review_comment", body, "")
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestHandleWebhook_InvalidSignature_Returns401(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("acme", "myrepo", nil)
 	w := postWebhook(t, s, "pull_request_review_comment", body, "sha256=deadbeef")
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestHandleWebhook_PreviousSecretAcceptedAfterRotation(t *testing.T) {
+	cfg := testConfig()
+	cfg.Secrets.Repos[0].WebhookSecret = "rotatedsecret"
+	cfg.Secrets.Repos[0].PreviousSecret = "supersecret"
+	s := NewServer(cfg, output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	body := buildPayload("acme", "myrepo", nil)
+
+	assert.Equal(t, http.StatusOK, postWebhook(t, s, "unknown_event_type", body, sign(body, "rotatedsecret")).Code)
+	assert.Equal(t, http.StatusOK, postWebhook(t, s, "unknown_event_type", body, sign(body, "supersecret")).Code)
+	assert.Equal(t, http.StatusUnauthorized, postWebhook(t, s, "unknown_event_type", body, sign(body, "othersecret")).Code)
+}
+
 func TestHandleWebhook_WrongPrefixSignature_Returns401(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("acme", "myrepo", nil)
 	w := postWebhook(t, s, "pull_request_review_comment", body, "sha1=abc123")
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestHandleWebhook_FilteredEvent_Returns200(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("acme", "myrepo", nil)
 	sig := sign(body, "supersecret")
 	w := postWebhook(t, s, "unknown_event_type", body, sig)
@@ -126,7 +139,7 @@ func TestHandleWebhook_FilteredEvent_Returns200(t *testing.T) {
 }
 
 func TestHandleWebhook_EmptyRepoOwner_Returns400(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("", "myrepo", nil)
 	sig := sign(body, "supersecret")
 	w := postWebhook(t, s, "pull_request_review_comment", body, sig)
@@ -134,7 +147,7 @@ func TestHandleWebhook_EmptyRepoOwner_Returns400(t *testing.T) {
 }
 
 func TestHandleWebhook_EmptyRepoName_Returns400(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	body := buildPayload("acme", "", nil)
 	sig := sign(body, "supersecret")
 	w := postWebhook(t, s, "pull_request_review_comment", body, sig)
@@ -142,7 +155,7 @@ func TestHandleWebhook_EmptyRepoName_Returns400(t *testing.T) {
 }
 
 func TestHandleWebhook_ToWorkflowError_Returns200(t *testing.T) {
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{})
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
 	payload := map[string]interface{}{
 		"repository": map[string]interface{}{
 			"name": "myrepo",
@@ -172,7 +185,7 @@ func TestHandleWebhook_IssueComment_SubmitsWorkflow(t *testing.T) {
 			return "test-workflow", nil
 		},
 	}
-	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), mock)
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), mock, &github.MockGH{})
 
 	payload := map[string]interface{}{
 		"repository": map[string]interface{}{
@@ -208,3 +221,285 @@ func TestHandleWebhook_IssueComment_SubmitsWorkflow(t *testing.T) {
 	}
 }
 
+func TestHandleWebhook_ReviewApproved_SubmitsMergeWorkflowForPR(t *testing.T) {
+	submitCh := make(chan string, 1)
+	mock := &argo.MockClient{
+		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+			submitCh <- workflowYAML
+			return "test-workflow", nil
+		},
+	}
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), mock, &github.MockGH{})
+
+	body := buildPayload("acme", "myrepo", map[string]interface{}{
+		"action": "submitted",
+		"review": map[string]interface{}{
+			"state": "approved",
+			"user":  map[string]interface{}{"login": "testuser"},
+		},
+		"pull_request": map[string]interface{}{
+			"number": 99,
+			"head": map[string]interface{}{
+				"ref": "ralph/my-feature",
+			},
+		},
+	})
+	w := postWebhook(t, s, "pull_request_review", body, sign(body, "supersecret"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case workflowYAML := <-submitCh:
+		assert.Contains(t, workflowYAML, "PR_NUMBER")
+		assert.Contains(t, workflowYAML, "99")
+		assert.Contains(t, workflowYAML, "ralph/my-feature")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for workflow submission")
+	}
+}
+func TestHandleWebhook_RequiredApprovals(t *testing.T) {
+	tests := []struct {
+		name       string
+		approvers  []string
+		wantSubmit bool
+	}{
+		{name: "one approval is below the threshold", approvers: []string{"testuser"}, wantSubmit: false},
+		{name: "two approvals meet the threshold", approvers: []string{"alice", "testuser"}, wantSubmit: true},
+		{name: "approvals from users outside allowedUsers are not counted", approvers: []string{"mallory", "testuser"}, wantSubmit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.App.RequiredApprovals = 2
+			cfg.App.Repos[0].AllowedUsers = []string{"alice", "testuser"}
+
+			submitCh := make(chan string, 1)
+			mock := &argo.MockClient{
+				SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+					submitCh <- workflowYAML
+					return "test-workflow", nil
+				},
+			}
+			var gotPR int
+			gh := &github.MockGH{
+				ListApproversFn: func(ctx context.Context, owner, repo string, pr int) ([]string, error) {
+					gotPR = pr
+					return tt.approvers, nil
+				},
+			}
+			s := NewServer(cfg, output.NewClient(os.Stdout, os.Stderr, false), mock, gh)
+
+			body := buildPayload("acme", "myrepo", map[string]interface{}{
+				"review": map[string]interface{}{
+					"state": "approved",
+					"user":  map[string]interface{}{"login": "testuser"},
+				},
+				"pull_request": map[string]interface{}{
+					"number": 99,
+					"head":   map[string]interface{}{"ref": "ralph/my-feature"},
+				},
+			})
+			w := postWebhook(t, s, "pull_request_review", body, sign(body, "supersecret"))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, 99, gotPR)
+
+			if tt.wantSubmit {
+				select {
+				case workflowYAML := <-submitCh:
+					assert.Contains(t, workflowYAML, "ralph-merge-")
+				case <-time.After(time.Second):
+					t.Fatal("timed out waiting for workflow submission")
+				}
+				return
+			}
+			select {
+			case <-submitCh:
+				t.Fatal("merge submitted below the required approvals")
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+func TestHandleWebhook_MergeLabel_SubmitsMergeWorkflow(t *testing.T) {
+	submitCh := make(chan string, 1)
+	mock := &argo.MockClient{
+		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+			submitCh <- workflowYAML
+			return "test-workflow", nil
+		},
+	}
+	cfg := testConfig()
+	cfg.App.MergeLabel = "ready-to-merge"
+	s := NewServer(cfg, output.NewClient(os.Stdout, os.Stderr, false), mock, &github.MockGH{})
+
+	body := buildPayload("acme", "myrepo", map[string]interface{}{
+		"action": "labeled",
+		"label":  map[string]interface{}{"name": "ready-to-merge"},
+		"sender": map[string]interface{}{"login": "testuser"},
+		"pull_request": map[string]interface{}{
+			"number": 12,
+			"head":   map[string]interface{}{"ref": "ralph/my-feature"},
+		},
+	})
+	w := postWebhook(t, s, "pull_request", body, sign(body, "supersecret"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case workflowYAML := <-submitCh:
+		assert.Contains(t, workflowYAML, "ralph-merge-")
+		assert.Contains(t, workflowYAML, "ralph/my-feature")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for workflow submission")
+	}
+}
+
+func TestHandleWebhook_MergeLabel_RequiredApprovals(t *testing.T) {
+	tests := []struct {
+		name       string
+		approvers  []string
+		wantSubmit bool
+	}{
+		{name: "the label alone is below the threshold", approvers: nil, wantSubmit: false},
+		{name: "the label and one review meet the threshold", approvers: []string{"alice"}, wantSubmit: true},
+		{name: "a review by the labeler is not counted twice", approvers: []string{"testuser"}, wantSubmit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.App.MergeLabel = "ready-to-merge"
+			cfg.App.RequiredApprovals = 2
+			cfg.App.Repos[0].AllowedUsers = []string{"alice", "testuser"}
+
+			submitCh := make(chan string, 1)
+			mock := &argo.MockClient{
+				SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+					submitCh <- workflowYAML
+					return "test-workflow", nil
+				},
+			}
+			gh := &github.MockGH{
+				ListApproversFn: func(ctx context.Context, owner, repo string, pr int) ([]string, error) {
+					return tt.approvers, nil
+				},
+			}
+			s := NewServer(cfg, output.NewClient(os.Stdout, os.Stderr, false), mock, gh)
+
+			body := buildPayload("acme", "myrepo", map[string]interface{}{
+				"action": "labeled",
+				"label":  map[string]interface{}{"name": "ready-to-merge"},
+				"sender": map[string]interface{}{"login": "testuser"},
+				"pull_request": map[string]interface{}{
+					"number": 12,
+					"head":   map[string]interface{}{"ref": "ralph/my-feature"},
+				},
+			})
+			w := postWebhook(t, s, "pull_request", body, sign(body, "supersecret"))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			if tt.wantSubmit {
+				select {
+				case workflowYAML := <-submitCh:
+					assert.Contains(t, workflowYAML, "ralph-merge-")
+				case <-time.After(time.Second):
+					t.Fatal("timed out waiting for workflow submission")
+				}
+				return
+			}
+			select {
+			case <-submitCh:
+				t.Fatal("label merge submitted below the required approvals")
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Trigger route tests
+// ──────────────────────────────────────────────────────────────────────────────
+
+// triggerConfig returns testConfig with a trigger token configured.
+func triggerConfig() *webhookconfig.Config {
+	cfg := testConfig()
+	cfg.Secrets.TriggerToken = "trigger-token-value"
+	return cfg
+}
+
+// postTrigger sends a POST /trigger request with the given Authorization header.
+func postTrigger(t *testing.T, s *Server, authorization string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/trigger", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	s.Router().ServeHTTP(w, req)
+	return w
+}
+
+func triggerBody(owner, name, branch string) []byte {
+	b, _ := json.Marshal(map[string]string{"owner": owner, "name": name, "branch": branch, "base": "develop"})
+	return b
+}
+
+func TestHandleTrigger_Authorized_SubmitsRunWorkflow(t *testing.T) {
+	submitCh := make(chan string, 1)
+	mock := &argo.MockClient{
+		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+			submitCh <- workflowYAML
+			return "test-workflow", nil
+		},
+	}
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), mock, &github.MockGH{})
+
+	w := postTrigger(t, s, "Bearer trigger-token-value", triggerBody("acme", "myrepo", "ralph/my-feature"))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	select {
+	case workflowYAML := <-submitCh:
+		assert.Contains(t, workflowYAML, "ralph/my-feature")
+		assert.Contains(t, workflowYAML, "projects/my-feature.yaml")
+		assert.Contains(t, workflowYAML, "develop")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for workflow submission")
+	}
+}
+
+func TestHandleTrigger_MissingToken_Returns401(t *testing.T) {
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	w := postTrigger(t, s, "", triggerBody("acme", "myrepo", "ralph/my-feature"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleTrigger_WrongToken_Returns401(t *testing.T) {
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	w := postTrigger(t, s, "Bearer not-the-token", triggerBody("acme", "myrepo", "ralph/my-feature"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleTrigger_NoTokenConfigured_Returns401(t *testing.T) {
+	s := NewServer(testConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	w := postTrigger(t, s, "Bearer ", triggerBody("acme", "myrepo", "ralph/my-feature"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleTrigger_UnknownRepo_Returns404(t *testing.T) {
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	w := postTrigger(t, s, "Bearer trigger-token-value", triggerBody("acme", "other", "ralph/my-feature"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleTrigger_MissingBranch_Returns400(t *testing.T) {
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	w := postTrigger(t, s, "Bearer trigger-token-value", triggerBody("acme", "myrepo", ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleTrigger_MissingBase_Returns400(t *testing.T) {
+	s := NewServer(triggerConfig(), output.NewClient(os.Stdout, os.Stderr, false), &argo.MockClient{}, &github.MockGH{})
+	body, _ := json.Marshal(map[string]string{"owner": "acme", "name": "myrepo", "branch": "ralph/my-feature"})
+	w := postTrigger(t, s, "Bearer trigger-token-value", body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -6,9 +6,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/output"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,32 +23,58 @@ type RepoConfig struct {
 	Namespace    string   `yaml:"namespace"` // Kubernetes namespace for Argo Workflow submission; required
 	AllowedUsers []string `yaml:"allowedUsers"`
 	IgnoredUsers []string `yaml:"ignoredUsers"` // Messages from these users are always ignored (e.g. the bot user)
+	// Populated is set when AllowedUsers was filled from the repo's collaborators, so a list
+	// emptied by excludeUsers or excludeBots denies everyone instead of allowing all users
+	Populated bool `yaml:"allowedUsersPopulated,omitempty"`
+}
+
+// Environment is one ralph-webhook deployment, such as staging or prod
+type Environment struct {
+	Hostname string `yaml:"hostname"` // Ingress hostname GitHub delivers webhooks to
 }
 
 // AppConfig is the application configuration loaded from a YAML file
 type AppConfig struct {
-	Port                    int          `yaml:"port"`
-	Repos                   []RepoConfig `yaml:"repos"`
-	RalphUser               string       `yaml:"ralphUser"`               // GitHub username of the ralph bot user; always ignored regardless of per-repo ignoredUsers
-	CommentInstructionsFile string       `yaml:"commentInstructionsFile"` // Path to a markdown file overriding the default comment-reply instructions
-	CommentInstructions     string       `yaml:"-"`                       // Loaded from CommentInstructionsFile; falls back to the embedded default
-	MergeInstructionsFile   string       `yaml:"mergeInstructionsFile"`   // Path to a markdown file overriding the default merge instructions
-	MergeInstructions       string       `yaml:"-"`                       // Loaded from MergeInstructionsFile; falls back to the embedded default
-	ImageRepository         string       `yaml:"imageRepository"`         // Container image repository for workflow
-	ImageTag                string       `yaml:"imageTag"`                // Container image tag for workflow
-	WorkflowContext         string       `yaml:"workflowContext"`         // Argo workflow context label
+	Port                    int                    `yaml:"port"`
+	Repos                   []RepoConfig           `yaml:"repos"`
+	RalphUser               string                 `yaml:"ralphUser"`                   // GitHub username of the ralph bot user; always ignored regardless of per-repo ignoredUsers
+	CommentInstructionsFile string                 `yaml:"commentInstructionsFile"`     // Path to a markdown file overriding the default comment-reply instructions
+	CommentInstructions     string                 `yaml:"-"`                           // Loaded from CommentInstructionsFile; falls back to the embedded default
+	MergeInstructionsFile   string                 `yaml:"mergeInstructionsFile"`       // Path to a markdown file overriding the default merge instructions
+	MergeInstructions       string                 `yaml:"-"`                           // Loaded from MergeInstructionsFile; falls back to the embedded default
+	ImageRepository         string                 `yaml:"imageRepository"`             // Container image repository for workflow
+	ImageTag                string                 `yaml:"imageTag"`                    // Container image tag for workflow
+	WorkflowContext         string                 `yaml:"workflowContext"`             // Argo workflow context label
+	ExcludeUsers            []string               `yaml:"excludeUsers,omitempty"`      // Login patterns (path.Match globs, case-insensitive) left out of auto-populated allowedUsers
+	ExcludeBots             *bool                  `yaml:"excludeBots,omitempty"`       // Leave every "[bot]" account out of auto-populated allowedUsers
+	RequiredApprovals       int                    `yaml:"requiredApprovals,omitempty"` // Distinct approving reviewers from allowed users needed before ralph merges; 0 or 1 merges on the first approval
+	MergeLabel              string                 `yaml:"mergeLabel,omitempty"`        // Adding this label to a PR, by an allowed user, counts as their approval toward requiredApprovals; unset disables label merges
+	Environments            map[string]Environment `yaml:"environments,omitempty"`      // Deployments by name that set config and rotate secret register GitHub webhooks for, selected with --env
 }
 
 // RepoSecret holds the webhook secret for a single repository
 type RepoSecret struct {
-	Owner         string `yaml:"owner"`
-	Name          string `yaml:"name"`
-	WebhookSecret string `yaml:"webhookSecret"`
+	Owner          string `yaml:"owner"`
+	Name           string `yaml:"name"`
+	WebhookSecret  string `yaml:"webhookSecret"`
+	PreviousSecret string `yaml:"previousSecret,omitempty"` // Secret replaced by the last rotation; still accepted so deliveries signed with it are not rejected
 }
 
 // Secrets holds all secrets loaded from the secrets YAML file
 type Secrets struct {
-	Repos []RepoSecret `yaml:"repos"`
+	Repos        []RepoSecret `yaml:"repos"`
+	TriggerToken string       `yaml:"triggerToken,omitempty"` // Bearer token accepted by POST /trigger; the route rejects every request when unset
+	GithubToken  string       `yaml:"githubToken,omitempty"`  // GitHub token for the service; GITHUB_TOKEN overrides it
+}
+
+// registerForRedaction keeps every webhook secret out of logged output
+func (s *Secrets) registerForRedaction() {
+	output.RegisterSecret(s.TriggerToken)
+	output.RegisterSecret(s.GithubToken)
+	for _, rs := range s.Repos {
+		output.RegisterSecret(rs.WebhookSecret)
+		output.RegisterSecret(rs.PreviousSecret)
+	}
 }
 
 // Config is the fully-loaded service configuration combining AppConfig and Secrets
@@ -99,13 +129,90 @@ func LoadSecrets(path string) (*Secrets, error) {
 	if err := yaml.Unmarshal(data, &s); err != nil {
 		return nil, fmt.Errorf("failed to parse secrets YAML: %w", err)
 	}
+	s.registerForRedaction()
 
 	return &s, nil
 }
 
+// WriteSecretsFile writes secrets as the YAML stored in the Kubernetes secret to a local file
+// readable only by its owner, so they can be kept outside the cluster. The YAML goes to a 0600
+// temp file in the same directory that is renamed over path, so the secrets are never written
+// into an existing file other users can read.
+func WriteSecretsFile(path string, secrets *Secrets) (err error) {
+	secretsBytes, err := yaml.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to serialize Secrets to YAML: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write secrets file %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(secretsBytes); err != nil {
+		return fmt.Errorf("failed to write secrets file %s: %w", path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write secrets file %s: %w", path, err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write secrets file %s: %w", path, err)
+	}
+	return nil
+}
+
+// hasSecretsEnv reports whether any secrets are provided through WEBHOOK_SECRETS_YAML or GITHUB_TOKEN
+func hasSecretsEnv() bool {
+	return os.Getenv("WEBHOOK_SECRETS_YAML") != "" || os.Getenv("GITHUB_TOKEN") != ""
+}
+
+// applySecretsEnv layers the secrets from WEBHOOK_SECRETS_YAML and GITHUB_TOKEN over s.
+// A repo in WEBHOOK_SECRETS_YAML replaces the entry for the same repo in s, and
+// non-empty tokens replace those in s.
+func applySecretsEnv(s *Secrets) error {
+	if data := os.Getenv("WEBHOOK_SECRETS_YAML"); data != "" {
+		var env Secrets
+		if err := yaml.Unmarshal([]byte(data), &env); err != nil {
+			return fmt.Errorf("failed to parse WEBHOOK_SECRETS_YAML: %w", err)
+		}
+		for _, rs := range env.Repos {
+			s.setRepoSecret(rs)
+		}
+		if env.TriggerToken != "" {
+			s.TriggerToken = env.TriggerToken
+		}
+		if env.GithubToken != "" {
+			s.GithubToken = env.GithubToken
+		}
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		s.GithubToken = token
+	}
+	s.registerForRedaction()
+	return nil
+}
+
+// setRepoSecret replaces the entry for rs's repo, or appends rs when there is none
+func (s *Secrets) setRepoSecret(rs RepoSecret) {
+	key := repoKey(rs.Owner, rs.Name)
+	for i := range s.Repos {
+		if repoKey(s.Repos[i].Owner, s.Repos[i].Name) == key {
+			s.Repos[i] = rs
+			return
+		}
+	}
+	s.Repos = append(s.Repos, rs)
+}
+
 // LoadConfig loads and validates the full service configuration.
 // configPath and secretsPath may be empty; in that case the values from
 // the environment variables WEBHOOK_CONFIG and WEBHOOK_SECRETS are used.
+// Secrets from WEBHOOK_SECRETS_YAML and GITHUB_TOKEN are layered over the
+// secrets file, and are enough on their own when no secrets path is set.
 func LoadConfig(configPath, secretsPath string) (*Config, error) {
 	if configPath == "" {
 		configPath = os.Getenv("WEBHOOK_CONFIG")
@@ -117,17 +224,26 @@ func LoadConfig(configPath, secretsPath string) (*Config, error) {
 	if configPath == "" {
 		return nil, fmt.Errorf("app config path is required (set --config flag or WEBHOOK_CONFIG env var)")
 	}
-	if secretsPath == "" {
-		return nil, fmt.Errorf("secrets path is required (set --secrets flag or WEBHOOK_SECRETS env var)")
+	if secretsPath == "" && !hasSecretsEnv() {
+		return nil, fmt.Errorf("secrets path is required (set --secrets flag, WEBHOOK_SECRETS or WEBHOOK_SECRETS_YAML env var)")
 	}
 
 	appCfg, err := LoadAppConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
+	if err := appCfg.Validate(); err != nil {
+		return nil, err
+	}
 
-	secrets, err := LoadSecrets(secretsPath)
-	if err != nil {
+	secrets := &Secrets{}
+	if secretsPath != "" {
+		secrets, err = LoadSecrets(secretsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := applySecretsEnv(secrets); err != nil {
 		return nil, err
 	}
 
@@ -143,6 +259,72 @@ func LoadConfig(configPath, secretsPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// Validate reports every problem with a loaded app config in one error: a port outside
+// 1-65535, a missing ralphUser, a negative requiredApprovals, repos without an owner or
+// name, repos listed twice, and environments without a bare hostname.
+// Partial configs merged by BuildWebhookAppConfig are not expected to pass.
+func (c *AppConfig) Validate() error {
+	var problems []string
+	if c.Port < 1 || c.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("port %d is out of range (1-65535)", c.Port))
+	}
+	if c.RalphUser == "" {
+		problems = append(problems, "ralphUser is required")
+	}
+	if c.RequiredApprovals < 0 {
+		problems = append(problems, fmt.Sprintf("requiredApprovals %d must not be negative", c.RequiredApprovals))
+	}
+	// repos are identified by owner and name together, so same-named repos under different owners do not conflict
+	seen := make(map[string]int)
+	for i, repo := range c.Repos {
+		if repo.Owner == "" {
+			problems = append(problems, fmt.Sprintf("repos[%d]: owner is required", i))
+		}
+		if repo.Name == "" {
+			problems = append(problems, fmt.Sprintf("repos[%d]: name is required", i))
+		}
+		if repo.Owner == "" || repo.Name == "" {
+			continue
+		}
+		key := strings.ToLower(repoKey(repo.Owner, repo.Name))
+		if first, ok := seen[key]; ok {
+			problems = append(problems, fmt.Sprintf("repos[%d]: %s/%s is already configured by repos[%d]", i, repo.Owner, repo.Name, first))
+			continue
+		}
+		seen[key] = i
+	}
+	for _, name := range environmentNames(c.Environments) {
+		if err := validateHostname(name, c.Environments[name].Hostname); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid app config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateHostname checks that the hostname of environment name is set and bare, with no scheme or path
+func validateHostname(name, hostname string) error {
+	if hostname == "" {
+		return fmt.Errorf("environments.%s: hostname is required", name)
+	}
+	if strings.Contains(hostname, "/") || strings.ContainsAny(hostname, " \t") {
+		return fmt.Errorf("environments.%s: hostname %q must be a bare hostname such as ralph.example.com", name, hostname)
+	}
+	return nil
+}
+
+// environmentNames returns the names of environments in sorted order
+func environmentNames(environments map[string]Environment) []string {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ValidateConfig validates that required secrets are present
 func ValidateConfig(cfg *Config) error {
 	// Build a lookup of repo secrets for validation
@@ -178,6 +360,18 @@ func (c *Config) WebhookSecretForRepo(owner, name string) string {
 	return ""
 }
 
+// PreviousWebhookSecretForRepo returns the secret replaced by the last rotation for the
+// given owner/name pair. Returns an empty string if there is none.
+func (c *Config) PreviousWebhookSecretForRepo(owner, name string) string {
+	key := repoKey(owner, name)
+	for _, rs := range c.Secrets.Repos {
+		if repoKey(rs.Owner, rs.Name) == key {
+			return rs.PreviousSecret
+		}
+	}
+	return ""
+}
+
 // RepoByFullName looks up a RepoConfig by owner and name.
 // Returns nil if not found.
 func (c *Config) RepoByFullName(owner, name string) *RepoConfig {
@@ -191,11 +385,12 @@ func (c *Config) RepoByFullName(owner, name string) *RepoConfig {
 }
 
 // IsUserAllowed reports whether the given username is permitted to interact with
-// this repository. If AllowedUsers is empty, all users are allowed.
+// this repository. If AllowedUsers is empty, all users are allowed, unless it was populated from
+// collaborators who were all excluded, in which case nobody is.
 // Comparison is case-insensitive to match GitHub's behaviour.
 func (r *RepoConfig) IsUserAllowed(username string) bool {
 	if len(r.AllowedUsers) == 0 {
-		return true
+		return !r.Populated
 	}
 	for _, u := range r.AllowedUsers {
 		if strings.EqualFold(u, username) {
@@ -205,6 +400,23 @@ func (r *RepoConfig) IsUserAllowed(username string) bool {
 	return false
 }
 
+// IsUserExcluded reports whether login must be left out of the AllowedUsers populated from a
+// repo's collaborators, because it is a bot and ExcludeBots is set or it matches an ExcludeUsers pattern.
+func (c *AppConfig) IsUserExcluded(login string) bool {
+	login = strings.ToLower(login)
+	if c.ExcludeBots != nil && *c.ExcludeBots && strings.HasSuffix(login, "[bot]") {
+		return true
+	}
+	for _, pattern := range c.ExcludeUsers {
+		// compare literally too, since a login such as "dependabot[bot]" is also a valid pattern
+		pattern = strings.ToLower(pattern)
+		if matched, _ := path.Match(pattern, login); matched || pattern == login {
+			return true
+		}
+	}
+	return false
+}
+
 // IsUserIgnored reports whether the given username should be ignored for the given repo.
 // A user is ignored if they match the global RalphUser or appear in the repo's IgnoredUsers list.
 // Events from ignored users are always silently dropped regardless of AllowedUsers.
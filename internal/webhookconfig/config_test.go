@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zon/ralph/internal/config"
+	"github.com/zon/ralph/internal/output"
+	"gopkg.in/yaml.v3"
 )
 
 func writeFile(t *testing.T, dir, name, content string) string {
@@ -19,6 +21,7 @@ func writeFile(t *testing.T, dir, name, content string) string {
 
 const validAppConfig = `
 port: 8080
+ralphUser: ralph[bot]
 repos:
   - owner: acme
     name: my-service
@@ -142,6 +145,16 @@ func TestLoadSecrets(t *testing.T) {
 		assert.Equal(t, "secret-abc", s.Repos[0].WebhookSecret)
 	})
 
+	t.Run("registers loaded secrets for redaction", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeFile(t, dir, "secrets.yaml", "repos:\n  - owner: acme\n    name: api\n    webhookSecret: loaded-secret-value\n")
+
+		_, err := LoadSecrets(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "secret="+output.Redacted, output.Redact("secret=loaded-secret-value"))
+	})
+
 	t.Run("error on missing file", func(t *testing.T) {
 		_, err := LoadSecrets("/nonexistent/secrets.yaml")
 		require.Error(t, err)
@@ -158,6 +171,57 @@ func TestLoadSecrets(t *testing.T) {
 	})
 }
 
+func TestWriteSecretsFile(t *testing.T) {
+	secrets := &Secrets{
+		Repos: []RepoSecret{
+			{Owner: "acme", Name: "repo-a", WebhookSecret: "s3kr3t"},
+		},
+	}
+
+	t.Run("writes the secrets YAML readable only by the owner", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secrets.yaml")
+		require.NoError(t, WriteSecretsFile(path, secrets))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		want, err := yaml.Marshal(secrets)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(data))
+
+		loaded, err := LoadSecrets(path)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.Repos, loaded.Repos)
+	})
+
+	t.Run("replaces a file that already exists", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "secrets.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0644))
+		before, err := os.Stat(path)
+		require.NoError(t, err)
+
+		require.NoError(t, WriteSecretsFile(path, secrets))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		assert.False(t, os.SameFile(before, info), "the secrets are never written into the readable file")
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temp file is left behind")
+	})
+
+	t.Run("returns an error when the file cannot be written", func(t *testing.T) {
+		err := WriteSecretsFile(filepath.Join(t.TempDir(), "missing", "secrets.yaml"), secrets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to write secrets file")
+	})
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Run("loads from explicit paths", func(t *testing.T) {
 		dir := t.TempDir()
@@ -215,12 +279,83 @@ func TestLoadConfig(t *testing.T) {
 
 		t.Setenv("WEBHOOK_CONFIG", "")
 		t.Setenv("WEBHOOK_SECRETS", "")
+		t.Setenv("WEBHOOK_SECRETS_YAML", "")
+		t.Setenv("GITHUB_TOKEN", "")
 
 		_, err := LoadConfig(cfgPath, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "secrets path is required")
 	})
 
+	t.Run("loads secrets from environment when no secrets path is given", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := writeFile(t, dir, "config.yaml", validAppConfig)
+
+		t.Setenv("WEBHOOK_SECRETS", "")
+		t.Setenv("WEBHOOK_SECRETS_YAML", validSecrets)
+		t.Setenv("GITHUB_TOKEN", "ghp_envtoken")
+
+		cfg, err := LoadConfig(cfgPath, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "secret-abc", cfg.WebhookSecretForRepo("acme", "my-service"))
+		assert.Equal(t, "secret-xyz", cfg.WebhookSecretForRepo("acme", "another-service"))
+		assert.Equal(t, "ghp_envtoken", cfg.Secrets.GithubToken)
+	})
+
+	t.Run("environment secrets layer over the secrets file", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := writeFile(t, dir, "config.yaml", validAppConfig)
+		secPath := writeFile(t, dir, "secrets.yaml", validSecrets+"githubToken: file-token\n")
+
+		t.Setenv("WEBHOOK_SECRETS_YAML", "repos:\n  - owner: acme\n    name: my-service\n    webhookSecret: env-secret\n")
+		t.Setenv("GITHUB_TOKEN", "")
+
+		cfg, err := LoadConfig(cfgPath, secPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, "env-secret", cfg.WebhookSecretForRepo("acme", "my-service"))
+		assert.Equal(t, "secret-xyz", cfg.WebhookSecretForRepo("acme", "another-service"))
+		assert.Equal(t, "file-token", cfg.Secrets.GithubToken)
+	})
+
+	t.Run("GITHUB_TOKEN overrides the file token", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := writeFile(t, dir, "config.yaml", validAppConfig)
+		secPath := writeFile(t, dir, "secrets.yaml", validSecrets+"githubToken: file-token\n")
+
+		t.Setenv("WEBHOOK_SECRETS_YAML", "")
+		t.Setenv("GITHUB_TOKEN", "env-token")
+
+		cfg, err := LoadConfig(cfgPath, secPath)
+		require.NoError(t, err)
+		assert.Equal(t, "env-token", cfg.Secrets.GithubToken)
+	})
+
+	t.Run("error on invalid WEBHOOK_SECRETS_YAML", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := writeFile(t, dir, "config.yaml", validAppConfig)
+
+		t.Setenv("WEBHOOK_SECRETS", "")
+		t.Setenv("WEBHOOK_SECRETS_YAML", "repos: [bad\n")
+
+		_, err := LoadConfig(cfgPath, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse WEBHOOK_SECRETS_YAML")
+	})
+
+	t.Run("error when app config is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := writeFile(t, dir, "config.yaml", "port: 0\nrepos:\n  - name: my-service\n    namespace: ns-a\n")
+		secPath := writeFile(t, dir, "secrets.yaml", validSecrets)
+
+		_, err := LoadConfig(cfgPath, secPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid app config")
+		assert.Contains(t, err.Error(), "ralphUser is required")
+		assert.Contains(t, err.Error(), "repos[0]: owner is required")
+	})
+
 	t.Run("error when secrets file missing", func(t *testing.T) {
 		dir := t.TempDir()
 		cfgPath := writeFile(t, dir, "config.yaml", validAppConfig)
@@ -231,6 +366,98 @@ func TestLoadConfig(t *testing.T) {
 	})
 }
 
+func TestAppConfigValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          AppConfig
+		wantContains []string
+	}{
+		{
+			name: "valid config",
+			cfg: AppConfig{
+				Port:      8080,
+				RalphUser: "ralph[bot]",
+				Repos:     []RepoConfig{{Owner: "acme", Name: "my-service", Namespace: "ns-a"}},
+			},
+		},
+		{
+			name: "missing repo owner",
+			cfg: AppConfig{
+				Port:      8080,
+				RalphUser: "ralph[bot]",
+				Repos:     []RepoConfig{{Owner: "acme", Name: "ok"}, {Name: "my-service"}},
+			},
+			wantContains: []string{"repos[1]: owner is required"},
+		},
+		{
+			name: "same-named repos under different owners",
+			cfg: AppConfig{
+				Port:      8080,
+				RalphUser: "ralph[bot]",
+				Repos:     []RepoConfig{{Owner: "acme", Name: "api"}, {Owner: "globex", Name: "api"}},
+			},
+		},
+		{
+			name: "repo listed twice",
+			cfg: AppConfig{
+				Port:      8080,
+				RalphUser: "ralph[bot]",
+				Repos:     []RepoConfig{{Owner: "acme", Name: "api"}, {Owner: "Acme", Name: "API"}},
+			},
+			wantContains: []string{"repos[1]: Acme/API is already configured by repos[0]"},
+		},
+		{
+			name:         "port out of range",
+			cfg:          AppConfig{Port: 70000, RalphUser: "ralph[bot]"},
+			wantContains: []string{"port 70000 is out of range"},
+		},
+		{
+			name:         "negative required approvals",
+			cfg:          AppConfig{Port: 8080, RalphUser: "ralph[bot]", RequiredApprovals: -1},
+			wantContains: []string{"requiredApprovals -1 must not be negative"},
+		},
+		{
+			name: "environments with bare hostnames",
+			cfg: AppConfig{Port: 8080, RalphUser: "ralph[bot]", Environments: map[string]Environment{
+				"staging": {Hostname: "ralph-staging.example.com"},
+				"prod":    {Hostname: "ralph.example.com"},
+			}},
+		},
+		{
+			name: "environment hostnames that are missing or not bare",
+			cfg: AppConfig{Port: 8080, RalphUser: "ralph[bot]", Environments: map[string]Environment{
+				"dev":     {},
+				"prod":    {Hostname: "https://ralph.example.com"},
+				"staging": {Hostname: "ralph-staging.example.com/webhook"},
+			}},
+			wantContains: []string{
+				"environments.dev: hostname is required",
+				`environments.prod: hostname "https://ralph.example.com" must be a bare hostname`,
+				`environments.staging: hostname "ralph-staging.example.com/webhook" must be a bare hostname`,
+			},
+		},
+		{
+			name:         "lists every problem",
+			cfg:          AppConfig{Repos: []RepoConfig{{}}},
+			wantContains: []string{"port 0 is out of range", "ralphUser is required", "repos[0]: owner is required", "repos[0]: name is required"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if len(tc.wantContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tc.wantContains {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
 func TestValidateConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -341,6 +568,21 @@ func TestWebhookSecretForRepo(t *testing.T) {
 	}
 }
 
+func TestPreviousWebhookSecretForRepo(t *testing.T) {
+	cfg := &Config{
+		Secrets: Secrets{
+			Repos: []RepoSecret{
+				{Owner: "acme", Name: "rotated", WebhookSecret: "new", PreviousSecret: "old"},
+				{Owner: "acme", Name: "fresh", WebhookSecret: "only"},
+			},
+		},
+	}
+
+	assert.Equal(t, "old", cfg.PreviousWebhookSecretForRepo("acme", "rotated"))
+	assert.Equal(t, "", cfg.PreviousWebhookSecretForRepo("acme", "fresh"))
+	assert.Equal(t, "", cfg.PreviousWebhookSecretForRepo("acme", "nonexistent"))
+}
+
 func TestIsUserAllowed(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -392,6 +634,53 @@ func TestIsUserIgnored(t *testing.T) {
 	}
 }
 
+func TestRepoConfigIsUserAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  RepoConfig
+		login string
+		want  bool
+	}{
+		{"empty list allows everyone", RepoConfig{}, "alice", true},
+		{"listed user allowed", RepoConfig{AllowedUsers: []string{"alice"}}, "Alice", true},
+		{"unlisted user denied", RepoConfig{AllowedUsers: []string{"alice"}}, "mallory", false},
+		{"list emptied by exclusion denies everyone", RepoConfig{Populated: true}, "alice", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.repo.IsUserAllowed(tc.login))
+		})
+	}
+}
+
+func TestIsUserExcluded(t *testing.T) {
+	tests := []struct {
+		name         string
+		excludeUsers []string
+		excludeBots  bool
+		login        string
+		want         bool
+	}{
+		{"nothing excluded by default", nil, false, "dependabot[bot]", false},
+		{"bots excluded when enabled", nil, true, "dependabot[bot]", true},
+		{"normal user kept when bots excluded", nil, true, "alice", false},
+		{"listed user excluded", []string{"ci-user"}, false, "ci-user", true},
+		{"listed user comparison is case-insensitive", []string{"CI-User"}, false, "ci-user", true},
+		{"glob pattern excluded", []string{"svc-*"}, false, "svc-deploy", true},
+		{"bot login listed literally", []string{"renovate[bot]"}, false, "renovate[bot]", true},
+		{"unlisted user kept", []string{"ci-user", "svc-*"}, false, "alice", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			excludeBots := tc.excludeBots
+			cfg := &AppConfig{ExcludeUsers: tc.excludeUsers, ExcludeBots: &excludeBots}
+			assert.Equal(t, tc.want, cfg.IsUserExcluded(tc.login))
+		})
+	}
+}
+
 func TestRepoConfig_Namespace(t *testing.T) {
 	const configWithNamespace = `
 port: 8080
@@ -0,0 +1,34 @@
+package webhookconfig
+
+import (
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// DiffAppConfig returns a unified diff between the YAML of base and updated.
+// A nil base is treated as an empty configmap. The diff is empty when nothing changed.
+func DiffAppConfig(base *AppConfig, updated AppConfig) (string, error) {
+	var before string
+	if base != nil {
+		data, err := yaml.Marshal(base)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal existing AppConfig: %w", err)
+		}
+		before = string(data)
+	}
+
+	after, err := yaml.Marshal(updated)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal new AppConfig: %w", err)
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: WebhookConfigMapName + " (current)",
+		ToFile:   WebhookConfigMapName + " (new)",
+		Context:  3,
+	})
+}
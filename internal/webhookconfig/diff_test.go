@@ -0,0 +1,96 @@
+package webhookconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zon/ralph/internal/github"
+	"github.com/zon/ralph/internal/k8s"
+)
+
+func TestDiffAppConfig(t *testing.T) {
+	t.Run("shows added repos and changed fields", func(t *testing.T) {
+		base := &AppConfig{
+			Port:      8080,
+			RalphUser: "ralph[bot]",
+			Repos: []RepoConfig{
+				{Owner: "acme", Name: "api", Namespace: "api-ns"},
+			},
+		}
+		updated := AppConfig{
+			Port:      9090,
+			RalphUser: "ralph[bot]",
+			Repos: []RepoConfig{
+				{Owner: "acme", Name: "api", Namespace: "api-ns"},
+				{Owner: "acme", Name: "web", Namespace: "web-ns"},
+			},
+		}
+
+		diff, err := DiffAppConfig(base, updated)
+		require.NoError(t, err)
+
+		assert.Contains(t, diff, "--- webhook-config (current)")
+		assert.Contains(t, diff, "+++ webhook-config (new)")
+		assert.Contains(t, diff, "-port: 8080")
+		assert.Contains(t, diff, "+port: 9090")
+		assert.Contains(t, diff, "+      name: web")
+		assert.Contains(t, diff, "+      namespace: web-ns")
+		assert.NotContains(t, diff, "-      name: api")
+	})
+
+	t.Run("is empty when nothing changed", func(t *testing.T) {
+		cfg := AppConfig{Port: 8080, Repos: []RepoConfig{{Owner: "acme", Name: "api"}}}
+
+		diff, err := DiffAppConfig(&cfg, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("shows every line as added without a base", func(t *testing.T) {
+		diff, err := DiffAppConfig(nil, AppConfig{Port: 8080})
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+port: 8080")
+		assert.NotContains(t, diff, "\n-")
+	})
+}
+
+func TestDiffWebhookAppConfigFromK8s(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("diffs the partial config against the existing configmap", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, _, _, _ string) (string, error) {
+				return "port: 8080\nralphUser: ralph[bot]\nrepos:\n  - owner: acme\n    name: api\n    namespace: old-ns\n    allowedUsers: [alice]\n", nil
+			},
+		}
+		dir := t.TempDir()
+		path := filepath.Join(dir, "partial.yaml")
+		partial := "repos:\n  - owner: acme\n    name: api\n    namespace: new-ns\n    allowedUsers: [alice]\n  - owner: acme\n    name: web\n    namespace: web-ns\n    allowedUsers: [bob]\n"
+		require.NoError(t, os.WriteFile(path, []byte(partial), 0644))
+
+		diff, err := DiffWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", path, nil, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, diff, "-      namespace: old-ns")
+		assert.Contains(t, diff, "+      namespace: new-ns")
+		assert.Contains(t, diff, "+      name: web")
+		assert.NotContains(t, diff, "port: 8080")
+	})
+
+	t.Run("diffs against an empty config when the configmap cannot be read", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, _, _, _ string) (string, error) {
+				return "", fmt.Errorf("configmap not found")
+			},
+		}
+
+		diff, err := DiffWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", "", nil, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+port: 8080")
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/github"
@@ -29,11 +31,48 @@ func mergeRepo(repos []RepoConfig, incoming RepoConfig) []RepoConfig {
 	return append(repos, incoming)
 }
 
+func removeRepo(repos []RepoConfig, owner, name string) ([]RepoConfig, bool) {
+	for i, r := range repos {
+		if r.Owner == owner && r.Name == name {
+			return append(repos[:i:i], repos[i+1:]...), true
+		}
+	}
+	return repos, false
+}
+
+// ParseRepoFullName splits an "owner/name" repository reference.
+func ParseRepoFullName(fullName string) (string, string, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q: expected owner/name", fullName)
+	}
+	return owner, name, nil
+}
+
+// RemoveRepos drops each "owner/name" in fullNames from cfg.Repos. A repo that is not
+// configured is skipped with a warning.
+func RemoveRepos(cfg *AppConfig, fullNames []string, out *output.Client) error {
+	for _, fullName := range fullNames {
+		owner, name, err := ParseRepoFullName(fullName)
+		if err != nil {
+			return err
+		}
+		var removed bool
+		cfg.Repos, removed = removeRepo(cfg.Repos, owner, name)
+		if !removed && out != nil {
+			out.Warnf("Repository %s/%s is not configured (nothing to remove)", owner, name)
+		}
+	}
+	return nil
+}
+
 func BuildWebhookAppConfig(ctx context.Context, out *output.Client, base, updates *AppConfig, repoOwner, repoName, repoNamespace string, gh github.GHClient) AppConfig {
 	var cfg AppConfig
 
 	if base != nil {
 		cfg = *base
+		// copy the repos so merging updates never rewrites the caller's base
+		cfg.Repos = append([]RepoConfig(nil), base.Repos...)
 	}
 
 	if updates != nil {
@@ -46,6 +85,21 @@ func BuildWebhookAppConfig(ctx context.Context, out *output.Client, base, update
 		if updates.CommentInstructionsFile != "" {
 			cfg.CommentInstructionsFile = updates.CommentInstructionsFile
 		}
+		if updates.ExcludeUsers != nil {
+			cfg.ExcludeUsers = updates.ExcludeUsers
+		}
+		if updates.ExcludeBots != nil {
+			cfg.ExcludeBots = updates.ExcludeBots
+		}
+		if updates.RequiredApprovals != 0 {
+			cfg.RequiredApprovals = updates.RequiredApprovals
+		}
+		if updates.MergeLabel != "" {
+			cfg.MergeLabel = updates.MergeLabel
+		}
+		if updates.Environments != nil {
+			cfg.Environments = updates.Environments
+		}
 		for _, r := range updates.Repos {
 			cfg.Repos = mergeRepo(cfg.Repos, r)
 		}
@@ -67,31 +121,90 @@ func BuildWebhookAppConfig(ctx context.Context, out *output.Client, base, update
 		})
 	}
 
-	for i, r := range cfg.Repos {
-		if len(r.AllowedUsers) == 0 {
-			users, err := gh.ListCollaborators(ctx, r.Owner, r.Name)
-			if err != nil {
-				if out != nil {
-					out.Warnf("Failed to fetch collaborators for %s/%s: %v (skipping AllowedUsers)", r.Owner, r.Name, err)
+	populateAllowedUsers(ctx, out, &cfg, gh)
+
+	return cfg
+}
+
+func excludeUsers(cfg *AppConfig, users []string) []string {
+	var kept []string
+	for _, u := range users {
+		if !cfg.IsUserExcluded(u) {
+			kept = append(kept, u)
+		}
+	}
+	return kept
+}
+
+// collaboratorWorkers bounds how many collaborator lookups run against the GitHub API at once
+const collaboratorWorkers = 8
+
+// populateAllowedUsers fills in the AllowedUsers of every repo that has none with its
+// collaborators that cfg does not exclude, fetching them concurrently. Repos whose lookup fails
+// keep no AllowedUsers and are warned about in repo order.
+func populateAllowedUsers(ctx context.Context, out *output.Client, cfg *AppConfig, gh github.GHClient) {
+	repos := cfg.Repos
+	errs := make([]error, len(repos))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(collaboratorWorkers, len(repos)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				users, err := gh.ListCollaborators(ctx, repos[i].Owner, repos[i].Name)
+				if err != nil {
+					errs[i] = err
+					continue
 				}
-			} else {
-				cfg.Repos[i].AllowedUsers = users
+				repos[i].AllowedUsers = excludeUsers(cfg, users)
+				repos[i].Populated = true
 			}
+		}()
+	}
+	for i, r := range repos {
+		if len(r.AllowedUsers) == 0 {
+			indexes <- i
 		}
 	}
+	close(indexes)
+	wg.Wait()
 
-	return cfg
+	for i, err := range errs {
+		if err != nil && out != nil {
+			out.Warnf("Failed to fetch collaborators for %s/%s: %v (skipping AllowedUsers)", repos[i].Owner, repos[i].Name, err)
+		}
+	}
+}
+
+func BuildWebhookAppConfigFromK8s(ctx context.Context, namespace, kubeContext, configPath string, remove []string, client k8s.Client, ghClient github.GHClient, out *output.Client) (AppConfig, error) {
+	base := readWebhookConfigBase(ctx, client, namespace, kubeContext, out)
+	return buildWebhookAppConfigFromBase(ctx, base, configPath, remove, ghClient, out)
+}
+
+// DiffWebhookAppConfigFromK8s builds the config exactly as BuildWebhookAppConfigFromK8s does
+// and returns a unified diff of it against the existing configmap, without writing anything.
+func DiffWebhookAppConfigFromK8s(ctx context.Context, namespace, kubeContext, configPath string, remove []string, client k8s.Client, ghClient github.GHClient, out *output.Client) (string, error) {
+	base := readWebhookConfigBase(ctx, client, namespace, kubeContext, out)
+	cfg, err := buildWebhookAppConfigFromBase(ctx, base, configPath, remove, ghClient, out)
+	if err != nil {
+		return "", err
+	}
+	return DiffAppConfig(base, cfg)
 }
 
-func BuildWebhookAppConfigFromK8s(ctx context.Context, namespace, kubeContext, configPath string, client k8s.Client, ghClient github.GHClient, out *output.Client) AppConfig {
+func readWebhookConfigBase(ctx context.Context, client k8s.Client, namespace, kubeContext string, out *output.Client) *AppConfig {
 	base, err := ReadWebhookConfigFromK8s(ctx, client, namespace, kubeContext)
 	if err != nil {
 		if out != nil {
 			out.Warnf("Could not read existing configmap '%s': %v (starting from scratch)", WebhookConfigMapName, err)
 		}
-		base = nil
+		return nil
 	}
+	return base
+}
 
+func buildWebhookAppConfigFromBase(ctx context.Context, base *AppConfig, configPath string, remove []string, ghClient github.GHClient, out *output.Client) (AppConfig, error) {
 	var updates *AppConfig
 	if configPath != "" {
 		loaded, err := LoadAppConfig(configPath)
@@ -104,7 +217,11 @@ func BuildWebhookAppConfigFromK8s(ctx context.Context, namespace, kubeContext, c
 		}
 	}
 
-	return BuildWebhookAppConfig(ctx, out, base, updates, "", "", "", ghClient)
+	cfg := BuildWebhookAppConfig(ctx, out, base, updates, "", "", "", ghClient)
+	if err := RemoveRepos(&cfg, remove, out); err != nil {
+		return AppConfig{}, err
+	}
+	return cfg, nil
 }
 
 func GenerateWebhookSecret() (string, error) {
@@ -112,7 +229,9 @@ func GenerateWebhookSecret() (string, error) {
 	if _, err := rand.Read(b); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
-	return base64.RawURLEncoding.EncodeToString(b), nil
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	output.RegisterSecret(secret)
+	return secret, nil
 }
 
 func ReadWebhookConfigFromK8s(ctx context.Context, client k8s.Client, namespace, kubeContext string) (*AppConfig, error) {
@@ -129,12 +248,70 @@ func ReadWebhookConfigFromK8s(ctx context.Context, client k8s.Client, namespace,
 	return &appCfg, nil
 }
 
+func ReadWebhookSecretsFromK8s(ctx context.Context, client k8s.Client, namespace, kubeContext string) (*Secrets, error) {
+	raw, err := client.GetSecretData(ctx, WebhookSecretsSecretName, namespace, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret '%s' from namespace '%s': %w", WebhookSecretsSecretName, namespace, err)
+	}
+
+	var secrets Secrets
+	if err := yaml.Unmarshal([]byte(raw), &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse Secrets YAML from secret: %w", err)
+	}
+	secrets.registerForRedaction()
+
+	return &secrets, nil
+}
+
 func RegisterGitHubWebhook(ctx context.Context, gh github.GHClient, owner, repo, webhookURL, secret string) error {
 	return gh.RegisterWebhook(ctx, owner, repo, webhookURL, secret)
 }
 
-func RegisterAllGitHubWebhooks(ctx context.Context, ghClient github.GHClient, out *output.Client, repos []RepoSecret) {
-	webhookURL := fmt.Sprintf("https://%s/webhook", WebhookIngressHostname)
+func deleteGitHubWebhook(ctx context.Context, gh github.GHClient, owner, repo, webhookURL string) error {
+	return gh.DeleteWebhook(ctx, owner, repo, webhookURL)
+}
+
+// IngressWebhookURL returns the URL GitHub delivers webhooks to. env names one of the
+// environments in appCfg; empty selects the default deployment at WebhookIngressHostname.
+func IngressWebhookURL(appCfg *AppConfig, env string) (string, error) {
+	if env == "" {
+		return webhookURLForHost(WebhookIngressHostname), nil
+	}
+	var environments map[string]Environment
+	if appCfg != nil {
+		environments = appCfg.Environments
+	}
+	environment, ok := environments[env]
+	if !ok {
+		if len(environments) == 0 {
+			return "", fmt.Errorf("unknown webhook environment %q: no environments are set in configmap '%s'", env, WebhookConfigMapName)
+		}
+		return "", fmt.Errorf("unknown webhook environment %q (configured: %s)", env, strings.Join(environmentNames(environments), ", "))
+	}
+	if err := validateHostname(env, environment.Hostname); err != nil {
+		return "", err
+	}
+	return webhookURLForHost(environment.Hostname), nil
+}
+
+// IngressWebhookURLFromK8s returns IngressWebhookURL for the app config in the existing
+// configmap. The configmap is only read when env selects an environment.
+func IngressWebhookURLFromK8s(ctx context.Context, client k8s.Client, namespace, kubeContext, env string) (string, error) {
+	if env == "" {
+		return IngressWebhookURL(nil, env)
+	}
+	appCfg, err := ReadWebhookConfigFromK8s(ctx, client, namespace, kubeContext)
+	if err != nil {
+		return "", err
+	}
+	return IngressWebhookURL(appCfg, env)
+}
+
+func webhookURLForHost(hostname string) string {
+	return fmt.Sprintf("https://%s/webhook", hostname)
+}
+
+func RegisterAllGitHubWebhooks(ctx context.Context, ghClient github.GHClient, out *output.Client, webhookURL string, repos []RepoSecret) {
 	out.Infof("Registering webhooks at %s...", webhookURL)
 	for _, rs := range repos {
 		if err := RegisterGitHubWebhook(ctx, ghClient, rs.Owner, rs.Name, webhookURL, rs.WebhookSecret); err != nil {
@@ -146,10 +323,46 @@ func RegisterAllGitHubWebhooks(ctx context.Context, ghClient github.GHClient, ou
 	out.Info("")
 }
 
-func BuildWebhookSecrets(appCfg *AppConfig, secretGenerator func() (string, error)) (*Secrets, error) {
+// UnregisterGitHubWebhooks deletes the ralph webhook at webhookURL from each "owner/name" in fullNames.
+// Failures are warned about and do not stop the remaining repos.
+func UnregisterGitHubWebhooks(ctx context.Context, ghClient github.GHClient, out *output.Client, webhookURL string, fullNames []string) {
+	out.Infof("Removing webhooks at %s...", webhookURL)
+	for _, fullName := range fullNames {
+		owner, name, err := ParseRepoFullName(fullName)
+		if err != nil {
+			out.Warnf("Skipping webhook removal: %v", err)
+			continue
+		}
+		if err := deleteGitHubWebhook(ctx, ghClient, owner, name, webhookURL); err != nil {
+			out.Warnf("Failed to remove webhook for %s/%s: %v", owner, name, err)
+		} else {
+			out.Successf("Webhook removed for %s/%s", owner, name)
+		}
+	}
+	out.Info("")
+}
+
+// BuildWebhookSecrets returns the secrets for every repo in appCfg. Repos that already have a
+// secret in existing keep it, along with its PreviousSecret, so re-running set config neither
+// breaks deliveries in flight nor ends a rotation's grace period; new repos get a generated
+// secret. The trigger and GitHub tokens are carried over from existing, and a trigger token
+// is generated when there is none yet. existing may be nil.
+func BuildWebhookSecrets(appCfg *AppConfig, existing *Secrets, secretGenerator func() (string, error)) (*Secrets, error) {
 	secrets := &Secrets{}
+	current := make(map[string]RepoSecret)
+	if existing != nil {
+		secrets.TriggerToken = existing.TriggerToken
+		secrets.GithubToken = existing.GithubToken
+		for _, rs := range existing.Repos {
+			current[repoKey(rs.Owner, rs.Name)] = rs
+		}
+	}
 
 	for _, repo := range appCfg.Repos {
+		if rs, ok := current[repoKey(repo.Owner, repo.Name)]; ok && rs.WebhookSecret != "" {
+			secrets.Repos = append(secrets.Repos, rs)
+			continue
+		}
 		secret, err := secretGenerator()
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate secret for %s/%s: %w", repo.Owner, repo.Name, err)
@@ -161,9 +374,64 @@ func BuildWebhookSecrets(appCfg *AppConfig, secretGenerator func() (string, erro
 		})
 	}
 
+	if secrets.TriggerToken == "" {
+		token, err := secretGenerator()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate trigger token: %w", err)
+		}
+		secrets.TriggerToken = token
+	}
+
 	return secrets, nil
 }
 
+// ReadExistingWebhookSecretsFromK8s returns the secrets stored in the webhook secret, or empty
+// secrets when the secret does not exist yet. Any other read failure is returned, so a
+// transient error never causes the stored tokens to be overwritten.
+func ReadExistingWebhookSecretsFromK8s(ctx context.Context, client k8s.Client, namespace, kubeContext string) (*Secrets, error) {
+	exists, err := client.SecretExists(ctx, WebhookSecretsSecretName, namespace, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check secret '%s' in namespace '%s': %w", WebhookSecretsSecretName, namespace, err)
+	}
+	if !exists {
+		return &Secrets{}, nil
+	}
+	return ReadWebhookSecretsFromK8s(ctx, client, namespace, kubeContext)
+}
+
+// RotateWebhookSecrets gives the repo named by fullName ("owner/name"), or every repo when
+// fullName is empty, a new secret and keeps the replaced one as PreviousSecret. It returns
+// the rotated entries.
+func RotateWebhookSecrets(secrets *Secrets, fullName string, secretGenerator func() (string, error)) ([]RepoSecret, error) {
+	var owner, name string
+	if fullName != "" {
+		var err error
+		owner, name, err = ParseRepoFullName(fullName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rotated []RepoSecret
+	for i, rs := range secrets.Repos {
+		if fullName != "" && (rs.Owner != owner || rs.Name != name) {
+			continue
+		}
+		secret, err := secretGenerator()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret for %s/%s: %w", rs.Owner, rs.Name, err)
+		}
+		secrets.Repos[i].PreviousSecret = rs.WebhookSecret
+		secrets.Repos[i].WebhookSecret = secret
+		rotated = append(rotated, secrets.Repos[i])
+	}
+
+	if fullName != "" && len(rotated) == 0 {
+		return nil, fmt.Errorf("no webhook secret for %s/%s", owner, name)
+	}
+	return rotated, nil
+}
+
 func WriteWebhookConfigMap(ctx context.Context, client k8s.Client, kubeContext, namespace string, appCfg AppConfig) error {
 	cfgBytes, err := yaml.Marshal(appCfg)
 	if err != nil {
@@ -209,14 +477,3 @@ func WriteWebhookSecrets(ctx context.Context, client k8s.Client, kubeContext, na
 
 	return nil
 }
-
-func GetKubeContext(ctx context.Context, client k8s.Client, contextOverride string) (string, error) {
-	if contextOverride != "" {
-		return contextOverride, nil
-	}
-	currentCtx, err := client.GetCurrentContext(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get current Kubernetes context: %w", err)
-	}
-	return currentCtx.Name, nil
-}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -108,6 +110,21 @@ func TestBuildWebhookAppConfig(t *testing.T) {
 		assert.Equal(t, "new-ns", cfg.Repos[0].Namespace)
 	})
 
+	t.Run("same-named repo under another owner is kept separately", func(t *testing.T) {
+		base := &AppConfig{
+			Repos: []RepoConfig{
+				{Owner: "acme", Name: "api", Namespace: "ns-acme"},
+			},
+		}
+		cfg := BuildWebhookAppConfig(ctx, nil, base, nil, "globex", "api", "ns-globex", &github.MockGH{})
+
+		require.Len(t, cfg.Repos, 2)
+		full := &Config{App: cfg}
+		assert.Equal(t, "ns-acme", full.RepoByFullName("acme", "api").Namespace)
+		assert.Equal(t, "ns-globex", full.RepoByFullName("globex", "api").Namespace)
+		assert.NoError(t, cfg.Validate())
+	})
+
 	t.Run("auto-detected repo adds alongside existing repos", func(t *testing.T) {
 		base := &AppConfig{
 			Repos: []RepoConfig{
@@ -148,6 +165,92 @@ func TestBuildWebhookAppConfig(t *testing.T) {
 		assert.Equal(t, []string{"alice", "bob"}, cfg.Repos[0].AllowedUsers)
 	})
 
+	t.Run("updates override requiredApprovals", func(t *testing.T) {
+		base := &AppConfig{RequiredApprovals: 2}
+		cfg := BuildWebhookAppConfig(ctx, nil, base, &AppConfig{RequiredApprovals: 3}, "", "", "", &github.MockGH{})
+		assert.Equal(t, 3, cfg.RequiredApprovals)
+
+		cfg = BuildWebhookAppConfig(ctx, nil, base, &AppConfig{}, "", "", "", &github.MockGH{})
+		assert.Equal(t, 2, cfg.RequiredApprovals)
+	})
+
+	t.Run("updates replace environments", func(t *testing.T) {
+		base := &AppConfig{Environments: map[string]Environment{"prod": {Hostname: "ralph.example.com"}}}
+		staging := map[string]Environment{"staging": {Hostname: "ralph-staging.example.com"}}
+		cfg := BuildWebhookAppConfig(ctx, nil, base, &AppConfig{Environments: staging}, "", "", "", &github.MockGH{})
+		assert.Equal(t, staging, cfg.Environments)
+
+		cfg = BuildWebhookAppConfig(ctx, nil, base, &AppConfig{}, "", "", "", &github.MockGH{})
+		assert.Equal(t, base.Environments, cfg.Environments)
+	})
+
+	t.Run("filters excluded users and bots from fetched collaborators", func(t *testing.T) {
+		excludeBots := true
+		updates := &AppConfig{ExcludeUsers: []string{"svc-*"}, ExcludeBots: &excludeBots}
+		gh := &github.MockGH{
+			ListCollaboratorsFn: func(_ context.Context, owner, repo string) ([]string, error) {
+				return []string{"alice", "dependabot[bot]", "svc-deploy", "bob"}, nil
+			},
+		}
+		cfg := BuildWebhookAppConfig(ctx, nil, nil, updates, "my-owner", "my-repo", "my-ns", gh)
+
+		require.Len(t, cfg.Repos, 1)
+		assert.Equal(t, []string{"alice", "bob"}, cfg.Repos[0].AllowedUsers)
+		assert.True(t, cfg.Repos[0].Populated)
+		assert.Equal(t, []string{"svc-*"}, cfg.ExcludeUsers)
+		require.NotNil(t, cfg.ExcludeBots)
+		assert.True(t, *cfg.ExcludeBots)
+	})
+
+	t.Run("denies everyone when every collaborator is excluded", func(t *testing.T) {
+		excludeBots := true
+		updates := &AppConfig{ExcludeUsers: []string{"svc-*"}, ExcludeBots: &excludeBots}
+		gh := &github.MockGH{
+			ListCollaboratorsFn: func(_ context.Context, owner, repo string) ([]string, error) {
+				return []string{"dependabot[bot]", "svc-deploy"}, nil
+			},
+		}
+		cfg := BuildWebhookAppConfig(ctx, nil, nil, updates, "my-owner", "my-repo", "my-ns", gh)
+
+		require.Len(t, cfg.Repos, 1)
+		assert.Empty(t, cfg.Repos[0].AllowedUsers)
+		assert.False(t, cfg.Repos[0].IsUserAllowed("dependabot[bot]"))
+		assert.False(t, cfg.Repos[0].IsUserAllowed("alice"))
+
+		data, err := yaml.Marshal(cfg)
+		require.NoError(t, err)
+		var roundTripped AppConfig
+		require.NoError(t, yaml.Unmarshal(data, &roundTripped))
+		assert.False(t, roundTripped.Repos[0].IsUserAllowed("alice"), "the denial survives the configmap round trip")
+	})
+
+	t.Run("an update can turn excludeBots off", func(t *testing.T) {
+		on, off := true, false
+		base := &AppConfig{ExcludeBots: &on}
+
+		cfg := BuildWebhookAppConfig(ctx, nil, base, &AppConfig{ExcludeBots: &off}, "", "", "", &github.MockGH{})
+		require.NotNil(t, cfg.ExcludeBots)
+		assert.False(t, *cfg.ExcludeBots)
+
+		cfg = BuildWebhookAppConfig(ctx, nil, base, &AppConfig{}, "", "", "", &github.MockGH{})
+		require.NotNil(t, cfg.ExcludeBots)
+		assert.True(t, *cfg.ExcludeBots, "an update that leaves excludeBots unset keeps it")
+	})
+
+	t.Run("does not filter explicitly configured AllowedUsers", func(t *testing.T) {
+		excludeBots := true
+		base := &AppConfig{
+			ExcludeBots: &excludeBots,
+			Repos: []RepoConfig{
+				{Owner: "my-owner", Name: "my-repo", Namespace: "my-ns", AllowedUsers: []string{"deploy[bot]"}},
+			},
+		}
+		cfg := BuildWebhookAppConfig(ctx, nil, base, nil, "", "", "", &github.MockGH{})
+
+		require.Len(t, cfg.Repos, 1)
+		assert.Equal(t, []string{"deploy[bot]"}, cfg.Repos[0].AllowedUsers)
+	})
+
 	t.Run("does not override existing AllowedUsers from base", func(t *testing.T) {
 		base := &AppConfig{
 			Repos: []RepoConfig{
@@ -177,6 +280,51 @@ func TestBuildWebhookAppConfig(t *testing.T) {
 		assert.Empty(t, cfg.Repos[0].AllowedUsers)
 	})
 
+	t.Run("fetches collaborators of many repos concurrently in repo order", func(t *testing.T) {
+		base := &AppConfig{}
+		for i := 0; i < 20; i++ {
+			base.Repos = append(base.Repos, RepoConfig{Owner: "acme", Name: fmt.Sprintf("repo-%d", i)})
+		}
+		base.Repos[3].AllowedUsers = []string{"existing-user"}
+
+		var mu sync.Mutex
+		var inFlight, maxInFlight int
+		gh := &github.MockGH{
+			ListCollaboratorsFn: func(_ context.Context, owner, repo string) ([]string, error) {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				if repo == "repo-7" {
+					return nil, fmt.Errorf("API error")
+				}
+				return []string{repo + "-dev"}, nil
+			},
+		}
+		var outBuf, errBuf bytes.Buffer
+		cfg := BuildWebhookAppConfig(ctx, output.NewClient(&outBuf, &errBuf, false), base, nil, "", "", "", gh)
+
+		require.Len(t, cfg.Repos, 20)
+		for i, r := range cfg.Repos {
+			assert.Equal(t, fmt.Sprintf("repo-%d", i), r.Name)
+			switch i {
+			case 3:
+				assert.Equal(t, []string{"existing-user"}, r.AllowedUsers)
+			case 7:
+				assert.Empty(t, r.AllowedUsers)
+			default:
+				assert.Equal(t, []string{r.Name + "-dev"}, r.AllowedUsers)
+			}
+		}
+		assert.Greater(t, maxInFlight, 1)
+		assert.LessOrEqual(t, maxInFlight, collaboratorWorkers)
+		assert.Contains(t, outBuf.String()+errBuf.String(), "Failed to fetch collaborators for acme/repo-7")
+	})
+
 	t.Run("sets RalphUser to DefaultAppName[bot] by default", func(t *testing.T) {
 		cfg := BuildWebhookAppConfig(ctx, nil, nil, nil, "", "", "", &github.MockGH{})
 
@@ -247,7 +395,8 @@ func TestBuildWebhookAppConfigFromK8s(t *testing.T) {
 				return "", fmt.Errorf("configmap not found")
 			},
 		}
-		cfg := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", "", client, &github.MockGH{}, nil)
+		cfg, err := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", "", nil, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
 
 		assert.Equal(t, 8080, cfg.Port)
 		assert.Equal(t, config.DefaultAppName+"[bot]", cfg.RalphUser)
@@ -264,7 +413,8 @@ func TestBuildWebhookAppConfigFromK8s(t *testing.T) {
 		path := filepath.Join(dir, "partial.yaml")
 		require.NoError(t, os.WriteFile(path, []byte(partialYAML), 0644))
 
-		cfg := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", path, client, &github.MockGH{}, nil)
+		cfg, err := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", path, nil, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
 
 		assert.Equal(t, 7070, cfg.Port)
 	})
@@ -278,12 +428,52 @@ func TestBuildWebhookAppConfigFromK8s(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "nonexistent.yaml")
 
-		cfg := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", path, client, &github.MockGH{}, nil)
+		cfg, err := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", path, nil, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
 
 		assert.Equal(t, 8080, cfg.Port)
 	})
 }
 
+func TestRemoveRepos(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("drops the repo from the built config", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, _, _, _ string) (string, error) {
+				return "repos:\n  - owner: acme\n    name: api\n    allowedUsers: [alice]\n  - owner: acme\n    name: web\n    allowedUsers: [bob]\n", nil
+			},
+		}
+
+		cfg, err := BuildWebhookAppConfigFromK8s(ctx, "test-ns", "test-ctx", "", []string{"acme/api"}, client, &github.MockGH{}, nil)
+		require.NoError(t, err)
+
+		require.Len(t, cfg.Repos, 1)
+		assert.Equal(t, "web", cfg.Repos[0].Name)
+	})
+
+	t.Run("warns and keeps the config when the repo is not configured", func(t *testing.T) {
+		var outBuf, errBuf bytes.Buffer
+		out := output.NewClient(&outBuf, &errBuf, false)
+		cfg := AppConfig{Repos: []RepoConfig{{Owner: "acme", Name: "api"}}}
+
+		err := RemoveRepos(&cfg, []string{"acme/missing"}, out)
+		require.NoError(t, err)
+
+		assert.Len(t, cfg.Repos, 1)
+		assert.Contains(t, outBuf.String()+errBuf.String(), "acme/missing is not configured")
+	})
+
+	t.Run("rejects a malformed repo reference", func(t *testing.T) {
+		cfg := AppConfig{Repos: []RepoConfig{{Owner: "acme", Name: "api"}}}
+
+		err := RemoveRepos(&cfg, []string{"acme"}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected owner/name")
+		assert.Len(t, cfg.Repos, 1)
+	})
+}
+
 func TestRegisterGitHubWebhook(t *testing.T) {
 	ctx := context.Background()
 
@@ -320,14 +510,92 @@ func TestRegisterGitHubWebhook(t *testing.T) {
 	})
 }
 
+func TestIngressWebhookURL(t *testing.T) {
+	appCfg := &AppConfig{
+		Environments: map[string]Environment{
+			"staging": {Hostname: "ralph-staging.example.com"},
+			"prod":    {Hostname: "ralph.example.com"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		appCfg  *AppConfig
+		env     string
+		want    string
+		wantErr string
+	}{
+		{name: "default deployment", appCfg: appCfg, want: "https://" + WebhookIngressHostname + "/webhook"},
+		{name: "default without config", want: "https://" + WebhookIngressHostname + "/webhook"},
+		{name: "staging", appCfg: appCfg, env: "staging", want: "https://ralph-staging.example.com/webhook"},
+		{name: "prod", appCfg: appCfg, env: "prod", want: "https://ralph.example.com/webhook"},
+		{name: "unknown environment", appCfg: appCfg, env: "dev", wantErr: `unknown webhook environment "dev" (configured: prod, staging)`},
+		{name: "no environments configured", appCfg: &AppConfig{}, env: "prod", wantErr: "no environments are set in configmap 'webhook-config'"},
+		{name: "no config", env: "prod", wantErr: "no environments are set"},
+		{name: "hostname with scheme", appCfg: &AppConfig{Environments: map[string]Environment{"prod": {Hostname: "https://ralph.example.com"}}}, env: "prod", wantErr: "must be a bare hostname"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IngressWebhookURL(tt.appCfg, tt.env)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIngressWebhookURLFromK8s(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reads environments from the configmap", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, name, namespace, kubeContext string) (string, error) {
+				return "environments:\n  staging:\n    hostname: ralph-staging.example.com\n", nil
+			},
+		}
+		got, err := IngressWebhookURLFromK8s(ctx, client, "ns", "ctx", "staging")
+		require.NoError(t, err)
+		assert.Equal(t, "https://ralph-staging.example.com/webhook", got)
+	})
+
+	t.Run("default deployment does not read the configmap", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, name, namespace, kubeContext string) (string, error) {
+				return "", fmt.Errorf("configmap not found")
+			},
+		}
+		got, err := IngressWebhookURLFromK8s(ctx, client, "ns", "ctx", "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://"+WebhookIngressHostname+"/webhook", got)
+	})
+
+	t.Run("returns error when the configmap cannot be read", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetConfigMapDataFunc: func(_ context.Context, name, namespace, kubeContext string) (string, error) {
+				return "", fmt.Errorf("configmap not found")
+			},
+		}
+		_, err := IngressWebhookURLFromK8s(ctx, client, "ns", "ctx", "staging")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "configmap not found")
+	})
+}
+
 func TestRegisterAllGitHubWebhooks(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("calls Successf for each successful registration", func(t *testing.T) {
 		var outBuf, errBuf bytes.Buffer
 		out := output.NewClient(&outBuf, &errBuf, false)
+		var urls []string
 		gh := &github.MockGH{
 			RegisterWebhookFn: func(_ context.Context, owner, repo, webhookURL, secret string) error {
+				urls = append(urls, webhookURL)
 				return nil
 			},
 		}
@@ -336,11 +604,13 @@ func TestRegisterAllGitHubWebhooks(t *testing.T) {
 			{Owner: "acme", Name: "repo-a", WebhookSecret: "s3kr3t1"},
 			{Owner: "acme", Name: "repo-b", WebhookSecret: "s3kr3t2"},
 		}
-		RegisterAllGitHubWebhooks(ctx, gh, out, repos)
+		RegisterAllGitHubWebhooks(ctx, gh, out, "https://ralph.example.com/webhook", repos)
 
 		output := outBuf.String()
 		assert.Contains(t, output, "✓ Webhook registered for acme/repo-a")
 		assert.Contains(t, output, "✓ Webhook registered for acme/repo-b")
+		assert.Contains(t, output, "Registering webhooks at https://ralph.example.com/webhook")
+		assert.Equal(t, []string{"https://ralph.example.com/webhook", "https://ralph.example.com/webhook"}, urls)
 	})
 
 	t.Run("calls Warnf for failed registration and continues", func(t *testing.T) {
@@ -361,7 +631,7 @@ func TestRegisterAllGitHubWebhooks(t *testing.T) {
 			{Owner: "acme", Name: "repo-a", WebhookSecret: "s3kr3t1"},
 			{Owner: "acme", Name: "repo-b", WebhookSecret: "s3kr3t2"},
 		}
-		RegisterAllGitHubWebhooks(ctx, gh, out, repos)
+		RegisterAllGitHubWebhooks(ctx, gh, out, "https://ralph.example.com/webhook", repos)
 
 		output := outBuf.String()
 		assert.Contains(t, output, "Failed to register webhook for acme/repo-a: network error")
@@ -378,7 +648,7 @@ func TestRegisterAllGitHubWebhooks(t *testing.T) {
 			},
 		}
 
-		RegisterAllGitHubWebhooks(ctx, gh, out, nil)
+		RegisterAllGitHubWebhooks(ctx, gh, out, "https://ralph.example.com/webhook", nil)
 
 		output := outBuf.String()
 		assert.NotContains(t, output, "Webhook registered")
@@ -386,6 +656,161 @@ func TestRegisterAllGitHubWebhooks(t *testing.T) {
 	})
 }
 
+func TestUnregisterGitHubWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deletes the ralph webhook on each removed repo", func(t *testing.T) {
+		var outBuf, errBuf bytes.Buffer
+		out := output.NewClient(&outBuf, &errBuf, false)
+		var deleted []string
+		gh := &github.MockGH{
+			DeleteWebhookFn: func(_ context.Context, owner, repo, webhookURL string) error {
+				assert.Equal(t, "https://"+WebhookIngressHostname+"/webhook", webhookURL)
+				deleted = append(deleted, owner+"/"+repo)
+				return nil
+			},
+		}
+
+		UnregisterGitHubWebhooks(ctx, gh, out, "https://"+WebhookIngressHostname+"/webhook", []string{"acme/api", "acme/web"})
+
+		assert.Equal(t, []string{"acme/api", "acme/web"}, deleted)
+		assert.Contains(t, outBuf.String(), "Webhook removed for acme/api")
+	})
+
+	t.Run("warns on failure and continues", func(t *testing.T) {
+		var outBuf, errBuf bytes.Buffer
+		out := output.NewClient(&outBuf, &errBuf, false)
+		var deleted []string
+		gh := &github.MockGH{
+			DeleteWebhookFn: func(_ context.Context, owner, repo, _ string) error {
+				if repo == "api" {
+					return fmt.Errorf("not found")
+				}
+				deleted = append(deleted, owner+"/"+repo)
+				return nil
+			},
+		}
+
+		UnregisterGitHubWebhooks(ctx, gh, out, "https://"+WebhookIngressHostname+"/webhook", []string{"acme/api", "acme/web"})
+
+		assert.Equal(t, []string{"acme/web"}, deleted)
+		assert.Contains(t, outBuf.String(), "Failed to remove webhook for acme/api: not found")
+	})
+}
+
+func TestReadWebhookSecretsFromK8s(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns parsed Secrets when GetSecretData succeeds", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetSecretDataFunc: func(_ context.Context, name, _, _ string) (string, error) {
+				assert.Equal(t, WebhookSecretsSecretName, name)
+				return "repos:\n  - owner: acme\n    name: api\n    webhookSecret: s3kr3t\n", nil
+			},
+		}
+		secrets, err := ReadWebhookSecretsFromK8s(ctx, client, "ns", "ctx")
+		require.NoError(t, err)
+		assert.Equal(t, []RepoSecret{{Owner: "acme", Name: "api", WebhookSecret: "s3kr3t"}}, secrets.Repos)
+	})
+
+	t.Run("returns error when GetSecretData fails", func(t *testing.T) {
+		client := &k8s.MockClient{
+			GetSecretDataFunc: func(_ context.Context, _, _, _ string) (string, error) {
+				return "", fmt.Errorf("secret not found")
+			},
+		}
+		_, err := ReadWebhookSecretsFromK8s(ctx, client, "ns", "ctx")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secret not found")
+	})
+}
+
+func TestReadExistingWebhookSecretsFromK8s(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns empty secrets when the secret does not exist", func(t *testing.T) {
+		client := &k8s.MockClient{
+			SecretExistsFunc: func(context.Context, string, string, string) (bool, error) { return false, nil },
+		}
+		secrets, err := ReadExistingWebhookSecretsFromK8s(ctx, client, "ns", "ctx")
+		require.NoError(t, err)
+		assert.Equal(t, &Secrets{}, secrets)
+	})
+
+	t.Run("reads the existing secret", func(t *testing.T) {
+		client := &k8s.MockClient{
+			SecretExistsFunc: func(context.Context, string, string, string) (bool, error) { return true, nil },
+			GetSecretDataFunc: func(context.Context, string, string, string) (string, error) {
+				return "triggerToken: trigger\n", nil
+			},
+		}
+		secrets, err := ReadExistingWebhookSecretsFromK8s(ctx, client, "ns", "ctx")
+		require.NoError(t, err)
+		assert.Equal(t, "trigger", secrets.TriggerToken)
+	})
+
+	t.Run("returns error when the check fails", func(t *testing.T) {
+		client := &k8s.MockClient{
+			SecretExistsFunc: func(context.Context, string, string, string) (bool, error) {
+				return false, fmt.Errorf("cluster unreachable")
+			},
+		}
+		_, err := ReadExistingWebhookSecretsFromK8s(ctx, client, "ns", "ctx")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cluster unreachable")
+	})
+}
+
+func TestRotateWebhookSecrets(t *testing.T) {
+	newSecrets := func() *Secrets {
+		return &Secrets{Repos: []RepoSecret{
+			{Owner: "acme", Name: "api", WebhookSecret: "old-api", PreviousSecret: "older-api"},
+			{Owner: "acme", Name: "web", WebhookSecret: "old-web"},
+		}}
+	}
+	counter := func() func() (string, error) {
+		n := 0
+		return func() (string, error) {
+			n++
+			return fmt.Sprintf("new-%d", n), nil
+		}
+	}
+
+	t.Run("rotates every repo and keeps the replaced secret", func(t *testing.T) {
+		secrets := newSecrets()
+		rotated, err := RotateWebhookSecrets(secrets, "", counter())
+		require.NoError(t, err)
+
+		expected := []RepoSecret{
+			{Owner: "acme", Name: "api", WebhookSecret: "new-1", PreviousSecret: "old-api"},
+			{Owner: "acme", Name: "web", WebhookSecret: "new-2", PreviousSecret: "old-web"},
+		}
+		assert.Equal(t, expected, secrets.Repos)
+		assert.Equal(t, expected, rotated)
+	})
+
+	t.Run("rotates only the named repo", func(t *testing.T) {
+		secrets := newSecrets()
+		rotated, err := RotateWebhookSecrets(secrets, "acme/web", counter())
+		require.NoError(t, err)
+
+		assert.Equal(t, []RepoSecret{{Owner: "acme", Name: "web", WebhookSecret: "new-1", PreviousSecret: "old-web"}}, rotated)
+		assert.Equal(t, "old-api", secrets.Repos[0].WebhookSecret)
+	})
+
+	t.Run("returns error for an unknown repo", func(t *testing.T) {
+		_, err := RotateWebhookSecrets(newSecrets(), "acme/missing", counter())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no webhook secret for acme/missing")
+	})
+
+	t.Run("returns error when generation fails", func(t *testing.T) {
+		_, err := RotateWebhookSecrets(newSecrets(), "", func() (string, error) { return "", fmt.Errorf("no entropy") })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no entropy")
+	})
+}
+
 func TestBuildWebhookSecrets(t *testing.T) {
 	counter := 0
 	deterministicGenerator := func() (string, error) {
@@ -402,7 +827,7 @@ func TestBuildWebhookSecrets(t *testing.T) {
 			},
 		}
 
-		secrets, err := BuildWebhookSecrets(appCfg, deterministicGenerator)
+		secrets, err := BuildWebhookSecrets(appCfg, nil, deterministicGenerator)
 		require.NoError(t, err)
 
 		require.Len(t, secrets.Repos, 2)
@@ -414,13 +839,52 @@ func TestBuildWebhookSecrets(t *testing.T) {
 		assert.Equal(t, "test-secret-2", secrets.Repos[1].WebhookSecret)
 	})
 
+	t.Run("keeps existing repo secrets and tokens", func(t *testing.T) {
+		counter = 0
+		appCfg := &AppConfig{
+			Repos: []RepoConfig{
+				{Owner: "acme", Name: "repo-a"},
+				{Owner: "acme", Name: "repo-b"},
+			},
+		}
+		existing := &Secrets{
+			Repos: []RepoSecret{
+				{Owner: "acme", Name: "repo-a", WebhookSecret: "current-a", PreviousSecret: "old-a"},
+				{Owner: "acme", Name: "removed", WebhookSecret: "current-removed"},
+			},
+			TriggerToken: "trigger",
+			GithubToken:  "gh-token",
+		}
+
+		secrets, err := BuildWebhookSecrets(appCfg, existing, deterministicGenerator)
+		require.NoError(t, err)
+
+		assert.Equal(t, []RepoSecret{
+			{Owner: "acme", Name: "repo-a", WebhookSecret: "current-a", PreviousSecret: "old-a"},
+			{Owner: "acme", Name: "repo-b", WebhookSecret: "test-secret-1"},
+		}, secrets.Repos)
+		assert.Equal(t, "trigger", secrets.TriggerToken)
+		assert.Equal(t, "gh-token", secrets.GithubToken)
+	})
+
+	t.Run("generates a trigger token when there is none", func(t *testing.T) {
+		counter = 0
+		appCfg := &AppConfig{Repos: []RepoConfig{{Owner: "acme", Name: "repo-a"}}}
+
+		secrets, err := BuildWebhookSecrets(appCfg, &Secrets{}, deterministicGenerator)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-secret-1", secrets.Repos[0].WebhookSecret)
+		assert.Equal(t, "test-secret-2", secrets.TriggerToken)
+	})
+
 	t.Run("returns empty repos list when no repos configured", func(t *testing.T) {
 		counter = 0
 		appCfg := &AppConfig{
 			Repos: []RepoConfig{},
 		}
 
-		secrets, err := BuildWebhookSecrets(appCfg, deterministicGenerator)
+		secrets, err := BuildWebhookSecrets(appCfg, nil, deterministicGenerator)
 		require.NoError(t, err)
 
 		assert.Empty(t, secrets.Repos)
@@ -437,7 +901,7 @@ func TestBuildWebhookSecrets(t *testing.T) {
 			},
 		}
 
-		_, err := BuildWebhookSecrets(appCfg, failingGenerator)
+		_, err := BuildWebhookSecrets(appCfg, nil, failingGenerator)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "entropy exhausted")
 	})
@@ -451,7 +915,7 @@ func TestBuildWebhookSecrets(t *testing.T) {
 			},
 		}
 
-		secrets, err := BuildWebhookSecrets(appCfg, GenerateWebhookSecret)
+		secrets, err := BuildWebhookSecrets(appCfg, nil, GenerateWebhookSecret)
 		require.NoError(t, err)
 
 		require.Len(t, secrets.Repos, 3)
@@ -472,7 +936,7 @@ func TestBuildWebhookSecrets(t *testing.T) {
 			},
 		}
 
-		secrets, err := BuildWebhookSecrets(appCfg, deterministicGenerator)
+		secrets, err := BuildWebhookSecrets(appCfg, nil, deterministicGenerator)
 		require.NoError(t, err)
 
 		secretsBytes, err := yaml.Marshal(secrets)
@@ -488,37 +952,6 @@ func TestBuildWebhookSecrets(t *testing.T) {
 	})
 }
 
-func TestGetKubeContext(t *testing.T) {
-	t.Run("returns contextOverride when non-empty", func(t *testing.T) {
-		client := &k8s.MockClient{}
-		result, err := GetKubeContext(context.Background(), client, "my-cluster")
-		require.NoError(t, err)
-		assert.Equal(t, "my-cluster", result)
-	})
-
-	t.Run("calls client.GetCurrentContext when contextOverride is empty", func(t *testing.T) {
-		client := &k8s.MockClient{
-			GetCurrentContextFunc: func(ctx context.Context) (k8s.Context, error) {
-				return k8s.Context{Name: "current-cluster"}, nil
-			},
-		}
-		result, err := GetKubeContext(context.Background(), client, "")
-		require.NoError(t, err)
-		assert.Equal(t, "current-cluster", result)
-	})
-
-	t.Run("propagates error from client.GetCurrentContext", func(t *testing.T) {
-		client := &k8s.MockClient{
-			GetCurrentContextFunc: func(ctx context.Context) (k8s.Context, error) {
-				return k8s.Context{}, fmt.Errorf("kubeconfig not found")
-			},
-		}
-		_, err := GetKubeContext(context.Background(), client, "")
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "kubeconfig not found")
-	})
-}
-
 func TestWriteWebhookConfigMap(t *testing.T) {
 	t.Run("calls client.CreateOrUpdateConfigMap with serialized config", func(t *testing.T) {
 		var capturedName, capturedNamespace, capturedKubeContext string
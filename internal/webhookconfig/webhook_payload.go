@@ -9,7 +9,8 @@ import (
 // IsAcceptable reports whether the payload should be dispatched for the given
 // event type, applying user ignore/allowlist rules from cfg.
 // Returns false for unrecognised event types, non-PR issue comments, empty
-// review bodies, and non-approved/non-commented review states.
+// review bodies, non-approved/non-commented review states, and pull_request
+// events other than adding the configured mergeLabel.
 func IsAcceptable(p *github.WebhookPayload, eventType string, cfg *Config) bool {
 	repo := cfg.RepoByFullName(p.RepoOwner(), p.RepoName())
 
@@ -25,6 +26,13 @@ func IsAcceptable(p *github.WebhookPayload, eventType string, cfg *Config) bool
 		author := p.Comment.User.Login
 		return !cfg.IsUserIgnored(repo, author) && (repo == nil || repo.IsUserAllowed(author))
 
+	case "pull_request":
+		if p.EventAction() != "labeled" || cfg.App.MergeLabel == "" || !strings.EqualFold(p.LabelName(), cfg.App.MergeLabel) {
+			return false
+		}
+		actor := p.Sender.Login
+		return !cfg.IsUserIgnored(repo, actor) && (repo == nil || repo.IsUserAllowed(actor))
+
 	case "pull_request_review":
 		author := p.Review.User.Login
 		if cfg.IsUserIgnored(repo, author) || (repo != nil && !repo.IsUserAllowed(author)) {
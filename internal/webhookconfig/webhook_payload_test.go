@@ -181,3 +181,45 @@ func TestIsAcceptable_ReviewApproved_EmptyAllowedList_ReturnsTrue(t *testing.T)
 	cfg := configWithAllowedUsers([]string{})
 	assert.True(t, IsAcceptable(&p, "pull_request_review", cfg))
 }
+
+// labeledPayload builds a pull_request "labeled" payload for acme/myrepo.
+func labeledPayload(label, sender string) github.WebhookPayload {
+	p := minimalPayload("acme", "myrepo")
+	p.Action = "labeled"
+	p.Label.Name = label
+	p.Sender.Login = sender
+	return p
+}
+
+// configWithMergeLabel returns a Config with mergeLabel set and an AllowedUsers list on acme/myrepo.
+func configWithMergeLabel(label string, users []string) *Config {
+	cfg := configWithAllowedUsers(users)
+	cfg.App.MergeLabel = label
+	return cfg
+}
+
+func TestIsAcceptable_PullRequestLabeled_MatchingLabel_ReturnsTrue(t *testing.T) {
+	p := labeledPayload("Ready-To-Merge", "alice")
+	assert.True(t, IsAcceptable(&p, "pull_request", configWithMergeLabel("ready-to-merge", []string{"alice"})))
+}
+
+func TestIsAcceptable_PullRequestLabeled_OtherLabel_ReturnsFalse(t *testing.T) {
+	p := labeledPayload("needs-work", "alice")
+	assert.False(t, IsAcceptable(&p, "pull_request", configWithMergeLabel("ready-to-merge", []string{"alice"})))
+}
+
+func TestIsAcceptable_PullRequestLabeled_DisallowedActor_ReturnsFalse(t *testing.T) {
+	p := labeledPayload("ready-to-merge", "mallory")
+	assert.False(t, IsAcceptable(&p, "pull_request", configWithMergeLabel("ready-to-merge", []string{"alice"})))
+}
+
+func TestIsAcceptable_PullRequestLabeled_NoMergeLabelConfigured_ReturnsFalse(t *testing.T) {
+	p := labeledPayload("ready-to-merge", "alice")
+	assert.False(t, IsAcceptable(&p, "pull_request", configWithMergeLabel("", nil)))
+}
+
+func TestIsAcceptable_PullRequestUnlabeled_ReturnsFalse(t *testing.T) {
+	p := labeledPayload("ready-to-merge", "alice")
+	p.Action = "unlabeled"
+	assert.False(t, IsAcceptable(&p, "pull_request", configWithMergeLabel("ready-to-merge", nil)))
+}
@@ -8,10 +8,12 @@ import (
 
 type MockClient struct {
 	SubmitFunc       func(input *project.InputFile, cloneBranch string, debug string, baseBranch string) (string, error)
+	DryRunFunc       func(input *project.InputFile, cloneBranch string, debug string, baseBranch string) error
 	FollowLogsFunc   func(workflowName string) error
 	PrintLogHintFunc func(workflowName string)
 
 	SubmitCalled       bool
+	DryRunCalled       bool
 	FollowLogsCalled   bool
 	PrintLogHintCalled bool
 	LastDebugBranch    string
@@ -28,6 +30,16 @@ func (m *MockClient) Submit(input *project.InputFile, cloneBranch string, debug
 	return "test-workflow", nil
 }
 
+func (m *MockClient) DryRun(input *project.InputFile, cloneBranch string, debug string, baseBranch string) error {
+	m.DryRunCalled = true
+	m.LastDebugBranch = debug
+	m.LastBaseBranch = baseBranch
+	if m.DryRunFunc != nil {
+		return m.DryRunFunc(input, cloneBranch, debug, baseBranch)
+	}
+	return nil
+}
+
 func (m *MockClient) FollowLogs(workflowName string) error {
 	m.FollowLogsCalled = true
 	if m.FollowLogsFunc != nil {
@@ -1,10 +1,13 @@
 package workflow
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
+	execcontext "github.com/zon/ralph/internal/context"
 	githubpkg "github.com/zon/ralph/internal/github"
+	"github.com/zon/ralph/internal/project"
 	"github.com/zon/ralph/internal/webhookconfig"
 )
 
@@ -17,6 +20,7 @@ type WebhookEvent struct {
 	RepoOwner string
 	RepoName  string
 	PRNumber  string
+	Author    string
 }
 
 // WorkflowResult holds the output of FromWebhookEvent. Exactly one of Run or Merge is non-nil.
@@ -30,14 +34,20 @@ type WorkflowResult struct {
 // Comment events produce a Run workflow that calls `ralph comment`.
 // Approval events produce a MergeWorkflow that calls `ralph merge --local`.
 func FromWebhookEvent(event WebhookEvent, opts WorkflowOptions) (*WorkflowResult, error) {
-	projectFile := ProjectFileFromBranch(event.PRBranch)
+	projectFile := project.ProjectFileFromBranch(event.PRBranch)
+	actor := execcontext.WebhookActor(event.Author)
 	repoURL := githubpkg.CloneURL(event.RepoOwner, event.RepoName)
 
 	if event.Approved {
+		// ralph merge finds the project files on the PR branch itself, but needs both to target the PR
+		if event.PRBranch == "" || event.PRNumber == "" {
+			return nil, fmt.Errorf("approval for %s/%s has no pull request branch or number", event.RepoOwner, event.RepoName)
+		}
 		mw, err := GenerateMergeWorkflowWithGitInfo(repoURL, event.PRBranch, event.PRBranch, event.PRNumber, opts)
 		if err != nil {
 			return nil, err
 		}
+		mw.Actor = actor
 		return &WorkflowResult{Merge: mw, Namespace: opts.Namespace}, nil
 	}
 
@@ -57,26 +67,11 @@ func FromWebhookEvent(event WebhookEvent, opts WorkflowOptions) (*WorkflowResult
 		Image:       opts.Image,
 		KubeContext: opts.KubeContext,
 		Namespace:   opts.Namespace,
+		Actor:       actor,
 	}
 	return &WorkflowResult{Run: wf, Namespace: opts.Namespace}, nil
 }
 
-// ProjectFileFromBranch derives the project file path from the PR head branch name.
-//
-// Convention: branch "ralph/<project-name>" → "projects/<project-name>.yaml"
-//
-// If the branch does not follow the ralph/ prefix convention the full branch
-// name (with slashes replaced by dashes) is used as the project name.
-func ProjectFileFromBranch(branch string) string {
-	projectName := branch
-	if strings.HasPrefix(branch, "ralph/") {
-		projectName = strings.TrimPrefix(branch, "ralph/")
-	} else {
-		projectName = strings.ReplaceAll(branch, "/", "-")
-	}
-	return filepath.Join("projects", projectName+".yaml")
-}
-
 // FromWebhookEventWithConfig is a convenience wrapper that constructs WorkflowOptions
 // from a webhookconfig.Config and calls FromWebhookEvent. It resolves the image,
 // kube context, and namespace (per-repo) from the config.
@@ -88,6 +83,7 @@ func FromWebhookEventWithConfig(fields githubpkg.EventFields, cfg *webhookconfig
 		RepoOwner: fields.RepoOwner,
 		RepoName:  fields.RepoName,
 		PRNumber:  fields.PRNumber,
+		Author:    fields.Author,
 	}
 	image := MakeImage(cfg.App.ImageRepository, cfg.App.ImageTag)
 	namespace := ""
@@ -101,3 +97,31 @@ func FromWebhookEventWithConfig(fields githubpkg.EventFields, cfg *webhookconfig
 	}
 	return FromWebhookEvent(we, opts)
 }
+
+// FromTriggerWithConfig builds the run workflow for a manual trigger of owner/name: the container
+// clones branch, works on the project derived from it, and opens its pull request against base.
+// Image, kube context and namespace are resolved from cfg as for webhook events.
+func FromTriggerWithConfig(owner, name, branch, base string, cfg *webhookconfig.Config) (*WorkflowResult, error) {
+	repo, err := githubpkg.ParseRemoteURL(githubpkg.CloneURL(owner, name))
+	if err != nil {
+		return nil, err
+	}
+	namespace := ""
+	if rc := cfg.RepoByFullName(owner, name); rc != nil {
+		namespace = rc.Namespace
+	}
+	projectFile := project.ProjectFileFromBranch(branch)
+	wf := &Workflow{
+		ProjectName:   strings.TrimSuffix(filepath.Base(projectFile), filepath.Ext(projectFile)),
+		Repo:          repo,
+		CloneBranch:   branch,
+		ProjectBranch: branch,
+		ProjectPath:   projectFile,
+		BaseBranch:    base,
+		Image:         MakeImage(cfg.App.ImageRepository, cfg.App.ImageTag),
+		KubeContext:   cfg.App.WorkflowContext,
+		Namespace:     namespace,
+		Actor:         execcontext.WebhookActor(""),
+	}
+	return &WorkflowResult{Run: wf, Namespace: namespace}, nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	githubpkg "github.com/zon/ralph/internal/github"
+	"github.com/zon/ralph/internal/webhookconfig"
 )
 
 // workflowTestDir sets up a temp dir with the minimal .ralph/config.yaml that
@@ -17,7 +19,7 @@ func workflowTestDir(t *testing.T) {
 	tmp := t.TempDir()
 	ralphDir := filepath.Join(tmp, ".ralph")
 	require.NoError(t, os.MkdirAll(ralphDir, 0755))
-	require.NoError(t, os.WriteFile(filepath.Join(ralphDir, "config.yaml"), []byte("maxIterations: 3\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(ralphDir, "config.yaml"), []byte("extraIterations: 3\n"), 0644))
 
 	orig, err := os.Getwd()
 	require.NoError(t, err)
@@ -25,23 +27,6 @@ func workflowTestDir(t *testing.T) {
 	t.Cleanup(func() { os.Chdir(orig) })
 }
 
-func TestProjectFileFromBranch(t *testing.T) {
-	tests := []struct {
-		branch string
-		want   string
-	}{
-		{"ralph/my-feature", "projects/my-feature.yaml"},
-		{"ralph/github-webhook-service", "projects/github-webhook-service.yaml"},
-		{"feature/something", "projects/feature-something.yaml"},
-		{"", "projects/.yaml"},
-	}
-	for _, tc := range tests {
-		t.Run(tc.branch, func(t *testing.T) {
-			assert.Equal(t, tc.want, ProjectFileFromBranch(tc.branch))
-		})
-	}
-}
-
 func TestFromWebhookEvent_CommentEvent_ReturnsRunWorkflow(t *testing.T) {
 	workflowTestDir(t)
 
@@ -87,6 +72,59 @@ func TestFromWebhookEvent_ApprovalEvent_ReturnsMergeWorkflow(t *testing.T) {
 	assert.Equal(t, "99", result.Merge.PRNumber)
 }
 
+func TestFromWebhookEvent_ApprovalEvent_RequiresPullRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		prBranch string
+		prNumber string
+	}{
+		{name: "no branch", prNumber: "99"},
+		{name: "no number", prBranch: "ralph/my-feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			we := WebhookEvent{
+				Approved:  true,
+				PRBranch:  tt.prBranch,
+				PRNumber:  tt.prNumber,
+				RepoOwner: "acme",
+				RepoName:  "myrepo",
+			}
+
+			_, err := FromWebhookEvent(we, WorkflowOptions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "acme/myrepo")
+		})
+	}
+}
+
+func TestFromWebhookEventWithConfig_ApprovalTargetsPRBranch(t *testing.T) {
+	cfg := &webhookconfig.Config{
+		App: webhookconfig.AppConfig{
+			Repos: []webhookconfig.RepoConfig{{Owner: "acme", Name: "myrepo", Namespace: "team-ns"}},
+		},
+	}
+	fields := githubpkg.EventFields{
+		Approved:  true,
+		PRBranch:  "ralph/my-feature",
+		PRNumber:  "99",
+		RepoOwner: "acme",
+		RepoName:  "myrepo",
+		Author:    "carol",
+	}
+
+	result, err := FromWebhookEventWithConfig(fields, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, result.Merge)
+
+	workflowYAML, err := result.Merge.Render()
+	require.NoError(t, err)
+	assert.Contains(t, workflowYAML, "--pr-branch")
+	assert.Contains(t, workflowYAML, "ralph/my-feature")
+	assert.Equal(t, "team-ns", result.Namespace)
+}
+
 func TestFromWebhookEvent_RunWorkflow_RendersToYAML(t *testing.T) {
 	workflowTestDir(t)
 
@@ -132,6 +170,7 @@ func TestFromWebhookEvent_NamespacePropagated(t *testing.T) {
 		we := WebhookEvent{
 			Approved:  true,
 			PRBranch:  "ralph/my-feature",
+			PRNumber:  "99",
 			RepoOwner: "acme",
 			RepoName:  "myrepo",
 		}
@@ -166,6 +205,7 @@ func TestFromWebhookEvent_MergeWorkflow_RendersToYAML(t *testing.T) {
 	we := WebhookEvent{
 		Approved:  true,
 		PRBranch:  "ralph/my-feature",
+		PRNumber:  "99",
 		RepoOwner: "acme",
 		RepoName:  "myrepo",
 	}
@@ -178,3 +218,64 @@ func TestFromWebhookEvent_MergeWorkflow_RendersToYAML(t *testing.T) {
 	assert.Contains(t, yaml, "argoproj.io/v1alpha1")
 	assert.Contains(t, yaml, "ralph-merge-")
 }
+
+func TestFromWebhookEvent_ActorFromAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		approved bool
+	}{
+		{name: "comment", approved: false},
+		{name: "approval", approved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflowTestDir(t)
+
+			we := WebhookEvent{
+				Body:      "looks good",
+				Approved:  tt.approved,
+				PRBranch:  "ralph/my-feature",
+				PRNumber:  "5",
+				RepoOwner: "acme",
+				RepoName:  "myrepo",
+				Author:    "octocat",
+			}
+
+			result, err := FromWebhookEvent(we, WorkflowOptions{})
+			require.NoError(t, err)
+			if tt.approved {
+				require.NotNil(t, result.Merge)
+				assert.Equal(t, "webhook:octocat", result.Merge.Actor)
+			} else {
+				require.NotNil(t, result.Run)
+				assert.Equal(t, "webhook:octocat", result.Run.Actor)
+			}
+		})
+	}
+}
+
+func TestFromTriggerWithConfig(t *testing.T) {
+	workflowTestDir(t)
+
+	cfg := &webhookconfig.Config{
+		App: webhookconfig.AppConfig{
+			Repos: []webhookconfig.RepoConfig{{Owner: "acme", Name: "myrepo", Namespace: "team-ns"}},
+		},
+	}
+
+	result, err := FromTriggerWithConfig("acme", "myrepo", "ralph/my-feature", "develop", cfg)
+	require.NoError(t, err)
+	require.NotNil(t, result.Run)
+	assert.Nil(t, result.Merge)
+	assert.Equal(t, "team-ns", result.Namespace)
+	assert.Equal(t, "ralph/my-feature", result.Run.CloneBranch)
+	assert.Equal(t, "ralph/my-feature", result.Run.ProjectBranch)
+	assert.Equal(t, "projects/my-feature.yaml", result.Run.ProjectPath)
+	assert.Empty(t, result.Run.CommentBody)
+
+	yaml, err := result.Run.Render()
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "- run")
+	assert.Contains(t, yaml, "develop")
+}
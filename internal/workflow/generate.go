@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
@@ -17,6 +18,22 @@ func DefaultContainerVersion() string {
 	return version.Version()
 }
 
+// isDevelopmentVersion reports whether v is a version that no published image is tagged with,
+// as happens in forks and dev builds whose VERSION file was never released.
+func isDevelopmentVersion(v string) bool {
+	v = strings.ToLower(strings.TrimSpace(v))
+	return v == "" || v == "unknown" || v == "dev" || strings.Contains(v, "-dev") || strings.HasSuffix(v, "-dirty")
+}
+
+// unpinnedImageWarning returns a warning when image falls back to a development default version,
+// or an empty string when the image is pinned by tag or digest or the default is a release.
+func unpinnedImageWarning(image Image, defaultVersion string) string {
+	if image.Tag != "" || image.Digest != "" || !isDevelopmentVersion(defaultVersion) {
+		return ""
+	}
+	return fmt.Sprintf("Workflow image %s uses development version %q; set workflow.image.tag or workflow.image.digest to pin a published image", resolveImage(image), defaultVersion)
+}
+
 // GenerateWorkflow builds a Workflow for remote execution.
 // cloneBranch is the branch the container will clone (current local branch).
 // projectBranch is the branch the container will create and work on (derived from the project file name).
@@ -54,38 +71,79 @@ func GenerateWorkflowWithGitInfo(ctx *execcontext.Context, projectName, repoURL,
 	}
 
 	workflowOptions := WorkflowOptions{
-		Image:      MakeImage(cfg.Workflow.Image.Repository, cfg.Workflow.Image.Tag),
-		ConfigMaps: cfg.Workflow.ConfigMaps,
-		Secrets:    cfg.Workflow.Secrets,
-		Env:        cfg.Workflow.Env,
-		Namespace:  cfg.Workflow.Namespace,
-		Labels:     cfg.Workflow.Labels,
+		Image:          imageFromConfig(cfg.Workflow),
+		ConfigMaps:     cfg.Workflow.ConfigMaps,
+		Secrets:        cfg.Workflow.Secrets,
+		Env:            cfg.Workflow.Env,
+		Namespace:      cfg.Workflow.Namespace,
+		Labels:         cfg.Workflow.Labels,
+		Submodules:     cfg.Workflow.Submodules,
+		LFS:            cfg.Workflow.LFS,
+		ArchiveLogs:    cfg.Workflow.ArchiveLogs,
+		WorkDir:        cfg.Workflow.WorkDir,
+		CloneDir:       cfg.Workflow.CloneDir,
+		Entrypoint:     cfg.Workflow.Entrypoint,
+		Shell:          cfg.Workflow.Shell,
+		EnvFrom:        cfg.Workflow.EnvFrom,
+		EnvRefs:        cfg.Workflow.EnvRefs,
+		SubmitAttempts: cfg.Workflow.SubmitAttempts,
+	}
+
+	if ref := ctx.Image(); ref != "" {
+		image, err := ParseImageRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		image.PullPolicy = workflowOptions.Image.PullPolicy
+		workflowOptions.Image = image
+	}
+
+	if out := ctx.Output(); verbose && out != nil {
+		if warning := unpinnedImageWarning(workflowOptions.Image, DefaultContainerVersion()); warning != "" {
+			out.Warn(warning)
+		}
 	}
 
 	kubeContext := ctx.KubeContext()
 	if kubeContext == "" {
 		kubeContext = cfg.Workflow.Context
 	}
+	if namespace := ctx.KubeNamespace(); namespace != "" {
+		workflowOptions.Namespace = namespace
+	}
 
 	return &Workflow{
-		ProjectName:   projectName,
-		Repo:          repo,
-		CloneBranch:   cloneBranch,
-		ProjectBranch: projectBranch,
-		ProjectPath:   relProjectPath,
-		Instructions:  instructions,
-		Verbose:       verbose,
-		DebugBranch:   ctx.DebugBranch(),
-		BaseBranch:    baseBranch,
-		Image:         workflowOptions.Image,
-		ConfigMaps:    workflowOptions.ConfigMaps,
-		Secrets:       workflowOptions.Secrets,
-		Env:           workflowOptions.Env,
-		KubeContext:   kubeContext,
-		Namespace:     workflowOptions.Namespace,
-		NoServices:    ctx.NoServices(),
-		Model:         ctx.Model(),
-		Labels:        workflowOptions.Labels,
+		ProjectName:    projectName,
+		Repo:           repo,
+		CloneBranch:    cloneBranch,
+		ProjectBranch:  projectBranch,
+		ProjectPath:    relProjectPath,
+		Instructions:   instructions,
+		Verbose:        verbose,
+		DebugBranch:    ctx.DebugBranch(),
+		BaseBranch:     baseBranch,
+		Image:          workflowOptions.Image,
+		ConfigMaps:     workflowOptions.ConfigMaps,
+		Secrets:        workflowOptions.Secrets,
+		Env:            workflowOptions.Env,
+		KubeContext:    kubeContext,
+		Namespace:      workflowOptions.Namespace,
+		NoServices:     ctx.NoServices(),
+		Model:          ctx.Model(),
+		Labels:         workflowOptions.Labels,
+		Submodules:     workflowOptions.Submodules,
+		LFS:            workflowOptions.LFS,
+		ArchiveLogs:    workflowOptions.ArchiveLogs,
+		WorkDir:        workflowOptions.WorkDir,
+		CloneDir:       workflowOptions.CloneDir,
+		Entrypoint:     workflowOptions.Entrypoint,
+		Shell:          workflowOptions.Shell,
+		EnvFrom:        workflowOptions.EnvFrom,
+		EnvRefs:        workflowOptions.EnvRefs,
+		Params:         ctx.Params(),
+		KeepFailed:     ctx.IsKeepFailed(),
+		Actor:          ctx.Actor(),
+		SubmitAttempts: workflowOptions.SubmitAttempts,
 	}, nil
 }
 
@@ -121,9 +179,14 @@ func GenerateMergeWorkflow(prBranch, repoURL, currentBranch string) (*MergeWorkf
 	}
 
 	opts := WorkflowOptions{
-		Image:       MakeImage(ralphConfig.Workflow.Image.Repository, ralphConfig.Workflow.Image.Tag),
-		KubeContext: ralphConfig.Workflow.Context,
-		Namespace:   ralphConfig.Workflow.Namespace,
+		Image:          imageFromConfig(ralphConfig.Workflow),
+		KubeContext:    ralphConfig.Workflow.Context,
+		Namespace:      ralphConfig.Workflow.Namespace,
+		WorkDir:        ralphConfig.Workflow.WorkDir,
+		CloneDir:       ralphConfig.Workflow.CloneDir,
+		Entrypoint:     ralphConfig.Workflow.Entrypoint,
+		Shell:          ralphConfig.Workflow.Shell,
+		SubmitAttempts: ralphConfig.Workflow.SubmitAttempts,
 	}
 
 	return GenerateMergeWorkflowWithGitInfo(repoURL, currentBranch, prBranch, "", opts)
@@ -138,13 +201,18 @@ func GenerateMergeWorkflowWithGitInfo(repoURL, cloneBranch, prBranch, prNumber s
 	}
 
 	return &MergeWorkflow{
-		Repo:        repo,
-		CloneBranch: cloneBranch,
-		PRBranch:    prBranch,
-		PRNumber:    prNumber,
-		Image:       opts.Image,
-		KubeContext: opts.KubeContext,
-		Namespace:   opts.Namespace,
+		Repo:           repo,
+		CloneBranch:    cloneBranch,
+		PRBranch:       prBranch,
+		PRNumber:       prNumber,
+		Image:          opts.Image,
+		KubeContext:    opts.KubeContext,
+		Namespace:      opts.Namespace,
+		WorkDir:        opts.WorkDir,
+		CloneDir:       opts.CloneDir,
+		Entrypoint:     opts.Entrypoint,
+		Shell:          opts.Shell,
+		SubmitAttempts: opts.SubmitAttempts,
 	}, nil
 }
 
@@ -166,33 +234,48 @@ func GenerateCommandWorkflow(ctx *execcontext.Context, cloneBranch, remoteURL st
 	opts := workflowOptionsFromConfig(ralphConfig, ctx)
 
 	return &Workflow{
-		ProjectName: "command",
-		Repo:        repo,
-		CloneBranch: cloneBranch,
-		Command:     ctx.Command(),
-		Verbose:     ctx.IsVerbose(),
-		DebugBranch: ctx.DebugBranch(),
-		NoServices:  ctx.NoServices(),
-		Model:       ctx.Model(),
-		Image:       opts.Image,
-		ConfigMaps:  opts.ConfigMaps,
-		Secrets:     opts.Secrets,
-		Env:         opts.Env,
-		KubeContext: opts.KubeContext,
-		Namespace:   opts.Namespace,
-		Labels:      opts.Labels,
+		ProjectName:    "command",
+		Repo:           repo,
+		CloneBranch:    cloneBranch,
+		Command:        ctx.Command(),
+		Verbose:        ctx.IsVerbose(),
+		DebugBranch:    ctx.DebugBranch(),
+		NoServices:     ctx.NoServices(),
+		Model:          ctx.Model(),
+		Image:          opts.Image,
+		ConfigMaps:     opts.ConfigMaps,
+		Secrets:        opts.Secrets,
+		Env:            opts.Env,
+		KubeContext:    opts.KubeContext,
+		Namespace:      opts.Namespace,
+		Labels:         opts.Labels,
+		Submodules:     opts.Submodules,
+		LFS:            opts.LFS,
+		ArchiveLogs:    opts.ArchiveLogs,
+		WorkDir:        opts.WorkDir,
+		CloneDir:       opts.CloneDir,
+		Entrypoint:     opts.Entrypoint,
+		Shell:          opts.Shell,
+		EnvFrom:        opts.EnvFrom,
+		EnvRefs:        opts.EnvRefs,
+		Actor:          ctx.Actor(),
+		SubmitAttempts: opts.SubmitAttempts,
 	}, nil
 }
 
 // resolveImage returns the container image string from config, falling back to the default.
-func resolveImage(imageRepository, imageTag string) string {
+// A digest pins the image as repo@sha256:... and takes precedence over the tag.
+func resolveImage(image Image) string {
 	imageRepo := "ghcr.io/zon/ralph"
 	imageVersion := DefaultContainerVersion()
-	if imageRepository != "" {
-		imageRepo = imageRepository
+	if image.Repository != "" {
+		imageRepo = image.Repository
+	}
+	if image.Digest != "" {
+		return fmt.Sprintf("%s@%s", imageRepo, image.Digest)
 	}
-	if imageTag != "" {
-		imageVersion = imageTag
+	if image.Tag != "" {
+		imageVersion = image.Tag
 	}
 	return fmt.Sprintf("%s:%s", imageRepo, imageVersion)
 }
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/zon/ralph/internal/config"
 	execcontext "github.com/zon/ralph/internal/context"
 	githubpkg "github.com/zon/ralph/internal/github"
+	"github.com/zon/ralph/internal/output"
 	"gopkg.in/yaml.v3"
 )
 
@@ -355,6 +357,190 @@ func TestGenerateMergeWorkflow_DefaultImage(t *testing.T) {
 	assert.Equal(t, expectedImage, container["image"])
 }
 
+func TestResolveImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		name  string
+		image Image
+		want  string
+	}{
+		{name: "default", image: Image{}, want: "ghcr.io/zon/ralph:" + DefaultContainerVersion()},
+		{name: "tag", image: MakeImage("my-registry/ralph", "v2.0.0"), want: "my-registry/ralph:v2.0.0"},
+		{name: "digest wins over tag", image: Image{Repository: "my-registry/ralph", Tag: "v2.0.0", Digest: digest}, want: "my-registry/ralph@" + digest},
+		{name: "digest with default repository", image: Image{Digest: digest}, want: "ghcr.io/zon/ralph@" + digest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveImage(tt.image))
+		})
+	}
+}
+
+func TestUnpinnedImageWarning(t *testing.T) {
+	tests := []struct {
+		name           string
+		image          Image
+		defaultVersion string
+		wantWarning    bool
+	}{
+		{name: "dev version", image: Image{}, defaultVersion: "dev", wantWarning: true},
+		{name: "unknown version", image: Image{Repository: "my-registry/ralph"}, defaultVersion: "unknown", wantWarning: true},
+		{name: "dev pre-release", image: Image{}, defaultVersion: "16.1.0-dev", wantWarning: true},
+		{name: "pinned tag", image: MakeImage("", "v2.0.0"), defaultVersion: "dev", wantWarning: false},
+		{name: "pinned digest", image: Image{Digest: "sha256:" + strings.Repeat("ab", 32)}, defaultVersion: "unknown", wantWarning: false},
+		{name: "release version", image: Image{}, defaultVersion: "16.0.0", wantWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := unpinnedImageWarning(tt.image, tt.defaultVersion)
+			if tt.wantWarning {
+				assert.Contains(t, warning, tt.defaultVersion)
+				assert.Contains(t, warning, "workflow.image.tag")
+			} else {
+				assert.Empty(t, warning)
+			}
+		})
+	}
+}
+
+func TestGenerateWorkflow_NoImageWarningWhenPinned(t *testing.T) {
+	var stdout bytes.Buffer
+	ctx := execcontext.NewContext()
+	ctx.SetOutput(output.NewClient(&stdout, &stdout, true))
+	cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{Image: config.ImageConfig{Tag: "v1.0.0"}}}
+
+	_, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", true, cfg, "")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout.String(), "development version")
+}
+
+func TestGenerateWorkflow_ImageDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("0f", 32)
+	cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{
+		Image: config.ImageConfig{Repository: "my-registry/ralph", Tag: "v1.0.0", Digest: digest},
+	}}
+	wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+	require.NoError(t, err)
+
+	workflowYAML, err := wf.Render()
+	require.NoError(t, err)
+	var wfData map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData))
+	tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "my-registry/ralph@"+digest, tmpl["container"].(map[string]interface{})["image"])
+}
+
+func TestGenerateWorkflow_ImageOverride(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("1e", 32)
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{name: "tag", ref: "localhost:5000/ralph-dev:test", want: "localhost:5000/ralph-dev:test"},
+		{name: "digest", ref: "ghcr.io/me/ralph@" + digest, want: "ghcr.io/me/ralph@" + digest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := execcontext.NewContext()
+			ctx.SetImage(tt.ref)
+			cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{
+				Image:           config.ImageConfig{Repository: "my-registry/ralph", Tag: "v1.0.0", Digest: "sha256:" + strings.Repeat("0f", 32)},
+				ImagePullPolicy: "Always",
+			}}
+			wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+
+			workflowYAML, err := wf.Render()
+			require.NoError(t, err)
+			var wfData map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData))
+			tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+			container := tmpl["container"].(map[string]interface{})
+			assert.Equal(t, tt.want, container["image"])
+			assert.Equal(t, "Always", container["imagePullPolicy"])
+		})
+	}
+}
+
+func TestGenerateWorkflow_InvalidImageOverride(t *testing.T) {
+	ctx := execcontext.NewContext()
+	ctx.SetImage("ralph")
+
+	_, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, &config.RalphConfig{}, "")
+	assert.ErrorContains(t, err, `invalid --image "ralph"`)
+}
+
+func TestWorkflowRender_ImagePullPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{name: "configured", policy: "IfNotPresent"},
+		{name: "omitted by default", policy: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{ImagePullPolicy: tt.policy}}
+			wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+			mw, err := GenerateMergeWorkflowWithGitInfo("git@github.com:owner/repo.git", "main", "ralph/test-project", "7", workflowOptionsFromConfig(cfg, execcontext.NewContext()))
+			require.NoError(t, err)
+
+			runYAML, err := wf.Render()
+			require.NoError(t, err)
+			mergeYAML, err := mw.Render()
+			require.NoError(t, err)
+
+			for _, rendered := range []string{runYAML, mergeYAML} {
+				var wfData map[string]interface{}
+				require.NoError(t, yaml.Unmarshal([]byte(rendered), &wfData))
+				tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+				container := tmpl["container"].(map[string]interface{})
+				if tt.policy != "" {
+					assert.Equal(t, tt.policy, container["imagePullPolicy"])
+				} else {
+					assert.NotContains(t, container, "imagePullPolicy")
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowRender_KeepFailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepFailed bool
+		strategy   string
+		ttlKey     string
+	}{
+		{name: "default", keepFailed: false, strategy: "OnWorkflowCompletion", ttlKey: "secondsAfterCompletion"},
+		{name: "keep failed", keepFailed: true, strategy: "OnWorkflowSuccess", ttlKey: "secondsAfterSuccess"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := execcontext.NewContext()
+			ctx.SetKeepFailed(tt.keepFailed)
+			wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, &config.RalphConfig{}, "")
+			require.NoError(t, err)
+
+			workflowYAML, err := wf.Render()
+			require.NoError(t, err)
+			var wfData map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData))
+			spec := wfData["spec"].(map[string]interface{})
+
+			assert.Equal(t, tt.strategy, spec["podGC"].(map[string]interface{})["strategy"])
+			assert.Equal(t, map[string]interface{}{tt.ttlKey: 86400}, spec["ttlStrategy"])
+		})
+	}
+}
+
 func TestSubmitWorkflow_ArgoNotInstalled(t *testing.T) {
 	wf := &Workflow{}
 
@@ -362,12 +548,32 @@ func TestSubmitWorkflow_ArgoNotInstalled(t *testing.T) {
 
 	client := &argo.MockClient{
 		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
-			return "", fmt.Errorf("argo CLI not found")
+			return "", argo.ErrArgoNotInstalled
 		},
 	}
 	_, err := wf.Submit(context.Background(), client)
 	require.Error(t, err, "Expected error when argo CLI is not installed")
-	assert.True(t, strings.Contains(err.Error(), "argo CLI not found"), "Error message should mention argo CLI not found, got: %v", err)
+	assert.ErrorIs(t, err, argo.ErrArgoNotInstalled)
+}
+
+func TestSubmitWorkflow_SubmitAttempts(t *testing.T) {
+	cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{SubmitAttempts: 1}}
+	wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, wf.SubmitAttempts)
+
+	calls := 0
+	client := &argo.MockClient{
+		SubmitYAMLFunc: func(ctx context.Context, workflowYAML string, kubeCtx argo.K8sContext) (string, error) {
+			calls++
+			return "", &argo.SubmitError{Reason: "service unavailable", Output: "Error from server (ServiceUnavailable): service unavailable"}
+		},
+	}
+	_, err = wf.Submit(context.Background(), client)
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, config.DefaultSubmitAttempts, submitAttempts(0))
 }
 
 func TestWorkflowRender_CommentBranching(t *testing.T) {
@@ -676,6 +882,46 @@ func TestKubeContextOverride(t *testing.T) {
 
 		assert.Equal(t, "config-context", wf.KubeContext, "KubeContext should fall back to config")
 	})
+
+	t.Run("namespace override takes precedence over config", func(t *testing.T) {
+		ctx := &execcontext.Context{}
+		ctx.SetKubeNamespace("override-namespace")
+
+		wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:test/repo.git", "main", "test-project", "main", "project.yaml", false, cfg, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "override-namespace", wf.Namespace, "Namespace should be set from namespace override")
+	})
+
+	t.Run("falls back to config when namespace override is empty", func(t *testing.T) {
+		ctx := &execcontext.Context{}
+
+		wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:test/repo.git", "main", "test-project", "main", "project.yaml", false, cfg, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "my-namespace", wf.Namespace, "Namespace should fall back to config")
+	})
+
+	t.Run("overrides reach the submitted workflow", func(t *testing.T) {
+		ctx := &execcontext.Context{}
+		ctx.SetKubeContext("override-context")
+		ctx.SetKubeNamespace("override-namespace")
+
+		wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:test/repo.git", "main", "test-project", "main", "project.yaml", false, cfg, "")
+		require.NoError(t, err)
+
+		var submitted argo.K8sContext
+		client := &argo.MockClient{
+			SubmitYAMLFunc: func(_ context.Context, _ string, kubeCtx argo.K8sContext) (string, error) {
+				submitted = kubeCtx
+				return "wf-123", nil
+			},
+		}
+		_, err = wf.Submit(context.Background(), client)
+		require.NoError(t, err)
+
+		assert.Equal(t, argo.K8sContext{Name: "override-context", Namespace: "override-namespace"}, submitted)
+	})
 }
 
 func TestWorkflowRender_CommandField(t *testing.T) {
@@ -794,3 +1040,357 @@ func TestGenerateCommandWorkflow(t *testing.T) {
 	assert.Equal(t, "hello", args[4], "Fifth arg should be command token 'hello'")
 	assert.Equal(t, "--verbose", args[5], "Sixth arg should be '--verbose'")
 }
+
+func TestWorkflowRender_Actor(t *testing.T) {
+	renderMetadataAndEnv := func(t *testing.T, workflowYAML string) (map[string]interface{}, map[string]interface{}) {
+		var wfData map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData), "Failed to parse workflow YAML")
+
+		metadata := wfData["metadata"].(map[string]interface{})
+		tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+		env := map[string]interface{}{}
+		for _, e := range tmpl["container"].(map[string]interface{})["env"].([]interface{}) {
+			em := e.(map[string]interface{})
+			env[em["name"].(string)] = em["value"]
+		}
+		return metadata, env
+	}
+
+	t.Run("run workflow", func(t *testing.T) {
+		ctx := execcontext.NewContext()
+		ctx.SetActor("cli:alice")
+		wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, &config.RalphConfig{}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "cli:alice", wf.Actor)
+
+		workflowYAML, err := wf.Render()
+		require.NoError(t, err)
+		metadata, env := renderMetadataAndEnv(t, workflowYAML)
+		assert.Equal(t, map[string]interface{}{ActorAnnotation: "cli:alice"}, metadata["annotations"])
+		assert.Equal(t, "cli:alice", env["RALPH_ACTOR"])
+	})
+
+	t.Run("merge workflow", func(t *testing.T) {
+		mw := &MergeWorkflow{
+			Repo:        githubpkg.MakeRepo("owner", "repo"),
+			CloneBranch: "main",
+			PRBranch:    "ralph/test-project",
+			PRNumber:    "7",
+			Actor:       "webhook:octocat",
+		}
+
+		workflowYAML, err := mw.Render()
+		require.NoError(t, err)
+		metadata, env := renderMetadataAndEnv(t, workflowYAML)
+		assert.Equal(t, map[string]interface{}{ActorAnnotation: "webhook:octocat"}, metadata["annotations"])
+		assert.Equal(t, "webhook:octocat", env["RALPH_ACTOR"])
+	})
+
+	t.Run("no actor", func(t *testing.T) {
+		wf := &Workflow{
+			ProjectName:   "test-project",
+			Repo:          githubpkg.MakeRepo("owner", "repo"),
+			CloneBranch:   "main",
+			ProjectBranch: "ralph/test-project",
+			ProjectPath:   "project.yaml",
+		}
+
+		workflowYAML, err := wf.Render()
+		require.NoError(t, err)
+		metadata, env := renderMetadataAndEnv(t, workflowYAML)
+		assert.NotContains(t, metadata, "annotations")
+		assert.NotContains(t, env, "RALPH_ACTOR")
+	})
+}
+
+// renderContainer renders wf, a run or merge workflow, and returns its main container.
+func renderContainer(t *testing.T, wf interface{ Render() (string, error) }) map[string]interface{} {
+	t.Helper()
+
+	workflowYAML, err := wf.Render()
+	require.NoError(t, err)
+
+	var wfData map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData), "Failed to parse workflow YAML")
+	tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+	return tmpl["container"].(map[string]interface{})
+}
+
+// containerEnv returns the environment of container as a name/value map.
+func containerEnv(container map[string]interface{}) map[string]interface{} {
+	env := map[string]interface{}{}
+	for _, e := range container["env"].([]interface{}) {
+		em := e.(map[string]interface{})
+		env[em["name"].(string)] = em["value"]
+	}
+	return env
+}
+
+// renderContainerEnv renders wf and returns the main container's environment as a name/value map.
+func renderContainerEnv(t *testing.T, wf *Workflow) map[string]interface{} {
+	t.Helper()
+	return containerEnv(renderContainer(t, wf))
+}
+
+func TestWorkflowRender_Submodules(t *testing.T) {
+	tests := []struct {
+		name       string
+		submodules bool
+	}{
+		{name: "enabled", submodules: true},
+		{name: "disabled by default", submodules: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{Submodules: tt.submodules}}
+			wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+
+			env := renderContainerEnv(t, wf)
+			if tt.submodules {
+				assert.Equal(t, "true", env["RALPH_SUBMODULES"])
+			} else {
+				assert.NotContains(t, env, "RALPH_SUBMODULES")
+			}
+		})
+	}
+}
+
+func TestWorkflowRender_LFS(t *testing.T) {
+	tests := []struct {
+		name string
+		lfs  bool
+	}{
+		{name: "enabled", lfs: true},
+		{name: "disabled by default", lfs: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{LFS: tt.lfs}}
+			wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+
+			env := renderContainerEnv(t, wf)
+			if tt.lfs {
+				assert.Equal(t, "true", env["RALPH_LFS"])
+			} else {
+				assert.NotContains(t, env, "RALPH_LFS")
+			}
+		})
+	}
+}
+
+func TestWorkflowRender_ArchiveLogs(t *testing.T) {
+	tests := []struct {
+		name        string
+		archiveLogs bool
+	}{
+		{name: "enabled", archiveLogs: true},
+		{name: "disabled by default", archiveLogs: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{ArchiveLogs: tt.archiveLogs}}
+			wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+
+			workflowYAML, err := wf.Render()
+			require.NoError(t, err)
+
+			var wfData map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData), "Failed to parse workflow YAML")
+			spec := wfData["spec"].(map[string]interface{})
+			if tt.archiveLogs {
+				assert.Equal(t, true, spec["archiveLogs"])
+			} else {
+				assert.NotContains(t, spec, "archiveLogs")
+			}
+		})
+	}
+}
+
+func TestWorkflowRender_Params(t *testing.T) {
+	ctx := execcontext.NewContext()
+	ctx.SetParams(map[string]string{"region": "eu-west-1", "deploy-target": "staging"})
+	wf, err := GenerateWorkflowWithGitInfo(ctx, "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, &config.RalphConfig{}, "")
+	require.NoError(t, err)
+
+	workflowYAML, err := wf.Render()
+	require.NoError(t, err)
+
+	var wfData map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData), "Failed to parse workflow YAML")
+	parameters := wfData["spec"].(map[string]interface{})["arguments"].(map[string]interface{})["parameters"].([]interface{})
+
+	var names []string
+	values := map[string]interface{}{}
+	for _, p := range parameters {
+		pm := p.(map[string]interface{})
+		names = append(names, pm["name"].(string))
+		values[pm["name"].(string)] = pm["value"]
+	}
+	assert.Equal(t, []string{"project-path", "instructions-md", "comment-body", "pr-number", "base-branch", "deploy-target", "region"}, names)
+	assert.Equal(t, "staging", values["deploy-target"])
+	assert.Equal(t, "eu-west-1", values["region"])
+
+	env := renderContainerEnv(t, wf)
+	assert.Equal(t, "{{workflow.parameters.deploy-target}}", env["RALPH_PARAM_DEPLOY_TARGET"])
+	assert.Equal(t, "{{workflow.parameters.region}}", env["RALPH_PARAM_REGION"])
+}
+
+func TestWorkflowRender_ContainerPaths(t *testing.T) {
+	mountPaths := func(container map[string]interface{}) map[string]interface{} {
+		paths := map[string]interface{}{}
+		for _, m := range container["volumeMounts"].([]interface{}) {
+			mm := m.(map[string]interface{})
+			paths[mm["name"].(string)] = mm["mountPath"]
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name         string
+		workflow     config.WorkflowConfig
+		wantWorkDir  string
+		wantCloneDir string
+		wantMount    string
+	}{
+		{
+			name:         "defaults",
+			workflow:     config.WorkflowConfig{ConfigMaps: []config.ConfigMapMount{{Name: "settings", DestDir: "settings"}}},
+			wantWorkDir:  "/workspace",
+			wantCloneDir: "/workspace/repo",
+			wantMount:    "/workspace/settings",
+		},
+		{
+			name:         "custom work dir",
+			workflow:     config.WorkflowConfig{WorkDir: "/home/runner", ConfigMaps: []config.ConfigMapMount{{Name: "settings", DestDir: "settings"}}},
+			wantWorkDir:  "/home/runner",
+			wantCloneDir: "/home/runner/repo",
+			wantMount:    "/home/runner/settings",
+		},
+		{
+			name:         "relative clone dir",
+			workflow:     config.WorkflowConfig{WorkDir: "/home/runner", CloneDir: "src"},
+			wantWorkDir:  "/home/runner",
+			wantCloneDir: "/home/runner/src",
+		},
+		{
+			name:         "absolute clone dir",
+			workflow:     config.WorkflowConfig{CloneDir: "/src/app"},
+			wantWorkDir:  "/workspace",
+			wantCloneDir: "/src/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.RalphConfig{Workflow: tt.workflow}
+			wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+			require.NoError(t, err)
+
+			container := renderContainer(t, wf)
+			assert.Equal(t, tt.wantWorkDir, container["workingDir"])
+			assert.Equal(t, tt.wantCloneDir, containerEnv(container)["RALPH_CLONE_DIR"])
+			if tt.wantMount != "" {
+				assert.Equal(t, tt.wantMount, mountPaths(container)["settings"])
+			}
+
+			mw, err := GenerateMergeWorkflowWithGitInfo("git@github.com:owner/repo.git", "main", "ralph/test-project", "7", WorkflowOptions{WorkDir: tt.workflow.WorkDir, CloneDir: tt.workflow.CloneDir})
+			require.NoError(t, err)
+
+			mergeContainer := renderContainer(t, mw)
+			assert.Equal(t, tt.wantWorkDir, mergeContainer["workingDir"])
+			assert.Equal(t, tt.wantCloneDir, containerEnv(mergeContainer)["RALPH_CLONE_DIR"])
+		})
+	}
+}
+
+func TestWorkflowRender_Shell(t *testing.T) {
+	cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{Shell: "/bin/bash"}}
+	wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+	require.NoError(t, err)
+
+	container := renderContainer(t, wf)
+	command := container["command"].([]interface{})
+	require.Len(t, command, 3)
+	assert.Equal(t, "/bin/bash", command[0])
+	assert.Equal(t, "-c", command[1])
+	assert.True(t, strings.HasPrefix(command[2].(string), "'ralph' 'workflow' 'run'"), "script should invoke ralph workflow run: %s", command[2])
+	assert.Empty(t, container["args"])
+
+	mw, err := GenerateMergeWorkflowWithGitInfo("git@github.com:owner/repo.git", "main", "ralph/test-project", "7", WorkflowOptions{Shell: "/bin/bash"})
+	require.NoError(t, err)
+
+	mergeCommand := renderContainer(t, mw)["command"].([]interface{})
+	require.Len(t, mergeCommand, 3)
+	assert.Equal(t, "/bin/bash", mergeCommand[0])
+	assert.True(t, strings.HasPrefix(mergeCommand[2].(string), "'ralph' 'workflow' 'merge'"), "script should invoke ralph workflow merge: %s", mergeCommand[2])
+}
+
+func TestWorkflowRender_RejectsDuplicateMountTargets(t *testing.T) {
+	wf := &Workflow{
+		ProjectName:   "test-project",
+		Repo:          githubpkg.MakeRepo("owner", "repo"),
+		CloneBranch:   "main",
+		ProjectBranch: "ralph/test-project",
+		ConfigMaps: []config.ConfigMapMount{
+			{Name: "app", DestFile: "config.yaml"},
+			{Name: "other", DestFile: "config.yaml"},
+		},
+	}
+
+	_, err := wf.Render()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid workflow mounts")
+}
+
+func TestWorkflowRender_EnvFromReferences(t *testing.T) {
+	cfg := &config.RalphConfig{Workflow: config.WorkflowConfig{
+		EnvFrom: []config.EnvFromSource{{Secret: "app-env"}, {ConfigMap: "app-settings"}},
+		EnvRefs: []config.EnvRef{
+			{Name: "API_TOKEN", Secret: "api", Key: "token"},
+			{Name: "REGION", ConfigMap: "app-settings", Key: "region"},
+		},
+	}}
+	wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, cfg, "")
+	require.NoError(t, err)
+
+	workflowYAML, err := wf.Render()
+	require.NoError(t, err)
+
+	var wfData map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(workflowYAML), &wfData), "Failed to parse workflow YAML")
+	tmpl := wfData["spec"].(map[string]interface{})["templates"].([]interface{})[0].(map[string]interface{})
+	container := tmpl["container"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"secretRef": map[string]interface{}{"name": "app-env"}},
+		map[string]interface{}{"configMapRef": map[string]interface{}{"name": "app-settings"}},
+	}, container["envFrom"])
+
+	valueFrom := map[string]interface{}{}
+	for _, e := range container["env"].([]interface{}) {
+		em := e.(map[string]interface{})
+		if vf, ok := em["valueFrom"]; ok {
+			valueFrom[em["name"].(string)] = vf
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"API_TOKEN": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "api", "key": "token"}},
+		"REGION":    map[string]interface{}{"configMapKeyRef": map[string]interface{}{"name": "app-settings", "key": "region"}},
+	}, valueFrom)
+}
+
+func TestWorkflowRender_NoEnvFrom(t *testing.T) {
+	wf, err := GenerateWorkflowWithGitInfo(execcontext.NewContext(), "test-project", "git@github.com:owner/repo.git", "main", "ralph/test-project", "main", "projects/test-project.yaml", false, &config.RalphConfig{}, "")
+	require.NoError(t, err)
+
+	workflowYAML, err := wf.Render()
+	require.NoError(t, err)
+	assert.NotContains(t, workflowYAML, "envFrom")
+	assert.NotContains(t, workflowYAML, "valueFrom")
+}
@@ -1,12 +1,65 @@
 package workflow
 
-// Image holds the container image repository and tag for a workflow.
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zon/ralph/internal/config"
+)
+
+// Image holds the container image repository, tag, optional digest and pull policy for a workflow.
 type Image struct {
 	Repository string
 	Tag        string
+	Digest     string
+	PullPolicy string
 }
 
 // MakeImage creates an Image with the given repository and tag.
 func MakeImage(repository, tag string) Image {
 	return Image{Repository: repository, Tag: tag}
 }
+
+// imageFromConfig creates an Image from the workflow configuration, including its digest and pull policy.
+func imageFromConfig(w config.WorkflowConfig) Image {
+	return Image{Repository: w.Image.Repository, Tag: w.Image.Tag, Digest: w.Image.Digest, PullPolicy: w.ImagePullPolicy}
+}
+
+// ResolvedImage returns the container image reference a workflow runs with the given
+// configuration, falling back to the default repository and version.
+func ResolvedImage(w config.WorkflowConfig) string {
+	return resolveImage(imageFromConfig(w))
+}
+
+// imageRepositoryPattern matches an image repository: an optional registry host with port,
+// then lowercase path components separated by '.', '_', '-' or '/'
+var imageRepositoryPattern = regexp.MustCompile(`^([a-zA-Z0-9.-]+(:[0-9]+)?/)?[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+
+var imageTagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// ParseImageRef parses an --image reference of the form repository:tag or repository@sha256:<digest>.
+// A bare repository is rejected so the flag always pins the image it runs.
+func ParseImageRef(ref string) (Image, error) {
+	if repository, digest, ok := strings.Cut(ref, "@"); ok {
+		if err := config.ValidateImageDigest(digest); err != nil {
+			return Image{}, fmt.Errorf("invalid --image %q: %w", ref, err)
+		}
+		if !imageRepositoryPattern.MatchString(repository) {
+			return Image{}, fmt.Errorf("invalid --image %q: invalid repository %q", ref, repository)
+		}
+		return Image{Repository: repository, Digest: digest}, nil
+	}
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i:], "/") {
+		return Image{}, fmt.Errorf("invalid --image %q: expected repository:tag or repository@sha256:<digest>", ref)
+	}
+	repository, tag := ref[:i], ref[i+1:]
+	if !imageRepositoryPattern.MatchString(repository) {
+		return Image{}, fmt.Errorf("invalid --image %q: invalid repository %q", ref, repository)
+	}
+	if !imageTagPattern.MatchString(tag) {
+		return Image{}, fmt.Errorf("invalid --image %q: invalid tag %q", ref, tag)
+	}
+	return MakeImage(repository, tag), nil
+}
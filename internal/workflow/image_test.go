@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		ref     string
+		want    Image
+		wantErr string
+	}{
+		{name: "repository and tag", ref: "ghcr.io/me/ralph:pr-42", want: MakeImage("ghcr.io/me/ralph", "pr-42")},
+		{name: "registry with port", ref: "localhost:5000/ralph:dev", want: MakeImage("localhost:5000/ralph", "dev")},
+		{name: "single component", ref: "ralph:1.2.3", want: MakeImage("ralph", "1.2.3")},
+		{name: "digest", ref: "ghcr.io/me/ralph@" + digest, want: Image{Repository: "ghcr.io/me/ralph", Digest: digest}},
+		{name: "bare repository", ref: "ghcr.io/me/ralph", wantErr: "expected repository:tag or repository@sha256:<digest>"},
+		{name: "registry port without tag", ref: "localhost:5000/ralph", wantErr: "expected repository:tag"},
+		{name: "empty tag", ref: "ralph:", wantErr: `invalid tag ""`},
+		{name: "invalid tag", ref: "ralph:-dev", wantErr: `invalid tag "-dev"`},
+		{name: "uppercase repository", ref: "ghcr.io/Me/Ralph:dev", wantErr: `invalid repository "ghcr.io/Me/Ralph"`},
+		{name: "empty repository", ref: ":dev", wantErr: `invalid repository ""`},
+		{name: "short digest", ref: "ralph@sha256:abc", wantErr: `has invalid digest "sha256:abc"`},
+		{name: "tag and digest", ref: "ralph:dev@" + digest, wantErr: `invalid repository "ralph:dev"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := ParseImageRef(tt.ref)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, image)
+		})
+	}
+}
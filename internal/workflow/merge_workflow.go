@@ -27,6 +27,18 @@ type MergeWorkflow struct {
 	KubeContext string
 	// Namespace is the Kubernetes namespace for workflow submission.
 	Namespace string
+	// Actor is who or what triggered the merge; recorded as a workflow annotation and passed to the container.
+	Actor string
+	// WorkDir is the container working directory (default: /workspace).
+	WorkDir string
+	// CloneDir is where the container clones the repository; relative paths resolve under WorkDir (default: <WorkDir>/repo).
+	CloneDir string
+	// Entrypoint replaces the ralph executable in the container command (default: ralph).
+	Entrypoint []string
+	// Shell, when set, runs the container command as a single script through `<Shell> -c`.
+	Shell string
+	// SubmitAttempts is how many times a transiently failing submission is tried (default: config.DefaultSubmitAttempts).
+	SubmitAttempts int
 }
 
 // Render produces the Argo Workflow YAML string for this MergeWorkflow.
@@ -34,12 +46,9 @@ func (m *MergeWorkflow) Render() (string, error) {
 	wf := map[string]interface{}{
 		"apiVersion": "argoproj.io/v1alpha1",
 		"kind":       "Workflow",
-		"metadata": map[string]interface{}{
-			"generateName": "ralph-merge-",
-			"labels": map[string]string{
-				"app.kubernetes.io/managed-by": "ralph",
-			},
-		},
+		"metadata": workflowMetadata("ralph-merge-", map[string]string{
+			"app.kubernetes.io/managed-by": "ralph",
+		}, m.Actor),
 		"spec": map[string]interface{}{
 			"entrypoint": "ralph-merger",
 			"ttlStrategy": map[string]interface{}{
@@ -75,37 +84,50 @@ func (m *MergeWorkflow) Submit(ctx context.Context, client argo.Client) (string,
 	if err != nil {
 		return "", err
 	}
-	return client.SubmitYAML(ctx, workflowYAML, argo.K8sContext{Name: m.KubeContext, Namespace: m.Namespace})
+	return argo.SubmitWithRetry(ctx, client, workflowYAML, argo.K8sContext{Name: m.KubeContext, Namespace: m.Namespace}, submitAttempts(m.SubmitAttempts))
 }
 
 func (m *MergeWorkflow) buildMergeTemplate() map[string]interface{} {
-	return map[string]interface{}{
-		"name": "ralph-merger",
-		"container": map[string]interface{}{
-			"image": resolveImage(m.Image.Repository, m.Image.Tag),
-			"command": []string{"ralph"},
-			"args": []string{
-				"workflow", "merge",
-				"--pr-branch", m.PRBranch,
-				"--pr", m.PRNumber,
-				"--repo", m.Repo.Owner + "/" + m.Repo.Name,
-				"--clone-branch", m.CloneBranch,
-				"--bot-name", config.DefaultAppName + "[bot]",
-				"--bot-email", config.DefaultAppName + "[bot]@users.noreply.github.com",
-			},
-			"env": []map[string]interface{}{
-				{"name": "GIT_REPO_URL", "value": m.Repo.CloneURL()},
-				{"name": "GITHUB_REPO_OWNER", "value": m.Repo.Owner},
-				{"name": "GITHUB_REPO_NAME", "value": m.Repo.Name},
-				{"name": "GIT_BRANCH", "value": m.CloneBranch},
-				{"name": "PR_BRANCH", "value": m.PRBranch},
-				{"name": "PR_NUMBER", "value": m.PRNumber},
-			},
-			"volumeMounts": []map[string]interface{}{
-				{"name": "github-credentials", "mountPath": "/secrets/github", "readOnly": true},
-			},
-			"workingDir": "/workspace",
+	envVars := []map[string]interface{}{
+		{"name": "GIT_REPO_URL", "value": m.Repo.CloneURL()},
+		{"name": "GITHUB_REPO_OWNER", "value": m.Repo.Owner},
+		{"name": "GITHUB_REPO_NAME", "value": m.Repo.Name},
+		{"name": "GIT_BRANCH", "value": m.CloneBranch},
+		{"name": "PR_BRANCH", "value": m.PRBranch},
+		{"name": "PR_NUMBER", "value": m.PRNumber},
+		{"name": "RALPH_CLONE_DIR", "value": resolveCloneDir(m.WorkDir, m.CloneDir)},
+	}
+	if m.Actor != "" {
+		envVars = append(envVars, map[string]interface{}{"name": "RALPH_ACTOR", "value": m.Actor})
+	}
+
+	command, args := containerCommand(m.Entrypoint, m.Shell, []string{
+		"workflow", "merge",
+		"--pr-branch", m.PRBranch,
+		"--pr", m.PRNumber,
+		"--repo", m.Repo.Owner + "/" + m.Repo.Name,
+		"--clone-branch", m.CloneBranch,
+		"--bot-name", config.DefaultAppName + "[bot]",
+		"--bot-email", config.DefaultAppName + "[bot]@users.noreply.github.com",
+	})
+
+	container := map[string]interface{}{
+		"image":   resolveImage(m.Image),
+		"command": command,
+		"args":    args,
+		"env":     envVars,
+		"volumeMounts": []map[string]interface{}{
+			{"name": "github-credentials", "mountPath": "/secrets/github", "readOnly": true},
 		},
+		"workingDir": resolveWorkDir(m.WorkDir),
+	}
+	if m.Image.PullPolicy != "" {
+		container["imagePullPolicy"] = m.Image.PullPolicy
+	}
+
+	return map[string]interface{}{
+		"name":      "ralph-merger",
+		"container": container,
 		"volumes": []map[string]interface{}{
 			{
 				"name": "github-credentials",
@@ -13,22 +13,46 @@ type WorkflowOptions struct {
 	KubeContext string
 	Namespace   string
 	Labels      map[string]string
+	Submodules  bool
+	LFS         bool
+	ArchiveLogs bool
+	WorkDir     string
+	CloneDir    string
+	Entrypoint  []string
+	Shell       string
+	EnvFrom     []config.EnvFromSource
+	EnvRefs     []config.EnvRef
+	// SubmitAttempts is how many times a transiently failing submission is tried
+	SubmitAttempts int
 }
 
 func workflowOptionsFromConfig(cfg *config.RalphConfig, ctx *execcontext.Context) WorkflowOptions {
 	opts := WorkflowOptions{
-		Image:       MakeImage(cfg.Workflow.Image.Repository, cfg.Workflow.Image.Tag),
-		ConfigMaps:  cfg.Workflow.ConfigMaps,
-		Secrets:     cfg.Workflow.Secrets,
-		Env:         cfg.Workflow.Env,
-		KubeContext: cfg.Workflow.Context,
-		Namespace:   cfg.Workflow.Namespace,
-		Labels:      cfg.Workflow.Labels,
+		Image:          imageFromConfig(cfg.Workflow),
+		ConfigMaps:     cfg.Workflow.ConfigMaps,
+		Secrets:        cfg.Workflow.Secrets,
+		Env:            cfg.Workflow.Env,
+		KubeContext:    cfg.Workflow.Context,
+		Namespace:      cfg.Workflow.Namespace,
+		Labels:         cfg.Workflow.Labels,
+		Submodules:     cfg.Workflow.Submodules,
+		LFS:            cfg.Workflow.LFS,
+		ArchiveLogs:    cfg.Workflow.ArchiveLogs,
+		WorkDir:        cfg.Workflow.WorkDir,
+		CloneDir:       cfg.Workflow.CloneDir,
+		Entrypoint:     cfg.Workflow.Entrypoint,
+		Shell:          cfg.Workflow.Shell,
+		EnvFrom:        cfg.Workflow.EnvFrom,
+		EnvRefs:        cfg.Workflow.EnvRefs,
+		SubmitAttempts: cfg.Workflow.SubmitAttempts,
 	}
 
 	if ctx != nil && ctx.KubeContext() != "" {
 		opts.KubeContext = ctx.KubeContext()
 	}
+	if ctx != nil && ctx.KubeNamespace() != "" {
+		opts.Namespace = ctx.KubeNamespace()
+	}
 
 	return opts
 }
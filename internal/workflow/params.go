@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// builtinParameters are the workflow parameters ralph always sets, in the order they are rendered.
+var builtinParameters = []string{"project-path", "instructions-md", "comment-body", "pr-number", "base-branch"}
+
+var paramKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ParamEnvPrefix prefixes the container environment variable that carries each custom parameter.
+const ParamEnvPrefix = "RALPH_PARAM_"
+
+// ParseParams parses key=value pairs from --param flags into custom workflow parameters.
+// Keys must start with a letter, contain only letters, digits, '-' and '_', and must not
+// repeat or shadow a parameter ralph sets itself. Two keys that map to the same environment
+// variable, such as foo-bar and FOO_BAR, are rejected too.
+func ParseParams(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string, len(values))
+	envKeys := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --param %q: expected key=value", value)
+		}
+		if !paramKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid --param key %q: must start with a letter and contain only letters, digits, '-' and '_'", key)
+		}
+		for _, builtin := range builtinParameters {
+			if key == builtin {
+				return nil, fmt.Errorf("invalid --param key %q: reserved by ralph", key)
+			}
+		}
+		if _, exists := params[key]; exists {
+			return nil, fmt.Errorf("duplicate --param key %q", key)
+		}
+		env := paramEnvName(key)
+		if other, exists := envKeys[env]; exists {
+			return nil, fmt.Errorf("invalid --param key %q: %q already sets %s", key, other, env)
+		}
+		envKeys[env] = key
+		params[key] = val
+	}
+	return params, nil
+}
+
+// paramEnvName returns the container environment variable name for a custom parameter key.
+func paramEnvName(key string) string {
+	return ParamEnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
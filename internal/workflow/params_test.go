@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "none", values: nil, want: nil},
+		{name: "key value pairs", values: []string{"env=staging", "replicas=3"}, want: map[string]string{"env": "staging", "replicas": "3"}},
+		{name: "value may contain equals", values: []string{"query=a=b"}, want: map[string]string{"query": "a=b"}},
+		{name: "empty value", values: []string{"flag="}, want: map[string]string{"flag": ""}},
+		{name: "missing equals", values: []string{"env"}, wantErr: "expected key=value"},
+		{name: "empty key", values: []string{"=staging"}, wantErr: "expected key=value"},
+		{name: "invalid key", values: []string{"my key=value"}, wantErr: "must start with a letter"},
+		{name: "leading digit", values: []string{"1env=staging"}, wantErr: "must start with a letter"},
+		{name: "reserved key", values: []string{"project-path=other.yaml"}, wantErr: "reserved by ralph"},
+		{name: "duplicate key", values: []string{"env=staging", "env=prod"}, wantErr: "duplicate --param key"},
+		{name: "same env var", values: []string{"foo-bar=a", "foo_bar=b"}, wantErr: `"foo-bar" already sets RALPH_PARAM_FOO_BAR`},
+		{name: "same env var by case", values: []string{"region=a", "Region=b"}, wantErr: `"region" already sets RALPH_PARAM_REGION`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseParams(tt.values)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParamEnvName(t *testing.T) {
+	assert.Equal(t, "RALPH_PARAM_DEPLOY_TARGET", paramEnvName("deploy-target"))
+	assert.Equal(t, "RALPH_PARAM_REGION", paramEnvName("region"))
+}
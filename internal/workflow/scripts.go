@@ -1,19 +1,23 @@
 package workflow
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/zon/ralph/internal/config"
 	"github.com/zon/ralph/internal/k8s"
+	"github.com/zon/ralph/internal/workspace"
 )
 
-// buildParameters builds workflow parameters from the params map
-func buildParameters(params map[string]string) []map[string]interface{} {
-	allParams := []string{"project-path", "instructions-md", "comment-body", "pr-number", "base-branch"}
+// buildParameters builds workflow parameters from the params map, followed by the custom parameters sorted by name
+func buildParameters(params map[string]string, custom map[string]string) []map[string]interface{} {
 	var parameters []map[string]interface{}
-	for _, name := range allParams {
+	for _, name := range builtinParameters {
 		param := map[string]interface{}{"name": name}
 		if value, exists := params[name]; exists {
 			param["value"] = value
@@ -22,10 +26,68 @@ func buildParameters(params map[string]string) []map[string]interface{} {
 		}
 		parameters = append(parameters, param)
 	}
+	for _, name := range sortedKeys(custom) {
+		parameters = append(parameters, map[string]interface{}{"name": name, "value": custom[name]})
+	}
 	return parameters
 }
 
-func buildConfigMapVolumeMount(name string, destFile, destDir string, index int) map[string]interface{} {
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DefaultContainerWorkDir is the container working directory when workflow.workDir is unset.
+const DefaultContainerWorkDir = workspace.DefaultWorkspaceDir
+
+// resolveWorkDir returns the container working directory, falling back to DefaultContainerWorkDir.
+func resolveWorkDir(workDir string) string {
+	if workDir == "" {
+		return DefaultContainerWorkDir
+	}
+	return workDir
+}
+
+// resolveCloneDir returns where the container clones the repository. It defaults to a repo
+// directory under the working directory, and relative paths resolve under the working directory.
+func resolveCloneDir(workDir, cloneDir string) string {
+	if cloneDir == "" {
+		cloneDir = "repo"
+	}
+	if path.IsAbs(cloneDir) {
+		return cloneDir
+	}
+	return path.Join(resolveWorkDir(workDir), cloneDir)
+}
+
+// containerCommand returns the container command and args that invoke ralph with args.
+// The entrypoint replaces the ralph executable; with a shell, the whole invocation is
+// quoted into a single script run by `<shell> -c`.
+func containerCommand(entrypoint []string, shell string, args []string) ([]string, []string) {
+	command := []string{"ralph"}
+	if len(entrypoint) > 0 {
+		command = append([]string{}, entrypoint...)
+	}
+	if shell == "" {
+		return command, args
+	}
+	words := make([]string, 0, len(command)+len(args))
+	for _, word := range append(command, args...) {
+		words = append(words, shellQuote(word))
+	}
+	return []string{shell, "-c", strings.Join(words, " ")}, nil
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func buildConfigMapVolumeMount(name string, destFile, destDir string, index int, workDir string) map[string]interface{} {
 	mount := map[string]interface{}{
 		"name":     sanitizeName(name),
 		"readOnly": true,
@@ -34,15 +96,15 @@ func buildConfigMapVolumeMount(name string, destFile, destDir string, index int)
 		if filepath.IsAbs(destFile) {
 			mount["mountPath"] = destFile
 		} else {
-			mount["mountPath"] = "/workspace/" + destFile
+			mount["mountPath"] = path.Join(workDir, destFile)
 		}
 		mount["subPath"] = filepath.Base(destFile)
-		mount["name"] = fmt.Sprintf("%s-%d", sanitizeName(name), index)
+		mount["name"] = indexedName(name, index)
 	} else if destDir != "" {
 		if filepath.IsAbs(destDir) {
 			mount["mountPath"] = destDir
 		} else {
-			mount["mountPath"] = "/workspace/" + destDir
+			mount["mountPath"] = path.Join(workDir, destDir)
 		}
 	} else {
 		mount["mountPath"] = fmt.Sprintf("/configmaps/%s", name)
@@ -50,7 +112,7 @@ func buildConfigMapVolumeMount(name string, destFile, destDir string, index int)
 	return mount
 }
 
-func buildSecretVolumeMount(name string, destFile, destDir string, index int) map[string]interface{} {
+func buildSecretVolumeMount(name string, destFile, destDir string, index int, workDir string) map[string]interface{} {
 	mount := map[string]interface{}{
 		"name":     sanitizeName(name),
 		"readOnly": true,
@@ -59,15 +121,15 @@ func buildSecretVolumeMount(name string, destFile, destDir string, index int) ma
 		if filepath.IsAbs(destFile) {
 			mount["mountPath"] = destFile
 		} else {
-			mount["mountPath"] = "/workspace/" + destFile
+			mount["mountPath"] = path.Join(workDir, destFile)
 		}
 		mount["subPath"] = filepath.Base(destFile)
-		mount["name"] = fmt.Sprintf("%s-%d", sanitizeName(name), index)
+		mount["name"] = indexedName(name, index)
 	} else if destDir != "" {
 		if filepath.IsAbs(destDir) {
 			mount["mountPath"] = destDir
 		} else {
-			mount["mountPath"] = "/workspace/" + destDir
+			mount["mountPath"] = path.Join(workDir, destDir)
 		}
 	} else {
 		mount["mountPath"] = fmt.Sprintf("/secrets/%s", name)
@@ -78,7 +140,7 @@ func buildSecretVolumeMount(name string, destFile, destDir string, index int) ma
 func buildConfigMapVolume(name string, destFile string, index int) map[string]interface{} {
 	volumeName := sanitizeName(name)
 	if destFile != "" {
-		volumeName = fmt.Sprintf("%s-%d", sanitizeName(name), index)
+		volumeName = indexedName(name, index)
 		return map[string]interface{}{
 			"name": volumeName,
 			"configMap": map[string]interface{}{
@@ -98,7 +160,7 @@ func buildConfigMapVolume(name string, destFile string, index int) map[string]in
 func buildSecretVolume(name string, destFile string, index int) map[string]interface{} {
 	volumeName := sanitizeName(name)
 	if destFile != "" {
-		volumeName = fmt.Sprintf("%s-%d", sanitizeName(name), index)
+		volumeName = indexedName(name, index)
 		return map[string]interface{}{
 			"name": volumeName,
 			"secret": map[string]interface{}{
@@ -115,6 +177,54 @@ func buildSecretVolume(name string, destFile string, index int) map[string]inter
 	}
 }
 
+// buildProjectedVolume builds a projected volume exposing several keys of one ConfigMap or
+// Secret at their own paths; sourceType is "configMap" or "secret".
+func buildProjectedVolume(sourceType, name string, items []config.MountItem) map[string]interface{} {
+	projectedItems := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		itemPath := item.Path
+		if itemPath == "" {
+			itemPath = item.Key
+		}
+		projectedItems = append(projectedItems, map[string]interface{}{"key": item.Key, "path": itemPath})
+	}
+	return map[string]interface{}{
+		"name": sanitizeName(name),
+		"projected": map[string]interface{}{
+			"sources": []map[string]interface{}{
+				{sourceType: map[string]interface{}{"name": name, "items": projectedItems}},
+			},
+		},
+	}
+}
+
+// buildEnvFrom builds the container envFrom list importing whole Secrets and ConfigMaps.
+func buildEnvFrom(sources []config.EnvFromSource) []map[string]interface{} {
+	envFrom := make([]map[string]interface{}, 0, len(sources))
+	for _, source := range sources {
+		if source.Secret != "" {
+			envFrom = append(envFrom, map[string]interface{}{"secretRef": map[string]interface{}{"name": source.Secret}})
+		} else {
+			envFrom = append(envFrom, map[string]interface{}{"configMapRef": map[string]interface{}{"name": source.ConfigMap}})
+		}
+	}
+	return envFrom
+}
+
+// buildEnvRef builds a container env var whose value comes from a Secret or ConfigMap key.
+func buildEnvRef(ref config.EnvRef) map[string]interface{} {
+	keyRef := map[string]interface{}{"key": ref.Key}
+	valueFrom := map[string]interface{}{}
+	if ref.Secret != "" {
+		keyRef["name"] = ref.Secret
+		valueFrom["secretKeyRef"] = keyRef
+	} else {
+		keyRef["name"] = ref.ConfigMap
+		valueFrom["configMapKeyRef"] = keyRef
+	}
+	return map[string]interface{}{"name": ref.Name, "valueFrom": valueFrom}
+}
+
 func buildCredentialVolumes() []map[string]interface{} {
 	return []map[string]interface{}{
 		{
@@ -139,15 +249,16 @@ func buildCredentialMounts() []map[string]interface{} {
 	}
 }
 
-func buildVolumeMounts(configMaps []config.ConfigMapMount, secrets []config.SecretMount) []map[string]interface{} {
+func buildVolumeMounts(configMaps []config.ConfigMapMount, secrets []config.SecretMount, workDir string) []map[string]interface{} {
 	mounts := buildCredentialMounts()
+	names := newVolumeNamer(mounts)
 
 	for i, cm := range configMaps {
-		mounts = append(mounts, buildConfigMapVolumeMount(cm.Name, cm.DestFile, cm.DestDir, i))
+		mounts = append(mounts, names.rename(buildConfigMapVolumeMount(cm.Name, cm.DestFile, cm.DestDir, i, workDir)))
 	}
 
 	for i, secret := range secrets {
-		mounts = append(mounts, buildSecretVolumeMount(secret.Name, secret.DestFile, secret.DestDir, i))
+		mounts = append(mounts, names.rename(buildSecretVolumeMount(secret.Name, secret.DestFile, secret.DestDir, i, workDir)))
 	}
 
 	return mounts
@@ -155,47 +266,127 @@ func buildVolumeMounts(configMaps []config.ConfigMapMount, secrets []config.Secr
 
 func buildVolumes(configMaps []config.ConfigMapMount, secrets []config.SecretMount) []map[string]interface{} {
 	volumes := buildCredentialVolumes()
+	names := newVolumeNamer(volumes)
 
 	for i, cm := range configMaps {
-		volumes = append(volumes, buildConfigMapVolume(cm.Name, cm.DestFile, i))
+		if cm.DestFile == "" && len(cm.Items) > 0 {
+			volumes = append(volumes, names.rename(buildProjectedVolume("configMap", cm.Name, cm.Items)))
+			continue
+		}
+		volumes = append(volumes, names.rename(buildConfigMapVolume(cm.Name, cm.DestFile, i)))
 	}
 
 	for i, secret := range secrets {
-		volumes = append(volumes, buildSecretVolume(secret.Name, secret.DestFile, i))
+		if secret.DestFile == "" && len(secret.Items) > 0 {
+			volumes = append(volumes, names.rename(buildProjectedVolume("secret", secret.Name, secret.Items)))
+			continue
+		}
+		volumes = append(volumes, names.rename(buildSecretVolume(secret.Name, secret.DestFile, i)))
 	}
 
 	return volumes
 }
 
-// sanitizeName sanitizes a name for use as a Kubernetes volume/resource name.
-func sanitizeName(name string) string {
-	// Replace common special characters with hyphens
-	sanitized := strings.ReplaceAll(name, "_", "-")
-	sanitized = strings.ReplaceAll(sanitized, ".", "-")
-	sanitized = strings.ReplaceAll(sanitized, "/", "-")
-	sanitized = strings.ToLower(sanitized)
+// volumeNamer keeps volume names unique within a pod. buildVolumes and buildVolumeMounts
+// rename entries in the same order, so each mount keeps the name of its volume.
+type volumeNamer struct {
+	used map[string]bool
+}
+
+func newVolumeNamer(reserved []map[string]interface{}) *volumeNamer {
+	n := &volumeNamer{used: map[string]bool{}}
+	for _, entry := range reserved {
+		n.used[entry["name"].(string)] = true
+	}
+	return n
+}
+
+// rename gives entry the first free name among its own name and name-2, name-3, ...
+func (n *volumeNamer) rename(entry map[string]interface{}) map[string]interface{} {
+	base := entry["name"].(string)
+	name := base
+	for i := 2; n.used[name]; i++ {
+		candidate := fmt.Sprintf("%s-%d", base, i)
+		name = truncateName(candidate, candidate)
+	}
+	n.used[name] = true
+	entry["name"] = name
+	return entry
+}
+
+// validateMountTargets returns an error when two mounts, including the credential mounts,
+// target the same path in the container.
+func validateMountTargets(configMaps []config.ConfigMapMount, secrets []config.SecretMount, workDir string) error {
+	targets := map[string]string{}
+	for _, mount := range buildCredentialMounts() {
+		targets[mount["mountPath"].(string)] = mount["name"].(string)
+	}
+
+	check := func(kind, name string, mount map[string]interface{}) error {
+		mountPath := mount["mountPath"].(string)
+		source := fmt.Sprintf("%s %q", kind, name)
+		if other, exists := targets[mountPath]; exists {
+			return fmt.Errorf("%s and %s both mount at %s", other, source, mountPath)
+		}
+		targets[mountPath] = source
+		return nil
+	}
 
-	// Remove any other non-alphanumeric characters except hyphens
+	for i, cm := range configMaps {
+		if err := check("configMap", cm.Name, buildConfigMapVolumeMount(cm.Name, cm.DestFile, cm.DestDir, i, workDir)); err != nil {
+			return err
+		}
+	}
+	for i, secret := range secrets {
+		if err := check("secret", secret.Name, buildSecretVolumeMount(secret.Name, secret.DestFile, secret.DestDir, i, workDir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxNameLength is the longest DNS-1123 label Kubernetes accepts as a volume name.
+const maxNameLength = 63
+
+// sanitizeName sanitizes a name for use as a Kubernetes volume/resource name, producing a
+// valid DNS-1123 label: lowercase alphanumerics and single hyphens, at most 63 characters.
+func sanitizeName(name string) string {
+	// Replace every run of characters outside [a-z0-9] with a single hyphen
 	var result strings.Builder
-	for _, r := range sanitized {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
 			result.WriteRune(r)
-		} else {
+		} else if !strings.HasSuffix(result.String(), "-") {
 			result.WriteRune('-')
 		}
 	}
 
 	// Remove leading/trailing hyphens and ensure not empty
-	sanitized = result.String()
-	sanitized = strings.Trim(sanitized, "-")
+	sanitized := strings.Trim(result.String(), "-")
 	if sanitized == "" {
 		return "default"
 	}
 
 	// Ensure it starts with a letter
-	if len(sanitized) > 0 && sanitized[0] >= '0' && sanitized[0] <= '9' {
+	if sanitized[0] >= '0' && sanitized[0] <= '9' {
 		sanitized = "branch-" + sanitized
 	}
 
-	return sanitized
+	return truncateName(sanitized, name)
+}
+
+// indexedName returns the sanitized name with an index suffix, used for single-file mounts.
+func indexedName(name string, index int) string {
+	return truncateName(fmt.Sprintf("%s-%d", sanitizeName(name), index), fmt.Sprintf("%s-%d", name, index))
+}
+
+// truncateName shortens name to maxNameLength, replacing the tail with a hash of key so
+// that distinct long names stay distinct.
+func truncateName(name, key string) string {
+	if len(name) <= maxNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(name[:maxNameLength-len(hash)-1], "-") + "-" + hash
 }
@@ -1,9 +1,11 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zon/ralph/internal/config"
 )
@@ -18,7 +20,7 @@ func TestBuildVolumeMounts_WorkspacePrefix(t *testing.T) {
 		{Name: "my-secret-dir", DestDir: "config/auth"},
 	}
 
-	mounts := buildVolumeMounts(configMaps, secrets)
+	mounts := buildVolumeMounts(configMaps, secrets, DefaultContainerWorkDir)
 
 	expected := map[string]string{
 		"my-config-0":   "/workspace/config/main.yaml",
@@ -55,6 +57,12 @@ func TestSanitizeName(t *testing.T) {
 		{name: "leading_digits", input: "123-branch", expected: "branch-123-branch"},
 		{name: "leading_only_dashes", input: "---test---", expected: "test"},
 		{name: "empty_string", input: "", expected: "default"},
+		{name: "repeated_separators", input: "my__config..map", expected: "my-config-map"},
+		{name: "leading_dash", input: "-leading", expected: "leading"},
+		{name: "spaces", input: "  my config  ", expected: "my-config"},
+		{name: "unicode_letters", input: "café/straße", expected: "caf-stra-e"},
+		{name: "unicode_only", input: "日本語", expected: "default"},
+		{name: "max_length_kept", input: strings.Repeat("a", 63), expected: strings.Repeat("a", 63)},
 	}
 
 	for _, tt := range tests {
@@ -65,6 +73,21 @@ func TestSanitizeName(t *testing.T) {
 	}
 }
 
+func TestSanitizeName_Truncates(t *testing.T) {
+	long := "feature/" + strings.Repeat("very-long-branch-name-", 5)
+	other := long + "2"
+
+	result := sanitizeName(long)
+	assert.Len(t, result, maxNameLength)
+	assert.True(t, strings.HasPrefix(result, "feature-very-long-branch-name"), "truncated name should keep its prefix: %s", result)
+	assert.Regexp(t, `^[a-z]([-a-z0-9]*[a-z0-9])?$`, result, "truncated name should be a DNS-1123 label")
+	assert.Equal(t, result, sanitizeName(long), "truncation should be deterministic")
+	assert.NotEqual(t, result, sanitizeName(other), "names differing after the limit should stay distinct")
+
+	assert.LessOrEqual(t, len(indexedName(long, 12)), maxNameLength)
+	assert.NotEqual(t, indexedName(long, 1), indexedName(long, 2))
+}
+
 func TestBuildConfigMapVolumeMount(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -128,18 +151,18 @@ func TestBuildConfigMapVolumeMount(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mount := buildConfigMapVolumeMount(tt.name, tt.destFile, tt.destDir, tt.index)
+			mount := buildConfigMapVolumeMount(tt.name, tt.destFile, tt.destDir, tt.index, DefaultContainerWorkDir)
 			tt.check(t, mount)
 		})
 	}
 }
 
 func TestBuildSecretVolumeMount(t *testing.T) {
-	mount := buildSecretVolumeMount("my-secret", "secrets.yaml", "", 0)
+	mount := buildSecretVolumeMount("my-secret", "secrets.yaml", "", 0, DefaultContainerWorkDir)
 	assert.Equal(t, "my-secret-0", mount["name"], "name should match")
 	assert.Equal(t, "/workspace/secrets.yaml", mount["mountPath"], "mountPath should match")
 
-	mount = buildSecretVolumeMount("my-secret", "/etc/id/backend/secrets.json", "", 0)
+	mount = buildSecretVolumeMount("my-secret", "/etc/id/backend/secrets.json", "", 0, DefaultContainerWorkDir)
 	assert.Equal(t, "my-secret-0", mount["name"], "name should match")
 	assert.Equal(t, "/etc/id/backend/secrets.json", mount["mountPath"], "mountPath should use absolute path")
 }
@@ -173,3 +196,178 @@ func TestBuildSecretVolume(t *testing.T) {
 	vol = buildSecretVolume("my-secret", "", 0)
 	assert.Equal(t, "my-secret", vol["name"], "name should match")
 }
+
+func TestContainerCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		entrypoint  []string
+		shell       string
+		args        []string
+		wantCommand []string
+		wantArgs    []string
+	}{
+		{
+			name:        "default",
+			args:        []string{"workflow", "run"},
+			wantCommand: []string{"ralph"},
+			wantArgs:    []string{"workflow", "run"},
+		},
+		{
+			name:        "entrypoint",
+			entrypoint:  []string{"/usr/local/bin/with-env", "ralph"},
+			args:        []string{"workflow", "run"},
+			wantCommand: []string{"/usr/local/bin/with-env", "ralph"},
+			wantArgs:    []string{"workflow", "run"},
+		},
+		{
+			name:        "shell",
+			shell:       "/bin/bash",
+			args:        []string{"workflow", "comment", "--comment-body", "it's done"},
+			wantCommand: []string{"/bin/bash", "-c", `'ralph' 'workflow' 'comment' '--comment-body' 'it'\''s done'`},
+		},
+		{
+			name:        "shell with entrypoint",
+			entrypoint:  []string{"/opt/ralph"},
+			shell:       "/bin/sh",
+			args:        []string{"workflow", "run"},
+			wantCommand: []string{"/bin/sh", "-c", `'/opt/ralph' 'workflow' 'run'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, args := containerCommand(tt.entrypoint, tt.shell, tt.args)
+			assert.Equal(t, tt.wantCommand, command)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestBuildVolumes_ProjectedItems(t *testing.T) {
+	secrets := []config.SecretMount{
+		{
+			Name:    "app-credentials",
+			DestDir: "/etc/app",
+			Items: []config.MountItem{
+				{Key: "tls.crt", Path: "certs/server.crt"},
+				{Key: "tls.key", Path: "keys/server.key"},
+				{Key: "token"},
+			},
+		},
+	}
+	configMaps := []config.ConfigMapMount{
+		{Name: "app-settings", Items: []config.MountItem{{Key: "a.yaml"}, {Key: "b.yaml", Path: "nested/b.yaml"}}},
+	}
+
+	volumes := buildVolumes(configMaps, secrets)
+	volumesByName := map[string]map[string]interface{}{}
+	for _, volume := range volumes {
+		volumesByName[volume["name"].(string)] = volume
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"sources": []map[string]interface{}{
+			{"secret": map[string]interface{}{
+				"name": "app-credentials",
+				"items": []map[string]interface{}{
+					{"key": "tls.crt", "path": "certs/server.crt"},
+					{"key": "tls.key", "path": "keys/server.key"},
+					{"key": "token", "path": "token"},
+				},
+			}},
+		},
+	}, volumesByName["app-credentials"]["projected"])
+	assert.Equal(t, map[string]interface{}{
+		"sources": []map[string]interface{}{
+			{"configMap": map[string]interface{}{
+				"name": "app-settings",
+				"items": []map[string]interface{}{
+					{"key": "a.yaml", "path": "a.yaml"},
+					{"key": "b.yaml", "path": "nested/b.yaml"},
+				},
+			}},
+		},
+	}, volumesByName["app-settings"]["projected"])
+
+	mountPaths := map[string]string{}
+	for _, mount := range buildVolumeMounts(configMaps, secrets, DefaultContainerWorkDir) {
+		mountPaths[mount["name"].(string)] = mount["mountPath"].(string)
+	}
+	assert.Equal(t, "/etc/app", mountPaths["app-credentials"])
+	assert.Equal(t, "/configmaps/app-settings", mountPaths["app-settings"])
+}
+
+func TestBuildVolumes_UniqueNames(t *testing.T) {
+	configMaps := []config.ConfigMapMount{
+		{Name: "app", DestFile: "config/a.yaml"},
+		{Name: "app", DestFile: "config/b.yaml"},
+		{Name: "app-1", DestDir: "extra"},
+		{Name: "app", DestDir: "whole"},
+	}
+	secrets := []config.SecretMount{
+		{Name: "app", DestFile: "secrets/s.yaml"},
+		{Name: "github-credentials", DestDir: "/etc/github"},
+	}
+
+	volumes := buildVolumes(configMaps, secrets)
+	mounts := buildVolumeMounts(configMaps, secrets, DefaultContainerWorkDir)
+	require.Len(t, mounts, len(volumes))
+
+	var volumeNames, mountNames []string
+	for i := range volumes {
+		volumeNames = append(volumeNames, volumes[i]["name"].(string))
+		mountNames = append(mountNames, mounts[i]["name"].(string))
+	}
+	assert.Equal(t, []string{
+		"github-credentials", "opencode-credentials",
+		"app-0", "app-1", "app-1-2", "app",
+		"app-0-2", "github-credentials-2",
+	}, volumeNames)
+	assert.Equal(t, volumeNames, mountNames, "each mount should reference its own volume")
+}
+
+func TestValidateMountTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		configMaps []config.ConfigMapMount
+		secrets    []config.SecretMount
+		wantErr    string
+	}{
+		{
+			name: "distinct targets",
+			configMaps: []config.ConfigMapMount{
+				{Name: "app", DestFile: "config/a.yaml"},
+				{Name: "app", DestFile: "config/b.yaml"},
+			},
+		},
+		{
+			name: "duplicate dest file",
+			configMaps: []config.ConfigMapMount{
+				{Name: "app", DestFile: "config/a.yaml"},
+			},
+			secrets: []config.SecretMount{
+				{Name: "other", DestFile: "/workspace/config/a.yaml"},
+			},
+			wantErr: `configMap "app" and secret "other" both mount at /workspace/config/a.yaml`,
+		},
+		{
+			name: "credential mount path",
+			secrets: []config.SecretMount{
+				{Name: "token", DestDir: "/secrets/github"},
+			},
+			wantErr: `github-credentials and secret "token" both mount at /secrets/github`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMountTargets(tt.configMaps, tt.secrets, DefaultContainerWorkDir)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
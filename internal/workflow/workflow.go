@@ -42,6 +42,10 @@ type Workflow struct {
 	Secrets []config.SecretMount
 	// Env is the environment variables to set in the container.
 	Env map[string]string
+	// EnvFrom imports every key of the referenced Secrets and ConfigMaps as environment variables.
+	EnvFrom []config.EnvFromSource
+	// EnvRefs set individual environment variables from keys of Secrets and ConfigMaps.
+	EnvRefs []config.EnvRef
 	// KubeContext is the Argo workflow context label.
 	KubeContext string
 	// Namespace is the Kubernetes namespace for workflow submission.
@@ -56,10 +60,72 @@ type Workflow struct {
 	Labels map[string]string
 	// Command is the command tokens to pass to `ralph workflow --command -- <tokens>`.
 	Command []string
+	// Actor is who or what triggered the run; recorded as a workflow annotation and passed to the container.
+	Actor string
+	// Submodules controls whether the container initializes git submodules after cloning.
+	Submodules bool
+	// LFS controls whether the container pulls Git LFS content after cloning; the image must provide git-lfs.
+	LFS bool
+	// ArchiveLogs controls whether Argo archives the executor logs to the configured artifact repository.
+	ArchiveLogs bool
+	// Params are custom workflow parameters from --param, also exposed to the container as RALPH_PARAM_<KEY> env vars.
+	Params map[string]string
+	// WorkDir is the container working directory, under which relative mount paths resolve (default: /workspace).
+	WorkDir string
+	// CloneDir is where the container clones the repository; relative paths resolve under WorkDir (default: <WorkDir>/repo).
+	CloneDir string
+	// Entrypoint replaces the ralph executable in the container command, e.g. a wrapper script (default: ralph).
+	Entrypoint []string
+	// Shell, when set, runs the container command as a single script through `<Shell> -c`.
+	Shell string
+	// KeepFailed keeps the pods of a failed workflow, and the workflow for the TTL, so the run can be debugged.
+	KeepFailed bool
+	// SubmitAttempts is how many times a transiently failing submission is tried (default: config.DefaultSubmitAttempts).
+	SubmitAttempts int
+}
+
+// ActorAnnotation is the workflow annotation recording who or what triggered the run.
+const ActorAnnotation = "ralph/actor"
+
+// workflowMetadata builds the workflow metadata, annotating it with the actor when known.
+func workflowMetadata(generateName string, labels map[string]string, actor string) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"generateName": generateName,
+		"labels":       labels,
+	}
+	if actor != "" {
+		metadata["annotations"] = map[string]string{ActorAnnotation: actor}
+	}
+	return metadata
+}
+
+// buildTTLStrategy deletes the workflow a day after it completes, or only after it succeeds when
+// keepFailed is set, so failed workflows stay around until removed by hand.
+func buildTTLStrategy(keepFailed bool) map[string]interface{} {
+	if keepFailed {
+		return map[string]interface{}{"secondsAfterSuccess": 86400}
+	}
+	return map[string]interface{}{"secondsAfterCompletion": 86400}
+}
+
+// buildPodGC deletes the workflow pods shortly after completion, or only after success when keepFailed is set.
+func buildPodGC(keepFailed bool) map[string]interface{} {
+	strategy := "OnWorkflowCompletion"
+	if keepFailed {
+		strategy = "OnWorkflowSuccess"
+	}
+	return map[string]interface{}{
+		"strategy":            strategy,
+		"deleteDelayDuration": "10m",
+	}
 }
 
 // Render produces the Argo Workflow YAML string for this Workflow.
 func (w *Workflow) Render() (string, error) {
+	if err := validateMountTargets(w.ConfigMaps, w.Secrets, resolveWorkDir(w.WorkDir)); err != nil {
+		return "", fmt.Errorf("invalid workflow mounts: %w", err)
+	}
+
 	params := map[string]string{
 		"project-path":    w.ProjectPath,
 		"instructions-md": w.Instructions,
@@ -75,37 +141,34 @@ func (w *Workflow) Render() (string, error) {
 		wfLabels[k] = v
 	}
 
-	wf := map[string]interface{}{
-		"apiVersion": "argoproj.io/v1alpha1",
-		"kind":       "Workflow",
-		"metadata": map[string]interface{}{
-			"generateName": fmt.Sprintf("ralph-%s-", w.ProjectName),
-			"labels":       wfLabels,
-		},
-		"spec": map[string]interface{}{
-			"entrypoint": "ralph-executor",
-			"ttlStrategy": map[string]interface{}{
-				"secondsAfterCompletion": 86400,
-			},
-			"podGC": map[string]interface{}{
-				"strategy":            "OnWorkflowCompletion",
-				"deleteDelayDuration": "10m",
-			},
-			"synchronization": map[string]interface{}{
-				"mutexes": []interface{}{
-					map[string]interface{}{
-						"name": sanitizeName(w.ProjectBranch),
-					},
+	spec := map[string]interface{}{
+		"entrypoint":  "ralph-executor",
+		"ttlStrategy": buildTTLStrategy(w.KeepFailed),
+		"podGC":       buildPodGC(w.KeepFailed),
+		"synchronization": map[string]interface{}{
+			"mutexes": []interface{}{
+				map[string]interface{}{
+					"name": sanitizeName(w.ProjectBranch),
 				},
 			},
-			"arguments": map[string]interface{}{
-				"parameters": buildParameters(params),
-			},
-			"templates": []interface{}{
-				w.buildMainTemplate(),
-			},
+		},
+		"arguments": map[string]interface{}{
+			"parameters": buildParameters(params, w.Params),
+		},
+		"templates": []interface{}{
+			w.buildMainTemplate(),
 		},
 	}
+	if w.ArchiveLogs {
+		spec["archiveLogs"] = true
+	}
+
+	wf := map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Workflow",
+		"metadata":   workflowMetadata(fmt.Sprintf("ralph-%s-", w.ProjectName), wfLabels, w.Actor),
+		"spec":       spec,
+	}
 
 	yamlData, err := yaml.Marshal(wf)
 	if err != nil {
@@ -120,16 +183,22 @@ func (w *Workflow) Submit(ctx context.Context, client argo.Client) (string, erro
 	if err != nil {
 		return "", err
 	}
-	return client.SubmitYAML(ctx, workflowYAML, argo.K8sContext{Name: w.KubeContext, Namespace: w.Namespace})
+	return argo.SubmitWithRetry(ctx, client, workflowYAML, argo.K8sContext{Name: w.KubeContext, Namespace: w.Namespace}, submitAttempts(w.SubmitAttempts))
+}
+
+// submitAttempts returns attempts, or the default for workflows not built from a config.
+func submitAttempts(attempts int) int {
+	if attempts <= 0 {
+		return config.DefaultSubmitAttempts
+	}
+	return attempts
 }
 
 func (w *Workflow) buildMainTemplate() map[string]interface{} {
-	var command []string
 	var args []string
 
 	switch {
 	case w.CommentBody != "":
-		command = []string{"ralph"}
 		args = []string{
 			"workflow", "comment",
 			"--repo", w.Repo.Owner + "/" + w.Repo.Name,
@@ -151,7 +220,6 @@ func (w *Workflow) buildMainTemplate() map[string]interface{} {
 		}
 
 	case len(w.Command) > 0:
-		command = []string{"ralph"}
 		args = []string{"workflow", "--command", "--"}
 		args = append(args, w.Command...)
 		if w.Verbose {
@@ -162,7 +230,6 @@ func (w *Workflow) buildMainTemplate() map[string]interface{} {
 		}
 
 	default:
-		command = []string{"ralph"}
 		args = []string{
 			"workflow", "run",
 			"--repo", w.Repo.Owner + "/" + w.Repo.Name,
@@ -184,17 +251,27 @@ func (w *Workflow) buildMainTemplate() map[string]interface{} {
 		}
 	}
 
+	command, args := containerCommand(w.Entrypoint, w.Shell, args)
+
+	container := map[string]interface{}{
+		"image":        resolveImage(w.Image),
+		"command":      command,
+		"args":         args,
+		"env":          w.buildEnvVars(),
+		"volumeMounts": buildVolumeMounts(w.ConfigMaps, w.Secrets, resolveWorkDir(w.WorkDir)),
+		"workingDir":   resolveWorkDir(w.WorkDir),
+	}
+	if w.Image.PullPolicy != "" {
+		container["imagePullPolicy"] = w.Image.PullPolicy
+	}
+	if len(w.EnvFrom) > 0 {
+		container["envFrom"] = buildEnvFrom(w.EnvFrom)
+	}
+
 	template := map[string]interface{}{
-		"name": "ralph-executor",
-		"container": map[string]interface{}{
-			"image":        resolveImage(w.Image.Repository, w.Image.Tag),
-			"command":      command,
-			"args":         args,
-			"env":          w.buildEnvVars(),
-			"volumeMounts": buildVolumeMounts(w.ConfigMaps, w.Secrets),
-			"workingDir":   "/workspace",
-		},
-		"volumes": buildVolumes(w.ConfigMaps, w.Secrets),
+		"name":      "ralph-executor",
+		"container": container,
+		"volumes":   buildVolumes(w.ConfigMaps, w.Secrets),
 	}
 
 	if len(w.Labels) > 0 {
@@ -221,6 +298,23 @@ func (w *Workflow) buildEnvVars() []map[string]interface{} {
 		{"name": "RALPH_DEBUG_BRANCH", "value": w.DebugBranch},
 		{"name": "RALPH_VERBOSE", "value": fmt.Sprintf("%t", w.Verbose)},
 		{"name": "RALPH_NO_SERVICES", "value": fmt.Sprintf("%t", w.NoServices)},
+		{"name": "RALPH_CLONE_DIR", "value": resolveCloneDir(w.WorkDir, w.CloneDir)},
+	}
+	if w.Actor != "" {
+		envVars = append(envVars, map[string]interface{}{"name": "RALPH_ACTOR", "value": w.Actor})
+	}
+	if w.Submodules {
+		envVars = append(envVars, map[string]interface{}{"name": "RALPH_SUBMODULES", "value": "true"})
+	}
+	if w.LFS {
+		envVars = append(envVars, map[string]interface{}{"name": "RALPH_LFS", "value": "true"})
+	}
+
+	for _, key := range sortedKeys(w.Params) {
+		envVars = append(envVars, map[string]interface{}{
+			"name":  paramEnvName(key),
+			"value": fmt.Sprintf("{{workflow.parameters.%s}}", key),
+		})
 	}
 
 	for key, value := range w.Env {
@@ -230,5 +324,9 @@ func (w *Workflow) buildEnvVars() []map[string]interface{} {
 		})
 	}
 
+	for _, ref := range w.EnvRefs {
+		envVars = append(envVars, buildEnvRef(ref))
+	}
+
 	return envVars
 }
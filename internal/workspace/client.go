@@ -1,6 +1,11 @@
 package workspace
 
-type Client struct{}
+import "github.com/zon/ralph/internal/output"
+
+type Client struct {
+	Out              *output.Client
+	CleanupRegistrar func(func())
+}
 
 func (c *Client) ChangeDirectory(path string) error {
 	if path == "" {
@@ -8,3 +13,16 @@ func (c *Client) ChangeDirectory(path string) error {
 	}
 	return Chdir(path)
 }
+
+// Isolate clones the repository into a temporary directory for the run and registers its removal,
+// so the clone is deleted even when the run is interrupted.
+func (c *Client) Isolate(inputPath string) (string, func(), error) {
+	iso, err := IsolatedClone(c.Out, inputPath)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.CleanupRegistrar != nil {
+		c.CleanupRegistrar(iso.Remove)
+	}
+	return iso.InputPath, iso.Remove, nil
+}
@@ -0,0 +1,133 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/zon/ralph/internal/git"
+	"github.com/zon/ralph/internal/output"
+)
+
+// Isolated is a temporary clone of the repository that a local run works in instead of the
+// user's checkout.
+type Isolated struct {
+	RepoRoot  string // Root of the user's checkout
+	CloneRoot string // Root of the clone
+	InputPath string // Path of the input file in the clone
+
+	out     *output.Client
+	tempDir string
+	origDir string
+	once    sync.Once
+}
+
+// IsolatedClone clones the repository containing the working directory into a temporary directory
+// and changes into the same subdirectory of the clone. The clone's origin is pointed at the
+// checkout's origin and fetched, so the run compares against and pushes to the real remote. The
+// input file is copied across so uncommitted edits to it are kept. Other uncommitted changes are
+// not part of the clone.
+func IsolatedClone(out *output.Client, inputPath string) (*Isolated, error) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	repoRoot, err := git.FindRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	remoteURL, err := git.RemoteURL()
+	if err != nil {
+		return nil, err
+	}
+	if git.HasUncommittedChanges() {
+		out.Warnf("Uncommitted changes in %s are not part of the isolated clone, except for the input file", repoRoot)
+	}
+
+	tempDir, err := os.MkdirTemp("", "ralph-isolated-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create isolated clone directory: %w", err)
+	}
+	iso := &Isolated{
+		RepoRoot:  repoRoot,
+		CloneRoot: filepath.Join(tempDir, filepath.Base(repoRoot)),
+		out:       out,
+		tempDir:   tempDir,
+		origDir:   origDir,
+	}
+	if err := iso.setup(remoteURL, inputPath); err != nil {
+		iso.Remove()
+		return nil, err
+	}
+	return iso, nil
+}
+
+func (i *Isolated) setup(remoteURL, inputPath string) error {
+	i.out.Infof("Cloning %s into %s", i.RepoRoot, i.CloneRoot)
+	if err := git.Clone(i.RepoRoot, "", i.CloneRoot); err != nil {
+		return err
+	}
+	dir, ok := i.path(i.origDir)
+	if !ok {
+		dir = i.CloneRoot
+	}
+	if err := Chdir(dir); err != nil {
+		return err
+	}
+	if err := git.SetRemoteURL(remoteURL); err != nil {
+		return err
+	}
+	// The clone's origin refs still describe the checkout until they are fetched from the remote
+	if err := git.Fetch(nil); err != nil {
+		return err
+	}
+	clonePath, inRepo := i.path(inputPath)
+	if !inRepo {
+		i.InputPath = inputPath
+		return nil
+	}
+	i.InputPath = clonePath
+	return copyFile(inputPath, clonePath)
+}
+
+// path maps a path inside the user's checkout to the same path in the clone. It reports false for
+// paths outside the checkout, such as a project read from stdin.
+func (i *Isolated) path(path string) (string, bool) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	rel, err := filepath.Rel(i.RepoRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(i.CloneRoot, rel), true
+}
+
+// Remove changes back to the original working directory and deletes the clone. Only the first
+// call does anything, so it can be both deferred and registered as a cleanup.
+func (i *Isolated) Remove() {
+	i.once.Do(func() {
+		if err := Chdir(i.origDir); err != nil {
+			i.out.Warnf("Could not leave the isolated clone: %v", err)
+		}
+		if err := os.RemoveAll(i.tempDir); err != nil {
+			i.out.Warnf("Could not remove the isolated clone %s: %v", i.tempDir, err)
+		}
+	})
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
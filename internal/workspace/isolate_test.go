@@ -0,0 +1,128 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zon/ralph/internal/output"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	out, err := c.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return strings.TrimSpace(string(out))
+}
+
+// setupCheckout clones a remote onto its feature/test branch, commits a project file in projects/
+// and leaves an uncommitted edit to it, like a user's working copy mid-change.
+func setupCheckout(t *testing.T) (remoteDir, checkout string) {
+	t.Helper()
+	remoteDir = setupBareRemoteRepo(t)
+	checkout = t.TempDir()
+	gitOutput(t, checkout, "clone", remoteDir, ".")
+	gitOutput(t, checkout, "config", "--local", "user.email", "test@example.com")
+	gitOutput(t, checkout, "config", "--local", "user.name", "Test User")
+	gitOutput(t, checkout, "checkout", "feature/test")
+	require.NoError(t, os.MkdirAll(filepath.Join(checkout, "projects"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(checkout, "projects", "project.yaml"), []byte("name: committed\n"), 0644))
+	gitOutput(t, checkout, "add", ".")
+	gitOutput(t, checkout, "commit", "-m", "add project")
+	require.NoError(t, os.WriteFile(filepath.Join(checkout, "projects", "project.yaml"), []byte("name: edited\n"), 0644))
+
+	checkout, err := filepath.EvalSymlinks(checkout)
+	require.NoError(t, err)
+	return remoteDir, checkout
+}
+
+func TestIsolatedClone(t *testing.T) {
+	// Fix CWD in case an earlier test left it in a cleaned-up temp directory
+	safeDir := t.TempDir()
+	require.NoError(t, os.Chdir(safeDir))
+	out := output.NewClient(os.Stdout, os.Stderr, false)
+
+	t.Run("runs in a temporary clone and removes it", func(t *testing.T) {
+		defer os.Chdir(safeDir)
+
+		remoteDir, checkout := setupCheckout(t)
+		inputPath := filepath.Join(checkout, "projects", "project.yaml")
+		statusBefore := gitOutput(t, checkout, "status", "--porcelain")
+		require.NoError(t, os.Chdir(filepath.Join(checkout, "projects")))
+
+		iso, err := IsolatedClone(out, inputPath)
+		require.NoError(t, err)
+		assert.Equal(t, checkout, iso.RepoRoot)
+		assert.NotEqual(t, checkout, iso.CloneRoot)
+
+		cwd, err := os.Getwd()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(iso.CloneRoot, "projects"), cwd, "the run starts in the same subdirectory of the clone")
+		assert.Equal(t, remoteDir, gitOutput(t, iso.CloneRoot, "config", "--get", "remote.origin.url"), "the clone pushes to the checkout's remote")
+		assert.Equal(t, "feature/test", gitOutput(t, iso.CloneRoot, "rev-parse", "--abbrev-ref", "HEAD"), "the clone starts on the checkout's branch")
+		assert.Equal(t, gitOutput(t, remoteDir, "rev-parse", "feature/test"), gitOutput(t, iso.CloneRoot, "rev-parse", "origin/feature/test"), "the clone's origin refs come from the remote, not the checkout")
+
+		assert.Equal(t, filepath.Join(iso.CloneRoot, "projects", "project.yaml"), iso.InputPath)
+		data, err := os.ReadFile(iso.InputPath)
+		require.NoError(t, err)
+		assert.Equal(t, "name: edited\n", string(data), "uncommitted edits to the input file are carried across")
+
+		require.NoError(t, os.WriteFile(filepath.Join(iso.CloneRoot, "agent.txt"), []byte("agent work\n"), 0644))
+		gitOutput(t, iso.CloneRoot, "checkout", "-b", "project-branch")
+
+		iso.Remove()
+		iso.Remove()
+
+		_, err = os.Stat(iso.CloneRoot)
+		assert.True(t, os.IsNotExist(err), "the clone is removed")
+		cwd, err = os.Getwd()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(checkout, "projects"), cwd)
+
+		assert.Equal(t, statusBefore, gitOutput(t, checkout, "status", "--porcelain"), "the checkout is untouched")
+		assert.Equal(t, "feature/test", gitOutput(t, checkout, "rev-parse", "--abbrev-ref", "HEAD"))
+		_, err = os.Stat(filepath.Join(checkout, "agent.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("leaves an input file outside the checkout in place", func(t *testing.T) {
+		defer os.Chdir(safeDir)
+
+		_, checkout := setupCheckout(t)
+		inputPath := filepath.Join(t.TempDir(), "stdin-project.yaml")
+		require.NoError(t, os.WriteFile(inputPath, []byte("name: stdin\n"), 0644))
+		require.NoError(t, os.Chdir(checkout))
+
+		iso, err := IsolatedClone(out, inputPath)
+		require.NoError(t, err)
+		defer iso.Remove()
+		assert.Equal(t, inputPath, iso.InputPath)
+	})
+
+	t.Run("fails without an origin remote", func(t *testing.T) {
+		defer os.Chdir(safeDir)
+
+		_, checkout := setupCheckout(t)
+		gitOutput(t, checkout, "remote", "remove", "origin")
+		require.NoError(t, os.Chdir(checkout))
+
+		_, err := IsolatedClone(out, filepath.Join(checkout, "projects", "project.yaml"))
+		require.ErrorContains(t, err, "failed to get remote URL")
+	})
+
+	t.Run("fails outside a repository", func(t *testing.T) {
+		defer os.Chdir(safeDir)
+
+		require.NoError(t, os.Chdir(t.TempDir()))
+
+		_, err := IsolatedClone(out, "project.yaml")
+		require.ErrorContains(t, err, "failed to find repo root")
+	})
+}
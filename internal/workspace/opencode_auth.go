@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Provider entry types written to auth.json by `opencode auth login`
+const (
+	OpenCodeAuthAPIKey    = "api"
+	OpenCodeAuthOAuth     = "oauth"
+	OpenCodeAuthWellKnown = "wellknown"
+)
+
+// openCodeAuthGuidance ends every auth.json validation error
+const openCodeAuthGuidance = "run `opencode auth login` to sign in to a provider and recreate the file"
+
+// openCodeAuthEntry is one provider's credentials in OpenCode's auth.json
+type openCodeAuthEntry struct {
+	Type    string `json:"type"`
+	Key     string `json:"key,omitempty"`
+	Refresh string `json:"refresh,omitempty"`
+	Access  string `json:"access,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
+// ValidateOpenCodeAuth checks that auth.json content is a JSON object holding at least one
+// provider entry, and that every entry has a known type with the credential that type needs.
+func ValidateOpenCodeAuth(content []byte) error {
+	var entries map[string]openCodeAuthEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return fmt.Errorf("auth.json is not a JSON object of provider credentials (%v); %s", err, openCodeAuthGuidance)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("auth.json has no provider credentials; %s", openCodeAuthGuidance)
+	}
+	providers := make([]string, 0, len(entries))
+	for provider := range entries {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		if err := validateOpenCodeAuthEntry(entries[provider]); err != nil {
+			return fmt.Errorf("auth.json provider %q %v; %s", provider, err, openCodeAuthGuidance)
+		}
+	}
+	return nil
+}
+
+func validateOpenCodeAuthEntry(entry openCodeAuthEntry) error {
+	switch entry.Type {
+	case OpenCodeAuthAPIKey:
+		if entry.Key == "" {
+			return fmt.Errorf("has no key")
+		}
+	case OpenCodeAuthOAuth:
+		if entry.Refresh == "" && entry.Access == "" {
+			return fmt.Errorf("has no OAuth tokens")
+		}
+	case OpenCodeAuthWellKnown:
+		if entry.Key == "" && entry.Token == "" {
+			return fmt.Errorf("has no token")
+		}
+	case "":
+		return fmt.Errorf("has no type")
+	default:
+		return fmt.Errorf("has unknown type %q (expected %s, %s or %s)", entry.Type, OpenCodeAuthAPIKey, OpenCodeAuthOAuth, OpenCodeAuthWellKnown)
+	}
+	return nil
+}
+
+// OpenCodeOAuthProviders returns the providers in the auth.json content that sign in with OAuth,
+// sorted by name. An empty result means every provider uses an API key, which stays valid when the
+// file is copied into a workflow secret.
+func OpenCodeOAuthProviders(content []byte) ([]string, error) {
+	var entries map[string]openCodeAuthEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse auth.json: %w", err)
+	}
+	var providers []string
+	for provider, entry := range entries {
+		if entry.Type == OpenCodeAuthOAuth {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
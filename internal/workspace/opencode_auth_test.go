@@ -0,0 +1,111 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenCodeOAuthProviders(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "api keys only",
+			content: `{"deepseek":{"type":"api","key":"sk-one"},"openai":{"type":"api","key":"sk-two"}}`,
+		},
+		{
+			name:    "oauth providers are sorted",
+			content: `{"openai":{"type":"oauth","refresh":"r","access":"a","expires":1},"deepseek":{"type":"api","key":"sk"},"anthropic":{"type":"oauth","refresh":"r","access":"a","expires":1}}`,
+			want:    []string{"anthropic", "openai"},
+		},
+		{
+			name:    "malformed",
+			content: `{"anthropic":`,
+			wantErr: "failed to parse auth.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenCodeOAuthProviders([]byte(tt.content))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateOpenCodeAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "api key", content: `{"deepseek":{"type":"api","key":"sk-test"}}`},
+		{name: "oauth", content: `{"anthropic":{"type":"oauth","refresh":"r","access":"a","expires":1}}`},
+		{name: "wellknown", content: `{"https://example.com":{"type":"wellknown","key":"K","token":"t"}}`},
+		{name: "empty object", content: `{}`, wantErr: "no provider credentials"},
+		{name: "null", content: `null`, wantErr: "no provider credentials"},
+		{name: "malformed", content: `{"deepseek":{"type":"api",`, wantErr: "not a JSON object"},
+		{name: "array", content: `[{"type":"api","key":"sk"}]`, wantErr: "not a JSON object"},
+		{name: "entry is not an object", content: `{"deepseek":"sk-test"}`, wantErr: "not a JSON object"},
+		{name: "missing type", content: `{"deepseek":{"key":"sk-test"}}`, wantErr: `provider "deepseek" has no type`},
+		{name: "unknown type", content: `{"deepseek":{"type":"password"}}`, wantErr: `provider "deepseek" has unknown type "password"`},
+		{name: "api without key", content: `{"deepseek":{"type":"api"}}`, wantErr: `provider "deepseek" has no key`},
+		{name: "oauth without tokens", content: `{"anthropic":{"type":"oauth"}}`, wantErr: `provider "anthropic" has no OAuth tokens`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOpenCodeAuth([]byte(tt.content))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Contains(t, err.Error(), "opencode auth login")
+		})
+	}
+}
+
+func TestReadOpenCodeCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: `{"deepseek":{"type":"api","key":"sk-test"}}`},
+		{name: "empty file", content: "", wantErr: "auth.json is empty"},
+		{name: "empty object", content: "{}", wantErr: "no provider credentials"},
+		{name: "malformed", content: "not json", wantErr: "invalid OpenCode credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "auth.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			content, err := ReadOpenCodeCredentials(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, content)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(content))
+		})
+	}
+}
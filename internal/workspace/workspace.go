@@ -29,6 +29,10 @@ func ReadOpenCodeCredentials(authFilePath string) ([]byte, error) {
 		return nil, fmt.Errorf("auth.json is empty at %s", authFilePath)
 	}
 
+	if err := ValidateOpenCodeAuth(authFileContent); err != nil {
+		return nil, fmt.Errorf("invalid OpenCode credentials at %s: %w", authFilePath, err)
+	}
+
 	return authFileContent, nil
 }
 
@@ -100,9 +104,14 @@ func SetupSymlinks(out *output.Client) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
+	workspaceDir := cfg.Workflow.WorkDir
+	if workspaceDir == "" {
+		workspaceDir = DefaultWorkspaceDir
+	}
+
 	for _, cm := range cfg.Workflow.ConfigMaps {
 		if cm.Link {
-			if err := link(cwd, DefaultWorkspaceDir, cm.DestFile, cm.DestDir, out); err != nil {
+			if err := link(cwd, workspaceDir, cm.DestFile, cm.DestDir, out); err != nil {
 				return err
 			}
 		}
@@ -110,7 +119,7 @@ func SetupSymlinks(out *output.Client) error {
 
 	for _, secret := range cfg.Workflow.Secrets {
 		if secret.Link {
-			if err := link(cwd, DefaultWorkspaceDir, secret.DestFile, secret.DestDir, out); err != nil {
+			if err := link(cwd, workspaceDir, secret.DestFile, secret.DestDir, out); err != nil {
 				return err
 			}
 		}